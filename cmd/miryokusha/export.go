@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/export"
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+)
+
+// runExport exports chapters of a manga to an EPUB or PDF. It returns the
+// process exit code.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	mangaID := fs.String("manga", "", "ID of the manga to export (omit to list the library)")
+	chapterRange := fs.String("chapters", "", "Chapter or chapter range to export, e.g. 12 or 10-20 (default: all)")
+	volume := fs.Float64("volume", 0, "Volume to export")
+	formatName := fs.String("format", "epub", "Output format: epub or pdf")
+	title := fs.String("title", "", "Book title (default: manga title and chapters)")
+	output := fs.String("o", "", "Output file (default: in the exports directory)")
+	localDir := fs.String("local", "", "Export from local files in this directory instead of the server")
+	profileName := fs.String("profile", "", "Image profile for the reading device, \"none\" to keep pages as they are (default: export_profile from the config)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Export chapters as a fixed-layout EPUB or image PDF\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s export [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  # List manga IDs\n")
+		fmt.Fprintf(os.Stderr, "  %s export\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Export chapters 10 to 20 as EPUB\n")
+		fmt.Fprintf(os.Stderr, "  %s export -manga 42 -chapters 10-20\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Export volume 3 as PDF\n")
+		fmt.Fprintf(os.Stderr, "  %s export -manga 42 -volume 3 -format pdf -o vol3.pdf\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Built-in image profiles: %s\n", strings.Join(imageproc.PresetNames(), ", "))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	if *profileName == "" {
+		*profileName = cfg.ImageProcessing.ExportProfile
+	}
+	var profile *imageproc.Profile
+	if *profileName != "none" {
+		profile, err = cfg.ImageProcessing.Profile(*profileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	src, err := exportSource(cfg, *localDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	sm := source.NewSourceManager()
+	sm.AddSource(src)
+
+	if *mangaID == "" {
+		mangaList, err := src.ListManga()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing manga: %v\n", err)
+			return 1
+		}
+		for _, manga := range mangaList {
+			fmt.Printf("%-12s %s\n", manga.ID, manga.Title)
+		}
+		return 0
+	}
+
+	manga, err := src.GetManga(*mangaID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading manga %s: %v\n", *mangaID, err)
+		return 1
+	}
+	chapters, err := src.ListChapters(*mangaID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading chapters: %v\n", err)
+		return 1
+	}
+
+	switch {
+	case *volume > 0:
+		chapters = export.SelectVolume(chapters, *volume)
+	case *chapterRange != "":
+		from, to, err := parseRange(*chapterRange)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		chapters = export.SelectRange(chapters, from, to)
+	}
+	if len(chapters) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no chapters match\n")
+		return 1
+	}
+
+	fmt.Printf("Exporting %d chapters of %s...\n", len(chapters), manga.Title)
+	exporter := export.NewExporter(sm, cfg.Paths.Exports)
+	exporter.SetImageProfile(profile)
+	path, err := exporter.Export(manga, chapters, export.Options{
+		Format: format,
+		Title:  *title,
+		Path:   *output,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Exported to %s\n", path)
+	return 0
+}
+
+// exportSource returns the local source for dir, or the default server
+func exportSource(cfg *config.Config, dir string) (source.Source, error) {
+	if dir != "" {
+		local := source.NewLocalSource("local", "Local", dir)
+		if err := local.AddScanDirectory(dir); err != nil {
+			return nil, err
+		}
+		if err := local.Scan(); err != nil {
+			return nil, err
+		}
+		return local, nil
+	}
+
+	server := cfg.GetDefaultServer()
+	if server == nil {
+		return nil, fmt.Errorf("no Suwayomi server is configured; use -local to export local files")
+	}
+	return source.NewSuwayomiSource("suwayomi-default", server.Name, server.URL), nil
+}
+
+// parseRange parses "from-to" or a single chapter number
+func parseRange(s string) (float64, float64, error) {
+	fromStr, toStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		toStr = fromStr
+	}
+
+	from, err := strconv.ParseFloat(strings.TrimSpace(fromStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid chapter range: %s", s)
+	}
+	to, err := strconv.ParseFloat(strings.TrimSpace(toStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid chapter range: %s", s)
+	}
+	if from > to {
+		from, to = to, from
+	}
+	return from, to, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/hooks"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/tui"
+)
+
+// runHooks lists the configured hooks, shows their recent deliveries or
+// sends them a test event. It returns the process exit code.
+func runHooks(args []string) int {
+	fs := flag.NewFlagSet("hooks", flag.ExitOnError)
+	logCount := fs.Int("log", 0, "Show this many recent deliveries")
+	testHook := fs.String("test", "", "Send a test event to the named hook")
+	eventName := fs.String("event", string(hooks.EventNewChapters), "Event sent by -test")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Show and test the webhooks and commands run on library events\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s hooks [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  # List hooks\n")
+		fmt.Fprintf(os.Stderr, "  %s hooks\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Show the last 20 deliveries\n")
+		fmt.Fprintf(os.Stderr, "  %s hooks -log 20\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Send a test download_failed event to the hook named discord\n")
+		fmt.Fprintf(os.Stderr, "  %s hooks -test discord -event download_failed\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Events: %s\n", strings.Join(config.HookEvents, ", "))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	st, err := storage.NewStorage(cfg.Paths.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+		return 1
+	}
+	defer st.Close()
+
+	switch {
+	case *logCount > 0:
+		return printDeliveries(st, *logCount)
+	case *testHook != "":
+		if !isHookEvent(*eventName) {
+			fmt.Fprintf(os.Stderr, "Error: unknown event: %s\n", *eventName)
+			return 2
+		}
+		return testHookDelivery(cfg, st, *testHook, hooks.EventType(*eventName))
+	}
+
+	if len(cfg.Hooks) == 0 {
+		fmt.Println("No hooks configured")
+		return 0
+	}
+	for _, hook := range tui.HooksFromConfig(cfg.Hooks) {
+		events := "all events"
+		if len(hook.Events) > 0 {
+			var names []string
+			for _, event := range hooks.EventTypes {
+				if hook.Events[event] {
+					names = append(names, string(event))
+				}
+			}
+			events = strings.Join(names, ", ")
+		}
+		target := hook.URL
+		if target == "" {
+			target = strings.Join(hook.Command, " ")
+		}
+		fmt.Printf("%-16s %s\n%-16s %s\n", hook.Name, target, "", events)
+	}
+	return 0
+}
+
+// printDeliveries prints the most recent hook deliveries
+func printDeliveries(st *storage.Storage, limit int) int {
+	deliveries, err := st.HookDeliveries.GetRecentDeliveries(limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(deliveries) == 0 {
+		fmt.Println("No deliveries yet")
+		return 0
+	}
+	for _, d := range deliveries {
+		result := "✅"
+		if !d.Success {
+			result = "❌"
+		}
+		fmt.Printf("%s %s  %-16s %-18s %d attempt(s)  %s\n",
+			result, d.DeliveredAt.Local().Format("2006-01-02 15:04:05"), d.Hook, d.Event, d.Attempts, d.Status)
+		if d.Error != "" {
+			fmt.Printf("   %s\n", d.Error)
+		}
+	}
+	return 0
+}
+
+// testHookDelivery sends a sample event to a hook and reports the result
+func testHookDelivery(cfg *config.Config, st *storage.Storage, name string, eventType hooks.EventType) int {
+	dispatcher, err := hooks.NewDispatcher(tui.HooksFromConfig(cfg.Hooks), st.HookDeliveries)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer dispatcher.Close()
+
+	event := hooks.Event{
+		Type:          eventType,
+		Title:         "Miryokusha Test",
+		Message:       fmt.Sprintf("Test %s event", eventType),
+		MangaID:       "0",
+		MangaTitle:    "Miryokusha Test",
+		ChapterID:     "0",
+		ChapterName:   "Chapter 1",
+		ChapterNumber: 1,
+		Count:         1,
+	}
+	if eventType == hooks.EventDownloadFailed {
+		event.Error = "test error"
+	}
+
+	delivery, err := dispatcher.Deliver(name, event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if !delivery.Success {
+		fmt.Fprintf(os.Stderr, "❌ Delivery failed after %d attempt(s): %s\n", delivery.Attempts, delivery.Error)
+		return 1
+	}
+	fmt.Printf("✅ Delivered to %s (%s, %s)\n", name, delivery.Status, delivery.Duration.Round(time.Millisecond))
+	return 0
+}
+
+// isHookEvent reports whether hooks can receive an event
+func isHookEvent(event string) bool {
+	for _, e := range config.HookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
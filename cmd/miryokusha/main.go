@@ -9,6 +9,16 @@ import (
 )
 
 func main() {
+	// Subcommands run without the TUI
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "hooks":
+			os.Exit(runHooks(os.Args[2:]))
+		}
+	}
+
 	// Create the application model
 	m := tui.NewAppModel()
 
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/codegen"
+)
+
+// runCodegen validates .graphql operations against a saved schema and
+// writes the generated Go code. It returns the process exit code.
+func runCodegen(args []string) int {
+	fs := flag.NewFlagSet("codegen", flag.ExitOnError)
+	schemaPath := fs.String("schema", "schema/suwayomi_schema.json", "Path to the JSON schema")
+	opsDir := fs.String("ops", "internal/suwayomi/operations", "Directory containing .graphql operations")
+	output := fs.String("out", "", "Output Go file (default: operations_gen.go in the -ops directory)")
+	pkg := fs.String("package", "operations", "Package name of the generated file")
+	check := fs.Bool("check", false, "Only validate operations, do not write code")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Generate typed Go code from GraphQL operations\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s codegen [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	introspection, err := suwayomi.LoadSchemaFromFile(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading schema: %v\n", err)
+		return 1
+	}
+
+	doc, files, err := codegen.ParseDir(*opsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	code, err := codegen.Generate(doc, &introspection.Schema, codegen.Options{
+		Package: *pkg,
+		Sources: files,
+	})
+	if err != nil {
+		var errs codegen.ValidationErrors
+		if errors.As(err, &errs) {
+			fmt.Fprintf(os.Stderr, "❌ %d operation error(s):\n", len(errs))
+			for _, e := range errs {
+				fmt.Fprintf(os.Stderr, "  %v\n", e)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return 1
+	}
+
+	if *check {
+		fmt.Printf("✅ %d operation(s) valid against %s\n", len(doc.Operations), *schemaPath)
+		return 0
+	}
+
+	outPath := *output
+	if outPath == "" {
+		outPath = *opsDir + "/operations_gen.go"
+	}
+	if err := os.WriteFile(outPath, code, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
+		return 1
+	}
+
+	fmt.Printf("✅ Generated %d operation(s) into %s\n", len(doc.Operations), outPath)
+	return 0
+}
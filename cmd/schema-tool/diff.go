@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/codegen"
+)
+
+// runDiff compares two saved schema snapshots and reports breaking and
+// non-breaking changes. It returns 1 when breaking changes are found.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	clientPath := fs.String("client", "internal/suwayomi/graphql.go", "Go file with embedded client operations (empty to skip)")
+	opsDir := fs.String("ops", "internal/suwayomi/operations", "Directory with .graphql operations (empty to skip)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Compare two schema snapshots\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s diff [options] old.json new.json\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	oldSchema, err := suwayomi.LoadSchemaFromFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	newSchema, err := suwayomi.LoadSchemaFromFile(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading %s: %v\n", fs.Arg(1), err)
+		return 1
+	}
+
+	diff := suwayomi.DiffSchemas(oldSchema, newSchema)
+
+	// Client operations were written against the old schema
+	var ops []*codegen.ClientOperation
+	if *clientPath != "" {
+		clientOps, err := codegen.ExtractGoOperations(*clientPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read client operations: %v\n", err)
+		}
+		ops = append(ops, clientOps...)
+	}
+	if *opsDir != "" {
+		doc, _, err := codegen.ParseDir(*opsDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read operations: %v\n", err)
+		} else {
+			ops = append(ops, codegen.DocumentOperations(doc, "operations.")...)
+		}
+	}
+	diff.MapOperations(codegen.UsageIndex(ops, &oldSchema.Schema))
+
+	if *asJSON {
+		data, err := diff.JSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(diff.Report())
+	}
+
+	if diff.HasBreakingChanges() {
+		return 1
+	}
+	return 0
+}
@@ -9,6 +9,16 @@ import (
 )
 
 func main() {
+	// Subcommands work offline against saved schema files
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "codegen":
+			os.Exit(runCodegen(os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		}
+	}
+
 	// Define flags
 	serverURL := flag.String("server", "http://localhost:4567", "Suwayomi server URL")
 	outputJSON := flag.String("json", "schema/suwayomi_schema.json", "Output path for JSON schema")
@@ -19,7 +29,9 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Suwayomi GraphQL Schema Introspection Tool\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s codegen [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s diff [options] old.json new.json\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -31,6 +43,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s -server http://localhost:4567 -validate\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  # Save to custom paths\n")
 		fmt.Fprintf(os.Stderr, "  %s -server http://localhost:4567 -json schema.json -sdl schema.graphql\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Generate Go code from .graphql operations\n")
+		fmt.Fprintf(os.Stderr, "  %s codegen -schema schema/suwayomi_schema.json -ops internal/suwayomi/operations\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Compare two saved schema snapshots\n")
+		fmt.Fprintf(os.Stderr, "  %s diff schema/v1.json schema/suwayomi_schema.json\n\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -0,0 +1,145 @@
+// Package comicinfo reads and writes ComicInfo.xml, the metadata file used by
+// comic servers and readers such as Komga, Kavita and most e-readers.
+package comicinfo
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileName is the name of the metadata entry inside a CBZ archive
+const FileName = "ComicInfo.xml"
+
+// Page types defined by the ComicInfo schema
+const (
+	PageFrontCover = "FrontCover"
+	PageStory      = "Story"
+)
+
+// ComicInfo is the metadata of a single chapter archive. Only the fields
+// Miryokusha reads or writes are modelled.
+type ComicInfo struct {
+	XMLName    xml.Name `xml:"ComicInfo"`
+	Title      string   `xml:"Title,omitempty"`
+	Series     string   `xml:"Series,omitempty"`
+	Number     string   `xml:"Number,omitempty"`
+	Volume     int      `xml:"Volume,omitempty"`
+	Summary    string   `xml:"Summary,omitempty"`
+	Year       int      `xml:"Year,omitempty"`
+	Month      int      `xml:"Month,omitempty"`
+	Day        int      `xml:"Day,omitempty"`
+	Writer     string   `xml:"Writer,omitempty"`
+	Penciller  string   `xml:"Penciller,omitempty"`
+	Translator string   `xml:"Translator,omitempty"` // Scanlation group
+	Genre      string   `xml:"Genre,omitempty"`
+	Web        string   `xml:"Web,omitempty"`
+	PageCount  int      `xml:"PageCount,omitempty"`
+	Manga      string   `xml:"Manga,omitempty"` // "Yes" for right-to-left manga
+	Pages      *Pages   `xml:"Pages,omitempty"`
+}
+
+// Pages lists the pages of an archive in reading order
+type Pages struct {
+	Page []Page `xml:"Page"`
+}
+
+// Page describes a single page image
+type Page struct {
+	Image       int    `xml:"Image,attr"`
+	Type        string `xml:"Type,attr,omitempty"`
+	ImageSize   int64  `xml:"ImageSize,attr,omitempty"`
+	ImageWidth  int    `xml:"ImageWidth,attr,omitempty"`
+	ImageHeight int    `xml:"ImageHeight,attr,omitempty"`
+}
+
+// FormatNumber formats a chapter number without trailing zeros
+func FormatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// ChapterNumber parses the Number field, returning 0 if it is not numeric
+func (c *ComicInfo) ChapterNumber() float64 {
+	number, err := strconv.ParseFloat(strings.TrimSpace(c.Number), 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// SetDate sets Year, Month and Day from a release date
+func (c *ComicInfo) SetDate(date time.Time) {
+	if date.IsZero() {
+		return
+	}
+	c.Year = date.Year()
+	c.Month = int(date.Month())
+	c.Day = date.Day()
+}
+
+// Date returns the release date, or the zero time if no year is set
+func (c *ComicInfo) Date() time.Time {
+	if c.Year == 0 {
+		return time.Time{}
+	}
+	month, day := c.Month, c.Day
+	if month == 0 {
+		month = 1
+	}
+	if day == 0 {
+		day = 1
+	}
+	return time.Date(c.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Genres splits the comma-separated Genre field
+func (c *ComicInfo) Genres() []string {
+	var genres []string
+	for _, genre := range strings.Split(c.Genre, ",") {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			genres = append(genres, genre)
+		}
+	}
+	return genres
+}
+
+// Marshal encodes the metadata as an indented XML document
+func (c *ComicInfo) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ComicInfo: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Parse decodes a ComicInfo.xml document
+func Parse(r io.Reader) (*ComicInfo, error) {
+	var info ComicInfo
+	if err := xml.NewDecoder(r).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse ComicInfo: %w", err)
+	}
+	return &info, nil
+}
+
+// FromZip reads the ComicInfo.xml entry of an archive. It returns nil
+// without error if the archive has no metadata.
+func FromZip(archive *zip.Reader) (*ComicInfo, error) {
+	for _, file := range archive.File {
+		if !strings.EqualFold(file.Name, FileName) {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", FileName, err)
+		}
+		defer rc.Close()
+
+		return Parse(rc)
+	}
+	return nil, nil
+}
@@ -0,0 +1,79 @@
+package comicinfo
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComicInfo_RoundTrip(t *testing.T) {
+	info := &ComicInfo{
+		Title:      "The Beginning",
+		Series:     "Example Manga",
+		Number:     FormatNumber(12.5),
+		Volume:     3,
+		Translator: "Example Scans",
+		Genre:      "Action, Fantasy",
+		Web:        "https://example.com/chapter/12.5",
+		PageCount:  2,
+		Manga:      "Yes",
+		Pages: &Pages{Page: []Page{
+			{Image: 0, Type: PageFrontCover, ImageSize: 1024},
+			{Image: 1, ImageSize: 2048},
+		}},
+	}
+	info.SetDate(time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC))
+
+	data, err := info.Marshal()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "<?xml"))
+	assert.Contains(t, string(data), "<Number>12.5</Number>")
+	assert.Contains(t, string(data), `<Page Image="0" Type="FrontCover" ImageSize="1024"></Page>`)
+	assert.NotContains(t, string(data), "<Summary>", "empty fields are omitted")
+
+	parsed, err := Parse(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "Example Manga", parsed.Series)
+	assert.Equal(t, 12.5, parsed.ChapterNumber())
+	assert.Equal(t, 3, parsed.Volume)
+	assert.Equal(t, "Example Scans", parsed.Translator)
+	assert.Equal(t, []string{"Action", "Fantasy"}, parsed.Genres())
+	assert.Equal(t, time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), parsed.Date())
+	require.NotNil(t, parsed.Pages)
+	assert.Len(t, parsed.Pages.Page, 2)
+}
+
+func TestFromZip(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("comicinfo.xml")
+	require.NoError(t, err)
+	f.Write([]byte(`<ComicInfo><Series>Lowercase</Series><Number>7</Number></ComicInfo>`))
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	info, err := FromZip(archive)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "Lowercase", info.Series)
+	assert.Equal(t, 7.0, info.ChapterNumber())
+
+	// Archives without metadata report nil
+	buf.Reset()
+	w = zip.NewWriter(&buf)
+	w.Create("0001.jpg")
+	require.NoError(t, w.Close())
+	archive, err = zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	info, err = FromZip(archive)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
@@ -147,6 +147,31 @@ func setConfigDefaults(config *Config) {
 	if config.Updates.MinIntervalHours == 0 {
 		config.Updates = defaults.Updates
 	}
+
+	if config.Downloads.Format == "" {
+		config.Downloads.Format = defaults.Downloads.Format
+	}
+	if config.Downloads.PageConcurrency == 0 {
+		config.Downloads.PageConcurrency = defaults.Downloads.PageConcurrency
+	}
+	if config.Downloads.MaxConnectionsPerServer == 0 {
+		config.Downloads.MaxConnectionsPerServer = defaults.Downloads.MaxConnectionsPerServer
+	}
+
+	if config.Notifications.RetentionDays == 0 {
+		config.Notifications.RetentionDays = defaults.Notifications.RetentionDays
+	}
+	if config.Notifications.MaxCount == 0 {
+		config.Notifications.MaxCount = defaults.Notifications.MaxCount
+	}
+	if config.Notifications.RateLimit == 0 {
+		config.Notifications.RateLimit = defaults.Notifications.RateLimit
+	}
+	if config.Notifications.Terminal.Protocol == "" {
+		config.Notifications.Terminal.Protocol = defaults.Notifications.Terminal.Protocol
+	}
+
+	setHookDefaults(config.Hooks)
 }
 
 // setDefaultPaths sets default paths if not already set
@@ -183,11 +208,17 @@ func setDefaultPaths(config *Config) error {
 		}
 	}
 
+	// Set default exports path
+	if config.Paths.Exports == "" {
+		config.Paths.Exports = filepath.Join(config.Paths.Downloads, "Exports")
+	}
+
 	// Create directories if they don't exist
 	dirs := []string{
 		filepath.Dir(config.Paths.Database),
 		config.Paths.Cache,
 		config.Paths.Downloads,
+		config.Paths.Exports,
 	}
 
 	for _, dir := range dirs {
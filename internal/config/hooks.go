@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// HookEvents lists the library events hooks can receive
+var HookEvents = []string{
+	"new_chapters",
+	"chapter_completed",
+	"download_complete",
+	"download_failed",
+	"server_down",
+	"server_up",
+}
+
+// HookConfig represents a webhook or command run on library events
+type HookConfig struct {
+	Name   string   `mapstructure:"name" yaml:"name"`
+	Events []string `mapstructure:"events" yaml:"events,omitempty"` // Events received, empty for all
+
+	URL     string            `mapstructure:"url" yaml:"url,omitempty"`         // Webhook receiving a POST
+	Headers map[string]string `mapstructure:"headers" yaml:"headers,omitempty"` // Extra request headers, e.g. Authorization
+	Command []string          `mapstructure:"command" yaml:"command,omitempty"` // Command and arguments, run without a shell
+
+	Payload        string `mapstructure:"payload" yaml:"payload,omitempty"`                 // Go template of the payload, empty for the event as JSON
+	Retries        int    `mapstructure:"retries" yaml:"retries,omitempty"`                 // Retries of failed deliveries (default: 3), negative for none
+	TimeoutSeconds int    `mapstructure:"timeout_seconds" yaml:"timeout_seconds,omitempty"` // Limit of each attempt (default: 10)
+}
+
+// setHookDefaults fills in the retries and timeout of hooks without them
+func setHookDefaults(hooks []HookConfig) {
+	for i := range hooks {
+		if hooks[i].Retries == 0 {
+			hooks[i].Retries = 3
+		}
+		if hooks[i].TimeoutSeconds == 0 {
+			hooks[i].TimeoutSeconds = 10
+		}
+	}
+}
+
+// validateHooks validates the hooks
+func validateHooks(hooks []HookConfig) error {
+	names := make(map[string]bool)
+	for i, hook := range hooks {
+		if hook.Name == "" {
+			return fmt.Errorf("hook at index %d: name cannot be empty", i)
+		}
+		if names[hook.Name] {
+			return fmt.Errorf("duplicate hook name: %s", hook.Name)
+		}
+		names[hook.Name] = true
+
+		if (hook.URL == "") == (len(hook.Command) == 0) {
+			return fmt.Errorf("hook %s: exactly one of url and command must be set", hook.Name)
+		}
+		if hook.URL != "" {
+			u, err := url.Parse(hook.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("hook %s: invalid url: %s", hook.Name, hook.URL)
+			}
+		}
+		if hook.TimeoutSeconds < 0 {
+			return fmt.Errorf("hook %s: timeout_seconds cannot be negative", hook.Name)
+		}
+
+		for _, event := range hook.Events {
+			if !isHookEvent(event) {
+				return fmt.Errorf("hook %s: unknown event: %s", hook.Name, event)
+			}
+		}
+	}
+	return nil
+}
+
+// isHookEvent reports whether hooks can receive an event
+func isHookEvent(event string) bool {
+	for _, e := range HookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
+)
+
+// ImageProcessingConfig represents the page processing applied to downloads
+// and exports, with profiles per reading device
+type ImageProcessingConfig struct {
+	DownloadProfile string                        `mapstructure:"download_profile" yaml:"download_profile"` // Profile applied to downloaded chapters, empty for none
+	ExportProfile   string                        `mapstructure:"export_profile" yaml:"export_profile"`     // Profile applied to EPUB/PDF exports, empty for none
+	Profiles        map[string]ImageProfileConfig `mapstructure:"profiles" yaml:"profiles,omitempty"`       // Custom profiles, keyed by device name
+}
+
+// ImageProfileConfig represents the processing steps of a device profile
+type ImageProfileConfig struct {
+	SplitSpreads bool   `mapstructure:"split_spreads" yaml:"split_spreads"` // Split double-page spreads into two pages
+	RightToLeft  bool   `mapstructure:"right_to_left" yaml:"right_to_left"` // Right page first when splitting
+	CropBorders  bool   `mapstructure:"crop_borders" yaml:"crop_borders"`   // Remove white margins
+	Grayscale    bool   `mapstructure:"grayscale" yaml:"grayscale"`
+	MaxWidth     int    `mapstructure:"max_width" yaml:"max_width"`   // Pixels, 0 for no limit
+	MaxHeight    int    `mapstructure:"max_height" yaml:"max_height"` // Pixels, 0 for no limit
+	Format       string `mapstructure:"format" yaml:"format"`         // "jpeg", "png", or empty to keep the original
+	Quality      int    `mapstructure:"quality" yaml:"quality"`       // JPEG quality 1-100
+}
+
+// profiles converts the custom profiles
+func (c *ImageProcessingConfig) profiles() map[string]imageproc.Profile {
+	profiles := make(map[string]imageproc.Profile, len(c.Profiles))
+	for name, p := range c.Profiles {
+		profiles[name] = imageproc.Profile{
+			Name:         name,
+			SplitSpreads: p.SplitSpreads,
+			RightToLeft:  p.RightToLeft,
+			CropBorders:  p.CropBorders,
+			Grayscale:    p.Grayscale,
+			MaxWidth:     p.MaxWidth,
+			MaxHeight:    p.MaxHeight,
+			Format:       imageproc.Format(p.Format),
+			Quality:      p.Quality,
+		}
+	}
+	return profiles
+}
+
+// Profile returns the named custom or built-in profile, or nil for an
+// empty name
+func (c *ImageProcessingConfig) Profile(name string) (*imageproc.Profile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return imageproc.Lookup(name, c.profiles())
+}
+
+// validateImageProcessing validates the custom profiles and that the
+// selected profiles exist
+func validateImageProcessing(c *ImageProcessingConfig) error {
+	for _, p := range c.profiles() {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := c.Profile(c.DownloadProfile); err != nil {
+		return fmt.Errorf("download_profile: %w", err)
+	}
+	if _, err := c.Profile(c.ExportProfile); err != nil {
+		return fmt.Errorf("export_profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import "fmt"
+
+// NotificationEvents lists the events notifiers can deliver, named after the
+// notification kinds of the notification center
+var NotificationEvents = []string{
+	"new_chapters",
+	"update_failed",
+	"download_complete",
+	"download_failed",
+	"server_status",
+	"extension_update",
+	"error",
+}
+
+// DefaultNotifierEvents are the events delivered by notifiers unless their
+// events turn them off
+var DefaultNotifierEvents = []string{"new_chapters", "download_complete", "download_failed"}
+
+// NotificationsConfig represents how long the notification center keeps
+// notifications and how the user is told about them
+type NotificationsConfig struct {
+	RetentionDays int `mapstructure:"retention_days" yaml:"retention_days"` // Days notifications are kept, negative keeps them forever
+	MaxCount      int `mapstructure:"max_count" yaml:"max_count"`           // Most notifications kept, negative for no limit
+
+	RateLimit  int                    `mapstructure:"rate_limit" yaml:"rate_limit"`   // Notifications shown per minute, the rest are summarized; negative for no limit
+	Terminal   TerminalNotifierConfig `mapstructure:"terminal" yaml:"terminal"`       // OSC 9/777 escape sequences
+	NotifySend NotifierConfig         `mapstructure:"notify_send" yaml:"notify_send"` // Freedesktop notifications via notify-send
+	Bell       NotifierConfig         `mapstructure:"bell" yaml:"bell"`               // Terminal bell, used when no other notifier is available
+}
+
+// NotifierConfig represents a notifier and the events it delivers
+type NotifierConfig struct {
+	Enabled bool            `mapstructure:"enabled" yaml:"enabled"`
+	Events  map[string]bool `mapstructure:"events" yaml:"events,omitempty"` // Events turned on or off on top of the defaults
+}
+
+// TerminalNotifierConfig represents notifications shown by the terminal
+type TerminalNotifierConfig struct {
+	NotifierConfig `mapstructure:",squash" yaml:",inline"`
+
+	Protocol string `mapstructure:"protocol" yaml:"protocol"` // "osc9" or "osc777"
+}
+
+// DeliveredEvents returns the events the notifier delivers: the defaults,
+// turned on or off by the configured events
+func (c NotifierConfig) DeliveredEvents() []string {
+	delivered := make(map[string]bool, len(NotificationEvents))
+	for _, event := range DefaultNotifierEvents {
+		delivered[event] = true
+	}
+	for event, enabled := range c.Events {
+		delivered[event] = enabled
+	}
+
+	var events []string
+	for _, event := range NotificationEvents {
+		if delivered[event] {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// validateNotifications validates the notifier settings
+func validateNotifications(notifications *NotificationsConfig) error {
+	switch notifications.Terminal.Protocol {
+	case "", "osc9", "osc777":
+	default:
+		return fmt.Errorf("invalid terminal protocol: %s (must be 'osc9' or 'osc777')", notifications.Terminal.Protocol)
+	}
+
+	notifiers := map[string]NotifierConfig{
+		"terminal":    notifications.Terminal.NotifierConfig,
+		"notify_send": notifications.NotifySend,
+		"bell":        notifications.Bell,
+	}
+	for name, notifier := range notifiers {
+		for event := range notifier.Events {
+			if !isNotificationEvent(event) {
+				return fmt.Errorf("%s: unknown event: %s", name, event)
+			}
+		}
+	}
+	return nil
+}
+
+// isNotificationEvent reports whether an event can be delivered
+func isNotificationEvent(event string) bool {
+	for _, e := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
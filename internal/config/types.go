@@ -7,6 +7,10 @@ type Config struct {
 	Preferences      PreferencesConfig      `mapstructure:"preferences" yaml:"preferences"`
 	Paths            PathsConfig            `mapstructure:"paths" yaml:"paths"`
 	Updates          UpdateConfig           `mapstructure:"updates" yaml:"updates"`
+	Downloads        DownloadsConfig        `mapstructure:"downloads" yaml:"downloads"`
+	ImageProcessing  ImageProcessingConfig  `mapstructure:"image_processing" yaml:"image_processing"`
+	Notifications    NotificationsConfig    `mapstructure:"notifications" yaml:"notifications"`
+	Hooks            []HookConfig           `mapstructure:"hooks" yaml:"hooks,omitempty"`
 }
 
 // ServerConfig represents a Suwayomi server configuration
@@ -15,6 +19,10 @@ type ServerConfig struct {
 	URL     string      `mapstructure:"url" yaml:"url"`
 	Default bool        `mapstructure:"default" yaml:"default"`
 	Auth    *AuthConfig `mapstructure:"auth,omitempty" yaml:"auth,omitempty"`
+
+	// MaxConnections caps concurrent page downloads from this server,
+	// overriding downloads.max_connections_per_server when set
+	MaxConnections int `mapstructure:"max_connections,omitempty" yaml:"max_connections,omitempty"`
 }
 
 // AuthConfig represents authentication configuration
@@ -50,6 +58,7 @@ type PathsConfig struct {
 	Database  string `mapstructure:"database" yaml:"database"`
 	Cache     string `mapstructure:"cache" yaml:"cache"`
 	Downloads string `mapstructure:"downloads" yaml:"downloads"`
+	Exports   string `mapstructure:"exports" yaml:"exports"` // EPUB and PDF exports
 }
 
 // UpdateConfig represents library update configuration
@@ -59,10 +68,55 @@ type UpdateConfig struct {
 	UpdateOnlyOngoing      bool    `mapstructure:"update_only_ongoing" yaml:"update_only_ongoing"`                    // Only update ongoing series
 	UpdateOnlyStarted      bool    `mapstructure:"update_only_started" yaml:"update_only_started"`                    // Only update series that have been read
 	UpdateOnlyCompleted    bool    `mapstructure:"update_only_completed" yaml:"update_only_completed"`                // Only update completed series
-	MaxConsecutiveFailures int     `mapstructure:"max_consecutive_failures" yaml:"max_consecutive_failures"`          // Skip after this many failures
+	MaxConsecutiveFailures int     `mapstructure:"max_consecutive_failures" yaml:"max_consecutive_failures"`          // Back off after this many failures
 	IntervalMultiplier     float64 `mapstructure:"interval_multiplier" yaml:"interval_multiplier"`                    // Multiply expected interval by this
 	AutoUpdateEnabled      bool    `mapstructure:"auto_update_enabled" yaml:"auto_update_enabled"`                    // Enable automatic updates
 	AutoUpdateIntervalHrs  int     `mapstructure:"auto_update_interval_hrs" yaml:"auto_update_interval_hrs"`          // Hours between automatic updates
+
+	AutoDownload AutoDownloadConfig `mapstructure:"auto_download" yaml:"auto_download"`
+}
+
+// AutoDownloadConfig represents the rules that download new chapters found
+// by library updates
+type AutoDownloadConfig struct {
+	Enabled bool                     `mapstructure:"enabled" yaml:"enabled"`
+	Rules   []AutoDownloadRuleConfig `mapstructure:"rules" yaml:"rules"` // The first rule matching a manga applies
+}
+
+// AutoDownloadRuleConfig represents a single auto-download rule
+type AutoDownloadRuleConfig struct {
+	Categories         []string `mapstructure:"categories" yaml:"categories,omitempty"`           // Empty matches every manga
+	OnlyIfReadPrevious bool     `mapstructure:"only_if_read_previous" yaml:"only_if_read_previous"` // Only when caught up on the manga
+	KeepAhead          int      `mapstructure:"keep_ahead" yaml:"keep_ahead"`                       // Max unread chapters downloaded, 0 for no limit
+	Priority           int      `mapstructure:"priority" yaml:"priority"`                           // Lower downloads first, 0 for the default (20)
+}
+
+// DownloadsConfig represents chapter download configuration
+type DownloadsConfig struct {
+	Format                  string `mapstructure:"format" yaml:"format"`                                           // "images", "cbz", "cbz_comicinfo"
+	PageConcurrency         int    `mapstructure:"page_concurrency" yaml:"page_concurrency"`                       // Pages fetched at once within a chapter
+	BandwidthLimitKB        int    `mapstructure:"bandwidth_limit_kb" yaml:"bandwidth_limit_kb"`                   // KB/s shared by all downloads, 0 for unlimited
+	MaxConnectionsPerServer int    `mapstructure:"max_connections_per_server" yaml:"max_connections_per_server"` // Concurrent page requests per server
+
+	Retention RetentionConfig `mapstructure:"retention" yaml:"retention"`
+}
+
+// RetentionConfig represents the policies that delete downloaded chapters.
+// The top-level policy applies to every manga without a category policy.
+type RetentionConfig struct {
+	RetentionPolicyConfig `mapstructure:",squash" yaml:",inline"`
+
+	Categories    map[string]RetentionPolicyConfig `mapstructure:"categories" yaml:"categories,omitempty"` // Policies replacing the global one, keyed by category name
+	IntervalHours int                              `mapstructure:"interval_hours" yaml:"interval_hours"`   // Hours between scheduled runs, 0 to run only after reading
+	AutoApply     bool                             `mapstructure:"auto_apply" yaml:"auto_apply"`           // Delete without reviewing the dry-run report
+}
+
+// RetentionPolicyConfig represents a single retention policy
+type RetentionPolicyConfig struct {
+	DeleteAfterRead     bool `mapstructure:"delete_after_read" yaml:"delete_after_read"`           // Delete chapters once read
+	KeepRead            int  `mapstructure:"keep_read" yaml:"keep_read"`                           // Most recently read chapters kept per manga, 0 keeps all
+	DeleteReadAfterDays int  `mapstructure:"delete_read_after_days" yaml:"delete_read_after_days"` // Delete chapters read longer ago, 0 disables
+	QuotaMB             int  `mapstructure:"quota_mb" yaml:"quota_mb"`                             // Disk quota, 0 for no limit
 }
 
 // DefaultConfig returns a default configuration
@@ -89,17 +143,37 @@ func DefaultConfig() *Config {
 			Database:  "", // Will be set to default location
 			Cache:     "", // Will be set to default location
 			Downloads: "", // Will be set to default location
+			Exports:   "", // Will be set to default location
 		},
 		Updates: UpdateConfig{
 			SmartUpdate:            true,  // Enable smart updates by default
 			MinIntervalHours:       12,    // Check at most every 12 hours
 			UpdateOnlyOngoing:      true,  // Skip completed series
 			UpdateOnlyStarted:      false, // Update all, not just started
-			MaxConsecutiveFailures: 10,    // Skip after 10 consecutive failures
+			MaxConsecutiveFailures: 10,    // Back off after 10 consecutive failures
 			IntervalMultiplier:     1.5,   // 1.5x safety margin on expected interval
 			AutoUpdateEnabled:      false, // Disable auto-updates by default
 			AutoUpdateIntervalHrs:  24,    // Auto-update once per day if enabled
 		},
+		Downloads: DownloadsConfig{
+			Format:                  "images",
+			PageConcurrency:         4,
+			BandwidthLimitKB:        0,
+			MaxConnectionsPerServer: 4,
+			Retention: RetentionConfig{
+				IntervalHours: 24,
+			},
+		},
+		Notifications: NotificationsConfig{
+			RetentionDays: 30,
+			MaxCount:      500,
+			RateLimit:     5,
+			Terminal: TerminalNotifierConfig{
+				Protocol: "osc9",
+			},
+			NotifySend: NotifierConfig{Enabled: true},
+			Bell:       NotifierConfig{Enabled: true},
+		},
 	}
 }
 
@@ -29,6 +29,33 @@ func Validate(config *Config) error {
 		return fmt.Errorf("invalid paths: %w", err)
 	}
 
+	// Validate downloads
+	if err := validateDownloads(&config.Downloads); err != nil {
+		return fmt.Errorf("invalid downloads: %w", err)
+	}
+
+	// Validate image processing profiles
+	if err := validateImageProcessing(&config.ImageProcessing); err != nil {
+		return fmt.Errorf("invalid image processing: %w", err)
+	}
+
+	// Validate notifiers
+	if err := validateNotifications(&config.Notifications); err != nil {
+		return fmt.Errorf("invalid notifications: %w", err)
+	}
+
+	// Validate hooks
+	if err := validateHooks(config.Hooks); err != nil {
+		return fmt.Errorf("invalid hooks: %w", err)
+	}
+
+	// Validate auto-download rules
+	for i, rule := range config.Updates.AutoDownload.Rules {
+		if err := validateAutoDownloadRule(&rule); err != nil {
+			return fmt.Errorf("invalid auto-download rule at index %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
@@ -64,6 +91,10 @@ func validateServer(server *ServerConfig) error {
 		return fmt.Errorf("server URL must have a host")
 	}
 
+	if server.MaxConnections < 0 {
+		return fmt.Errorf("max_connections cannot be negative")
+	}
+
 	// Validate authentication if present
 	if server.Auth != nil {
 		if err := validateAuth(server.Auth); err != nil {
@@ -177,6 +208,87 @@ func validatePaths(paths *PathsConfig) error {
 		}
 	}
 
+	if paths.Exports != "" {
+		if !isValidPath(paths.Exports) {
+			return fmt.Errorf("invalid exports path: %s", paths.Exports)
+		}
+	}
+
+	return nil
+}
+
+// validateDownloads validates download configuration
+func validateDownloads(downloads *DownloadsConfig) error {
+	if downloads == nil {
+		return fmt.Errorf("downloads is nil")
+	}
+
+	validFormats := map[string]bool{
+		"images":        true,
+		"cbz":           true,
+		"cbz_comicinfo": true,
+	}
+
+	if !validFormats[downloads.Format] {
+		return fmt.Errorf("invalid format: %s (must be 'images', 'cbz', or 'cbz_comicinfo')", downloads.Format)
+	}
+
+	if downloads.PageConcurrency < 1 {
+		return fmt.Errorf("page_concurrency must be at least 1")
+	}
+
+	if downloads.BandwidthLimitKB < 0 {
+		return fmt.Errorf("bandwidth_limit_kb cannot be negative")
+	}
+
+	if downloads.MaxConnectionsPerServer < 1 {
+		return fmt.Errorf("max_connections_per_server must be at least 1")
+	}
+
+	if downloads.Retention.IntervalHours < 0 {
+		return fmt.Errorf("retention interval_hours cannot be negative")
+	}
+
+	if err := validateRetentionPolicy(&downloads.Retention.RetentionPolicyConfig); err != nil {
+		return fmt.Errorf("invalid retention: %w", err)
+	}
+
+	for name, policy := range downloads.Retention.Categories {
+		if err := validateRetentionPolicy(&policy); err != nil {
+			return fmt.Errorf("invalid retention for category %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateRetentionPolicy validates a download retention policy
+func validateRetentionPolicy(policy *RetentionPolicyConfig) error {
+	if policy.KeepRead < 0 {
+		return fmt.Errorf("keep_read cannot be negative")
+	}
+
+	if policy.DeleteReadAfterDays < 0 {
+		return fmt.Errorf("delete_read_after_days cannot be negative")
+	}
+
+	if policy.QuotaMB < 0 {
+		return fmt.Errorf("quota_mb cannot be negative")
+	}
+
+	return nil
+}
+
+// validateAutoDownloadRule validates an auto-download rule
+func validateAutoDownloadRule(rule *AutoDownloadRuleConfig) error {
+	if rule.KeepAhead < 0 {
+		return fmt.Errorf("keep_ahead cannot be negative")
+	}
+
+	if rule.Priority < 0 {
+		return fmt.Errorf("priority cannot be negative")
+	}
+
 	return nil
 }
 
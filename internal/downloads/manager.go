@@ -3,6 +3,8 @@ package downloads
 import (
 	"context"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
 )
 
 // Manager handles download queue and execution
@@ -20,19 +23,32 @@ type Manager struct {
 	config        *DownloadConfig
 	sourceManager *source.SourceManager
 
-	queue        []*DownloadItem
-	active       map[string]*DownloadItem
-	completed    []*DownloadItem
-	stats        *DownloadStats
+	queue     []*DownloadItem
+	active    map[string]*DownloadItem
+	completed []*DownloadItem
+	stats     *DownloadStats
+	store     *storage.DownloadManager // Optional queue persistence
 
-	running      bool
-	ctx          context.Context
-	cancel       context.CancelFunc
+	// Retention
+	progress         *storage.ProgressManager
+	categories       *storage.CategoryManager
+	pendingRetention *RetentionPlan // Automatic run awaiting confirmation
+
+	running bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wake    chan struct{}     // Signals the scheduler to look for due items
+	limiter *bandwidthLimiter // Shared by all downloads
+	slots   *serverSlots      // Connection caps per server
+
+	// Injectable for tests
+	now    func() time.Time
+	random func() float64 // Jitter source in [0, 1)
 
 	// Callbacks
-	onProgress   func(*DownloadItem)
-	onComplete   func(*DownloadItem)
-	onError      func(*DownloadItem, error)
+	onProgress func(*DownloadItem)
+	onComplete func(*DownloadItem)
+	onError    func(*DownloadItem, error)
 }
 
 // NewManager creates a new download manager
@@ -48,6 +64,11 @@ func NewManager(config *DownloadConfig, sm *source.SourceManager) *Manager {
 		active:        make(map[string]*DownloadItem),
 		completed:     make([]*DownloadItem, 0),
 		stats:         &DownloadStats{},
+		wake:          make(chan struct{}, 1),
+		limiter:       newBandwidthLimiter(config.BandwidthLimit, time.Now),
+		slots:         newServerSlots(config.MaxConnectionsPerServer, config.ServerConnections),
+		now:           time.Now,
+		random:        rand.Float64,
 	}
 }
 
@@ -67,6 +88,10 @@ func (m *Manager) Add(manga *source.Manga, chapter *source.Chapter, priority int
 		return fmt.Errorf("chapter is currently downloading")
 	}
 
+	if m.downloadedLocked(chapter.ID) {
+		return fmt.Errorf("chapter already downloaded")
+	}
+
 	// Create download item
 	item := &DownloadItem{
 		ID:          fmt.Sprintf("%s-%s-%d", manga.ID, chapter.ID, time.Now().Unix()),
@@ -75,21 +100,21 @@ func (m *Manager) Add(manga *source.Manga, chapter *source.Chapter, priority int
 		ChapterID:   chapter.ID,
 		ChapterName: chapter.Title,
 		SourceType:  manga.SourceType,
+		Chapter:     chapter,
+		Manga:       manga,
 		Status:      StatusQueued,
 		Priority:    priority,
 	}
 
 	m.queue = append(m.queue, item)
 	m.sortQueue()
+	m.persist(item)
 
 	m.stats.Update(func() {
 		m.stats.TotalDownloads++
 	})
 
-	// Start processing if running
-	if m.running {
-		go m.processQueue()
-	}
+	m.signal()
 
 	return nil
 }
@@ -103,6 +128,12 @@ func (m *Manager) Remove(chapterID string) error {
 	for i, item := range m.queue {
 		if item.ChapterID == chapterID {
 			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			m.unpersist(chapterID)
+			if item.Status == StatusFailed {
+				m.stats.Update(func() {
+					m.stats.FailedDownloads--
+				})
+			}
 			return nil
 		}
 	}
@@ -113,6 +144,7 @@ func (m *Manager) Remove(chapterID string) error {
 			item.Cancel()
 		}
 		delete(m.active, chapterID)
+		m.unpersist(chapterID)
 		return nil
 	}
 
@@ -129,9 +161,14 @@ func (m *Manager) Start() {
 
 	m.running = true
 	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.restore()
+	ctx := m.ctx
 	m.mu.Unlock()
 
-	go m.processQueue()
+	go m.processQueue(ctx)
+	if m.config.RetentionInterval > 0 && m.retentionEnabled() {
+		go m.retentionLoop(ctx)
+	}
 }
 
 // Stop stops the download manager
@@ -169,6 +206,7 @@ func (m *Manager) Pause() {
 		}
 		item.Status = StatusPaused
 		m.queue = append(m.queue, item)
+		m.persist(item)
 	}
 	m.active = make(map[string]*DownloadItem)
 	m.sortQueue()
@@ -182,12 +220,11 @@ func (m *Manager) Resume() {
 	for _, item := range m.queue {
 		if item.Status == StatusPaused {
 			item.Status = StatusQueued
+			m.persist(item)
 		}
 	}
 
-	if m.running {
-		go m.processQueue()
-	}
+	m.signal()
 }
 
 // ClearCompleted removes all completed downloads from the list
@@ -202,8 +239,12 @@ func (m *Manager) GetQueue() []*DownloadItem {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// Items are copied because the scheduler updates queued items in place
 	queue := make([]*DownloadItem, len(m.queue))
-	copy(queue, m.queue)
+	for i, item := range m.queue {
+		snapshot := *item
+		queue[i] = &snapshot
+	}
 	return queue
 }
 
@@ -212,9 +253,11 @@ func (m *Manager) GetActive() []*DownloadItem {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// Items are copied because downloads update their progress in place
 	active := make([]*DownloadItem, 0, len(m.active))
 	for _, item := range m.active {
-		active = append(active, item)
+		snapshot := *item
+		active = append(active, &snapshot)
 	}
 	return active
 }
@@ -234,53 +277,73 @@ func (m *Manager) GetStats() DownloadStats {
 	return m.stats.GetStats()
 }
 
-// SetCallbacks sets callback functions for download events
+// SetCallbacks sets callback functions for download events. onError is
+// called once a download has failed for good, not for attempts that are retried.
 func (m *Manager) SetCallbacks(onProgress, onComplete func(*DownloadItem), onError func(*DownloadItem, error)) {
 	m.onProgress = onProgress
 	m.onComplete = onComplete
 	m.onError = onError
 }
 
-// processQueue processes the download queue
-func (m *Manager) processQueue() {
+// processQueue starts due downloads until ctx is cancelled. It runs when
+// signalled and polls once a second so scheduled retries start on time.
+func (m *Manager) processQueue(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
 	for {
-		m.mu.Lock()
-		if !m.running {
-			m.mu.Unlock()
-			return
-		}
+		m.startDue()
 
-		// Check if we can start more downloads
-		if len(m.active) >= m.config.MaxConcurrent {
-			m.mu.Unlock()
-			time.Sleep(time.Second)
-			continue
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.wake:
+		case <-ticker.C:
 		}
+	}
+}
 
-		// Get next item from queue
-		var nextItem *DownloadItem
-		for i, item := range m.queue {
-			if item.Status == StatusQueued {
-				nextItem = item
-				m.queue = append(m.queue[:i], m.queue[i+1:]...)
-				break
-			}
-		}
+// startDue moves due queue items to the active set, up to MaxConcurrent
+func (m *Manager) startDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-		if nextItem == nil {
-			m.mu.Unlock()
-			time.Sleep(time.Second)
-			continue
+	for m.running && len(m.active) < m.config.MaxConcurrent {
+		i := m.nextDue()
+		if i < 0 {
+			return
 		}
 
-		// Start download
-		m.active[nextItem.ChapterID] = nextItem
+		item := m.queue[i]
+		m.queue = append(m.queue[:i], m.queue[i+1:]...)
+		item.Status = StatusDownloading
+		item.NextAttempt = time.Time{}
+		m.active[item.ChapterID] = item
 		m.stats.Update(func() {
 			m.stats.ActiveDownloads++
 		})
-		m.mu.Unlock()
 
-		go m.downloadChapter(nextItem)
+		go m.downloadChapter(item)
+	}
+}
+
+// nextDue returns the queue index of the highest priority item ready to
+// start, or -1 if none is. Callers hold mu.
+func (m *Manager) nextDue() int {
+	now := m.now()
+	for i, item := range m.queue {
+		if item.Status == StatusQueued && !now.Before(item.NextAttempt) {
+			return i
+		}
+	}
+	return -1
+}
+
+// signal wakes the scheduler without blocking
+func (m *Manager) signal() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
 	}
 }
 
@@ -288,29 +351,53 @@ func (m *Manager) processQueue() {
 func (m *Manager) downloadChapter(item *DownloadItem) {
 	defer func() {
 		m.mu.Lock()
-		delete(m.active, item.ChapterID)
+		// The item may have been paused, removed or retried in the meantime
+		if m.active[item.ChapterID] == item {
+			delete(m.active, item.ChapterID)
+		}
 		m.stats.Update(func() {
 			m.stats.ActiveDownloads--
 		})
 		m.mu.Unlock()
+		m.signal()
 	}()
 
 	// Create context with cancel
 	ctx, cancel := context.WithCancel(m.ctx)
-	item.Cancel = cancel
 	defer cancel()
 
-	item.Status = StatusDownloading
-	item.StartedAt = time.Now()
+	m.mu.Lock()
+	item.Cancel = cancel
+	item.StartedAt = m.now()
+	item.CurrentPage = 0
+	item.BytesDownloaded = 0
+	item.TotalBytes = 0
+	item.Throughput = 0
+	item.bytesFetched = 0
+	m.persist(item)
+	m.mu.Unlock()
 
-	// Get all pages for the chapter
-	pages, err := m.sourceManager.GetAllPages(item.Chapter)
+	// Learn the page count without downloading the pages themselves, so
+	// pages already on disk are not fetched again. Sources that cannot
+	// report a count deliver every page at once instead.
+	var pages []*source.Page
+	total, err := m.sourceManager.PreparePages(item.Chapter)
 	if err != nil {
 		m.handleError(item, err)
 		return
 	}
+	if total <= 0 {
+		pages, err = m.sourceManager.GetAllPages(item.Chapter)
+		if err != nil {
+			m.handleError(item, err)
+			return
+		}
+		total = len(pages)
+	}
 
-	item.TotalPages = len(pages)
+	m.mu.Lock()
+	item.TotalPages = total
+	m.mu.Unlock()
 
 	// Create chapter directory
 	chapterDir := filepath.Join(
@@ -323,42 +410,71 @@ func (m *Manager) downloadChapter(item *DownloadItem) {
 		m.handleError(item, err)
 		return
 	}
+	removeTempFiles(chapterDir)
 
-	// Download each page
-	for i, page := range pages {
-		select {
-		case <-ctx.Done():
-			// Download cancelled
-			item.Status = StatusFailed
-			item.Error = fmt.Errorf("download cancelled")
-			return
-		default:
-		}
+	// A corrupt manifest is discarded; intact pages are adopted again below
+	mf, err := loadManifest(chapterDir)
+	if err != nil {
+		mf = newManifest()
+	}
+	if mf.Profile != "" {
+		mf = discardProcessed(chapterDir, mf)
+	}
+	mf.setItem(item)
+	mf.TotalPages = total
+	mf.Complete = false
+	if err := mf.save(chapterDir); err != nil {
+		m.handleError(item, err)
+		return
+	}
 
-		// Download page with timeout
-		pageCtx, pageCancel := context.WithTimeout(ctx, m.config.PageTimeout)
-		err := m.downloadPage(pageCtx, page, chapterDir, i, item.Chapter)
-		pageCancel()
+	// Skip pages already verified on disk, then fetch the rest
+	var missing []int
+	for i := 0; i < total; i++ {
+		if pageDone(chapterDir, mf, i) {
+			m.pageStored(item, mf.Pages[i].Size, 0)
+		} else {
+			missing = append(missing, i)
+		}
+	}
 
-		if err != nil {
-			m.handleError(item, err)
+	if err := m.fetchPages(ctx, item, chapterDir, mf, pages, missing); err != nil {
+		if ctx.Err() != nil {
+			m.interrupted(item)
 			return
 		}
+		m.handleError(item, err)
+		return
+	}
 
-		item.CurrentPage = i + 1
+	mf.Complete = true
+	if err := mf.save(chapterDir); err != nil {
+		m.handleError(item, err)
+		return
+	}
 
-		// Call progress callback
-		if m.onProgress != nil {
-			m.onProgress(item)
-		}
+	if err := processChapter(chapterDir, mf, m.config.ImageProfile); err != nil {
+		m.handleError(item, err)
+		return
 	}
+	m.mu.Lock()
+	item.TotalPages = mf.TotalPages
+	item.CurrentPage = mf.TotalPages
+	m.mu.Unlock()
 
-	// Mark as completed
-	item.Status = StatusCompleted
-	item.CompletedAt = time.Now()
+	outputPath, err := m.packageChapter(chapterDir, mf)
+	if err != nil {
+		m.handleError(item, err)
+		return
+	}
 
+	// Mark as completed
 	m.mu.Lock()
+	item.Status = StatusCompleted
+	item.CompletedAt = m.now()
+	item.TotalBytes = item.BytesDownloaded
 	m.completed = append(m.completed, item)
+	m.recordCompleted(item, outputPath)
 	m.mu.Unlock()
 
 	m.stats.Update(func() {
@@ -370,84 +486,232 @@ func (m *Manager) downloadChapter(item *DownloadItem) {
 	}
 }
 
-// downloadPage downloads a single page
-func (m *Manager) downloadPage(ctx context.Context, page *source.Page, chapterDir string, index int, chapter *source.Chapter) error {
-	// Get page data - try using the ImageData if it's already loaded
-	var data []byte
-	if len(page.ImageData) > 0 {
-		data = page.ImageData
-	} else {
-		// Otherwise fetch from source
-		var err error
-		data, err = m.sourceManager.GetPage(chapter, index)
+// fetchPages downloads the given pages of a chapter, up to PageConcurrency
+// at a time, within the server's connection cap and the bandwidth limit.
+// It stops at the first failure and returns its error.
+func (m *Manager) fetchPages(ctx context.Context, item *DownloadItem, dir string, mf *chapterManifest, pages []*source.Page, missing []int) error {
+	if len(missing) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := m.config.PageConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(missing) {
+		workers = len(missing)
+	}
+
+	server := m.serverFor(item.Chapter)
+	indexes := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		mfMu     sync.Mutex // Guards the manifest
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	fetch := func(index int) error {
+		release, err := m.slots.acquire(ctx, server)
+		if err != nil {
+			return err
+		}
+		data, err := m.fetchPage(ctx, item.Chapter, pages, index)
+		release()
 		if err != nil {
 			return err
 		}
+
+		entry, err := writePage(dir, index, data)
+		if err != nil {
+			return err
+		}
+
+		mfMu.Lock()
+		mf.Pages[index] = entry
+		err = mf.save(dir)
+		mfMu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		m.pageStored(item, entry.Size, int64(len(data)))
+		return nil
 	}
 
-	// Determine file extension from URL or default to .jpg
-	ext := ".jpg"
-	if len(page.URL) > 0 {
-		ext = filepath.Ext(page.URL)
-		if ext == "" {
-			ext = ".jpg"
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if err := fetch(index); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, index := range missing {
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+			break feed
 		}
 	}
+	close(indexes)
+	wg.Wait()
 
-	// Save to file
-	filename := fmt.Sprintf("%04d%s", index+1, ext)
-	filePath := filepath.Join(chapterDir, filename)
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// pageStored updates an item's progress once a page is on disk. fetched is
+// the number of bytes transferred for it, zero for pages already on disk.
+func (m *Manager) pageStored(item *DownloadItem, size, fetched int64) {
+	m.mu.Lock()
+	item.CurrentPage++
+	item.BytesDownloaded += size
+	item.bytesFetched += fetched
+
+	// The chapter size is estimated from the average page size so far
+	item.TotalBytes = item.BytesDownloaded * int64(item.TotalPages) / int64(item.CurrentPage)
+	if elapsed := m.now().Sub(item.StartedAt).Seconds(); elapsed > 0 {
+		item.Throughput = float64(item.bytesFetched) / elapsed
+	}
+	m.mu.Unlock()
 
-	return os.WriteFile(filePath, data, 0644)
+	if fetched > 0 {
+		m.stats.Update(func() {
+			m.stats.TotalBytesDownloaded += fetched
+		})
+	}
+
+	if m.onProgress != nil {
+		m.onProgress(item)
+	}
 }
 
-// handleError handles download errors with retry logic
-func (m *Manager) handleError(item *DownloadItem, err error) {
-	item.Error = err
-	item.Status = StatusFailed
+// interrupted handles a download whose context was cancelled. Pause, Remove
+// and Stop already took the item out of the active set and recorded its
+// state: paused downloads stay resumable, and downloads interrupted by Stop
+// keep their persisted state so they restart on next Start.
+func (m *Manager) interrupted(item *DownloadItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	m.stats.Update(func() {
-		m.stats.FailedDownloads++
-	})
+	if m.active[item.ChapterID] != item {
+		return
+	}
+	delete(m.active, item.ChapterID)
+	item.Status = StatusFailed
+	item.Error = fmt.Errorf("download cancelled")
+	m.queue = append(m.queue, item)
+	m.sortQueue()
+	m.persist(item)
+}
 
-	if m.onError != nil {
-		m.onError(item, err)
+// fetchPage returns the data of a single page, using data already delivered
+// by the source when available and otherwise fetching it within PageTimeout.
+// Both are charged to the bandwidth limit, fetched pages as they arrive.
+func (m *Manager) fetchPage(ctx context.Context, chapter *source.Chapter, pages []*source.Page, index int) ([]byte, error) {
+	if index < len(pages) && len(pages[index].ImageData) > 0 {
+		data := pages[index].ImageData
+		if err := m.limiter.wait(ctx, len(data)); err != nil {
+			return nil, err
+		}
+		return data, nil
 	}
 
-	// Implement retry logic
-	if item.RetryCount < m.config.RetryAttempts {
-		// Increment retry count
-		item.RetryCount++
+	pageCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	deadline := newPageDeadline(m.config.PageTimeout, func() { cancel(context.DeadlineExceeded) })
+	defer deadline.stop()
 
-		// Reset status to queued for retry
-		item.Status = StatusQueued
-		item.Error = nil
-
-		// Add back to queue with lower priority (higher number)
-		// This ensures newer downloads are processed first
-		item.Priority += 100
-
-		// Wait before retrying (exponential backoff)
-		retryDelay := m.config.RetryDelay * time.Duration(item.RetryCount)
-		time.AfterFunc(retryDelay, func() {
-			m.mu.Lock()
-			defer m.mu.Unlock()
-
-			// Check if item is still in queue and queued status
-			for _, qItem := range m.queue {
-				if qItem.ID == item.ID && qItem.Status == StatusQueued {
-					// Item is ready to retry
-					m.processQueue()
-					break
-				}
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		body, err := m.sourceManager.OpenPage(pageCtx, chapter, index)
+		if err != nil {
+			done <- result{nil, err}
+			return
+		}
+		defer body.Close()
+		data, err := io.ReadAll(&throttledReader{ctx: pageCtx, r: body, limiter: m.limiter, deadline: deadline})
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			if cause := context.Cause(pageCtx); cause != nil {
+				r.err = cause
 			}
+			return nil, fmt.Errorf("failed to fetch page %d: %w", index+1, r.err)
+		}
+		return r.data, nil
+	case <-pageCtx.Done():
+		return nil, fmt.Errorf("failed to fetch page %d: %w", index+1, context.Cause(pageCtx))
+	}
+}
+
+// handleError records a failed attempt. Transient errors are retried after
+// an exponential backoff with jitter; permanent errors and exhausted retries
+// leave the item failed in the queue until it is retried by the user.
+func (m *Manager) handleError(item *DownloadItem, err error) {
+	m.mu.Lock()
+
+	if m.active[item.ChapterID] != item {
+		// Paused, removed or stopped while the attempt was failing
+		m.mu.Unlock()
+		return
+	}
+	delete(m.active, item.ChapterID)
+
+	item.Error = err
+	failed := isPermanent(err) || item.RetryCount >= m.config.RetryAttempts
+	if failed {
+		item.Status = StatusFailed
+		item.NextAttempt = time.Time{}
+		m.stats.Update(func() {
+			m.stats.FailedDownloads++
 		})
+	} else {
+		item.RetryCount++
+		item.Status = StatusQueued
+		item.NextAttempt = m.now().Add(m.backoff(item.RetryCount))
+	}
+
+	m.queue = append(m.queue, item)
+	m.sortQueue()
+	m.persist(item)
+	m.mu.Unlock()
+
+	if failed && m.onError != nil {
+		m.onError(item, err)
 	}
 }
 
 // sortQueue sorts the queue by priority (lower number = higher priority)
 func (m *Manager) sortQueue() {
-	sort.Slice(m.queue, func(i, j int) bool {
+	// Stable so chapters queued together keep their reading order
+	sort.SliceStable(m.queue, func(i, j int) bool {
 		return m.queue[i].Priority < m.queue[j].Priority
 	})
 }
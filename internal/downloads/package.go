@@ -0,0 +1,192 @@
+package downloads
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/comicinfo"
+)
+
+// archivePath returns the CBZ path for a chapter directory
+func archivePath(chapterDir string) string {
+	return chapterDir + ".cbz"
+}
+
+// packageChapter stores a completed chapter in the configured output format
+// and returns the path of the result
+func (m *Manager) packageChapter(chapterDir string, mf *chapterManifest) (string, error) {
+	switch m.config.OutputFormat {
+	case FormatCBZ:
+		return packageCBZ(chapterDir, mf, false)
+	case FormatCBZComicInfo:
+		return packageCBZ(chapterDir, mf, true)
+	default:
+		return chapterDir, nil
+	}
+}
+
+// packageCBZ writes a chapter's verified pages to a CBZ archive next to the
+// chapter directory, optionally with ComicInfo.xml, then removes the directory
+func packageCBZ(chapterDir string, mf *chapterManifest, withComicInfo bool) (string, error) {
+	target := archivePath(chapterDir)
+
+	tmp, err := os.CreateTemp(filepath.Dir(chapterDir), ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	zw := zip.NewWriter(tmp)
+	var pages []comicinfo.Page
+
+	for i := 0; i < mf.TotalPages; i++ {
+		entry, ok := mf.Pages[i]
+		if !ok {
+			tmp.Close()
+			return "", fmt.Errorf("failed to package chapter: page %d missing", i+1)
+		}
+
+		data, err := os.ReadFile(filepath.Join(chapterDir, entry.File))
+		if err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to read page %d: %w", i+1, err)
+		}
+
+		// Page images are already compressed, so store them as-is
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: entry.File, Method: zip.Store})
+		if err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to add page %d: %w", i+1, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to add page %d: %w", i+1, err)
+		}
+
+		page := comicinfo.Page{Image: i, ImageSize: entry.Size}
+		if i == 0 {
+			page.Type = comicinfo.PageFrontCover
+		}
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			page.ImageWidth = cfg.Width
+			page.ImageHeight = cfg.Height
+		}
+		pages = append(pages, page)
+	}
+
+	if withComicInfo {
+		info := comicInfoFor(mf)
+		info.Pages = &comicinfo.Pages{Page: pages}
+
+		data, err := info.Marshal()
+		if err != nil {
+			tmp.Close()
+			return "", err
+		}
+		w, err := zw.Create(comicinfo.FileName)
+		if err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to add %s: %w", comicinfo.FileName, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to add %s: %w", comicinfo.FileName, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to sync archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return "", fmt.Errorf("failed to set archive permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return "", fmt.Errorf("failed to rename archive: %w", err)
+	}
+
+	if err := os.RemoveAll(chapterDir); err != nil {
+		return "", fmt.Errorf("failed to remove chapter directory: %w", err)
+	}
+
+	return target, nil
+}
+
+// comicInfoFor builds the ComicInfo metadata of a chapter
+func comicInfoFor(mf *chapterManifest) *comicinfo.ComicInfo {
+	md := mf.Metadata
+
+	info := &comicinfo.ComicInfo{
+		Title:      mf.ChapterName,
+		Series:     mf.MangaTitle,
+		Number:     comicinfo.FormatNumber(mf.ChapterNumber),
+		Volume:     int(md.VolumeNumber),
+		Summary:    md.Description,
+		Writer:     md.Author,
+		Penciller:  md.Artist,
+		Translator: md.Scanlator,
+		Genre:      strings.Join(md.Genres, ", "),
+		Web:        md.URL,
+		PageCount:  mf.TotalPages,
+		Manga:      "Yes",
+	}
+	if info.Web == "" {
+		info.Web = md.MangaURL
+	}
+	info.SetDate(md.UploadDate)
+
+	return info
+}
+
+// verifyArchive decodes every page of a CBZ archive and returns the
+// problems found
+func verifyArchive(path string) []string {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return []string{"unreadable archive"}
+	}
+	defer zr.Close()
+
+	var problems []string
+	pages := 0
+	for _, file := range zr.File {
+		if !pageFilePattern.MatchString(file.Name) {
+			continue
+		}
+		pages++
+
+		rc, err := file.Open()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("unreadable page %s", file.Name))
+			continue
+		}
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rc)
+		rc.Close()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("unreadable page %s", file.Name))
+			continue
+		}
+		if _, err := verifyImage(buf.Bytes()); err != nil {
+			problems = append(problems, fmt.Sprintf("corrupt page %s", file.Name))
+		}
+	}
+
+	if pages == 0 {
+		problems = append(problems, "archive has no pages")
+	}
+	return problems
+}
@@ -0,0 +1,152 @@
+package downloads
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/comicinfo"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageCBZ_RoundTrip(t *testing.T) {
+	uploaded := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	item := &DownloadItem{
+		MangaID:     "m1",
+		MangaTitle:  "Frieren",
+		ChapterID:   "c1",
+		ChapterName: "The End of the Journey",
+		SourceType:  source.SourceTypeSuwayomi,
+		Chapter: &source.Chapter{
+			ID:             "c1",
+			ChapterNumber:  10.5,
+			VolumeNumber:   2,
+			ScanlatorGroup: "Group",
+			URL:            "https://example.com/chapter/10.5",
+			UploadDate:     uploaded,
+		},
+		Manga: &source.Manga{
+			ID:          "m1",
+			Title:       "Frieren",
+			Author:      "Kanehito Yamada",
+			Artist:      "Tsukasa Abe",
+			Genres:      []string{"Adventure", "Fantasy"},
+			Description: "An elf mage looks back.",
+		},
+	}
+
+	// Twelve pages of distinct widths, so the order is visible after reading
+	chapterDir := filepath.Join(t.TempDir(), "Frieren", "Chapter 10.5")
+	require.NoError(t, os.MkdirAll(chapterDir, 0755))
+	mf := newManifest()
+	mf.setItem(item)
+	mf.TotalPages = 12
+	for i := 0; i < mf.TotalPages; i++ {
+		entry, err := writePage(chapterDir, i, encodePNG(t, i+1, 2))
+		require.NoError(t, err)
+		mf.Pages[i] = entry
+	}
+	mf.Complete = true
+	require.NoError(t, mf.save(chapterDir))
+
+	path, err := packageCBZ(chapterDir, mf, true)
+	require.NoError(t, err)
+	assert.Equal(t, chapterDir+".cbz", path)
+	assert.NoDirExists(t, chapterDir)
+	assert.Empty(t, tempFiles(t, filepath.Dir(chapterDir)))
+
+	// Pages in reading order, then the metadata; the manifest is not packaged
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer zr.Close()
+	var names []string
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	var want []string
+	for i := 1; i <= 12; i++ {
+		want = append(want, fmt.Sprintf("%04d.png", i))
+	}
+	assert.Equal(t, append(want, comicinfo.FileName), names)
+	assert.Equal(t, zip.Store, zr.File[0].Method, "pages are stored uncompressed")
+
+	info, err := comicinfo.FromZip(&zr.Reader)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "The End of the Journey", info.Title)
+	assert.Equal(t, "Frieren", info.Series)
+	assert.Equal(t, "10.5", info.Number)
+	assert.Equal(t, 2, info.Volume)
+	assert.Equal(t, "Kanehito Yamada", info.Writer)
+	assert.Equal(t, "Tsukasa Abe", info.Penciller)
+	assert.Equal(t, "Group", info.Translator)
+	assert.Equal(t, "Adventure, Fantasy", info.Genre)
+	assert.Equal(t, "https://example.com/chapter/10.5", info.Web)
+	assert.Equal(t, uploaded, info.Date())
+	assert.Equal(t, 12, info.PageCount)
+	assert.Equal(t, "Yes", info.Manga)
+	require.NotNil(t, info.Pages)
+	require.Len(t, info.Pages.Page, 12)
+	assert.Equal(t, comicinfo.PageFrontCover, info.Pages.Page[0].Type)
+	assert.Empty(t, info.Pages.Page[1].Type)
+	assert.Equal(t, 11, info.Pages.Page[11].Image)
+	assert.Equal(t, 12, info.Pages.Page[11].ImageWidth)
+	assert.Equal(t, mf.Pages[11].Size, info.Pages.Page[11].ImageSize)
+
+	// The local source reads the archive as a chapter of its series
+	local := source.NewLocalSource("local", "Local", t.TempDir())
+	require.NoError(t, local.AddFile(path))
+
+	mangas, err := local.ListManga()
+	require.NoError(t, err)
+	require.Len(t, mangas, 1)
+	manga := mangas[0]
+	assert.Equal(t, "Frieren", manga.Title)
+	assert.Equal(t, "Kanehito Yamada", manga.Author)
+	assert.Equal(t, "Tsukasa Abe", manga.Artist)
+	assert.Equal(t, []string{"Adventure", "Fantasy"}, manga.Genres)
+	assert.Equal(t, "An elf mage looks back.", manga.Description)
+
+	chapters, err := local.ListChapters(manga.ID)
+	require.NoError(t, err)
+	require.Len(t, chapters, 1)
+	chapter := chapters[0]
+	assert.Equal(t, "The End of the Journey", chapter.Title)
+	assert.Equal(t, 10.5, chapter.ChapterNumber)
+	assert.Equal(t, 2.0, chapter.VolumeNumber)
+	assert.Equal(t, 12, chapter.PageCount)
+	assert.Equal(t, "Group", chapter.ScanlatorGroup)
+	assert.Equal(t, uploaded, chapter.UploadDate)
+
+	pages, err := local.GetAllPages(chapter)
+	require.NoError(t, err)
+	require.Len(t, pages, 12)
+	for i, page := range pages {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(page.ImageData))
+		require.NoError(t, err)
+		assert.Equal(t, i+1, cfg.Width, "page %d is in reading order", i+1)
+	}
+}
+
+func TestPackageCBZ_MissingPage(t *testing.T) {
+	chapterDir := filepath.Join(t.TempDir(), "Chapter 1")
+	require.NoError(t, os.MkdirAll(chapterDir, 0755))
+	mf := newManifest()
+	mf.TotalPages = 2
+	entry, err := writePage(chapterDir, 0, encodePNG(t, 2, 2))
+	require.NoError(t, err)
+	mf.Pages[0] = entry
+
+	_, err = packageCBZ(chapterDir, mf, false)
+	assert.Error(t, err)
+	assert.DirExists(t, chapterDir, "the pages are kept")
+	assert.NoFileExists(t, archivePath(chapterDir))
+	assert.Empty(t, tempFiles(t, filepath.Dir(chapterDir)))
+}
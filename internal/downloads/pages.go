@@ -0,0 +1,306 @@
+package downloads
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	// Image decoders used to verify downloaded pages
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	_ "golang.org/x/image/webp"
+)
+
+// manifestName is the file recording the pages of a chapter directory
+const manifestName = ".miryokusha.json"
+
+// chapterManifest records a chapter's identity and the checksum of every
+// verified page, so interrupted downloads resume and corruption is detectable
+type chapterManifest struct {
+	MangaID       string             `json:"mangaId"`
+	MangaTitle    string             `json:"mangaTitle"`
+	ChapterID     string             `json:"chapterId"`
+	ChapterName   string             `json:"chapterName"`
+	ChapterNumber float64            `json:"chapterNumber"`
+	SourceType    string             `json:"sourceType"`
+	SourceID      string             `json:"sourceId"`
+	Metadata      chapterMetadata    `json:"metadata"`
+	TotalPages    int                `json:"totalPages"`
+	Complete      bool               `json:"complete"`
+	Profile       string             `json:"profile,omitempty"` // Image profile applied to the pages
+	Pages         map[int]*pageEntry `json:"pages"`
+}
+
+// chapterMetadata holds the details written to ComicInfo.xml. It is kept in
+// the manifest because downloads restored from the queue lack manga details.
+type chapterMetadata struct {
+	VolumeNumber float64   `json:"volumeNumber,omitempty"`
+	Scanlator    string    `json:"scanlator,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	UploadDate   time.Time `json:"uploadDate"`
+	MangaURL     string    `json:"mangaUrl,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	Artist       string    `json:"artist,omitempty"`
+	Genres       []string  `json:"genres,omitempty"`
+	Description  string    `json:"description,omitempty"`
+}
+
+// pageEntry describes a verified page file
+type pageEntry struct {
+	File   string `json:"file"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// newManifest returns an empty chapter manifest
+func newManifest() *chapterManifest {
+	return &chapterManifest{Pages: make(map[int]*pageEntry)}
+}
+
+// loadManifest reads a chapter manifest, returning an empty one if missing
+func loadManifest(dir string) (*chapterManifest, error) {
+	mf := newManifest()
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if os.IsNotExist(err) {
+		return mf, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, mf); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if mf.Pages == nil {
+		mf.Pages = make(map[int]*pageEntry)
+	}
+	return mf, nil
+}
+
+// save writes the manifest atomically
+func (mf *chapterManifest) save(dir string) error {
+	data, err := json.MarshalIndent(mf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(dir, manifestName), data)
+}
+
+// setItem records the identity of the chapter being downloaded
+func (mf *chapterManifest) setItem(item *DownloadItem) {
+	mf.MangaID = item.MangaID
+	mf.MangaTitle = item.MangaTitle
+	mf.ChapterID = item.ChapterID
+	mf.ChapterName = item.ChapterName
+	mf.SourceType = string(item.SourceType)
+	if item.Chapter != nil {
+		mf.ChapterNumber = item.Chapter.ChapterNumber
+		mf.SourceID = item.Chapter.SourceID
+		mf.Metadata.setChapter(item.Chapter)
+	}
+	if item.Manga != nil {
+		mf.Metadata.setManga(item.Manga)
+	}
+}
+
+// setChapter records chapter details, keeping earlier values the chapter lacks
+func (md *chapterMetadata) setChapter(chapter *source.Chapter) {
+	if chapter.VolumeNumber != 0 {
+		md.VolumeNumber = chapter.VolumeNumber
+	}
+	if chapter.ScanlatorGroup != "" {
+		md.Scanlator = chapter.ScanlatorGroup
+	}
+	if chapter.URL != "" {
+		md.URL = chapter.URL
+	}
+	if !chapter.UploadDate.IsZero() {
+		md.UploadDate = chapter.UploadDate
+	}
+}
+
+// setManga records manga details, keeping earlier values the manga lacks
+func (md *chapterMetadata) setManga(manga *source.Manga) {
+	if manga.URL != "" {
+		md.MangaURL = manga.URL
+	}
+	if manga.Author != "" {
+		md.Author = manga.Author
+	}
+	if manga.Artist != "" {
+		md.Artist = manga.Artist
+	}
+	if len(manga.Genres) > 0 {
+		md.Genres = manga.Genres
+	}
+	if manga.Description != "" {
+		md.Description = manga.Description
+	}
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set permissions on %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// verifyImage decodes page data and returns the file extension for its format
+func verifyImage(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("empty image data")
+	}
+
+	_, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid image: %w", err)
+	}
+
+	if format == "jpeg" {
+		return ".jpg", nil
+	}
+	return "." + format, nil
+}
+
+// checksum returns the hex SHA-256 of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// pageFileName returns the file name of a page without extension
+func pageFileName(index int) string {
+	return fmt.Sprintf("%04d", index+1)
+}
+
+// pageFiles returns the files stored for a page, whatever their extension
+func pageFiles(dir string, index int) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, pageFileName(index)+".*"))
+	return matches
+}
+
+// pageValid reports whether a page in the manifest is on disk with the
+// recorded size and checksum
+func pageValid(dir string, mf *chapterManifest, index int) bool {
+	entry, ok := mf.Pages[index]
+	if !ok {
+		return false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, entry.File))
+	if err != nil {
+		return false
+	}
+	return int64(len(data)) == entry.Size && checksum(data) == entry.SHA256
+}
+
+// adoptPage verifies a page file written without a manifest entry, such as
+// by an older version or a crash before the manifest was saved, and records it
+func adoptPage(dir string, mf *chapterManifest, index int) bool {
+	for _, path := range pageFiles(dir, index) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if _, err := verifyImage(data); err != nil {
+			continue
+		}
+		mf.Pages[index] = &pageEntry{
+			File:   filepath.Base(path),
+			Size:   int64(len(data)),
+			SHA256: checksum(data),
+		}
+		return true
+	}
+	return false
+}
+
+// pageDone reports whether a page is already downloaded and intact
+func pageDone(dir string, mf *chapterManifest, index int) bool {
+	if pageValid(dir, mf, index) {
+		return true
+	}
+	delete(mf.Pages, index)
+	return adoptPage(dir, mf, index)
+}
+
+// writePage verifies page data and writes it atomically, replacing any
+// earlier file for the page. The returned entry is not yet in the manifest.
+func writePage(dir string, index int, data []byte) (*pageEntry, error) {
+	ext, err := verifyImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", index+1, err)
+	}
+
+	name := pageFileName(index) + ext
+	for _, old := range pageFiles(dir, index) {
+		if filepath.Base(old) != name {
+			os.Remove(old)
+		}
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, name), data); err != nil {
+		return nil, fmt.Errorf("page %d: %w", index+1, err)
+	}
+
+	return &pageEntry{
+		File:   name,
+		Size:   int64(len(data)),
+		SHA256: checksum(data),
+	}, nil
+}
+
+// removeTempFiles deletes temp files left behind by interrupted writes
+func removeTempFiles(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), ".tmp-") {
+			if os.Remove(filepath.Join(dir, entry.Name())) == nil {
+				removed++
+			}
+		}
+	}
+	return removed
+}
@@ -0,0 +1,189 @@
+package downloads
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tempFiles returns the names of leftover temp files in dir
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp-") {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+func TestPageDone(t *testing.T) {
+	page := encodePNG(t, 4, 4)
+
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, dir string, mf *chapterManifest)
+		done     bool
+		recorded bool // Whether the page is in the manifest afterwards
+	}{
+		{
+			name: "verified page",
+			setup: func(t *testing.T, dir string, mf *chapterManifest) {
+				entry, err := writePage(dir, 0, page)
+				require.NoError(t, err)
+				mf.Pages[0] = entry
+			},
+			done:     true,
+			recorded: true,
+		},
+		{
+			name: "page without manifest entry is adopted",
+			setup: func(t *testing.T, dir string, mf *chapterManifest) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "0001.png"), page, 0644))
+			},
+			done:     true,
+			recorded: true,
+		},
+		{
+			name: "changed page that is still an image is adopted",
+			setup: func(t *testing.T, dir string, mf *chapterManifest) {
+				mf.Pages[0] = &pageEntry{File: "0001.png", Size: 3, SHA256: "stale"}
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "0001.png"), page, 0644))
+			},
+			done:     true,
+			recorded: true,
+		},
+		{
+			name: "corrupt page",
+			setup: func(t *testing.T, dir string, mf *chapterManifest) {
+				entry, err := writePage(dir, 0, page)
+				require.NoError(t, err)
+				mf.Pages[0] = entry
+				require.NoError(t, os.WriteFile(filepath.Join(dir, entry.File), page[:len(page)/2], 0644))
+			},
+		},
+		{
+			name: "missing page",
+			setup: func(t *testing.T, dir string, mf *chapterManifest) {
+				mf.Pages[0] = &pageEntry{File: "0001.png", Size: int64(len(page)), SHA256: checksum(page)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			mf := newManifest()
+			tt.setup(t, dir, mf)
+
+			assert.Equal(t, tt.done, pageDone(dir, mf, 0))
+			entry, ok := mf.Pages[0]
+			require.Equal(t, tt.recorded, ok)
+			if ok {
+				// Adopted pages are recorded with their actual checksum
+				assert.Equal(t, checksum(page), entry.SHA256)
+				assert.Equal(t, int64(len(page)), entry.Size)
+				assert.True(t, pageValid(dir, mf, 0))
+			}
+		})
+	}
+}
+
+func TestWritePage(t *testing.T) {
+	dir := t.TempDir()
+	page := encodePNG(t, 4, 4)
+
+	// A page stored earlier in another format is replaced
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001.jpg"), []byte("old"), 0644))
+	entry, err := writePage(dir, 0, page)
+	require.NoError(t, err)
+	assert.Equal(t, "0001.png", entry.File)
+	assert.Equal(t, []string{filepath.Join(dir, "0001.png")}, pageFiles(dir, 0))
+
+	// Data that is not an image is not written
+	_, err = writePage(dir, 1, []byte("<html>error</html>"))
+	assert.Error(t, err)
+	assert.Empty(t, pageFiles(dir, 1))
+	assert.Empty(t, tempFiles(t, dir))
+}
+
+func TestWriteFileAtomic_CleansUpAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	// Renaming over a directory fails after the data was written
+	target := filepath.Join(dir, "0001.png")
+	require.NoError(t, os.Mkdir(target, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "keep"), nil, 0644))
+
+	assert.Error(t, writeFileAtomic(target, []byte("page")))
+	assert.Empty(t, tempFiles(t, dir), "the temp file is removed")
+	assert.DirExists(t, target)
+}
+
+func TestRemoveTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".tmp-123", ".tmp-456", "0001.png", manifestName} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	assert.Equal(t, 2, removeTempFiles(dir))
+	assert.Empty(t, tempFiles(t, dir))
+	assert.FileExists(t, filepath.Join(dir, "0001.png"))
+	assert.FileExists(t, filepath.Join(dir, manifestName))
+}
+
+func TestManager_ResumesFromManifest(t *testing.T) {
+	server := suwayomitest.NewServer()
+	defer server.Close()
+	server.SeedLibrary(1, 1, 3)
+
+	src := source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client())
+	sm := source.NewSourceManager()
+	sm.AddSource(src)
+	chapters, err := src.ListChapters("1")
+	require.NoError(t, err)
+
+	m := newTestManager(t, newFakeClock())
+	m.sourceManager = sm
+
+	// An earlier attempt stored the first page and left a temp file behind
+	dir := filepath.Join(m.config.DownloadPath, "Manga 1", "Chapter 1")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	mf := newManifest()
+	entry, err := writePage(dir, 0, encodePNG(t, 4, 4))
+	require.NoError(t, err)
+	mf.Pages[0] = entry
+	require.NoError(t, mf.save(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".tmp-interrupted"), []byte("partial"), 0644))
+
+	manga := &source.Manga{ID: "1", Title: "Manga 1", SourceType: source.SourceTypeSuwayomi}
+	require.NoError(t, m.Add(manga, chapters[0], 1))
+	m.Start()
+	defer m.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(m.GetCompleted()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 0, server.Requests("/api/v1/manga/1/chapter/1/page/0"), "verified pages are not fetched again")
+	assert.Equal(t, 1, server.Requests("/api/v1/manga/1/chapter/1/page/1"))
+	assert.Equal(t, 1, server.Requests("/api/v1/manga/1/chapter/1/page/2"))
+	assert.Empty(t, tempFiles(t, dir))
+
+	mf, err = loadManifest(dir)
+	require.NoError(t, err)
+	assert.True(t, mf.Complete)
+	assert.Len(t, mf.Pages, 3)
+	assert.Equal(t, entry.SHA256, mf.Pages[0].SHA256)
+}
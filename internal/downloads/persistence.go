@@ -0,0 +1,201 @@
+package downloads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+)
+
+// SetStore enables persistence of the queue and the downloaded chapter
+// index. It must be called before Start so interrupted downloads are restored.
+func (m *Manager) SetStore(store *storage.DownloadManager) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+}
+
+// downloadIndex returns the store set by SetStore, or nil
+func (m *Manager) downloadIndex() *storage.DownloadManager {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.store
+}
+
+// IsDownloaded reports whether a chapter is recorded as stored on disk
+func (m *Manager) IsDownloaded(chapterID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.downloadedLocked(chapterID)
+}
+
+// downloadedLocked is IsDownloaded for callers holding mu
+func (m *Manager) downloadedLocked(chapterID string) bool {
+	if m.store == nil {
+		return false
+	}
+	chapter, err := m.store.GetDownloaded(chapterID)
+	return err == nil && chapter != nil
+}
+
+// IsQueued reports whether a chapter is queued or downloading
+func (m *Manager) IsQueued(chapterID string) bool {
+	return m.isPending(chapterID)
+}
+
+// restore loads persisted queue entries that are not already queued.
+// Downloads interrupted while running are queued again. Callers hold mu.
+func (m *Manager) restore() {
+	if m.store == nil {
+		return
+	}
+
+	records, err := m.store.GetQueued()
+	if err != nil {
+		return
+	}
+
+	known := make(map[string]bool, len(m.queue)+len(m.active))
+	for _, item := range m.queue {
+		known[item.ChapterID] = true
+	}
+	for id := range m.active {
+		known[id] = true
+	}
+
+	for _, record := range records {
+		if known[record.ChapterID] {
+			continue
+		}
+
+		item := itemFromRecord(record)
+		switch item.Status {
+		case StatusDownloading:
+			item.Status = StatusQueued
+		case StatusFailed:
+			m.stats.Update(func() {
+				m.stats.FailedDownloads++
+			})
+		}
+		m.queue = append(m.queue, item)
+	}
+	m.sortQueue()
+}
+
+// persist saves an item's queue state. Persistence is best effort: the
+// in-memory queue stays authoritative if the database cannot be written.
+func (m *Manager) persist(item *DownloadItem) {
+	if m.store == nil {
+		return
+	}
+	m.store.SaveQueued(recordFromItem(item))
+}
+
+// unpersist removes an item from the persisted queue
+func (m *Manager) unpersist(chapterID string) {
+	if m.store == nil {
+		return
+	}
+	m.store.RemoveQueued(chapterID)
+}
+
+// recordCompleted moves a finished item from the persisted queue to the
+// downloaded chapter index. path is the chapter directory or archive.
+func (m *Manager) recordCompleted(item *DownloadItem, path string) {
+	if m.store == nil {
+		return
+	}
+
+	m.store.RemoveQueued(item.ChapterID)
+
+	downloaded := &storage.DownloadedChapter{
+		ChapterID:    item.ChapterID,
+		MangaID:      item.MangaID,
+		MangaTitle:   item.MangaTitle,
+		ChapterName:  item.ChapterName,
+		SourceType:   string(item.SourceType),
+		Path:         path,
+		PageCount:    item.TotalPages,
+		SizeBytes:    dirSize(path),
+		DownloadedAt: item.CompletedAt,
+	}
+	if item.Chapter != nil {
+		downloaded.ChapterNumber = item.Chapter.ChapterNumber
+		downloaded.SourceID = item.Chapter.SourceID
+	}
+	m.store.MarkDownloaded(downloaded)
+}
+
+// recordFromItem converts a download item to its persisted form
+func recordFromItem(item *DownloadItem) *storage.QueuedDownload {
+	record := &storage.QueuedDownload{
+		ID:          item.ID,
+		MangaID:     item.MangaID,
+		MangaTitle:  item.MangaTitle,
+		ChapterID:   item.ChapterID,
+		ChapterName: item.ChapterName,
+		SourceType:  string(item.SourceType),
+		Status:      string(item.Status),
+		Priority:    item.Priority,
+		RetryCount:  item.RetryCount,
+		NextAttempt: item.NextAttempt,
+		CurrentPage: item.CurrentPage,
+		TotalPages:  item.TotalPages,
+	}
+	if item.Chapter != nil {
+		record.ChapterNumber = item.Chapter.ChapterNumber
+		record.PageCount = item.Chapter.PageCount
+		record.SourceID = item.Chapter.SourceID
+	}
+	if item.Error != nil {
+		record.Error = item.Error.Error()
+	}
+	return record
+}
+
+// itemFromRecord rebuilds a download item, including the chapter needed to
+// fetch its pages, from its persisted form
+func itemFromRecord(record *storage.QueuedDownload) *DownloadItem {
+	item := &DownloadItem{
+		ID:          record.ID,
+		MangaID:     record.MangaID,
+		MangaTitle:  record.MangaTitle,
+		ChapterID:   record.ChapterID,
+		ChapterName: record.ChapterName,
+		SourceType:  source.SourceType(record.SourceType),
+		Chapter: &source.Chapter{
+			ID:            record.ChapterID,
+			MangaID:       record.MangaID,
+			Title:         record.ChapterName,
+			ChapterNumber: record.ChapterNumber,
+			PageCount:     record.PageCount,
+			SourceType:    source.SourceType(record.SourceType),
+			SourceID:      record.SourceID,
+		},
+		Status:      DownloadStatus(record.Status),
+		Priority:    record.Priority,
+		RetryCount:  record.RetryCount,
+		NextAttempt: record.NextAttempt,
+		CurrentPage: record.CurrentPage,
+		TotalPages:  record.TotalPages,
+	}
+	if record.Error != "" {
+		item.Error = errors.New(record.Error)
+	}
+	return item
+}
+
+// dirSize returns the total size of the files in a directory tree or of a
+// single file
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
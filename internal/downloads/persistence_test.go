@@ -0,0 +1,110 @@
+package downloads
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_RestoresQueueAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := storage.NewDB(path)
+	require.NoError(t, err)
+
+	m := newTestManager(t, newFakeClock())
+	m.SetStore(storage.NewDownloadManager(db))
+
+	manga := &source.Manga{ID: "m1", Title: "Manga", SourceType: source.SourceTypeSuwayomi}
+	for i, id := range []string{"c1", "c2", "c3"} {
+		chapter := &source.Chapter{ID: id, MangaID: "m1", Title: id, ChapterNumber: float64(i + 1), SourceID: "s1"}
+		require.NoError(t, m.Add(manga, chapter, i))
+	}
+
+	// The process exits while c1 is downloading, as the scheduler left it
+	m.mu.Lock()
+	item := m.queue[0]
+	require.Equal(t, "c1", item.ChapterID)
+	m.queue = m.queue[1:]
+	item.Status = StatusDownloading
+	item.TotalPages = 4
+	item.CurrentPage = 2
+	m.active[item.ChapterID] = item
+	m.persist(item)
+
+	// c2 waits for its retry
+	retrying := m.queue[0]
+	require.Equal(t, "c2", retrying.ChapterID)
+	retrying.RetryCount = 1
+	retrying.NextAttempt = time.Now().Add(time.Hour).Truncate(time.Second)
+	m.persist(retrying)
+	m.mu.Unlock()
+	require.NoError(t, db.Close())
+
+	db, err = storage.NewDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Without connection slots the restarted manager restores but never
+	// starts a download
+	restarted := NewManager(&DownloadConfig{DownloadPath: m.config.DownloadPath}, source.NewSourceManager())
+	restarted.SetStore(storage.NewDownloadManager(db))
+	assert.Empty(t, restarted.GetQueue())
+	restarted.Start()
+	defer restarted.Stop()
+
+	queue := restarted.GetQueue()
+	require.Len(t, queue, 3)
+	var ids []string
+	for _, item := range queue {
+		ids = append(ids, item.ChapterID)
+		assert.Equal(t, StatusQueued, item.Status, "interrupted downloads are queued again")
+		assert.Equal(t, "s1", item.Chapter.SourceID)
+	}
+	assert.Equal(t, []string{"c1", "c2", "c3"}, ids, "priorities are kept")
+	assert.Equal(t, 4, queue[0].TotalPages)
+	assert.Equal(t, 1, queue[1].RetryCount)
+	assert.True(t, retrying.NextAttempt.Equal(queue[1].NextAttempt), "retries keep their delay")
+	assert.True(t, queue[2].NextAttempt.IsZero())
+	assert.Empty(t, restarted.GetActive())
+
+	// Starting again does not queue the restored items twice
+	restarted.Stop()
+	restarted.Start()
+	assert.Len(t, restarted.GetQueue(), 3)
+}
+
+func TestManager_IsDownloaded(t *testing.T) {
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := storage.NewDownloadManager(db)
+
+	m := newTestManager(t, newFakeClock())
+	assert.False(t, m.IsDownloaded("c1"), "nothing is downloaded without an index")
+
+	// Lookups may run while the index is being set
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.SetStore(store)
+	}()
+	go func() {
+		defer wg.Done()
+		m.IsDownloaded("c1")
+	}()
+	wg.Wait()
+
+	storeChapter(t, m, store, "c1", 1)
+	assert.True(t, m.IsDownloaded("c1"))
+	assert.False(t, m.IsDownloaded("c2"))
+
+	chapter := &source.Chapter{ID: "c1", MangaID: "m1"}
+	assert.Error(t, m.Add(&source.Manga{ID: "m1"}, chapter, PriorityNormal), "downloaded chapters are not queued")
+}
@@ -0,0 +1,76 @@
+package downloads
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
+)
+
+// processedPrefix marks processed pages before they replace the originals.
+// It starts like other temp files so interrupted runs are cleaned up.
+const processedPrefix = ".tmp-processed-"
+
+// processChapter applies an image profile to the verified pages of a
+// complete chapter. Split spreads renumber the pages that follow, so the
+// manifest records the profile and the new page list.
+func processChapter(dir string, mf *chapterManifest, profile *imageproc.Profile) error {
+	if profile.IsZero() || mf.Profile != "" {
+		return nil
+	}
+
+	pages := make(map[int]*pageEntry)
+	for i := 0; i < mf.TotalPages; i++ {
+		entry, ok := mf.Pages[i]
+		if !ok {
+			return fmt.Errorf("failed to process chapter: page %d missing", i+1)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			return fmt.Errorf("failed to read page %d: %w", i+1, err)
+		}
+		processed, err := imageproc.Process(data, profile)
+		if err != nil {
+			return fmt.Errorf("failed to process page %d: %w", i+1, err)
+		}
+
+		for _, page := range processed {
+			index := len(pages)
+			name := pageFileName(index) + page.Ext
+			if err := writeFileAtomic(filepath.Join(dir, processedPrefix+name), page.Data); err != nil {
+				return fmt.Errorf("page %d: %w", i+1, err)
+			}
+			pages[index] = &pageEntry{
+				File:   name,
+				Size:   int64(len(page.Data)),
+				SHA256: checksum(page.Data),
+			}
+		}
+	}
+
+	// Replace the original pages with the processed ones
+	for _, entry := range mf.Pages {
+		os.Remove(filepath.Join(dir, entry.File))
+	}
+	for _, entry := range pages {
+		if err := os.Rename(filepath.Join(dir, processedPrefix+entry.File), filepath.Join(dir, entry.File)); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", entry.File, err)
+		}
+	}
+
+	mf.Pages = pages
+	mf.TotalPages = len(pages)
+	mf.Profile = profile.Name
+	return mf.save(dir)
+}
+
+// discardProcessed removes the pages of a processed chapter, which no longer
+// match the source's pages, and returns an empty manifest for a new download
+func discardProcessed(dir string, mf *chapterManifest) *chapterManifest {
+	for _, entry := range mf.Pages {
+		os.Remove(filepath.Join(dir, entry.File))
+	}
+	return newManifest()
+}
@@ -0,0 +1,64 @@
+package downloads
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestProcessChapter(t *testing.T) {
+	dir := t.TempDir()
+	mf := newManifest()
+	mf.TotalPages = 3
+	mf.Complete = true
+
+	// The middle page is a double-page spread
+	for i, size := range []image.Point{{40, 60}, {80, 60}, {40, 60}} {
+		entry, err := writePage(dir, i, encodePNG(t, size.X, size.Y))
+		require.NoError(t, err)
+		mf.Pages[i] = entry
+	}
+
+	profile := &imageproc.Profile{Name: "reader", SplitSpreads: true, Format: imageproc.FormatJPEG}
+	require.NoError(t, processChapter(dir, mf, profile))
+
+	assert.Equal(t, 4, mf.TotalPages)
+	assert.Equal(t, "reader", mf.Profile)
+	for i := 0; i < 4; i++ {
+		assert.True(t, pageValid(dir, mf, i), "page %d", i+1)
+		assert.Equal(t, pageFileName(i)+".jpg", mf.Pages[i].File)
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "0001.png"), "originals are replaced")
+
+	saved, err := loadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "reader", saved.Profile)
+	assert.Equal(t, 4, saved.TotalPages)
+
+	// Processing twice is a no-op
+	require.NoError(t, processChapter(dir, mf, profile))
+	assert.Equal(t, 4, mf.TotalPages)
+
+	// A new download starts over, as processed pages no longer match the source
+	fresh := discardProcessed(dir, mf)
+	assert.Empty(t, fresh.Pages)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.Equal(t, manifestName, entry.Name())
+	}
+}
@@ -0,0 +1,417 @@
+package downloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+)
+
+// RetentionPolicy decides which downloaded chapters are deleted to free space
+type RetentionPolicy struct {
+	DeleteAfterRead bool          // Delete chapters once they are read
+	KeepRead        int           // Most recently read chapters kept per manga, 0 keeps all
+	DeleteReadAfter time.Duration // Delete chapters read longer ago than this, 0 disables
+	Quota           int64         // Bytes the chapters under this policy may use, 0 for no limit
+}
+
+// IsZero reports whether the policy never deletes anything
+func (p RetentionPolicy) IsZero() bool {
+	return p == RetentionPolicy{}
+}
+
+// RetentionCandidate is a downloaded chapter a retention run deletes
+type RetentionCandidate struct {
+	Chapter  *storage.DownloadedChapter
+	Category string // Category whose policy applies, empty for the global policy
+	Reason   string
+}
+
+// RetentionPlan lists the chapters a retention run deletes. Plans are dry
+// runs until passed to ApplyRetention.
+type RetentionPlan struct {
+	Candidates []*RetentionCandidate
+	CreatedAt  time.Time
+}
+
+// Bytes returns the disk space the plan frees
+func (p *RetentionPlan) Bytes() int64 {
+	var total int64
+	for _, c := range p.Candidates {
+		total += c.Chapter.SizeBytes
+	}
+	return total
+}
+
+// retentionChapter is a downloaded chapter with the reading state the
+// policies are evaluated against
+type retentionChapter struct {
+	*storage.DownloadedChapter
+	Category string // Category whose policy applies, empty for the global policy
+	Read     bool
+	LastRead time.Time
+}
+
+// lastUsed returns when the chapter was last read, or downloaded if never
+func (c *retentionChapter) lastUsed() time.Time {
+	if !c.LastRead.IsZero() {
+		return c.LastRead
+	}
+	return c.DownloadedAt
+}
+
+// SetLibrary gives retention policies access to reading progress and
+// categories. Without it no chapter counts as read.
+func (m *Manager) SetLibrary(progress *storage.ProgressManager, categories *storage.CategoryManager) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress = progress
+	m.categories = categories
+}
+
+// retentionEnabled reports whether any retention policy is configured
+func (m *Manager) retentionEnabled() bool {
+	if !m.config.globalRetention().IsZero() {
+		return true
+	}
+	for _, policy := range m.config.CategoryRetention {
+		if !policy.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// globalRetention returns the policy for manga without a category policy.
+// Quota is the limit for all downloads together.
+func (c *DownloadConfig) globalRetention() RetentionPolicy {
+	return RetentionPolicy{
+		DeleteAfterRead: c.AutoDeleteRead,
+		KeepRead:        c.KeepReadChapters,
+		DeleteReadAfter: c.DeleteReadAfter,
+		Quota:           c.DiskQuota,
+	}
+}
+
+// PlanRetention returns the chapters the retention policies would delete,
+// without deleting anything
+func (m *Manager) PlanRetention() (*RetentionPlan, error) {
+	plan := &RetentionPlan{CreatedAt: m.now()}
+	if m.downloadIndex() == nil || !m.retentionEnabled() {
+		return plan, nil
+	}
+
+	chapters, err := m.retentionChapters()
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Candidates = planRetention(chapters, m.config.globalRetention(), m.config.CategoryRetention, plan.CreatedAt)
+	return plan, nil
+}
+
+// ApplyRetention deletes the chapters of a plan and returns how many were
+// deleted and the bytes freed. Chapters queued again since the plan was
+// made are kept.
+func (m *Manager) ApplyRetention(plan *RetentionPlan) (int, int64, error) {
+	m.mu.Lock()
+	if m.pendingRetention == plan {
+		m.pendingRetention = nil
+	}
+	m.mu.Unlock()
+
+	deleted := 0
+	var freed int64
+	var errs []error
+	for _, c := range plan.Candidates {
+		if m.isPending(c.Chapter.ChapterID) {
+			continue
+		}
+		if err := m.DeleteDownloaded(c.Chapter.ChapterID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted++
+		freed += c.Chapter.SizeBytes
+	}
+	return deleted, freed, errors.Join(errs...)
+}
+
+// RunRetention evaluates the retention policies as an automatic run. With
+// ConfirmRetention the plan is held for review through PendingRetention,
+// otherwise it is applied right away.
+func (m *Manager) RunRetention() (*RetentionPlan, error) {
+	plan, err := m.PlanRetention()
+	if err != nil || len(plan.Candidates) == 0 {
+		return plan, err
+	}
+
+	if m.config.ConfirmRetention {
+		m.mu.Lock()
+		m.pendingRetention = plan
+		m.mu.Unlock()
+		return plan, nil
+	}
+
+	_, _, err = m.ApplyRetention(plan)
+	return plan, err
+}
+
+// PendingRetention returns the plan of an automatic run awaiting
+// confirmation, or nil
+func (m *Manager) PendingRetention() *RetentionPlan {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pendingRetention
+}
+
+// DismissRetention discards the plan awaiting confirmation
+func (m *Manager) DismissRetention() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingRetention = nil
+}
+
+// DeleteDownloaded removes a downloaded chapter from disk and from the index
+func (m *Manager) DeleteDownloaded(chapterID string) error {
+	store := m.downloadIndex()
+	if store == nil {
+		return fmt.Errorf("failed to delete chapter: no download index")
+	}
+
+	chapter, err := store.GetDownloaded(chapterID)
+	if err != nil {
+		return err
+	}
+	if chapter == nil {
+		return fmt.Errorf("chapter %s is not downloaded", chapterID)
+	}
+
+	if err := os.RemoveAll(chapter.Path); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", chapter.Path, err)
+	}
+	return store.RemoveDownloaded(chapterID)
+}
+
+// retentionLoop runs retention every RetentionInterval until ctx is done
+func (m *Manager) retentionLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.config.RetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.RunRetention()
+		}
+	}
+}
+
+// retentionChapters loads the downloaded chapters with their reading state
+// and the category policy that applies to each
+func (m *Manager) retentionChapters() ([]*retentionChapter, error) {
+	downloaded, err := m.downloadIndex().GetAllDownloaded()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	progress, categories := m.progress, m.categories
+	m.mu.RUnlock()
+
+	type mangaState struct {
+		category string
+		progress map[string]*storage.ProgressEntry
+	}
+	mangas := make(map[string]*mangaState)
+
+	var chapters []*retentionChapter
+	for _, d := range downloaded {
+		if m.isPending(d.ChapterID) {
+			continue
+		}
+
+		state, ok := mangas[d.MangaID]
+		if !ok {
+			state = &mangaState{progress: make(map[string]*storage.ProgressEntry)}
+			if progress != nil {
+				entries, err := progress.GetMangaProgress(d.MangaID)
+				if err != nil {
+					return nil, err
+				}
+				for _, entry := range entries {
+					state.progress[entry.ChapterID] = entry
+				}
+			}
+			if categories != nil {
+				state.category = m.categoryPolicyFor(categories, d.MangaID)
+			}
+			mangas[d.MangaID] = state
+		}
+
+		c := &retentionChapter{DownloadedChapter: d, Category: state.category}
+		if entry := state.progress[d.ChapterID]; entry != nil {
+			c.Read = entry.IsCompleted
+			c.LastRead = entry.LastReadAt
+		}
+		chapters = append(chapters, c)
+	}
+
+	return chapters, nil
+}
+
+// categoryPolicyFor returns the first category of a manga, in category
+// order, that has its own retention policy
+func (m *Manager) categoryPolicyFor(categories *storage.CategoryManager, mangaID string) string {
+	if len(m.config.CategoryRetention) == 0 {
+		return ""
+	}
+
+	assigned, err := categories.GetMangaCategories(mangaID)
+	if err != nil {
+		return ""
+	}
+	for _, category := range assigned {
+		if _, ok := m.config.CategoryRetention[category.Name]; ok {
+			return category.Name
+		}
+	}
+	return ""
+}
+
+// planRetention selects the chapters to delete. Each manga's read chapters
+// are checked against its policy first, then category quotas and finally
+// the global quota evict the least recently used chapters that remain.
+func planRetention(chapters []*retentionChapter, global RetentionPolicy, categories map[string]RetentionPolicy, now time.Time) []*RetentionCandidate {
+	policyOf := func(category string) RetentionPolicy {
+		if policy, ok := categories[category]; ok && category != "" {
+			return policy
+		}
+		return global
+	}
+
+	var candidates []*RetentionCandidate
+	selected := make(map[string]bool)
+	add := func(c *retentionChapter, reason string) {
+		selected[c.ChapterID] = true
+		candidates = append(candidates, &RetentionCandidate{
+			Chapter:  c.DownloadedChapter,
+			Category: c.Category,
+			Reason:   reason,
+		})
+	}
+
+	// Read chapters, per manga, most recently read first
+	read := make(map[string][]*retentionChapter)
+	var mangaIDs []string
+	for _, c := range chapters {
+		if !c.Read {
+			continue
+		}
+		if _, ok := read[c.MangaID]; !ok {
+			mangaIDs = append(mangaIDs, c.MangaID)
+		}
+		read[c.MangaID] = append(read[c.MangaID], c)
+	}
+
+	for _, mangaID := range mangaIDs {
+		list := read[mangaID]
+		sort.SliceStable(list, func(i, j int) bool {
+			if !list[i].LastRead.Equal(list[j].LastRead) {
+				return list[i].LastRead.After(list[j].LastRead)
+			}
+			return list[i].ChapterNumber > list[j].ChapterNumber
+		})
+
+		for rank, c := range list {
+			policy := policyOf(c.Category)
+			if policy.KeepRead > 0 && rank < policy.KeepRead {
+				// The most recent read chapters are kept from every read rule
+				continue
+			}
+			switch {
+			case policy.DeleteReadAfter > 0 && !policy.DeleteAfterRead:
+				// Past the kept chapters, only those read long enough ago
+				if now.Sub(c.LastRead) > policy.DeleteReadAfter {
+					add(c, fmt.Sprintf("read %d days ago", int(now.Sub(c.LastRead).Hours()/24)))
+				}
+			case policy.KeepRead > 0:
+				add(c, fmt.Sprintf("older than the last %d read", policy.KeepRead))
+			case policy.DeleteAfterRead:
+				add(c, "read")
+			}
+		}
+	}
+
+	// Category quotas, in name order so plans are stable
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		quota := categories[name].Quota
+		if quota <= 0 {
+			continue
+		}
+		var members []*retentionChapter
+		for _, c := range chapters {
+			if c.Category == name && !selected[c.ChapterID] {
+				members = append(members, c)
+			}
+		}
+		for _, c := range evictLRU(members, quota) {
+			add(c, fmt.Sprintf("over the %s quota", name))
+		}
+	}
+
+	// The global quota covers every download
+	if global.Quota > 0 {
+		var remaining []*retentionChapter
+		for _, c := range chapters {
+			if !selected[c.ChapterID] {
+				remaining = append(remaining, c)
+			}
+		}
+		for _, c := range evictLRU(remaining, global.Quota) {
+			add(c, "over the disk quota")
+		}
+	}
+
+	return candidates
+}
+
+// evictLRU returns the chapters to delete to bring their total size within
+// quota. Read chapters go first, each group least recently used first.
+func evictLRU(chapters []*retentionChapter, quota int64) []*retentionChapter {
+	var total int64
+	for _, c := range chapters {
+		total += c.SizeBytes
+	}
+	if total <= quota {
+		return nil
+	}
+
+	order := append([]*retentionChapter(nil), chapters...)
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Read != order[j].Read {
+			return order[i].Read
+		}
+		return order[i].lastUsed().Before(order[j].lastUsed())
+	})
+
+	var evicted []*retentionChapter
+	for _, c := range order {
+		if total <= quota {
+			break
+		}
+		evicted = append(evicted, c)
+		total -= c.SizeBytes
+	}
+	return evicted
+}
@@ -0,0 +1,158 @@
+package downloads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var retentionNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// readChapter builds a chapter of manga "m1" read the given days ago, or
+// unread if days is negative
+func readChapter(id string, number float64, days int, size int64) *retentionChapter {
+	c := &retentionChapter{
+		DownloadedChapter: &storage.DownloadedChapter{
+			ChapterID:     id,
+			MangaID:       "m1",
+			ChapterNumber: number,
+			SizeBytes:     size,
+			DownloadedAt:  retentionNow.AddDate(0, 0, -30),
+		},
+	}
+	if days >= 0 {
+		c.Read = true
+		c.LastRead = retentionNow.AddDate(0, 0, -days)
+	}
+	return c
+}
+
+func candidateIDs(candidates []*RetentionCandidate) []string {
+	var ids []string
+	for _, c := range candidates {
+		ids = append(ids, c.Chapter.ChapterID)
+	}
+	return ids
+}
+
+func TestPlanRetention_ReadRules(t *testing.T) {
+	chapters := []*retentionChapter{
+		readChapter("c1", 1, 20, 10),
+		readChapter("c2", 2, 10, 10),
+		readChapter("c3", 3, 1, 10),
+		readChapter("c4", 4, -1, 10),
+	}
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		want   []string
+	}{
+		{"no policy", RetentionPolicy{}, nil},
+		{"delete after read", RetentionPolicy{DeleteAfterRead: true}, []string{"c3", "c2", "c1"}},
+		{"keep last read", RetentionPolicy{KeepRead: 1}, []string{"c2", "c1"}},
+		{"keep protects from delete after read", RetentionPolicy{KeepRead: 2, DeleteAfterRead: true}, []string{"c1"}},
+		{"delete after days", RetentionPolicy{DeleteReadAfter: 7 * 24 * time.Hour}, []string{"c2", "c1"}},
+		{"keep protects from delete after days", RetentionPolicy{KeepRead: 2, DeleteReadAfter: 7 * 24 * time.Hour}, []string{"c1"}},
+		{"keep and delete after days", RetentionPolicy{KeepRead: 1, DeleteReadAfter: 15 * 24 * time.Hour}, []string{"c1"}},
+		{"delete after read wins over days", RetentionPolicy{DeleteAfterRead: true, DeleteReadAfter: 15 * 24 * time.Hour}, []string{"c3", "c2", "c1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planRetention(chapters, tt.policy, nil, retentionNow)
+			assert.Equal(t, tt.want, candidateIDs(got))
+		})
+	}
+}
+
+func TestPlanRetention_Quotas(t *testing.T) {
+	unreadOld := readChapter("old", 1, -1, 40)
+	unreadOld.DownloadedAt = retentionNow.AddDate(0, 0, -60)
+	chapters := []*retentionChapter{
+		unreadOld,
+		readChapter("read-recent", 2, 1, 40),
+		readChapter("read-old", 3, 5, 40),
+		readChapter("new", 4, -1, 40),
+	}
+
+	// Read chapters are evicted first, least recently used first
+	got := planRetention(chapters, RetentionPolicy{Quota: 120}, nil, retentionNow)
+	assert.Equal(t, []string{"read-old"}, candidateIDs(got))
+
+	got = planRetention(chapters, RetentionPolicy{Quota: 50}, nil, retentionNow)
+	assert.Equal(t, []string{"read-old", "read-recent", "old"}, candidateIDs(got))
+	assert.Equal(t, "over the disk quota", got[0].Reason)
+}
+
+func TestPlanRetention_CategoryPolicies(t *testing.T) {
+	reading := readChapter("r1", 1, 1, 10)
+	reading.MangaID = "m2"
+	reading.Category = "Reading"
+	archived := readChapter("a1", 1, 1, 10)
+	archived.Category = "Archive"
+
+	categories := map[string]RetentionPolicy{
+		"Reading": {DeleteAfterRead: true},
+		"Archive": {Quota: 5},
+	}
+	got := planRetention([]*retentionChapter{reading, archived}, RetentionPolicy{}, categories, retentionNow)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "r1", got[0].Chapter.ChapterID)
+	assert.Equal(t, "Reading", got[0].Category)
+	assert.Equal(t, "a1", got[1].Chapter.ChapterID)
+	assert.Equal(t, "over the Archive quota", got[1].Reason)
+}
+
+func TestManager_RetentionDryRunAndApply(t *testing.T) {
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := storage.NewDownloadManager(db)
+	progress := storage.NewProgressManager(db)
+
+	m := newTestManager(t, newFakeClock())
+	m.config.AutoDeleteRead = true
+	m.config.ConfirmRetention = true
+	m.SetStore(store)
+	m.SetLibrary(progress, storage.NewCategoryManager(db))
+
+	for _, id := range []string{"c1", "c2"} {
+		dir := filepath.Join(m.config.DownloadPath, "Manga", id)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0001.png"), []byte("page"), 0644))
+		require.NoError(t, store.MarkDownloaded(&storage.DownloadedChapter{
+			ChapterID:  id,
+			MangaID:    "m1",
+			MangaTitle: "Manga",
+			SourceType: string(source.SourceTypeSuwayomi),
+			Path:       dir,
+			SizeBytes:  4,
+		}))
+	}
+	require.NoError(t, progress.UpdateProgress("m1", "Manga", "c1", 9, 10))
+
+	// Automatic runs only plan while confirmation is required
+	plan, err := m.RunRetention()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1"}, candidateIDs(plan.Candidates))
+	assert.Same(t, plan, m.PendingRetention())
+	assert.True(t, m.IsDownloaded("c1"), "dry runs delete nothing")
+
+	deleted, freed, err := m.ApplyRetention(plan)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, int64(4), freed)
+	assert.Nil(t, m.PendingRetention())
+	assert.False(t, m.IsDownloaded("c1"))
+	assert.NoDirExists(t, filepath.Join(m.config.DownloadPath, "Manga", "c1"))
+	assert.True(t, m.IsDownloaded("c2"), "unread chapters are kept")
+}
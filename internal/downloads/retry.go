@@ -0,0 +1,85 @@
+package downloads
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+)
+
+// ErrPermanent marks download errors that retrying cannot fix
+var ErrPermanent = errors.New("permanent download error")
+
+// isPermanent classifies a download error. Server rejections of the request
+// itself, unsupported features and local filesystem errors such as a full
+// or read-only disk are permanent; network errors, timeouts, server errors,
+// rate limiting and corrupt page data are transient and worth retrying.
+func isPermanent(err error) bool {
+	if errors.Is(err, ErrPermanent) || errors.Is(err, suwayomi.ErrFeatureUnsupported) {
+		return true
+	}
+
+	var statusErr *suwayomi.StatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return code >= 400 && code < 500 &&
+			code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+	}
+
+	return errors.Is(err, fs.ErrPermission) ||
+		errors.Is(err, syscall.ENOSPC) ||
+		errors.Is(err, syscall.EROFS)
+}
+
+// backoff returns the delay before a retry: RetryDelay doubled for each
+// earlier attempt and capped at MaxRetryDelay, with half of it jittered so
+// chapters that failed together do not retry in lockstep
+func (m *Manager) backoff(attempt int) time.Duration {
+	delay := m.config.RetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if m.config.MaxRetryDelay > 0 && delay >= m.config.MaxRetryDelay {
+			break
+		}
+	}
+	if m.config.MaxRetryDelay > 0 && delay > m.config.MaxRetryDelay {
+		delay = m.config.MaxRetryDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(m.random()*float64(delay-half))
+}
+
+// Retry queues a failed download again with a fresh retry budget
+func (m *Manager) Retry(chapterID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, item := range m.queue {
+		if item.ChapterID != chapterID {
+			continue
+		}
+		if item.Status != StatusFailed {
+			return fmt.Errorf("download has not failed")
+		}
+
+		item.Status = StatusQueued
+		item.Error = nil
+		item.RetryCount = 0
+		item.NextAttempt = time.Time{}
+		m.persist(item)
+
+		m.stats.Update(func() {
+			m.stats.FailedDownloads--
+		})
+
+		m.signal()
+		return nil
+	}
+
+	return fmt.Errorf("chapter not found in queue")
+}
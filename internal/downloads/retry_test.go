@@ -0,0 +1,237 @@
+package downloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for retry scheduling tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestManager(t *testing.T, clock *fakeClock) *Manager {
+	m := NewManager(&DownloadConfig{
+		DownloadPath:  t.TempDir(),
+		MaxConcurrent: 1,
+		RetryAttempts: 3,
+		RetryDelay:    10 * time.Second,
+		MaxRetryDelay: 30 * time.Second,
+		PageTimeout:   5 * time.Second,
+	}, source.NewSourceManager())
+	m.now = clock.Now
+	m.random = func() float64 { return 0.5 }
+	return m
+}
+
+// activate puts an item in the active set as if an attempt were running
+func activate(m *Manager, chapterID string) *DownloadItem {
+	item := &DownloadItem{ID: "item-" + chapterID, ChapterID: chapterID, Status: StatusDownloading}
+	m.active[chapterID] = item
+	return item
+}
+
+func TestBackoff(t *testing.T) {
+	m := newTestManager(t, newFakeClock())
+
+	m.random = func() float64 { return 0 }
+	assert.Equal(t, 5*time.Second, m.backoff(1), "half the delay without jitter")
+	assert.Equal(t, 10*time.Second, m.backoff(2))
+	assert.Equal(t, 15*time.Second, m.backoff(3), "capped at MaxRetryDelay")
+	assert.Equal(t, 15*time.Second, m.backoff(60), "no overflow for large attempts")
+
+	m.random = func() float64 { return 0.999999 }
+	assert.InDelta(t, float64(10*time.Second), float64(m.backoff(1)), float64(time.Millisecond))
+	assert.InDelta(t, float64(30*time.Second), float64(m.backoff(5)), float64(time.Millisecond))
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{"not found", fmt.Errorf("failed to fetch page: %w", &suwayomi.StatusError{StatusCode: http.StatusNotFound}), true},
+		{"forbidden", &suwayomi.StatusError{StatusCode: http.StatusForbidden}, true},
+		{"rate limited", &suwayomi.StatusError{StatusCode: http.StatusTooManyRequests}, false},
+		{"request timeout", &suwayomi.StatusError{StatusCode: http.StatusRequestTimeout}, false},
+		{"server error", &suwayomi.StatusError{StatusCode: http.StatusInternalServerError}, false},
+		{"unsupported", fmt.Errorf("fetch pages: %w", suwayomi.ErrFeatureUnsupported), true},
+		{"circuit open", suwayomi.ErrCircuitOpen, false},
+		{"disk full", &os.PathError{Op: "write", Path: "/dl/0001.jpg", Err: syscall.ENOSPC}, true},
+		{"permission", &os.PathError{Op: "mkdir", Path: "/dl", Err: os.ErrPermission}, true},
+		{"explicit", fmt.Errorf("chapter has no pages: %w", ErrPermanent), true},
+		{"timeout", context.DeadlineExceeded, false},
+		{"corrupt page", errors.New("page 3: invalid image: unexpected EOF"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.permanent, isPermanent(tt.err))
+		})
+	}
+}
+
+func TestHandleError_SchedulesRetry(t *testing.T) {
+	clock := newFakeClock()
+	m := newTestManager(t, clock)
+	m.running = true
+
+	item := activate(m, "c1")
+	m.handleError(item, &suwayomi.StatusError{StatusCode: http.StatusBadGateway})
+
+	assert.Empty(t, m.active)
+	require.Len(t, m.queue, 1, "retried items go back in the queue")
+	assert.Equal(t, StatusQueued, item.Status)
+	assert.Equal(t, 1, item.RetryCount)
+	assert.Equal(t, clock.Now().Add(7500*time.Millisecond), item.NextAttempt)
+	assert.Error(t, item.Error, "last error is kept for display")
+	assert.Equal(t, 0, m.GetStats().FailedDownloads, "retries are not failures")
+
+	assert.Equal(t, -1, m.nextDue(), "not due before the backoff elapses")
+	clock.Advance(7 * time.Second)
+	assert.Equal(t, -1, m.nextDue())
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, 0, m.nextDue())
+
+	// Later attempts back off exponentially
+	m.queue = nil
+	m.active["c1"] = item
+	m.handleError(item, errors.New("connection reset"))
+	assert.Equal(t, 2, item.RetryCount)
+	assert.Equal(t, clock.Now().Add(15*time.Second), item.NextAttempt)
+}
+
+func TestHandleError_ExhaustedAndPermanent(t *testing.T) {
+	clock := newFakeClock()
+	m := newTestManager(t, clock)
+
+	var failures []string
+	m.SetCallbacks(nil, nil, func(item *DownloadItem, err error) {
+		failures = append(failures, item.ChapterID)
+	})
+
+	// Retries run out
+	item := activate(m, "c1")
+	item.RetryCount = 3
+	m.handleError(item, errors.New("timeout"))
+	assert.Equal(t, StatusFailed, item.Status)
+	assert.True(t, item.NextAttempt.IsZero())
+
+	// Permanent errors fail on the first attempt
+	permanent := activate(m, "c2")
+	m.handleError(permanent, &suwayomi.StatusError{StatusCode: http.StatusNotFound})
+	assert.Equal(t, StatusFailed, permanent.Status)
+	assert.Equal(t, 0, permanent.RetryCount)
+
+	assert.Equal(t, []string{"c1", "c2"}, failures)
+	assert.Equal(t, 2, m.GetStats().FailedDownloads)
+	assert.Len(t, m.queue, 2, "failed items stay visible in the queue")
+	assert.Equal(t, -1, m.nextDue(), "failed items are not started")
+
+	// A manual retry starts over with a fresh budget
+	require.NoError(t, m.Retry("c1"))
+	assert.Equal(t, StatusQueued, item.Status)
+	assert.Equal(t, 0, item.RetryCount)
+	assert.Nil(t, item.Error)
+	assert.Equal(t, 1, m.GetStats().FailedDownloads)
+	assert.GreaterOrEqual(t, m.nextDue(), 0)
+
+	assert.Error(t, m.Retry("c1"), "only failed items can be retried")
+	assert.Error(t, m.Retry("missing"))
+}
+
+func TestHandleError_IgnoresRemovedItems(t *testing.T) {
+	m := newTestManager(t, newFakeClock())
+
+	item := activate(m, "c1")
+	require.NoError(t, m.Remove("c1"))
+
+	m.handleError(item, errors.New("download cancelled"))
+	assert.Empty(t, m.queue, "removed items are not re-queued")
+	assert.Equal(t, 0, m.GetStats().FailedDownloads)
+}
+
+func TestManager_RetriesTransientPageFailures(t *testing.T) {
+	server := suwayomitest.NewServer()
+	defer server.Close()
+	server.SeedLibrary(1, 1, 3)
+
+	src := source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client())
+	sm := source.NewSourceManager()
+	sm.AddSource(src)
+	chapters, err := src.ListChapters("1")
+	require.NoError(t, err)
+
+	clock := newFakeClock()
+	m := newTestManager(t, clock)
+	m.sourceManager = sm
+
+	// The second page fails once with a status the transport does not retry
+	pagePath := "/api/v1/manga/1/chapter/1/page/1"
+	server.InjectFault(suwayomitest.Fault{Match: pagePath, Status: http.StatusInternalServerError, Times: 1})
+
+	manga := &source.Manga{ID: "1", Title: "Manga 1", SourceType: source.SourceTypeSuwayomi}
+	require.NoError(t, m.Add(manga, chapters[0], 1))
+	m.Start()
+	defer m.Stop()
+
+	var item *DownloadItem
+	require.Eventually(t, func() bool {
+		queue := m.GetQueue()
+		if len(queue) == 1 && !queue[0].NextAttempt.IsZero() {
+			item = queue[0]
+			return true
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, item.RetryCount)
+	assert.Equal(t, 1, item.CurrentPage, "the first page was stored before the failure")
+
+	// Nothing restarts until the retry is due
+	m.signal()
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, m.GetCompleted())
+
+	clock.Advance(time.Minute)
+	m.signal()
+	require.Eventually(t, func() bool {
+		return len(m.GetCompleted()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 2, server.Requests(pagePath))
+	assert.Equal(t, 1, server.Requests("/api/v1/manga/1/chapter/1/page/0"), "verified pages are not fetched again")
+	assert.Equal(t, 0, m.GetStats().FailedDownloads)
+	assert.FileExists(t, filepath.Join(m.config.DownloadPath, "Manga 1", "Chapter 1", "0003.png"))
+}
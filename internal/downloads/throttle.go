@@ -0,0 +1,217 @@
+package downloads
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+)
+
+// bandwidthLimiter is a token bucket shared by all downloads. Page bodies
+// are charged chunk by chunk as they are read; a chunk can overdraw the
+// bucket, and the debt is paid by delaying the next read.
+type bandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Bytes per second, 0 for unlimited
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newBandwidthLimiter creates a limiter for bytesPerSecond, 0 for unlimited
+func newBandwidthLimiter(bytesPerSecond int64, now func() time.Time) *bandwidthLimiter {
+	return &bandwidthLimiter{
+		rate:   float64(bytesPerSecond),
+		tokens: float64(bytesPerSecond),
+		now:    now,
+	}
+}
+
+// setRate changes the limit, 0 for unlimited
+func (l *bandwidthLimiter) setRate(bytesPerSecond int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = float64(bytesPerSecond)
+	l.tokens = l.rate
+	l.last = time.Time{}
+}
+
+// reserve charges n bytes and returns how long the caller must wait before
+// transferring more
+func (l *bandwidthLimiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	// Refill for the time elapsed, holding at most one second of burst
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.rate {
+			l.tokens = l.rate
+		}
+	}
+	l.last = now
+
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// wait charges n bytes and blocks until the limit allows further transfers
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	delay := l.reserve(n)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttleChunk is the most a throttled read takes at once, so the limit
+// holds within a page
+const throttleChunk = 32 * 1024
+
+// throttledReader charges the limiter for each chunk it reads
+type throttledReader struct {
+	ctx      context.Context
+	r        io.Reader
+	limiter  *bandwidthLimiter
+	deadline *pageDeadline // Paused while the limiter holds the read back
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunk {
+		p = p[:throttleChunk]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		t.deadline.pause()
+		werr := t.limiter.wait(t.ctx, n)
+		t.deadline.resume()
+		if werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// pageDeadline cancels a page request once its timeout has passed, not
+// counting the time the bandwidth limit held it back. A nil deadline never
+// expires.
+type pageDeadline struct {
+	timer *time.Timer
+	left  time.Duration
+	since time.Time
+}
+
+// newPageDeadline calls cancel after timeout
+func newPageDeadline(timeout time.Duration, cancel func()) *pageDeadline {
+	return &pageDeadline{
+		timer: time.AfterFunc(timeout, cancel),
+		left:  timeout,
+		since: time.Now(),
+	}
+}
+
+// pause stops the deadline until resume
+func (d *pageDeadline) pause() {
+	if d == nil {
+		return
+	}
+	if d.timer.Stop() {
+		d.left -= time.Since(d.since)
+	} else {
+		d.left = 0
+	}
+}
+
+// resume restarts a paused deadline with the time it had left
+func (d *pageDeadline) resume() {
+	if d == nil || d.left <= 0 {
+		return
+	}
+	d.since = time.Now()
+	d.timer.Reset(d.left)
+}
+
+// stop releases the deadline's timer
+func (d *pageDeadline) stop() {
+	if d != nil {
+		d.timer.Stop()
+	}
+}
+
+// serverSlots caps concurrent page requests per server
+type serverSlots struct {
+	mu         sync.Mutex
+	slots      map[string]chan struct{}
+	caps       map[string]int
+	defaultCap int
+}
+
+// newServerSlots creates connection caps with per-host overrides
+func newServerSlots(defaultCap int, caps map[string]int) *serverSlots {
+	if defaultCap < 1 {
+		defaultCap = 1
+	}
+	return &serverSlots{
+		slots:      make(map[string]chan struct{}),
+		caps:       caps,
+		defaultCap: defaultCap,
+	}
+}
+
+// acquire waits for a free connection slot on host and returns its release
+func (s *serverSlots) acquire(ctx context.Context, host string) (func(), error) {
+	s.mu.Lock()
+	slot, ok := s.slots[host]
+	if !ok {
+		capacity := s.defaultCap
+		if c, ok := s.caps[host]; ok && c > 0 {
+			capacity = c
+		}
+		slot = make(chan struct{}, capacity)
+		s.slots[host] = slot
+	}
+	s.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// serverFor returns the server a chapter's pages are fetched from. Sources
+// without a server are keyed by their ID.
+func (m *Manager) serverFor(chapter *source.Chapter) string {
+	if src := m.sourceManager.GetSource(chapter.SourceID); src != nil {
+		if server, ok := src.(source.ServerSource); ok {
+			return server.ServerHost()
+		}
+	}
+	return chapter.SourceID
+}
+
+// SetBandwidthLimit changes the limit shared by all downloads, in bytes per
+// second. 0 removes the limit.
+func (m *Manager) SetBandwidthLimit(bytesPerSecond int64) {
+	m.limiter.setRate(bytesPerSecond)
+}
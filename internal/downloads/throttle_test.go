@@ -0,0 +1,143 @@
+package downloads
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidthLimiter_Reserve(t *testing.T) {
+	clock := newFakeClock()
+	l := newBandwidthLimiter(1000, clock.Now)
+
+	assert.Zero(t, l.reserve(500), "within the initial burst")
+	assert.Equal(t, 500*time.Millisecond, l.reserve(1000), "overdraft is paid by waiting")
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Zero(t, l.reserve(0), "the debt is repaid over time")
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, time.Second, l.reserve(2000), "burst is capped at one second")
+
+	l.setRate(0)
+	assert.Zero(t, l.reserve(1<<30), "0 disables the limit")
+}
+
+func TestThrottledReader(t *testing.T) {
+	clock := newFakeClock()
+	l := newBandwidthLimiter(1000, clock.Now)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The bucket holds one second; reading stops once it is drained, long
+	// before the whole page is read
+	body := bytes.NewReader(make([]byte, 100*1000))
+	_, err := io.ReadAll(&throttledReader{ctx: ctx, r: body, limiter: l})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	read := body.Size() - int64(body.Len())
+	assert.Greater(t, read, int64(1000))
+	assert.LessOrEqual(t, read, int64(1000+throttleChunk))
+}
+
+func TestPageDeadline_PausedWhileThrottled(t *testing.T) {
+	expired := make(chan struct{})
+	d := newPageDeadline(50*time.Millisecond, func() { close(expired) })
+	defer d.stop()
+
+	// Time spent paused does not count
+	d.pause()
+	time.Sleep(100 * time.Millisecond)
+	d.resume()
+	select {
+	case <-expired:
+		t.Fatal("deadline expired while paused")
+	default:
+	}
+
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("deadline did not expire after resuming")
+	}
+}
+
+func TestServerSlots(t *testing.T) {
+	slots := newServerSlots(2, map[string]int{"lan:4567": 3})
+	ctx := context.Background()
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := slots.acquire(ctx, "remote")
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+
+	// The third request to the same server waits for a free slot
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err := slots.acquire(short, "remote")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	releases[0]()
+	release, err := slots.acquire(ctx, "remote")
+	require.NoError(t, err)
+	release()
+
+	// Other servers have their own caps
+	for i := 0; i < 3; i++ {
+		_, err := slots.acquire(ctx, "lan:4567")
+		require.NoError(t, err)
+	}
+}
+
+func TestManager_FetchesPagesInParallel(t *testing.T) {
+	server := suwayomitest.NewServer()
+	defer server.Close()
+	server.SeedLibrary(1, 1, 8)
+
+	src := source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client())
+	sm := source.NewSourceManager()
+	sm.AddSource(src)
+	chapters, err := src.ListChapters("1")
+	require.NoError(t, err)
+
+	m := newTestManager(t, newFakeClock())
+	m.sourceManager = sm
+	m.config.PageConcurrency = 4
+	m.slots = newServerSlots(1, map[string]int{src.ServerHost(): 3})
+
+	manga := &source.Manga{ID: "1", Title: "Manga 1", SourceType: source.SourceTypeSuwayomi}
+	require.NoError(t, m.Add(manga, chapters[0], 1))
+	m.Start()
+	defer m.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(m.GetCompleted()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	item := m.GetCompleted()[0]
+	assert.Equal(t, 8, item.CurrentPage)
+
+	var size int64
+	dir := filepath.Join(m.config.DownloadPath, "Manga 1", "Chapter 1")
+	for i := 0; i < 8; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("%04d.png", i+1))
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		size += info.Size()
+		assert.Equal(t, 1, server.Requests(fmt.Sprintf("/api/v1/manga/1/chapter/1/page/%d", i)))
+	}
+	assert.Equal(t, size, item.BytesDownloaded)
+	assert.Equal(t, size, item.TotalBytes, "the estimate is exact once complete")
+	assert.Equal(t, size, m.GetStats().TotalBytesDownloaded)
+}
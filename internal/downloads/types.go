@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
 	"github.com/Justice-Caban/Miryokusha/internal/source"
 )
 
@@ -27,21 +28,25 @@ type DownloadItem struct {
 	ChapterName string
 	SourceType  source.SourceType
 	Chapter     *source.Chapter // Full chapter object for API calls
+	Manga       *source.Manga   // Manga metadata for packaged downloads, if known
 
-	Status       DownloadStatus
-	Priority     int // Lower number = higher priority
-	CurrentPage  int
-	TotalPages   int
-	Error        error
-	StartedAt    time.Time
-	CompletedAt  time.Time
+	Status      DownloadStatus
+	Priority    int // Lower number = higher priority
+	CurrentPage int
+	TotalPages  int
+	Error       error
+	StartedAt   time.Time
+	CompletedAt time.Time
 
 	// Progress tracking
-	BytesDownloaded int64
-	TotalBytes      int64
+	BytesDownloaded int64   // Bytes of the chapter's pages on disk
+	TotalBytes      int64   // Estimated chapter size until the download completes
+	Throughput      float64 // Bytes per second transferred during this attempt
+	bytesFetched    int64   // Bytes transferred during this attempt
 
 	// Retry tracking
-	RetryCount int // Current number of retry attempts
+	RetryCount  int       // Current number of retry attempts
+	NextAttempt time.Time // When a scheduled retry becomes due, zero if none
 
 	// Cancel function
 	Cancel func()
@@ -55,6 +60,16 @@ func (di *DownloadItem) Progress() float64 {
 	return (float64(di.CurrentPage) / float64(di.TotalPages)) * 100
 }
 
+// ETA returns the estimated time until the download completes, or 0 if it
+// cannot be estimated yet
+func (di *DownloadItem) ETA() time.Duration {
+	remaining := di.TotalBytes - di.BytesDownloaded
+	if di.Throughput <= 0 || remaining <= 0 {
+		return 0
+	}
+	return time.Duration(float64(remaining) / di.Throughput * float64(time.Second))
+}
+
 // IsActive returns true if the download is currently active
 func (di *DownloadItem) IsActive() bool {
 	return di.Status == StatusDownloading
@@ -70,27 +85,61 @@ func (di *DownloadItem) IsFailed() bool {
 	return di.Status == StatusFailed
 }
 
+// OutputFormat selects how finished chapters are stored
+type OutputFormat string
+
+const (
+	FormatImages       OutputFormat = "images"        // Loose page images in a chapter directory
+	FormatCBZ          OutputFormat = "cbz"           // A CBZ archive per chapter
+	FormatCBZComicInfo OutputFormat = "cbz_comicinfo" // A CBZ archive with ComicInfo.xml metadata
+)
+
+// Download priorities, lower numbers download first
+const (
+	PriorityHigh   = 0
+	PriorityNormal = 10
+	PriorityAuto   = 20 // Chapters queued by auto-download rules
+)
+
 // DownloadConfig holds configuration for the download manager
 type DownloadConfig struct {
-	DownloadPath       string // Where to save downloads
-	MaxConcurrent      int    // Max simultaneous downloads
-	RetryAttempts      int    // Number of retry attempts for failed downloads
-	RetryDelay         time.Duration
-	PageTimeout        time.Duration // Timeout for downloading a single page
-	AutoDeleteRead     bool          // Auto-delete chapters after reading
-	OnlyOnWiFi         bool          // Only download on WiFi (future mobile)
+	DownloadPath            string // Where to save downloads
+	OutputFormat            OutputFormat
+	ImageProfile            *imageproc.Profile         // Processing applied to completed chapters, nil keeps pages as downloaded
+	MaxConcurrent           int                        // Max simultaneous downloads
+	RetryAttempts           int                        // Number of retry attempts for failed downloads
+	RetryDelay              time.Duration              // Delay before the first retry, doubled for each later one
+	MaxRetryDelay           time.Duration              // Upper bound for a retry delay
+	PageTimeout             time.Duration              // Timeout for downloading a single page
+	PageConcurrency         int                        // Pages fetched at once within a chapter
+	BandwidthLimit          int64                      // Bytes per second shared by all downloads, 0 for unlimited
+	MaxConnectionsPerServer int                        // Concurrent page requests per server
+	ServerConnections       map[string]int             // Per-server overrides of MaxConnectionsPerServer, keyed by host
+	AutoDeleteRead          bool                       // Auto-delete chapters after reading
+	KeepReadChapters        int                        // Most recently read chapters kept per manga, 0 keeps all
+	DeleteReadAfter         time.Duration              // Delete chapters read longer ago than this, 0 disables
+	DiskQuota               int64                      // Bytes all downloads may use, 0 for no limit
+	CategoryRetention       map[string]RetentionPolicy // Policies replacing the global one, keyed by category name
+	RetentionInterval       time.Duration              // How often retention runs, 0 to run only after reading
+	ConfirmRetention        bool                       // Automatic retention runs wait for their plan to be confirmed
+	OnlyOnWiFi              bool                       // Only download on WiFi (future mobile)
 }
 
 // DefaultDownloadConfig returns default download configuration
 func DefaultDownloadConfig() *DownloadConfig {
 	return &DownloadConfig{
-		DownloadPath:    "~/.local/share/miryokusha/downloads",
-		MaxConcurrent:   3,
-		RetryAttempts:   3,
-		RetryDelay:      time.Second * 5,
-		PageTimeout:     time.Second * 30,
-		AutoDeleteRead:  false,
-		OnlyOnWiFi:      false,
+		DownloadPath:            "~/.local/share/miryokusha/downloads",
+		OutputFormat:            FormatImages,
+		MaxConcurrent:           3,
+		RetryAttempts:           3,
+		RetryDelay:              time.Second * 5,
+		MaxRetryDelay:           time.Minute * 5,
+		PageTimeout:             time.Second * 30,
+		PageConcurrency:         4,
+		MaxConnectionsPerServer: 4,
+		AutoDeleteRead:          false,
+		ConfirmRetention:        true,
+		OnlyOnWiFi:              false,
 	}
 }
 
@@ -98,11 +147,11 @@ func DefaultDownloadConfig() *DownloadConfig {
 type DownloadStats struct {
 	mu sync.RWMutex
 
-	TotalDownloads    int
-	CompletedDownloads int
-	FailedDownloads   int
+	TotalDownloads       int
+	CompletedDownloads   int
+	FailedDownloads      int
 	TotalBytesDownloaded int64
-	ActiveDownloads   int
+	ActiveDownloads      int
 }
 
 // Update updates the stats (thread-safe)
@@ -0,0 +1,348 @@
+package downloads
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+)
+
+// pageFilePattern matches page files written by the download manager
+var pageFilePattern = regexp.MustCompile(`^\d{4}\.[A-Za-z]+$`)
+
+// VerifyIssue describes a downloaded chapter that failed verification
+type VerifyIssue struct {
+	Path        string
+	MangaTitle  string
+	ChapterName string
+	ChapterID   string
+	Problems    []string
+	Requeued    bool
+}
+
+// VerifyReport summarizes a verification sweep of the download directory
+type VerifyReport struct {
+	Checked int
+	Issues  []*VerifyIssue
+}
+
+// Requeued returns the number of chapters queued again for download
+func (r *VerifyReport) Requeued() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Requeued {
+			count++
+		}
+	}
+	return count
+}
+
+// VerifyDownloads checks every chapter under DownloadPath against its
+// manifest, removes corrupt pages and leftover temp files, and re-queues
+// chapters that are corrupt or incomplete. Chapters currently downloading
+// are skipped.
+func (m *Manager) VerifyDownloads() (*VerifyReport, error) {
+	report := &VerifyReport{}
+
+	// Index entries let legacy directories without a manifest be identified
+	indexed := make(map[string]*storage.DownloadedChapter)
+	store := m.downloadIndex()
+	if store != nil {
+		chapters, err := store.GetAllDownloaded()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range chapters {
+			indexed[filepath.Clean(c.Path)] = c
+		}
+	}
+
+	mangaDirs, err := os.ReadDir(m.config.DownloadPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read download directory: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, mangaDir := range mangaDirs {
+		if !mangaDir.IsDir() {
+			continue
+		}
+
+		mangaPath := filepath.Join(m.config.DownloadPath, mangaDir.Name())
+		chapterDirs, err := os.ReadDir(mangaPath)
+		if err != nil {
+			continue
+		}
+
+		removeTempFiles(mangaPath)
+
+		for _, chapterDir := range chapterDirs {
+			dir := filepath.Clean(filepath.Join(mangaPath, chapterDir.Name()))
+
+			var issue *VerifyIssue
+			var checked bool
+			switch {
+			case chapterDir.IsDir():
+				issue, checked = m.verifyChapter(dir, indexed[dir])
+			case strings.EqualFold(filepath.Ext(dir), ".cbz"):
+				issue, checked = m.verifyPackaged(dir, indexed[dir])
+			default:
+				continue
+			}
+			seen[dir] = true
+
+			if checked {
+				report.Checked++
+			}
+			if issue != nil {
+				report.Issues = append(report.Issues, issue)
+			}
+		}
+	}
+
+	// Indexed chapters whose directory is gone are no longer downloaded
+	for path, c := range indexed {
+		if seen[path] {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		store.RemoveDownloaded(c.ChapterID)
+		report.Issues = append(report.Issues, &VerifyIssue{
+			Path:        path,
+			MangaTitle:  c.MangaTitle,
+			ChapterName: c.ChapterName,
+			ChapterID:   c.ChapterID,
+			Problems:    []string{"missing from disk"},
+		})
+	}
+
+	return report, nil
+}
+
+// verifyChapter checks a single chapter directory. It returns the issue
+// found, if any, and whether the directory was checked at all.
+func (m *Manager) verifyChapter(dir string, indexed *storage.DownloadedChapter) (*VerifyIssue, bool) {
+	mf, err := loadManifest(dir)
+	manifestErr := err != nil
+	if manifestErr {
+		mf = newManifest()
+	}
+
+	_, statErr := os.Stat(filepath.Join(dir, manifestName))
+	legacy := os.IsNotExist(statErr)
+
+	// Directories without a manifest take their identity from the index
+	if (legacy || manifestErr) && indexed != nil {
+		mf = manifestFromIndex(indexed)
+	}
+
+	// Queued chapters resume through their manifest on the next attempt
+	if mf.ChapterID != "" && m.isPending(mf.ChapterID) {
+		return nil, false
+	}
+
+	removeTempFiles(dir)
+
+	var problems []string
+	if manifestErr {
+		problems = append(problems, "unreadable manifest")
+	}
+
+	if legacy || manifestErr {
+		if mf.TotalPages == 0 {
+			mf.TotalPages = countPageFiles(dir)
+		}
+		if mf.TotalPages == 0 && mf.ChapterID == "" {
+			// Not a chapter directory written by the download manager
+			return nil, false
+		}
+		if indexed == nil {
+			mf.Complete = true
+		}
+	}
+
+	for i := 0; i < mf.TotalPages; i++ {
+		if pageValid(dir, mf, i) {
+			continue
+		}
+		delete(mf.Pages, i)
+		if (legacy || manifestErr) && adoptPage(dir, mf, i) {
+			continue
+		}
+
+		files := pageFiles(dir, i)
+		if len(files) == 0 {
+			problems = append(problems, fmt.Sprintf("missing page %d", i+1))
+			continue
+		}
+		for _, path := range files {
+			os.Remove(path)
+		}
+		problems = append(problems, fmt.Sprintf("corrupt page %d", i+1))
+	}
+
+	if !mf.Complete && len(problems) == 0 {
+		problems = append(problems, "incomplete download")
+	}
+
+	if len(problems) == 0 {
+		if legacy {
+			// Record checksums so later sweeps can detect corruption
+			mf.save(dir)
+		}
+		return nil, true
+	}
+
+	mf.Complete = false
+	mf.save(dir)
+
+	issue := &VerifyIssue{
+		Path:        dir,
+		MangaTitle:  mf.MangaTitle,
+		ChapterName: mf.ChapterName,
+		ChapterID:   mf.ChapterID,
+		Problems:    problems,
+	}
+	if issue.MangaTitle == "" {
+		issue.MangaTitle = filepath.Base(filepath.Dir(dir))
+	}
+	if issue.ChapterName == "" {
+		issue.ChapterName = filepath.Base(dir)
+	}
+
+	if mf.ChapterID != "" {
+		if store := m.downloadIndex(); store != nil {
+			store.RemoveDownloaded(mf.ChapterID)
+		}
+		issue.Requeued = m.requeue(mf)
+	}
+
+	return issue, true
+}
+
+// verifyPackaged checks a chapter stored as a CBZ archive. Corrupt archives
+// of indexed chapters are deleted and the chapter is re-queued.
+func (m *Manager) verifyPackaged(path string, indexed *storage.DownloadedChapter) (*VerifyIssue, bool) {
+	problems := verifyArchive(path)
+	if len(problems) == 0 {
+		return nil, true
+	}
+
+	name := filepath.Base(path)
+	issue := &VerifyIssue{
+		Path:        path,
+		MangaTitle:  filepath.Base(filepath.Dir(path)),
+		ChapterName: strings.TrimSuffix(name, filepath.Ext(name)),
+		Problems:    problems,
+	}
+	if indexed == nil {
+		// Without an index entry the chapter cannot be downloaded again,
+		// so the archive is left for the user to inspect
+		return issue, true
+	}
+
+	issue.MangaTitle = indexed.MangaTitle
+	issue.ChapterName = indexed.ChapterName
+	issue.ChapterID = indexed.ChapterID
+
+	os.Remove(path)
+	m.downloadIndex().RemoveDownloaded(indexed.ChapterID)
+	issue.Requeued = m.requeue(manifestFromIndex(indexed))
+
+	return issue, true
+}
+
+// manifestFromIndex builds a manifest carrying a downloaded chapter's identity
+func manifestFromIndex(indexed *storage.DownloadedChapter) *chapterManifest {
+	mf := newManifest()
+	mf.MangaID = indexed.MangaID
+	mf.MangaTitle = indexed.MangaTitle
+	mf.ChapterID = indexed.ChapterID
+	mf.ChapterName = indexed.ChapterName
+	mf.ChapterNumber = indexed.ChapterNumber
+	mf.SourceType = indexed.SourceType
+	mf.SourceID = indexed.SourceID
+	mf.TotalPages = indexed.PageCount
+	mf.Complete = true
+	return mf
+}
+
+// requeue queues a chapter described by a manifest for download again,
+// unless it is already queued or downloading
+func (m *Manager) requeue(mf *chapterManifest) bool {
+	if mf.SourceID == "" {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pendingLocked(mf.ChapterID) {
+		return false
+	}
+
+	item := itemFromRecord(&storage.QueuedDownload{
+		ID:            fmt.Sprintf("%s-%s-%d", mf.MangaID, mf.ChapterID, time.Now().Unix()),
+		MangaID:       mf.MangaID,
+		MangaTitle:    mf.MangaTitle,
+		ChapterID:     mf.ChapterID,
+		ChapterName:   mf.ChapterName,
+		ChapterNumber: mf.ChapterNumber,
+		PageCount:     mf.TotalPages,
+		SourceType:    mf.SourceType,
+		SourceID:      mf.SourceID,
+		Status:        string(StatusQueued),
+	})
+
+	m.queue = append(m.queue, item)
+	m.sortQueue()
+	m.persist(item)
+
+	m.stats.Update(func() {
+		m.stats.TotalDownloads++
+	})
+
+	m.signal()
+	return true
+}
+
+// isPending reports whether a chapter is queued or downloading
+func (m *Manager) isPending(chapterID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pendingLocked(chapterID)
+}
+
+// pendingLocked is isPending for callers holding mu
+func (m *Manager) pendingLocked(chapterID string) bool {
+	for _, item := range m.queue {
+		if item.ChapterID == chapterID {
+			return true
+		}
+	}
+	_, active := m.active[chapterID]
+	return active
+}
+
+// countPageFiles returns the number of page files in a chapter directory
+func countPageFiles(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && pageFilePattern.MatchString(entry.Name()) {
+			count++
+		}
+	}
+	return count
+}
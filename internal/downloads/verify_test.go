@@ -0,0 +1,162 @@
+package downloads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeChapter writes a verified chapter of pages pages as the download
+// manager would and records it in the index
+func storeChapter(t *testing.T, m *Manager, store *storage.DownloadManager, chapterID string, pages int) string {
+	t.Helper()
+	dir := filepath.Join(m.config.DownloadPath, "Manga", chapterID)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	mf := newManifest()
+	mf.setItem(&DownloadItem{
+		MangaID:     "m1",
+		MangaTitle:  "Manga",
+		ChapterID:   chapterID,
+		ChapterName: chapterID,
+		SourceType:  source.SourceTypeSuwayomi,
+		Chapter:     &source.Chapter{ID: chapterID, ChapterNumber: 1, SourceID: "s1"},
+	})
+	mf.TotalPages = pages
+	for i := 0; i < pages; i++ {
+		entry, err := writePage(dir, i, encodePNG(t, 4, 4))
+		require.NoError(t, err)
+		mf.Pages[i] = entry
+	}
+	mf.Complete = true
+	require.NoError(t, mf.save(dir))
+
+	require.NoError(t, store.MarkDownloaded(&storage.DownloadedChapter{
+		ChapterID:  chapterID,
+		MangaID:    "m1",
+		MangaTitle: "Manga",
+		SourceType: string(source.SourceTypeSuwayomi),
+		SourceID:   "s1",
+		Path:       dir,
+		PageCount:  pages,
+	}))
+	return dir
+}
+
+func TestManager_VerifyDownloads(t *testing.T) {
+	tests := []struct {
+		name     string
+		damage   func(t *testing.T, dir string)
+		problems []string
+		gone     string // Page file deleted by the sweep
+	}{
+		{
+			name:   "intact chapter",
+			damage: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "corrupt page",
+			damage: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "0002.png"), []byte("garbage"), 0644))
+			},
+			problems: []string{"corrupt page 2"},
+			gone:     "0002.png",
+		},
+		{
+			name: "missing page",
+			damage: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, "0003.png")))
+			},
+			problems: []string{"missing page 3"},
+		},
+		{
+			name: "interrupted download",
+			damage: func(t *testing.T, dir string) {
+				mf, err := loadManifest(dir)
+				require.NoError(t, err)
+				mf.Complete = false
+				require.NoError(t, mf.save(dir))
+			},
+			problems: []string{"incomplete download"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+			require.NoError(t, err)
+			defer db.Close()
+			store := storage.NewDownloadManager(db)
+
+			m := newTestManager(t, newFakeClock())
+			m.SetStore(store)
+
+			dir := storeChapter(t, m, store, "c1", 3)
+			tt.damage(t, dir)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ".tmp-left"), nil, 0644))
+
+			report, err := m.VerifyDownloads()
+			require.NoError(t, err)
+			assert.Equal(t, 1, report.Checked)
+			assert.Empty(t, tempFiles(t, dir), "leftover temp files are removed")
+
+			if tt.problems == nil {
+				assert.Empty(t, report.Issues)
+				assert.True(t, m.IsDownloaded("c1"))
+				assert.Empty(t, m.GetQueue())
+				return
+			}
+
+			require.Len(t, report.Issues, 1)
+			issue := report.Issues[0]
+			assert.Equal(t, tt.problems, issue.Problems)
+			assert.Equal(t, "c1", issue.ChapterID)
+			assert.True(t, issue.Requeued)
+			assert.Equal(t, 1, report.Requeued())
+
+			// The chapter is downloaded again, resuming from its intact pages
+			assert.False(t, m.IsDownloaded("c1"))
+			queue := m.GetQueue()
+			require.Len(t, queue, 1)
+			assert.Equal(t, "c1", queue[0].ChapterID)
+			assert.Equal(t, "s1", queue[0].Chapter.SourceID)
+			if tt.gone != "" {
+				assert.NoFileExists(t, filepath.Join(dir, tt.gone), "corrupt pages are deleted")
+			}
+			mf, err := loadManifest(dir)
+			require.NoError(t, err)
+			assert.False(t, mf.Complete)
+			assert.Contains(t, mf.Pages, 0)
+
+			// A second sweep does not queue the chapter twice
+			report, err = m.VerifyDownloads()
+			require.NoError(t, err)
+			assert.Empty(t, report.Issues)
+			assert.Len(t, m.GetQueue(), 1)
+		})
+	}
+}
+
+func TestManager_VerifyDownloads_MissingDirectory(t *testing.T) {
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := storage.NewDownloadManager(db)
+
+	m := newTestManager(t, newFakeClock())
+	m.SetStore(store)
+	dir := storeChapter(t, m, store, "c1", 2)
+	require.NoError(t, os.RemoveAll(dir))
+
+	report, err := m.VerifyDownloads()
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, []string{"missing from disk"}, report.Issues[0].Problems)
+	assert.False(t, report.Issues[0].Requeued)
+	assert.False(t, m.IsDownloaded("c1"), "the index no longer lists the chapter")
+}
@@ -0,0 +1,243 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// epubPage is a page document of an EPUB
+type epubPage struct {
+	ID        string // Manifest ID of the page document
+	ImageID   string // Manifest ID of the image
+	File      string // Page document, relative to OEBPS
+	ImageFile string // Image, relative to OEBPS
+	Title     string
+	Image     *bookImage
+}
+
+// epubChapter is a table of contents entry
+type epubChapter struct {
+	Title string
+	File  string
+}
+
+// epubData is the input of the EPUB templates
+type epubData struct {
+	*book
+	Pages    []*epubPage
+	Chapters []*epubChapter
+}
+
+var epubFuncs = template.FuncMap{
+	"xml": func(s string) string {
+		var sb strings.Builder
+		xml.EscapeText(&sb, []byte(s))
+		return sb.String()
+	},
+	"inc": func(i int) int { return i + 1 },
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+var opfTemplate = template.Must(template.New("opf").Funcs(epubFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" prefix="rendition: http://www.idpf.org/vocab/rendition/#">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">{{xml .ID}}</dc:identifier>
+    <dc:title>{{xml .Title}}</dc:title>
+    <dc:language>und</dc:language>
+{{- range .Authors}}
+    <dc:creator>{{xml .}}</dc:creator>
+{{- end}}
+{{- if .Description}}
+    <dc:description>{{xml .Description}}</dc:description>
+{{- end}}
+{{- range .Genres}}
+    <dc:subject>{{xml .}}</dc:subject>
+{{- end}}
+{{- if .Source}}
+    <dc:publisher>{{xml .Source}}</dc:publisher>
+{{- end}}
+    <meta property="belongs-to-collection" id="series">{{xml .Series}}</meta>
+    <meta refines="#series" property="collection-type">series</meta>
+    <meta property="dcterms:modified">{{.Modified.Format "2006-01-02T15:04:05Z"}}</meta>
+    <meta property="rendition:layout">pre-paginated</meta>
+    <meta property="rendition:orientation">auto</meta>
+    <meta property="rendition:spread">none</meta>
+    <meta name="cover" content="cover-image"/>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+{{- range .Pages}}
+    <item id="{{.ID}}" href="{{.File}}" media-type="application/xhtml+xml"/>
+    <item id="{{.ImageID}}" href="{{.ImageFile}}" media-type="{{.Image.MediaType}}"{{if eq .ImageID "cover-image"}} properties="cover-image"{{end}}/>
+{{- end}}
+  </manifest>
+  <spine toc="ncx">
+{{- range .Pages}}
+    <itemref idref="{{.ID}}"/>
+{{- end}}
+  </spine>
+</package>
+`))
+
+var navTemplate = template.Must(template.New("nav").Funcs(epubFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+  <title>{{xml .Title}}</title>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>{{xml .Title}}</h1>
+    <ol>
+{{- range .Chapters}}
+      <li><a href="{{.File}}">{{xml .Title}}</a></li>
+{{- end}}
+    </ol>
+  </nav>
+  <nav epub:type="landmarks" hidden="">
+    <ol>
+      <li><a epub:type="cover" href="pages/cover.xhtml">Cover</a></li>
+      <li><a epub:type="bodymatter" href="{{(index .Chapters 0).File}}">Start</a></li>
+    </ol>
+  </nav>
+</body>
+</html>
+`))
+
+var ncxTemplate = template.Must(template.New("ncx").Funcs(epubFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="{{xml .ID}}"/>
+  </head>
+  <docTitle><text>{{xml .Title}}</text></docTitle>
+  <navMap>
+{{- range $i, $c := .Chapters}}
+    <navPoint id="chapter-{{inc $i}}" playOrder="{{inc $i}}">
+      <navLabel><text>{{xml $c.Title}}</text></navLabel>
+      <content src="{{$c.File}}"/>
+    </navPoint>
+{{- end}}
+  </navMap>
+</ncx>
+`))
+
+var pageTemplate = template.Must(template.New("page").Funcs(epubFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+  <title>{{xml .Title}}</title>
+  <meta name="viewport" content="width={{.Image.Width}}, height={{.Image.Height}}"/>
+  <style>html, body { margin: 0; padding: 0; } img { position: absolute; top: 0; left: 0; width: {{.Image.Width}}px; height: {{.Image.Height}}px; }</style>
+</head>
+<body>
+  <img src="../{{.ImageFile}}" alt="{{xml .Title}}"/>
+</body>
+</html>
+`))
+
+// writeEPUB writes a fixed-layout EPUB 3 with one page document per image
+func writeEPUB(w io.Writer, b *book) error {
+	data := epubData{book: b}
+
+	data.Pages = append(data.Pages, &epubPage{
+		ID:        "page-cover",
+		ImageID:   "cover-image",
+		File:      "pages/cover.xhtml",
+		ImageFile: "images/cover" + b.Cover.Extension(),
+		Title:     "Cover",
+		Image:     b.Cover,
+	})
+	for i, chapter := range b.Chapters {
+		for j, img := range chapter.Pages {
+			name := fmt.Sprintf("c%03d-p%04d", i+1, j+1)
+			page := &epubPage{
+				ID:        "page-" + name,
+				ImageID:   "image-" + name,
+				File:      "pages/" + name + ".xhtml",
+				ImageFile: "images/" + name + img.Extension(),
+				Title:     fmt.Sprintf("%s - Page %d", chapter.Title, j+1),
+				Image:     img,
+			}
+			if j == 0 {
+				data.Chapters = append(data.Chapters, &epubChapter{Title: chapter.Title, File: page.File})
+			}
+			data.Pages = append(data.Pages, page)
+		}
+	}
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype must be the first entry and stored uncompressed
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+	if _, err := io.WriteString(mw, "application/epub+zip"); err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+
+	if err := writeZipEntry(zw, "META-INF/container.xml", []byte(containerXML)); err != nil {
+		return err
+	}
+	if err := writeZipTemplate(zw, "OEBPS/content.opf", opfTemplate, data); err != nil {
+		return err
+	}
+	if err := writeZipTemplate(zw, "OEBPS/nav.xhtml", navTemplate, data); err != nil {
+		return err
+	}
+	if err := writeZipTemplate(zw, "OEBPS/toc.ncx", ncxTemplate, data); err != nil {
+		return err
+	}
+
+	for _, page := range data.Pages {
+		if err := writeZipTemplate(zw, "OEBPS/"+page.File, pageTemplate, page); err != nil {
+			return err
+		}
+		// Images are already compressed
+		iw, err := zw.CreateHeader(&zip.FileHeader{Name: "OEBPS/" + page.ImageFile, Method: zip.Store})
+		if err != nil {
+			return fmt.Errorf("failed to write EPUB: %w", err)
+		}
+		if _, err := iw.Write(page.Image.Data); err != nil {
+			return fmt.Errorf("failed to write EPUB: %w", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+	return nil
+}
+
+// writeZipEntry adds a compressed file to the archive
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+	return nil
+}
+
+// writeZipTemplate adds a file rendered from a template to the archive
+func writeZipTemplate(zw *zip.Writer, name string, tmpl *template.Template, data interface{}) error {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return writeZipEntry(zw, name, []byte(sb.String()))
+}
@@ -0,0 +1,414 @@
+// Package export writes manga chapters to single-file books for e-readers
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+)
+
+// Format is the file format of an export
+type Format string
+
+const (
+	FormatEPUB Format = "epub" // Fixed-layout EPUB 3
+	FormatPDF  Format = "pdf"  // Image PDF
+)
+
+// ParseFormat parses a format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case FormatEPUB:
+		return FormatEPUB, nil
+	case FormatPDF:
+		return FormatPDF, nil
+	default:
+		return "", fmt.Errorf("unknown export format: %s (use epub or pdf)", name)
+	}
+}
+
+// Extension returns the file extension of the format, including the dot
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Options configures an export
+type Options struct {
+	Format Format
+	Title  string // Book title, defaults to the manga title and the chapters exported
+	Path   string // Output file, defaults to a file named after the title in the output directory
+
+	// Profile processes pages for the reading device, replacing the
+	// exporter's default profile
+	Profile *imageproc.Profile
+}
+
+// Exporter fetches chapter pages from their sources and writes them to books
+type Exporter struct {
+	sourceManager *source.SourceManager
+	outputDir     string
+	profile       *imageproc.Profile
+	now           func() time.Time
+}
+
+// NewExporter creates an exporter writing to outputDir by default
+func NewExporter(sm *source.SourceManager, outputDir string) *Exporter {
+	return &Exporter{
+		sourceManager: sm,
+		outputDir:     outputDir,
+		now:           time.Now,
+	}
+}
+
+// SetImageProfile sets the image processing applied to exports that do not
+// choose a profile. nil keeps pages as the source provides them.
+func (e *Exporter) SetImageProfile(profile *imageproc.Profile) {
+	e.profile = profile
+}
+
+// Export writes the chapters of a manga to a single book, one table of
+// contents entry per chapter, and returns the path of the file
+func (e *Exporter) Export(manga *source.Manga, chapters []*source.Chapter, opts Options) (string, error) {
+	if len(chapters) == 0 {
+		return "", fmt.Errorf("failed to export: no chapters selected")
+	}
+
+	chapters = SortChapters(chapters)
+	title := opts.Title
+	if title == "" {
+		title = DefaultTitle(manga, chapters)
+	}
+
+	path := opts.Path
+	if path == "" {
+		path = filepath.Join(e.outputDir, sanitizeFileName(title)+opts.Format.Extension())
+	}
+
+	profile := opts.Profile
+	if profile == nil {
+		profile = e.profile
+	}
+
+	b, err := e.buildBook(manga, chapters, title, profile)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeFile(path, b, opts.Format); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeFile writes the book to a temporary file next to path and renames
+// it into place, so failed exports leave no partial file behind
+func writeFile(path string, b *book, format Format) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	switch format {
+	case FormatEPUB:
+		err = writeEPUB(tmp, b)
+	case FormatPDF:
+		err = writePDF(tmp, b)
+	default:
+		err = fmt.Errorf("unknown export format: %s", format)
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to move export into place: %w", err)
+	}
+	return nil
+}
+
+// book is the content of an export, independent of the output format
+type book struct {
+	ID          string
+	Title       string
+	Series      string
+	Authors     []string
+	Description string
+	Genres      []string
+	Source      string
+	Modified    time.Time
+	Cover       *bookImage
+	Chapters    []*bookChapter
+}
+
+// bookChapter is a chapter of a book with its pages in reading order
+type bookChapter struct {
+	Title string
+	Pages []*bookImage
+}
+
+// bookImage is a page or cover image in a format both EPUB and PDF accept
+type bookImage struct {
+	Data      []byte
+	MediaType string // "image/jpeg", "image/png" or "image/gif"
+	Width     int
+	Height    int
+}
+
+// Extension returns the file extension of the image, including the dot
+func (img *bookImage) Extension() string {
+	switch img.MediaType {
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".jpg"
+	}
+}
+
+// buildBook fetches the cover and every page of the chapters and applies the
+// image profile to them
+func (e *Exporter) buildBook(manga *source.Manga, chapters []*source.Chapter, title string, profile *imageproc.Profile) (*book, error) {
+	b := &book{
+		ID:          bookID(manga, chapters),
+		Title:       title,
+		Series:      manga.Title,
+		Description: manga.Description,
+		Genres:      manga.Genres,
+		Source:      manga.SourceName,
+		Modified:    e.now().UTC(),
+	}
+	for _, name := range []string{manga.Author, manga.Artist} {
+		if name != "" && !contains(b.Authors, name) {
+			b.Authors = append(b.Authors, name)
+		}
+	}
+
+	for _, chapter := range chapters {
+		pages, err := e.sourceManager.GetAllPages(chapter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", ChapterTitle(chapter), err)
+		}
+
+		bc := &bookChapter{Title: ChapterTitle(chapter)}
+		for _, page := range pages {
+			images, err := processImage(page.ImageData, profile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read page %d of %s: %w", page.Index+1, bc.Title, err)
+			}
+			bc.Pages = append(bc.Pages, images...)
+		}
+		if len(bc.Pages) == 0 {
+			return nil, fmt.Errorf("failed to export %s: chapter has no pages", bc.Title)
+		}
+		b.Chapters = append(b.Chapters, bc)
+	}
+
+	// Fall back to the first page when the source has no cover
+	if manga.CoverURL != "" {
+		if data, err := e.sourceManager.GetCover(manga); err == nil {
+			// Covers are processed like pages, but never split
+			var coverProfile *imageproc.Profile
+			if profile != nil {
+				p := *profile
+				p.SplitSpreads = false
+				coverProfile = &p
+			}
+			if images, err := processImage(data, coverProfile); err == nil {
+				b.Cover = images[0]
+			}
+		}
+	}
+	if b.Cover == nil {
+		b.Cover = b.Chapters[0].Pages[0]
+	}
+
+	return b, nil
+}
+
+// processImage applies an image profile to page data, which yields two
+// images for split spreads
+func processImage(data []byte, profile *imageproc.Profile) ([]*bookImage, error) {
+	if profile.IsZero() {
+		img, err := loadImage(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*bookImage{img}, nil
+	}
+
+	pages, err := imageproc.Process(data, profile)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]*bookImage, 0, len(pages))
+	for _, page := range pages {
+		img, err := loadImage(page.Data)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// loadImage reads an image's dimensions, converting formats e-readers may
+// not support (such as WebP) to JPEG
+func loadImage(data []byte) (*bookImage, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	switch format {
+	case "jpeg", "png", "gif":
+		return &bookImage{
+			Data:      data,
+			MediaType: "image/" + format,
+			Width:     cfg.Width,
+			Height:    cfg.Height,
+		}, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("failed to convert %s image: %w", format, err)
+	}
+	return &bookImage{
+		Data:      buf.Bytes(),
+		MediaType: "image/jpeg",
+		Width:     cfg.Width,
+		Height:    cfg.Height,
+	}, nil
+}
+
+// SortChapters returns the chapters in reading order
+func SortChapters(chapters []*source.Chapter) []*source.Chapter {
+	sorted := make([]*source.Chapter, len(chapters))
+	copy(sorted, chapters)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ChapterNumber < sorted[j].ChapterNumber
+	})
+	return sorted
+}
+
+// SelectRange returns the chapters numbered from to to, inclusive, in
+// reading order
+func SelectRange(chapters []*source.Chapter, from, to float64) []*source.Chapter {
+	var selected []*source.Chapter
+	for _, c := range SortChapters(chapters) {
+		if c.ChapterNumber >= from && c.ChapterNumber <= to {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// SelectVolume returns the chapters of a volume in reading order
+func SelectVolume(chapters []*source.Chapter, volume float64) []*source.Chapter {
+	var selected []*source.Chapter
+	for _, c := range SortChapters(chapters) {
+		if c.VolumeNumber == volume {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// ChapterTitle returns the table of contents entry of a chapter
+func ChapterTitle(c *source.Chapter) string {
+	if c.Title != "" {
+		return c.Title
+	}
+	return "Chapter " + formatNumber(c.ChapterNumber)
+}
+
+// DefaultTitle names a book after the manga and the volume or chapters it
+// contains, e.g. "Title Vol. 2" or "Title Ch. 10-20"
+func DefaultTitle(manga *source.Manga, chapters []*source.Chapter) string {
+	chapters = SortChapters(chapters)
+	first, last := chapters[0], chapters[len(chapters)-1]
+
+	if first.VolumeNumber > 0 && first.VolumeNumber == last.VolumeNumber {
+		sameVolume := true
+		for _, c := range chapters {
+			if c.VolumeNumber != first.VolumeNumber {
+				sameVolume = false
+				break
+			}
+		}
+		if sameVolume {
+			return fmt.Sprintf("%s Vol. %s", manga.Title, formatNumber(first.VolumeNumber))
+		}
+	}
+
+	if len(chapters) == 1 {
+		return fmt.Sprintf("%s Ch. %s", manga.Title, formatNumber(first.ChapterNumber))
+	}
+	return fmt.Sprintf("%s Ch. %s-%s", manga.Title, formatNumber(first.ChapterNumber), formatNumber(last.ChapterNumber))
+}
+
+// bookID returns a stable identifier for an export of the given chapters
+func bookID(manga *source.Manga, chapters []*source.Chapter) string {
+	first, last := chapters[0], chapters[len(chapters)-1]
+	return fmt.Sprintf("urn:miryokusha:%s:%s:%s-%s", manga.SourceID, manga.ID, first.ID, last.ID)
+}
+
+// formatNumber formats a chapter or volume number without trailing zeros
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// sanitizeFileName replaces characters that are invalid in file names
+func sanitizeFileName(name string) string {
+	replacer := strings.NewReplacer(
+		"/", "_", "\\", "_", ":", "_", "*", "_",
+		"?", "_", "\"", "_", "<", "_", ">", "_", "|", "_",
+	)
+	name = strings.TrimSpace(replacer.Replace(name))
+	if name == "" {
+		return "export"
+	}
+	return name
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package export
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/imageproc"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestExporter serves a manga with three two-page chapters and returns
+// an exporter for it
+func newTestExporter(t *testing.T) (*Exporter, *suwayomitest.Server, *source.Manga, []*source.Chapter) {
+	t.Helper()
+
+	server := suwayomitest.NewServer()
+	t.Cleanup(server.Close)
+	server.SeedLibrary(1, 3, 2)
+
+	src := source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client())
+	sm := source.NewSourceManager()
+	sm.AddSource(src)
+
+	manga, err := src.GetManga("1")
+	require.NoError(t, err)
+	manga.CoverURL = "/api/v1/manga/1/thumbnail"
+	manga.Author = "Author"
+	manga.Genres = []string{"Action", "Drama"}
+
+	chapters, err := src.ListChapters("1")
+	require.NoError(t, err)
+
+	e := NewExporter(sm, t.TempDir())
+	e.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+	return e, server, manga, chapters
+}
+
+func readZipFile(t *testing.T, files map[string]*zip.File, name string) string {
+	t.Helper()
+	f, ok := files[name]
+	require.True(t, ok, "missing %s", name)
+	rc, err := f.Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExport_EPUB(t *testing.T) {
+	e, server, manga, chapters := newTestExporter(t)
+
+	path, err := e.Export(manga, chapters, Options{Format: FormatEPUB})
+	require.NoError(t, err)
+	assert.Equal(t, "Manga 1 Ch. 1-3.epub", filepath.Base(path))
+	assert.Equal(t, 1, server.Requests("/api/v1/manga/1/thumbnail"))
+
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	// The mimetype comes first and uncompressed
+	require.NotEmpty(t, zr.File)
+	assert.Equal(t, "mimetype", zr.File[0].Name)
+	assert.Equal(t, zip.Store, zr.File[0].Method)
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	assert.Equal(t, "application/epub+zip", readZipFile(t, files, "mimetype"))
+	assert.Contains(t, readZipFile(t, files, "META-INF/container.xml"), "OEBPS/content.opf")
+
+	opf := readZipFile(t, files, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<meta property="rendition:layout">pre-paginated</meta>`)
+	assert.Contains(t, opf, "<dc:title>Manga 1 Ch. 1-3</dc:title>")
+	assert.Contains(t, opf, "<dc:creator>Author</dc:creator>")
+	assert.Contains(t, opf, "<dc:subject>Drama</dc:subject>")
+	assert.Contains(t, opf, "2025-06-01T12:00:00Z")
+	assert.Contains(t, opf, `properties="cover-image"`)
+	assert.Len(t, regexp.MustCompile(`<itemref `).FindAllString(opf, -1), 7, "cover and six pages")
+
+	nav := readZipFile(t, files, "OEBPS/nav.xhtml")
+	assert.Len(t, regexp.MustCompile(`<li><a href="pages/c\d{3}-p0001.xhtml">`).FindAllString(nav, -1), 3, "one entry per chapter")
+	assert.Contains(t, nav, "Chapter 2")
+
+	page := readZipFile(t, files, "OEBPS/pages/c002-p0001.xhtml")
+	assert.Contains(t, page, `<meta name="viewport" content="width=`)
+	assert.Contains(t, page, `src="../images/c002-p0001.png"`)
+	assert.Contains(t, files, "OEBPS/images/cover.png")
+}
+
+func TestExport_PDF(t *testing.T) {
+	e, _, manga, chapters := newTestExporter(t)
+
+	path, err := e.Export(manga, SelectRange(chapters, 2, 3), Options{Format: FormatPDF, Title: "Custom"})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom.pdf", filepath.Base(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	pdf := string(data)
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.7"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "/Type /Pages")
+	assert.Contains(t, pdf, "/Count 5 >>", "cover and four pages")
+	assert.Contains(t, pdf, "/Title (Custom)")
+	assert.Contains(t, pdf, "/Keywords (Action, Drama)")
+	assert.Contains(t, pdf, "/Title (Chapter 2)")
+	assert.Contains(t, pdf, "/Title (Chapter 3)")
+	assert.NotContains(t, pdf, "/Title (Chapter 1)")
+
+	// Every object offset in the cross-reference table points at its object
+	xref := pdf[strings.LastIndex(pdf, "\nxref\n"):]
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(xref, -1)
+	require.NotEmpty(t, offsets)
+	for i, match := range offsets {
+		offset, err := strconv.Atoi(match[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(pdf[offset:], strconv.Itoa(i+1)+" 0 obj"), "object %d", i+1)
+	}
+}
+
+func TestExport_ImageProfile(t *testing.T) {
+	e, _, manga, chapters := newTestExporter(t)
+	e.SetImageProfile(&imageproc.Profile{Grayscale: true, MaxWidth: 4, Format: imageproc.FormatJPEG})
+
+	path, err := e.Export(manga, SelectRange(chapters, 1, 1), Options{Format: FormatEPUB})
+	require.NoError(t, err)
+
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	assert.Contains(t, files, "OEBPS/images/cover.jpg")
+	assert.Contains(t, files, "OEBPS/images/c001-p0002.jpg")
+	assert.Contains(t, readZipFile(t, files, "OEBPS/pages/c001-p0001.xhtml"), `content="width=4, height=6"`)
+}
+
+func TestExport_NoChapters(t *testing.T) {
+	e, _, manga, _ := newTestExporter(t)
+	_, err := e.Export(manga, nil, Options{Format: FormatEPUB})
+	assert.Error(t, err)
+}
+
+func TestSelectionAndTitles(t *testing.T) {
+	manga := &source.Manga{Title: "Series"}
+	chapters := []*source.Chapter{
+		{ID: "c3", ChapterNumber: 3, VolumeNumber: 2},
+		{ID: "c1", ChapterNumber: 1, VolumeNumber: 1},
+		{ID: "c2", ChapterNumber: 2, VolumeNumber: 1},
+		{ID: "c3.5", ChapterNumber: 3.5, VolumeNumber: 2, Title: "Extra"},
+	}
+
+	volume := SelectVolume(chapters, 1)
+	require.Len(t, volume, 2)
+	assert.Equal(t, "c1", volume[0].ID)
+	assert.Equal(t, "Series Vol. 1", DefaultTitle(manga, volume))
+
+	ranged := SelectRange(chapters, 2, 3.5)
+	require.Len(t, ranged, 3)
+	assert.Equal(t, "Series Ch. 2-3.5", DefaultTitle(manga, ranged))
+	assert.Equal(t, "Series Ch. 3", DefaultTitle(manga, []*source.Chapter{{ChapterNumber: 3}}))
+
+	assert.Equal(t, "Chapter 3", ChapterTitle(chapters[0]))
+	assert.Equal(t, "Extra", ChapterTitle(chapters[3]))
+
+	format, err := ParseFormat("PDF")
+	require.NoError(t, err)
+	assert.Equal(t, FormatPDF, format)
+	_, err = ParseFormat("mobi")
+	assert.Error(t, err)
+}
+
+func TestPDFString(t *testing.T) {
+	assert.Equal(t, `(a \(b\) \\ c)`, pdfString(`a (b) \ c`))
+	assert.Equal(t, "<FEFF30DE30F330AC>", pdfString("マンガ"))
+}
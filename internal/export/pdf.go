@@ -0,0 +1,250 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxPDFPageSize is the largest page dimension PDF readers accept, in points
+const maxPDFPageSize = 14400
+
+// pdfWriter writes numbered PDF objects and remembers their offsets for the
+// cross-reference table
+type pdfWriter struct {
+	w       *bufio.Writer
+	offset  int64
+	offsets []int64 // Offset of each object, indexed by object number - 1
+}
+
+// newObject reserves an object number
+func (pw *pdfWriter) newObject() int {
+	pw.offsets = append(pw.offsets, 0)
+	return len(pw.offsets)
+}
+
+func (pw *pdfWriter) write(s string) {
+	n, _ := pw.w.WriteString(s)
+	pw.offset += int64(n)
+}
+
+func (pw *pdfWriter) writeBytes(data []byte) {
+	n, _ := pw.w.Write(data)
+	pw.offset += int64(n)
+}
+
+// object writes a dictionary object
+func (pw *pdfWriter) object(id int, dict string) {
+	pw.offsets[id-1] = pw.offset
+	pw.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", id, dict))
+}
+
+// stream writes a stream object; dict holds the entries besides /Length
+func (pw *pdfWriter) stream(id int, dict string, data []byte) {
+	pw.offsets[id-1] = pw.offset
+	pw.write(fmt.Sprintf("%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data)))
+	pw.writeBytes(data)
+	pw.write("\nendstream\nendobj\n")
+}
+
+// pdfPage is a page of the PDF and the objects it is made of
+type pdfPage struct {
+	Image     *bookImage
+	PageID    int
+	ContentID int
+	ImageID   int
+}
+
+// writePDF writes an image PDF with one page per image, chapter bookmarks
+// and the book's metadata in the document information dictionary
+func writePDF(w io.Writer, b *book) error {
+	pw := &pdfWriter{w: bufio.NewWriter(w)}
+	pw.write("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	catalogID := pw.newObject()
+	pagesID := pw.newObject()
+	outlinesID := pw.newObject()
+	infoID := pw.newObject()
+
+	newPage := func(img *bookImage) *pdfPage {
+		return &pdfPage{Image: img, PageID: pw.newObject(), ContentID: pw.newObject(), ImageID: pw.newObject()}
+	}
+
+	pages := []*pdfPage{newPage(b.Cover)}
+	chapterStarts := make([]*pdfPage, len(b.Chapters))
+	for i, chapter := range b.Chapters {
+		for j, img := range chapter.Pages {
+			page := newPage(img)
+			if j == 0 {
+				chapterStarts[i] = page
+			}
+			pages = append(pages, page)
+		}
+	}
+
+	outlineIDs := make([]int, len(b.Chapters))
+	for i := range b.Chapters {
+		outlineIDs[i] = pw.newObject()
+	}
+
+	pw.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R /Outlines %d 0 R /PageMode /UseOutlines >>", pagesID, outlinesID))
+
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page.PageID)
+	}
+	pw.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	pw.object(outlinesID, fmt.Sprintf("<< /Type /Outlines /First %d 0 R /Last %d 0 R /Count %d >>",
+		outlineIDs[0], outlineIDs[len(outlineIDs)-1], len(outlineIDs)))
+
+	info := fmt.Sprintf("/Title %s /Creator (Miryokusha) /Producer (Miryokusha) /CreationDate %s",
+		pdfString(b.Title), pdfString(b.Modified.Format("D:20060102150405Z")))
+	if len(b.Authors) > 0 {
+		info += " /Author " + pdfString(strings.Join(b.Authors, ", "))
+	}
+	if b.Description != "" {
+		info += " /Subject " + pdfString(b.Description)
+	}
+	if len(b.Genres) > 0 {
+		info += " /Keywords " + pdfString(strings.Join(b.Genres, ", "))
+	}
+	pw.object(infoID, "<< "+info+" >>")
+
+	for _, page := range pages {
+		if err := writePDFPage(pw, pagesID, page); err != nil {
+			return err
+		}
+	}
+
+	for i, chapter := range b.Chapters {
+		item := fmt.Sprintf("/Title %s /Parent %d 0 R /Dest [%d 0 R /Fit]", pdfString(chapter.Title), outlinesID, chapterStarts[i].PageID)
+		if i > 0 {
+			item += fmt.Sprintf(" /Prev %d 0 R", outlineIDs[i-1])
+		}
+		if i < len(b.Chapters)-1 {
+			item += fmt.Sprintf(" /Next %d 0 R", outlineIDs[i+1])
+		}
+		pw.object(outlineIDs[i], "<< "+item+" >>")
+	}
+
+	// Cross-reference table and trailer
+	xref := pw.offset
+	pw.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1))
+	for _, offset := range pw.offsets {
+		pw.write(fmt.Sprintf("%010d 00000 n \n", offset))
+	}
+	pw.write(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(pw.offsets)+1, catalogID, infoID, xref))
+
+	if err := pw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return nil
+}
+
+// writePDFPage writes a page showing its image over the whole page. Pages
+// are sized at one point per pixel, scaled down to the PDF size limit.
+func writePDFPage(pw *pdfWriter, pagesID int, page *pdfPage) error {
+	dict, data, err := pdfImage(page.Image)
+	if err != nil {
+		return err
+	}
+
+	width, height := float64(page.Image.Width), float64(page.Image.Height)
+	if scale := maxPDFPageSize / max(width, height); scale < 1 {
+		width, height = width*scale, height*scale
+	}
+
+	pw.object(page.PageID, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>",
+		pagesID, width, height, page.ImageID, page.ContentID))
+	pw.stream(page.ContentID, "", []byte(fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q", width, height)))
+	pw.stream(page.ImageID, dict, data)
+	return nil
+}
+
+// pdfImage returns the image XObject dictionary entries and stream data of
+// an image. Grayscale and RGB JPEGs are embedded as they are; other images
+// are flattened onto white and compressed losslessly.
+func pdfImage(img *bookImage) (string, []byte, error) {
+	if img.MediaType == "image/jpeg" {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(img.Data))
+		if err == nil && (cfg.ColorModel == color.GrayModel || cfg.ColorModel == color.YCbCrModel) {
+			colorSpace := "/DeviceRGB"
+			if cfg.ColorModel == color.GrayModel {
+				colorSpace = "/DeviceGray"
+			}
+			return fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode",
+				cfg.Width, cfg.Height, colorSpace), img.Data, nil
+		}
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(img.Data))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := decoded.Bounds()
+	gray := decoded.ColorModel() == color.GrayModel
+	channels, colorSpace := 3, "/DeviceRGB"
+	if gray {
+		channels, colorSpace = 1, "/DeviceGray"
+	}
+
+	raw := make([]byte, 0, bounds.Dx()*bounds.Dy()*channels)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := decoded.At(x, y).RGBA()
+			// Composite the premultiplied color onto white
+			r, g, b = r+0xffff-a, g+0xffff-a, b+0xffff-a
+			if gray {
+				raw = append(raw, byte(r>>8))
+			} else {
+				raw = append(raw, byte(r>>8), byte(g>>8), byte(b>>8))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to compress image: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to compress image: %w", err)
+	}
+
+	return fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /FlateDecode",
+		bounds.Dx(), bounds.Dy(), colorSpace), buf.Bytes(), nil
+}
+
+// pdfString encodes a PDF text string, as UTF-16 when it is not plain ASCII
+func pdfString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 0x7e || (r < 0x20 && r != '\n') {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\n", `\n`)
+		return "(" + replacer.Replace(s) + ")"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", unit)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
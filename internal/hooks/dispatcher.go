@@ -0,0 +1,325 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+)
+
+const (
+	// queueSize is the number of events queued for each hook
+	queueSize = 64
+
+	// defaultTimeout limits attempts of hooks without a timeout
+	defaultTimeout = 10 * time.Second
+
+	// defaultBackoff is the wait before the first retry, doubled for each
+	// further one
+	defaultBackoff = 2 * time.Second
+
+	// maxErrorBody is the amount of a failed response kept in the log
+	maxErrorBody = 256
+)
+
+// DeliveryLog records the results of deliveries
+type DeliveryLog interface {
+	RecordDelivery(d *storage.HookDelivery) error
+}
+
+// hookWorker delivers the events of one hook in order, so a slow hook does
+// not hold up the others
+type hookWorker struct {
+	hook     Hook
+	template *template.Template // nil for the event as JSON
+	events   chan Event
+}
+
+// Dispatcher delivers events to hooks in the background, retrying failed
+// deliveries and recording them in a log
+type Dispatcher struct {
+	mu      sync.Mutex
+	workers []*hookWorker
+	log     DeliveryLog
+	client  *http.Client
+	backoff time.Duration
+	now     func() time.Time
+
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewDispatcher creates a dispatcher for hooks, recording deliveries in log
+// if set. It fails if a hook has no target or an invalid template.
+func NewDispatcher(hooks []Hook, log DeliveryLog) (*Dispatcher, error) {
+	d := &Dispatcher{
+		log:     log,
+		client:  &http.Client{},
+		backoff: defaultBackoff,
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+
+	for _, hook := range hooks {
+		if (hook.URL == "") == (len(hook.Command) == 0) {
+			return nil, fmt.Errorf("hook %s: exactly one of url and command must be set", hook.Name)
+		}
+		w := &hookWorker{hook: hook, events: make(chan Event, queueSize)}
+		if hook.Template != "" {
+			tmpl, err := parseTemplate(hook.Name, hook.Template)
+			if err != nil {
+				return nil, fmt.Errorf("hook %s: %w", hook.Name, err)
+			}
+			w.template = tmpl
+		}
+		d.workers = append(d.workers, w)
+	}
+
+	for _, w := range d.workers {
+		d.wg.Add(1)
+		go d.run(w)
+	}
+	return d, nil
+}
+
+// Fire queues an event for every hook receiving it. It does not block; an
+// event is dropped for a hook whose queue is full. Firing on a nil
+// dispatcher does nothing.
+func (d *Dispatcher) Fire(event Event) {
+	if d == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = d.now()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	for _, w := range d.workers {
+		if !w.hook.accepts(event.Type) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+		}
+	}
+}
+
+// Deliver sends an event to the named hook right away, ignoring the events
+// it is configured for, and returns the logged delivery
+func (d *Dispatcher) Deliver(name string, event Event) (*storage.HookDelivery, error) {
+	for _, w := range d.workers {
+		if w.hook.Name == name {
+			if event.Time.IsZero() {
+				event.Time = d.now()
+			}
+			return d.deliver(w, event), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown hook: %s", name)
+}
+
+// Close stops the dispatcher. Queued events are still attempted once, but
+// failed deliveries are no longer retried.
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.stop)
+	for _, w := range d.workers {
+		close(w.events)
+	}
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// run delivers a hook's events until the dispatcher is closed
+func (d *Dispatcher) run(w *hookWorker) {
+	defer d.wg.Done()
+	for event := range w.events {
+		d.deliver(w, event)
+	}
+}
+
+// deliver sends an event to a hook, retrying with exponential backoff, and
+// records the outcome
+func (d *Dispatcher) deliver(w *hookWorker, event Event) *storage.HookDelivery {
+	start := d.now()
+	delivery := &storage.HookDelivery{
+		Hook:  w.hook.Name,
+		Event: string(event.Type),
+	}
+
+	body, err := payload(w.template, event)
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		backoff := d.backoff
+		for attempt := 0; attempt <= max(w.hook.Retries, 0); attempt++ {
+			if attempt > 0 {
+				select {
+				case <-time.After(backoff):
+				case <-d.stop:
+				}
+				if d.stopped() {
+					break
+				}
+				backoff *= 2
+			}
+
+			delivery.Attempts++
+			status, retry, err := d.attempt(w.hook, event, body)
+			delivery.Status = status
+			if err == nil {
+				delivery.Success = true
+				delivery.Error = ""
+				break
+			}
+			delivery.Error = err.Error()
+			if !retry {
+				break
+			}
+		}
+	}
+
+	delivery.DeliveredAt = d.now()
+	delivery.Duration = delivery.DeliveredAt.Sub(start)
+	if d.log != nil {
+		// A delivery that could not be logged has still happened
+		_ = d.log.RecordDelivery(delivery)
+	}
+	return delivery
+}
+
+// stopped reports whether the dispatcher was closed
+func (d *Dispatcher) stopped() bool {
+	select {
+	case <-d.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// attempt delivers a payload once. It returns the HTTP status or exit code
+// and whether a failure is worth retrying.
+func (d *Dispatcher) attempt(hook Hook, event Event, body []byte) (string, bool, error) {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if hook.URL != "" {
+		return d.post(ctx, hook, body)
+	}
+	return runCommand(ctx, hook, event, body)
+}
+
+// post sends a payload to a webhook. Server errors, rate limiting and
+// network failures are retried; other client errors are not.
+func (d *Dispatcher) post(ctx context.Context, hook Hook, body []byte) (string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Miryokusha")
+	for key, value := range hook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.Status, false, nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	err = fmt.Errorf("webhook returned %s", resp.Status)
+	if text := strings.TrimSpace(string(snippet)); text != "" {
+		err = fmt.Errorf("webhook returned %s: %s", resp.Status, text)
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return resp.Status, retry, err
+}
+
+// runCommand runs a command hook. Commands that exit with an error or time
+// out are retried; commands that cannot be started are not.
+func runCommand(ctx context.Context, hook Hook, event Event, body []byte) (string, bool, error) {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = append(os.Environ(), commandEnv(event)...)
+	cmd.Stdin = bytes.NewReader(body)
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return "exit status 0", false, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return "", false, fmt.Errorf("failed to run command: %w", err)
+	}
+	if ctx.Err() != nil {
+		return exitErr.Error(), true, fmt.Errorf("command timed out: %w", ctx.Err())
+	}
+
+	if len(output) > maxErrorBody {
+		output = output[:maxErrorBody]
+	}
+	if text := strings.TrimSpace(string(output)); text != "" {
+		return exitErr.Error(), true, fmt.Errorf("command failed: %w: %s", err, text)
+	}
+	return exitErr.Error(), true, fmt.Errorf("command failed: %w", err)
+}
+
+// commandEnv returns the environment variables describing an event
+func commandEnv(event Event) []string {
+	env := []string{
+		"MIRYOKUSHA_EVENT=" + string(event.Type),
+		"MIRYOKUSHA_TIME=" + event.Time.Format(time.RFC3339),
+		"MIRYOKUSHA_TITLE=" + event.Title,
+		"MIRYOKUSHA_MESSAGE=" + event.Message,
+		"MIRYOKUSHA_MANGA_ID=" + event.MangaID,
+		"MIRYOKUSHA_MANGA_TITLE=" + event.MangaTitle,
+		"MIRYOKUSHA_CHAPTER_ID=" + event.ChapterID,
+		"MIRYOKUSHA_CHAPTER_NAME=" + event.ChapterName,
+		"MIRYOKUSHA_ERROR=" + event.Error,
+	}
+	if event.ChapterNumber != 0 {
+		env = append(env, "MIRYOKUSHA_CHAPTER_NUMBER="+strconv.FormatFloat(event.ChapterNumber, 'f', -1, 64))
+	}
+	if event.Count != 0 {
+		env = append(env, "MIRYOKUSHA_COUNT="+strconv.Itoa(event.Count))
+	}
+	return env
+}
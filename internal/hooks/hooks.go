@@ -0,0 +1,108 @@
+// Package hooks sends library events to webhooks and local commands, so
+// other tools can react to new chapters, finished downloads and the like
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// EventType is the kind of library event a hook receives
+type EventType string
+
+const (
+	EventNewChapters      EventType = "new_chapters"
+	EventChapterCompleted EventType = "chapter_completed"
+	EventDownloadComplete EventType = "download_complete"
+	EventDownloadFailed   EventType = "download_failed"
+	EventServerDown       EventType = "server_down"
+	EventServerUp         EventType = "server_up"
+)
+
+// EventTypes lists the events hooks can receive
+var EventTypes = []EventType{
+	EventNewChapters,
+	EventChapterCompleted,
+	EventDownloadComplete,
+	EventDownloadFailed,
+	EventServerDown,
+	EventServerUp,
+}
+
+// Event is a library event. It is the default JSON payload of webhooks and
+// the data of payload templates.
+type Event struct {
+	Type          EventType `json:"event"`
+	Time          time.Time `json:"time"`
+	Title         string    `json:"title"`
+	Message       string    `json:"message"`
+	MangaID       string    `json:"manga_id,omitempty"`
+	MangaTitle    string    `json:"manga_title,omitempty"`
+	ChapterID     string    `json:"chapter_id,omitempty"`
+	ChapterName   string    `json:"chapter_name,omitempty"`
+	ChapterNumber float64   `json:"chapter_number,omitempty"`
+	Count         int       `json:"count,omitempty"` // New chapters found
+	Error         string    `json:"error,omitempty"`
+}
+
+// Hook is a webhook or a command receiving library events
+type Hook struct {
+	Name   string
+	Events map[EventType]bool // Events received, empty for all
+
+	// Webhooks POST the payload to URL
+	URL     string
+	Headers map[string]string
+
+	// Commands run with the payload on stdin and the event in MIRYOKUSHA_*
+	// environment variables. The arguments are not templated, so event
+	// data never reaches a shell.
+	Command []string
+
+	Template string        // Payload template, empty for the event as JSON
+	Retries  int           // Attempts after the first failed one
+	Timeout  time.Duration // Limit of each attempt
+}
+
+// accepts reports whether the hook receives an event
+func (h *Hook) accepts(event EventType) bool {
+	return len(h.Events) == 0 || h.Events[event]
+}
+
+// templateFuncs are available in payload templates
+var templateFuncs = template.FuncMap{
+	// json quotes a value for JSON payloads, e.g. {"content": {{json .Title}}}
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// parseTemplate parses a payload template
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payload template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// payload renders the payload of an event
+func payload(tmpl *template.Template, event Event) ([]byte, error) {
+	if tmpl == nil {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event: %w", err)
+		}
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("failed to render payload template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
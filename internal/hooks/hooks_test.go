@@ -0,0 +1,252 @@
+package hooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLog keeps deliveries in memory
+type memoryLog struct {
+	mu         sync.Mutex
+	deliveries []*storage.HookDelivery
+}
+
+func (l *memoryLog) RecordDelivery(d *storage.HookDelivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.deliveries = append(l.deliveries, d)
+	return nil
+}
+
+// receiver is a webhook endpoint answering with the given statuses in turn
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, string(body))
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+	if status >= 400 {
+		_, _ = w.Write([]byte("try later"))
+	}
+}
+
+func newTestDispatcher(t *testing.T, hooks []Hook, log DeliveryLog) *Dispatcher {
+	t.Helper()
+	d, err := NewDispatcher(hooks, log)
+	require.NoError(t, err)
+	d.backoff = time.Millisecond
+	return d
+}
+
+var testEvent = Event{
+	Type:          EventNewChapters,
+	Time:          time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC),
+	Title:         "One Piece",
+	Message:       "2 new chapter(s) available",
+	MangaID:       "42",
+	MangaTitle:    "One Piece",
+	ChapterID:     "1101",
+	ChapterNumber: 1101,
+	Count:         2,
+}
+
+func TestDispatcher_Webhook(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	log := &memoryLog{}
+	d := newTestDispatcher(t, []Hook{
+		{Name: "json", URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}},
+		{
+			Name:     "chat",
+			URL:      server.URL + "/chat",
+			Events:   map[EventType]bool{EventNewChapters: true},
+			Template: `{"content": {{json (printf "%s: %s" .Title .Message)}}}`,
+		},
+	}, log)
+
+	d.Fire(testEvent)
+	d.Fire(Event{Type: EventServerDown, Title: "Server Unreachable"})
+	d.Close()
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	require.Len(t, recv.requests, 3)
+
+	var chat string
+	var events []Event
+	for i, req := range recv.requests {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		if req.URL.Path == "/chat" {
+			chat = recv.bodies[i]
+			continue
+		}
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(recv.bodies[i]), &event))
+		events = append(events, event)
+	}
+	assert.Equal(t, `{"content": "One Piece: 2 new chapter(s) available"}`, chat)
+	require.Len(t, events, 2)
+	assert.Equal(t, testEvent, events[0])
+	assert.Equal(t, EventServerDown, events[1].Type)
+	assert.False(t, events[1].Time.IsZero(), "fired events are timestamped")
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	require.Len(t, log.deliveries, 3)
+	for _, delivery := range log.deliveries {
+		assert.True(t, delivery.Success)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, "200 OK", delivery.Status)
+	}
+}
+
+func TestDispatcher_Retries(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	d := newTestDispatcher(t, []Hook{
+		{Name: "flaky", URL: server.URL, Retries: 3},
+		{Name: "once", URL: server.URL, Retries: 1},
+	}, nil)
+	defer d.Close()
+
+	delivery, err := d.Deliver("flaky", testEvent)
+	require.NoError(t, err)
+	assert.True(t, delivery.Success)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Empty(t, delivery.Error)
+
+	// Client errors other than rate limiting are not retried
+	recv.mu.Lock()
+	recv.statuses = []int{http.StatusBadRequest, http.StatusOK}
+	recv.mu.Unlock()
+	delivery, err = d.Deliver("flaky", testEvent)
+	require.NoError(t, err)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, "400 Bad Request", delivery.Status)
+	assert.Equal(t, "webhook returned 400 Bad Request: try later", delivery.Error)
+
+	// Retries run out
+	recv.mu.Lock()
+	recv.statuses = []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK}
+	recv.mu.Unlock()
+	delivery, err = d.Deliver("once", testEvent)
+	require.NoError(t, err)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, "503 Service Unavailable", delivery.Status)
+
+	_, err = d.Deliver("missing", testEvent)
+	assert.Error(t, err)
+}
+
+func TestDispatcher_Command(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	out := t.TempDir() + "/event"
+
+	d := newTestDispatcher(t, []Hook{
+		{Name: "script", Command: []string{"sh", "-c", `printf '%s|%s|%s|' "$MIRYOKUSHA_EVENT" "$MIRYOKUSHA_MANGA_ID" "$MIRYOKUSHA_COUNT" > "$0" && cat >> "$0"`, out}},
+		{Name: "failing", Command: []string{"sh", "-c", "echo broken >&2; exit 3"}, Retries: 2},
+		{Name: "missing", Command: []string{"miryokusha-no-such-command"}, Retries: 2},
+	}, nil)
+	defer d.Close()
+
+	delivery, err := d.Deliver("script", testEvent)
+	require.NoError(t, err)
+	require.True(t, delivery.Success, delivery.Error)
+	assert.Equal(t, "exit status 0", delivery.Status)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	prefix, body, _ := strings.Cut(string(data), "|42|2|")
+	assert.Equal(t, "new_chapters", prefix)
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(body), &event))
+	assert.Equal(t, testEvent, event)
+
+	delivery, err = d.Deliver("failing", testEvent)
+	require.NoError(t, err)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, "exit status 3", delivery.Status)
+	assert.Equal(t, "command failed: exit status 3: broken", delivery.Error)
+
+	// Commands that cannot be started are not retried
+	delivery, err = d.Deliver("missing", testEvent)
+	require.NoError(t, err)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 1, delivery.Attempts)
+}
+
+func TestNewDispatcher_Invalid(t *testing.T) {
+	_, err := NewDispatcher([]Hook{{Name: "none"}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewDispatcher([]Hook{{Name: "both", URL: "http://localhost", Command: []string{"true"}}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewDispatcher([]Hook{{Name: "bad", URL: "http://localhost", Template: "{{.Title"}}, nil)
+	assert.Error(t, err)
+
+	// Unknown fields fail when rendering
+	d := newTestDispatcher(t, []Hook{{Name: "typo", URL: "http://localhost", Template: "{{.Titel}}"}}, nil)
+	defer d.Close()
+	delivery, err := d.Deliver("typo", testEvent)
+	require.NoError(t, err)
+	assert.False(t, delivery.Success)
+	assert.Zero(t, delivery.Attempts)
+	assert.Contains(t, delivery.Error, "failed to render payload template")
+}
+
+func TestDispatcher_NilAndClosed(t *testing.T) {
+	var d *Dispatcher
+	d.Fire(testEvent)
+	d.Close()
+
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	d = newTestDispatcher(t, []Hook{{Name: "json", URL: server.URL}}, nil)
+	d.Close()
+	d.Fire(testEvent)
+	d.Close()
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	assert.Empty(t, recv.requests)
+}
@@ -0,0 +1,300 @@
+// Package imageproc prepares page images for reading devices: it splits
+// double-page spreads, crops white borders, converts to grayscale, scales
+// down and re-encodes pages
+package imageproc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"sort"
+
+	_ "image/gif"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
+)
+
+// Format is the encoding of processed pages
+type Format string
+
+const (
+	FormatOriginal Format = ""     // Keep the page's encoding, PNG for formats that cannot be written
+	FormatJPEG     Format = "jpeg" // JPEG at the profile's quality
+	FormatPNG      Format = "png"  // Lossless PNG
+)
+
+// DefaultQuality is the JPEG quality used when a profile sets none
+const DefaultQuality = 90
+
+// whiteThreshold is the gray level above which border pixels count as white
+const whiteThreshold = 235
+
+// Profile describes how pages are processed for a reading device. The zero
+// profile leaves pages untouched.
+type Profile struct {
+	Name         string
+	SplitSpreads bool   // Split landscape double-page spreads into two pages
+	RightToLeft  bool   // Order split pages right half first, as manga are read
+	CropBorders  bool   // Remove white margins
+	Grayscale    bool   // Convert to grayscale, e.g. for e-ink screens
+	MaxWidth     int    // Scale down to fit this width, 0 for no limit
+	MaxHeight    int    // Scale down to fit this height, 0 for no limit
+	Format       Format // Output encoding
+	Quality      int    // JPEG quality from 1 to 100, 0 for DefaultQuality
+}
+
+// Presets are the built-in profiles for common devices
+var Presets = map[string]Profile{
+	"kindle-paperwhite": {
+		Name: "kindle-paperwhite", SplitSpreads: true, RightToLeft: true, CropBorders: true,
+		Grayscale: true, MaxWidth: 1236, MaxHeight: 1648, Format: FormatJPEG, Quality: 85,
+	},
+	"kobo-clara": {
+		Name: "kobo-clara", SplitSpreads: true, RightToLeft: true, CropBorders: true,
+		Grayscale: true, MaxWidth: 1072, MaxHeight: 1448, Format: FormatJPEG, Quality: 85,
+	},
+	"kobo-libra": {
+		Name: "kobo-libra", SplitSpreads: true, RightToLeft: true, CropBorders: true,
+		Grayscale: true, MaxWidth: 1264, MaxHeight: 1680, Format: FormatJPEG, Quality: 85,
+	},
+	"remarkable": {
+		Name: "remarkable", SplitSpreads: true, RightToLeft: true, CropBorders: true,
+		Grayscale: true, MaxWidth: 1404, MaxHeight: 1872, Format: FormatJPEG, Quality: 85,
+	},
+	"tablet": {
+		Name: "tablet", CropBorders: true, MaxWidth: 1600, MaxHeight: 2560,
+		Format: FormatJPEG, Quality: 90,
+	},
+}
+
+// PresetNames returns the names of the built-in profiles in sorted order
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the named profile, preferring custom profiles over presets
+func Lookup(name string, custom map[string]Profile) (*Profile, error) {
+	if p, ok := custom[name]; ok {
+		p.Name = name
+		return &p, nil
+	}
+	if p, ok := Presets[name]; ok {
+		return &p, nil
+	}
+	return nil, fmt.Errorf("unknown image profile: %s", name)
+}
+
+// IsZero reports whether the profile leaves pages untouched
+func (p *Profile) IsZero() bool {
+	return p == nil || (!p.SplitSpreads && !p.CropBorders && !p.Grayscale &&
+		p.MaxWidth == 0 && p.MaxHeight == 0 && p.Format == FormatOriginal)
+}
+
+// Validate checks that the profile can be applied
+func (p *Profile) Validate() error {
+	switch p.Format {
+	case FormatOriginal, FormatJPEG, FormatPNG:
+	default:
+		return fmt.Errorf("image profile %s: unknown format %q (use jpeg or png)", p.Name, p.Format)
+	}
+	if p.Quality < 0 || p.Quality > 100 {
+		return fmt.Errorf("image profile %s: quality must be between 1 and 100", p.Name)
+	}
+	if p.MaxWidth < 0 || p.MaxHeight < 0 {
+		return fmt.Errorf("image profile %s: max size cannot be negative", p.Name)
+	}
+	return nil
+}
+
+// Page is a processed page image
+type Page struct {
+	Data []byte
+	Ext  string // File extension including the dot, e.g. ".jpg"
+}
+
+// Process applies the profile to a page image. Spreads split into two
+// pages; otherwise one page is returned. Zero profiles return the data as is.
+func Process(data []byte, p *Profile) ([]Page, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if p.IsZero() {
+		return []Page{{Data: data, Ext: extension(format)}}, nil
+	}
+
+	if p.CropBorders {
+		img = cropBorders(img)
+	}
+
+	images := []image.Image{img}
+	if p.SplitSpreads {
+		images = splitSpread(img, p.RightToLeft)
+	}
+
+	pages := make([]Page, 0, len(images))
+	for _, img := range images {
+		if p.Grayscale {
+			img = toGray(img)
+		}
+		img = fit(img, p.MaxWidth, p.MaxHeight)
+
+		page, err := encode(img, format, p)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// cropBorders removes white rows and columns around the content. Blank
+// pages are kept as they are.
+func cropBorders(img image.Image) image.Image {
+	b := img.Bounds()
+	isWhite := func(x, y int) bool {
+		gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+		return gray.Y >= whiteThreshold
+	}
+	rowWhite := func(y int) bool {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if !isWhite(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+	colWhite := func(x, minY, maxY int) bool {
+		for y := minY; y < maxY; y++ {
+			if !isWhite(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+
+	top, bottom := b.Min.Y, b.Max.Y
+	for top < bottom && rowWhite(top) {
+		top++
+	}
+	if top == bottom {
+		return img
+	}
+	for bottom > top && rowWhite(bottom-1) {
+		bottom--
+	}
+
+	left, right := b.Min.X, b.Max.X
+	for left < right && colWhite(left, top, bottom) {
+		left++
+	}
+	for right > left && colWhite(right-1, top, bottom) {
+		right--
+	}
+
+	crop := image.Rect(left, top, right, bottom)
+	if crop == b {
+		return img
+	}
+	return imaging.Crop(img, crop)
+}
+
+// splitSpread splits a landscape image into its two pages, in reading order
+func splitSpread(img image.Image, rightToLeft bool) []image.Image {
+	b := img.Bounds()
+	if b.Dx() <= b.Dy() {
+		return []image.Image{img}
+	}
+
+	mid := b.Min.X + b.Dx()/2
+	left := imaging.Crop(img, image.Rect(b.Min.X, b.Min.Y, mid, b.Max.Y))
+	right := imaging.Crop(img, image.Rect(mid, b.Min.Y, b.Max.X, b.Max.Y))
+	if rightToLeft {
+		return []image.Image{right, left}
+	}
+	return []image.Image{left, right}
+}
+
+// toGray converts an image to 8-bit grayscale, which also halves the size
+// of JPEGs compared to RGB
+func toGray(img image.Image) image.Image {
+	if _, ok := img.(*image.Gray); ok {
+		return img
+	}
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Src)
+	return gray
+}
+
+// fit scales an image down to fit within maxWidth x maxHeight, keeping its
+// aspect ratio. Images are never scaled up.
+func fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	if maxWidth <= 0 {
+		maxWidth = b.Dx()
+	}
+	if maxHeight <= 0 {
+		maxHeight = b.Dy()
+	}
+	if b.Dx() <= maxWidth && b.Dy() <= maxHeight {
+		return img
+	}
+
+	_, gray := img.(*image.Gray)
+	resized := imaging.Fit(img, maxWidth, maxHeight, imaging.Lanczos)
+	if gray {
+		return toGray(resized)
+	}
+	return resized
+}
+
+// encode writes an image in the profile's format
+func encode(img image.Image, original string, p *Profile) (Page, error) {
+	format := p.Format
+	if format == FormatOriginal {
+		format = FormatPNG
+		if original == "jpeg" {
+			format = FormatJPEG
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case FormatJPEG:
+		quality := p.Quality
+		if quality == 0 {
+			quality = DefaultQuality
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return Page{}, fmt.Errorf("failed to encode JPEG: %w", err)
+		}
+		return Page{Data: buf.Bytes(), Ext: ".jpg"}, nil
+	case FormatPNG:
+		if err := png.Encode(&buf, img); err != nil {
+			return Page{}, fmt.Errorf("failed to encode PNG: %w", err)
+		}
+		return Page{Data: buf.Bytes(), Ext: ".png"}, nil
+	default:
+		return Page{}, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// extension returns the file extension of a decoded image format
+func extension(format string) string {
+	if format == "jpeg" {
+		return ".jpg"
+	}
+	return "." + format
+}
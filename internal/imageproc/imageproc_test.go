@@ -0,0 +1,118 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spread builds a white 200x100 PNG with a red block on the left page and
+// a blue block on the right page, each inside a 10px white margin
+func spread(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			c := color.RGBA{255, 255, 255, 255}
+			if y >= 10 && y < 90 {
+				if x >= 10 && x < 100 {
+					c = color.RGBA{200, 0, 0, 255}
+				} else if x >= 100 && x < 190 {
+					c = color.RGBA{0, 0, 200, 255}
+				}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, page Page) image.Image {
+	t.Helper()
+	img, _, err := image.Decode(bytes.NewReader(page.Data))
+	require.NoError(t, err)
+	return img
+}
+
+func TestProcess_ZeroProfileKeepsData(t *testing.T) {
+	data := spread(t)
+	pages, err := Process(data, &Profile{})
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Equal(t, data, pages[0].Data)
+	assert.Equal(t, ".png", pages[0].Ext)
+}
+
+func TestProcess_SplitAndCrop(t *testing.T) {
+	pages, err := Process(spread(t), &Profile{SplitSpreads: true, RightToLeft: true, CropBorders: true, Format: FormatPNG})
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+
+	// The margins are cropped to 180x80 before splitting into two 90x80 pages
+	first, second := decode(t, pages[0]), decode(t, pages[1])
+	assert.Equal(t, image.Pt(90, 80), first.Bounds().Size())
+	assert.Equal(t, image.Pt(90, 80), second.Bounds().Size())
+
+	// Right to left puts the right (blue) page first
+	r, _, b, _ := first.At(45, 40).RGBA()
+	assert.Greater(t, b, r)
+	r, _, b, _ = second.At(45, 40).RGBA()
+	assert.Greater(t, r, b)
+
+	pages, err = Process(spread(t), &Profile{SplitSpreads: true, Format: FormatPNG})
+	require.NoError(t, err)
+	r, _, b, _ = decode(t, pages[0]).At(45, 40).RGBA()
+	assert.Greater(t, r, b, "left to right starts with the left page")
+}
+
+func TestProcess_GrayscaleResizeJPEG(t *testing.T) {
+	pages, err := Process(spread(t), &Profile{Grayscale: true, MaxWidth: 100, MaxHeight: 100, Format: FormatJPEG, Quality: 80})
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Equal(t, ".jpg", pages[0].Ext)
+
+	img := decode(t, pages[0])
+	assert.Equal(t, image.Pt(100, 50), img.Bounds().Size())
+	assert.Equal(t, color.GrayModel, img.ColorModel())
+}
+
+func TestProcess_PortraitIsNotSplit(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 50, 80))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	pages, err := Process(buf.Bytes(), &Profile{SplitSpreads: true, MaxWidth: 500})
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Equal(t, image.Pt(50, 80), decode(t, pages[0]).Bounds().Size(), "never scaled up")
+}
+
+func TestLookupAndValidate(t *testing.T) {
+	custom := map[string]Profile{"kobo-clara": {Grayscale: true}}
+
+	p, err := Lookup("kobo-clara", custom)
+	require.NoError(t, err)
+	assert.Equal(t, "kobo-clara", p.Name)
+	assert.Zero(t, p.MaxWidth, "custom profiles override presets")
+
+	p, err = Lookup("kindle-paperwhite", custom)
+	require.NoError(t, err)
+	assert.Equal(t, 1236, p.MaxWidth)
+
+	_, err = Lookup("missing", custom)
+	assert.Error(t, err)
+
+	for _, name := range PresetNames() {
+		preset := Presets[name]
+		assert.NoError(t, preset.Validate(), name)
+	}
+	assert.Error(t, (&Profile{Format: "webp"}).Validate(), "there is no webp encoder")
+	assert.Error(t, (&Profile{Quality: 101}).Validate())
+}
@@ -0,0 +1,253 @@
+// Package notify tells the user about events while Miryokusha runs in the
+// background: through the terminal, the desktop's notification daemon or
+// the terminal bell
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is what a message reports, e.g. "new_chapters". Events match the
+// notification kinds of the notification center.
+type Event string
+
+// Message is a notification sent to the user
+type Message struct {
+	Event Event
+	Title string
+	Body  string
+}
+
+// Notifier delivers messages to the user
+type Notifier interface {
+	Name() string
+	Notify(msg Message) error
+}
+
+// Route sends the messages of some events to a notifier
+type Route struct {
+	Notifier Notifier
+	Events   map[Event]bool // Events delivered by the notifier
+}
+
+// accepts reports whether the route delivers an event
+func (r Route) accepts(event Event) bool {
+	return r.Events[event]
+}
+
+// deliveryBuffer is the number of messages queued for delivery
+const deliveryBuffer = 64
+
+// Dispatcher fans messages out to notifiers in the background. At most
+// limit messages are delivered per window; the rest are summarized in one
+// message when the window ends.
+type Dispatcher struct {
+	mu     sync.Mutex
+	routes []Route
+	limit  int
+	window time.Duration
+
+	// Rate limiting
+	windowStart time.Time
+	sent        int
+	suppressed  map[Event]int
+	flushTimer  *time.Timer
+
+	now        func() time.Time
+	deliveries chan delivery
+	done       chan struct{}
+	closed     bool
+	onError    func(Notifier, error)
+}
+
+// delivery is a message queued for a notifier
+type delivery struct {
+	notifier Notifier
+	msg      Message
+}
+
+// NewDispatcher creates a dispatcher delivering at most limit messages per
+// window, or every message if limit is 0
+func NewDispatcher(routes []Route, limit int, window time.Duration) *Dispatcher {
+	d := &Dispatcher{
+		routes:     routes,
+		limit:      limit,
+		window:     window,
+		suppressed: make(map[Event]int),
+		now:        time.Now,
+		deliveries: make(chan delivery, deliveryBuffer),
+		done:       make(chan struct{}),
+	}
+	go d.deliver()
+	return d
+}
+
+// SetErrorHandler sets a function called when a notifier fails
+func (d *Dispatcher) SetErrorHandler(onError func(Notifier, error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onError = onError
+}
+
+// Notify queues a message for every notifier that delivers its event. It
+// does not block.
+func (d *Dispatcher) Notify(msg Message) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed || !d.routed(msg.Event) {
+		return
+	}
+
+	if d.limit > 0 {
+		now := d.now()
+		if d.windowStart.IsZero() || now.Sub(d.windowStart) >= d.window {
+			d.windowStart = now
+			d.sent = 0
+		}
+		if d.sent >= d.limit {
+			d.suppressed[msg.Event]++
+			if d.flushTimer == nil {
+				d.flushTimer = time.AfterFunc(d.window-now.Sub(d.windowStart), d.Flush)
+			}
+			return
+		}
+		d.sent++
+	}
+
+	d.queue(msg)
+}
+
+// Flush delivers a summary of the messages suppressed by the rate limit
+// and starts a new window
+func (d *Dispatcher) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.flushTimer != nil {
+		d.flushTimer.Stop()
+		d.flushTimer = nil
+	}
+	if d.closed || len(d.suppressed) == 0 {
+		return
+	}
+
+	summary := summarize(d.suppressed)
+	for _, route := range d.routes {
+		for event := range d.suppressed {
+			if route.accepts(event) {
+				d.enqueue(route.Notifier, summary)
+				break
+			}
+		}
+	}
+
+	d.suppressed = make(map[Event]int)
+	d.windowStart = d.now()
+	d.sent = 0
+}
+
+// Close stops the dispatcher after delivering the queued messages.
+// Messages suppressed by the rate limit are dropped.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	if d.flushTimer != nil {
+		d.flushTimer.Stop()
+		d.flushTimer = nil
+	}
+	close(d.deliveries)
+	d.mu.Unlock()
+
+	<-d.done
+}
+
+// routed reports whether any notifier delivers an event
+func (d *Dispatcher) routed(event Event) bool {
+	for _, route := range d.routes {
+		if route.accepts(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// queue queues a message for the notifiers of its event
+func (d *Dispatcher) queue(msg Message) {
+	for _, route := range d.routes {
+		if route.accepts(msg.Event) {
+			d.enqueue(route.Notifier, msg)
+		}
+	}
+}
+
+// enqueue queues a delivery, dropping it if the queue is full
+func (d *Dispatcher) enqueue(notifier Notifier, msg Message) {
+	select {
+	case d.deliveries <- delivery{notifier: notifier, msg: msg}:
+	default:
+	}
+}
+
+// deliver sends queued messages until the dispatcher is closed
+func (d *Dispatcher) deliver() {
+	defer close(d.done)
+	for del := range d.deliveries {
+		if err := del.notifier.Notify(del.msg); err != nil {
+			d.mu.Lock()
+			onError := d.onError
+			d.mu.Unlock()
+			if onError != nil {
+				onError(del.notifier, err)
+			}
+		}
+	}
+}
+
+// eventNames names events in summaries
+var eventNames = map[Event]string{
+	"new_chapters":      "manga with new chapters",
+	"update_failed":     "failed update checks",
+	"download_complete": "finished downloads",
+	"download_failed":   "failed downloads",
+	"server_status":     "server status changes",
+	"extension_update":  "extension updates",
+	"error":             "errors",
+}
+
+// summarize describes the suppressed messages of each event
+func summarize(suppressed map[Event]int) Message {
+	events := make([]Event, 0, len(suppressed))
+	total := 0
+	for event, count := range suppressed {
+		events = append(events, event)
+		total += count
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+
+	parts := make([]string, 0, len(events))
+	for _, event := range events {
+		name, ok := eventNames[event]
+		if !ok {
+			name = string(event)
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", suppressed[event], name))
+	}
+
+	msg := Message{
+		Title: fmt.Sprintf("%d more notifications", total),
+		Body:  strings.Join(parts, ", "),
+	}
+	if len(events) == 1 {
+		msg.Event = events[0]
+	}
+	return msg
+}
@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a notifier remembering its messages
+type recorder struct {
+	mu       sync.Mutex
+	name     string
+	messages []Message
+	err      error
+}
+
+func (r *recorder) Name() string { return r.name }
+
+func (r *recorder) Notify(msg Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return r.err
+}
+
+func (r *recorder) titles() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	titles := make([]string, len(r.messages))
+	for i, msg := range r.messages {
+		titles[i] = msg.Title
+	}
+	return titles
+}
+
+func TestDispatcher_Routes(t *testing.T) {
+	chapters := &recorder{name: "chapters"}
+	downloads := &recorder{name: "downloads"}
+	d := NewDispatcher([]Route{
+		{Notifier: chapters, Events: map[Event]bool{"new_chapters": true}},
+		{Notifier: downloads, Events: map[Event]bool{"new_chapters": true, "download_complete": true}},
+	}, 0, time.Minute)
+
+	d.Notify(Message{Event: "new_chapters", Title: "Alpha"})
+	d.Notify(Message{Event: "download_complete", Title: "Beta"})
+	d.Notify(Message{Event: "server_status", Title: "Ignored"})
+	d.Close()
+
+	assert.Equal(t, []string{"Alpha"}, chapters.titles())
+	assert.Equal(t, []string{"Alpha", "Beta"}, downloads.titles())
+
+	// Closed dispatchers drop messages
+	d.Notify(Message{Event: "new_chapters", Title: "Late"})
+	assert.Len(t, chapters.titles(), 1)
+}
+
+func TestDispatcher_RateLimit(t *testing.T) {
+	r := &recorder{name: "r"}
+	d := NewDispatcher([]Route{
+		{Notifier: r, Events: map[Event]bool{"new_chapters": true, "download_complete": true}},
+	}, 3, time.Minute)
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return clock }
+
+	// A 200-chapter update sends three messages, then one summary
+	for i := 0; i < 200; i++ {
+		d.Notify(Message{Event: "new_chapters", Title: "Manga"})
+	}
+	d.Notify(Message{Event: "download_complete", Title: "Download"})
+	d.Flush()
+
+	// The window restarts after the summary
+	d.Notify(Message{Event: "new_chapters", Title: "Next"})
+
+	// and once it has passed
+	clock = clock.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		d.Notify(Message{Event: "new_chapters", Title: "Later"})
+	}
+	d.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	require.Len(t, r.messages, 8)
+	summary := r.messages[3]
+	assert.Equal(t, "198 more notifications", summary.Title)
+	assert.Equal(t, "1 finished downloads, 197 manga with new chapters", summary.Body)
+	assert.Equal(t, Event(""), summary.Event, "summaries of several events have none")
+	assert.Equal(t, "Next", r.messages[4].Title)
+	assert.Equal(t, "Later", r.messages[7].Title)
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	failing := &recorder{name: "failing", err: errors.New("no display")}
+	d := NewDispatcher([]Route{{Notifier: failing, Events: map[Event]bool{"error": true}}}, 0, time.Minute)
+
+	var mu sync.Mutex
+	var failures []string
+	d.SetErrorHandler(func(n Notifier, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, n.Name()+": "+err.Error())
+	})
+	d.Notify(Message{Event: "error", Title: "Problem"})
+	d.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"failing: no display"}, failures)
+}
+
+func TestTerminalNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	msg := Message{Title: "One Piece", Body: "2 new\nchapter(s)\x1b available"}
+
+	osc9, err := NewTerminalNotifier(&buf, ProtocolOSC9, false)
+	require.NoError(t, err)
+	require.NoError(t, osc9.Notify(msg))
+	assert.Equal(t, "\x1b]9;One Piece: 2 new chapter(s) available\x07", buf.String())
+
+	buf.Reset()
+	osc777, err := NewTerminalNotifier(&buf, ProtocolOSC777, false)
+	require.NoError(t, err)
+	require.NoError(t, osc777.Notify(Message{Title: "A; B", Body: "body"}))
+	assert.Equal(t, "\x1b]777;notify;A, B;body\x07", buf.String())
+
+	buf.Reset()
+	tmux, err := NewTerminalNotifier(&buf, ProtocolOSC9, true)
+	require.NoError(t, err)
+	require.NoError(t, tmux.Notify(Message{Title: "Done"}))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]9;Done\x07\x1b\\", buf.String())
+
+	_, err = NewTerminalNotifier(&buf, "osc99", false)
+	assert.Error(t, err)
+
+	buf.Reset()
+	require.NoError(t, NewBellNotifier(&buf).Notify(msg))
+	assert.Equal(t, "\a", buf.String())
+}
+
+func TestNotifySendArgs(t *testing.T) {
+	assert.Equal(t,
+		[]string{"--app-name=Miryokusha", "--", "-Title", "Body"},
+		notifySendArgs(Message{Title: "-Title", Body: "Body"}))
+	assert.Equal(t,
+		[]string{"--app-name=Miryokusha", "--", "Title"},
+		notifySendArgs(Message{Title: "Title"}))
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// notifySendTimeout bounds how long a notify-send call may take
+const notifySendTimeout = 5 * time.Second
+
+// NotifySendNotifier shows freedesktop notifications with notify-send
+type NotifySendNotifier struct {
+	path string
+}
+
+// NewNotifySendNotifier creates a notifier running notify-send, or returns
+// an error if it is not installed
+func NewNotifySendNotifier() (*NotifySendNotifier, error) {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return nil, fmt.Errorf("notify-send not found: %w", err)
+	}
+	return &NotifySendNotifier{path: path}, nil
+}
+
+// Name returns the notifier's name
+func (n *NotifySendNotifier) Name() string {
+	return "notify-send"
+}
+
+// Notify runs notify-send
+func (n *NotifySendNotifier) Notify(msg Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifySendTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, n.path, notifySendArgs(msg)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send failed: %w: %s", err, output)
+	}
+	return nil
+}
+
+// notifySendArgs returns the arguments of notify-send for a message
+func notifySendArgs(msg Message) []string {
+	args := []string{"--app-name=Miryokusha", "--", msg.Title}
+	if msg.Body != "" {
+		args = append(args, msg.Body)
+	}
+	return args
+}
@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Protocol is the escape sequence a terminal shows notifications for
+type Protocol string
+
+const (
+	ProtocolOSC9   Protocol = "osc9"   // iTerm2, WezTerm, kitty, Windows Terminal, foot
+	ProtocolOSC777 Protocol = "osc777" // urxvt, VTE-based terminals such as GNOME Terminal
+)
+
+// TerminalNotifier shows notifications with terminal escape sequences
+type TerminalNotifier struct {
+	mu       sync.Mutex
+	w        io.Writer
+	protocol Protocol
+	tmux     bool // Wrap sequences so tmux passes them to the terminal
+}
+
+// NewTerminalNotifier creates a notifier writing escape sequences to w.
+// Inside tmux, sequences are passed through, which needs tmux's
+// allow-passthrough option.
+func NewTerminalNotifier(w io.Writer, protocol Protocol, tmux bool) (*TerminalNotifier, error) {
+	switch protocol {
+	case ProtocolOSC9, ProtocolOSC777:
+	default:
+		return nil, fmt.Errorf("unknown terminal protocol: %s (must be 'osc9' or 'osc777')", protocol)
+	}
+	return &TerminalNotifier{w: w, protocol: protocol, tmux: tmux}, nil
+}
+
+// Name returns the notifier's name
+func (n *TerminalNotifier) Name() string {
+	return "terminal"
+}
+
+// Notify writes the notification sequence
+func (n *TerminalNotifier) Notify(msg Message) error {
+	title, body := sanitize(msg.Title), sanitize(msg.Body)
+
+	var seq string
+	switch n.protocol {
+	case ProtocolOSC777:
+		seq = fmt.Sprintf("\x1b]777;notify;%s;%s\x07", strings.ReplaceAll(title, ";", ","), body)
+	default:
+		// OSC 9 has no title
+		text := title
+		if body != "" {
+			text += ": " + body
+		}
+		seq = "\x1b]9;" + text + "\x07"
+	}
+	if n.tmux {
+		seq = tmuxPassthrough(seq)
+	}
+	return n.write(seq)
+}
+
+// write writes a sequence in one call so it is not split by other output
+func (n *TerminalNotifier) write(seq string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := io.WriteString(n.w, seq); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
+
+// BellNotifier rings the terminal bell, which most terminals and tmux
+// turn into an alert for the window
+type BellNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewBellNotifier creates a notifier ringing the bell on w
+func NewBellNotifier(w io.Writer) *BellNotifier {
+	return &BellNotifier{w: w}
+}
+
+// Name returns the notifier's name
+func (n *BellNotifier) Name() string {
+	return "bell"
+}
+
+// Notify rings the bell
+func (n *BellNotifier) Notify(Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := io.WriteString(n.w, "\a"); err != nil {
+		return fmt.Errorf("failed to ring bell: %w", err)
+	}
+	return nil
+}
+
+// tmuxPassthrough wraps a sequence in a tmux DCS passthrough, doubling the
+// escapes inside it
+func tmuxPassthrough(seq string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// sanitize removes control characters, which would end the sequence early
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			if r == '\n' || r == '\t' {
+				return ' '
+			}
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Justice-Caban/Miryokusha/internal/comicinfo"
 	"github.com/nwaples/rardecode/v2"
 )
 
@@ -20,6 +21,7 @@ type LocalSource struct {
 	scanDirs  []string
 	manga     map[string]*Manga
 	chapters  map[string][]*Chapter
+	files     map[string]string // Chapter ID to the file it was read from
 }
 
 // NewLocalSource creates a new local file source
@@ -31,6 +33,7 @@ func NewLocalSource(id, name, baseDir string) *LocalSource {
 		scanDirs: make([]string, 0),
 		manga:    make(map[string]*Manga),
 		chapters: make(map[string][]*Chapter),
+		files:    make(map[string]string),
 	}
 }
 
@@ -121,21 +124,6 @@ func (ls *LocalSource) parseCBZ(filePath string) error {
 	baseName := filepath.Base(filePath)
 	title := strings.TrimSuffix(baseName, filepath.Ext(baseName))
 
-	// Create manga ID from file path
-	mangaID := fmt.Sprintf("local-%s", sanitizeID(filePath))
-
-	// Check if manga already exists
-	manga, exists := ls.manga[mangaID]
-	if !exists {
-		manga = &Manga{
-			ID:         mangaID,
-			Title:      title,
-			SourceType: SourceTypeLocal,
-			SourceID:   ls.id,
-		}
-		ls.manga[mangaID] = manga
-	}
-
 	// Open ZIP file to count pages
 	zipReader, err := zip.OpenReader(filePath)
 	if err != nil {
@@ -151,6 +139,31 @@ func (ls *LocalSource) parseCBZ(filePath string) error {
 		}
 	}
 
+	// Archives with ComicInfo.xml are grouped into series with full metadata
+	info, err := comicinfo.FromZip(&zipReader.Reader)
+	if err != nil {
+		return err
+	}
+	if info != nil && info.Series != "" {
+		ls.addComicInfoChapter(filePath, title, pageCount, info)
+		return nil
+	}
+
+	// Create manga ID from file path
+	mangaID := fmt.Sprintf("local-%s", sanitizeID(filePath))
+
+	// Check if manga already exists
+	manga, exists := ls.manga[mangaID]
+	if !exists {
+		manga = &Manga{
+			ID:         mangaID,
+			Title:      title,
+			SourceType: SourceTypeLocal,
+			SourceID:   ls.id,
+		}
+		ls.manga[mangaID] = manga
+	}
+
 	// Create chapter
 	chapterID := fmt.Sprintf("local-chapter-%s", sanitizeID(filePath))
 	chapter := &Chapter{
@@ -164,10 +177,81 @@ func (ls *LocalSource) parseCBZ(filePath string) error {
 	}
 
 	ls.chapters[mangaID] = []*Chapter{chapter}
+	ls.files[chapterID] = filePath
 
 	return nil
 }
 
+// addComicInfoChapter adds a CBZ described by ComicInfo.xml as a chapter of
+// its series, such as archives packaged by the download manager
+func (ls *LocalSource) addComicInfoChapter(filePath, title string, pageCount int, info *comicinfo.ComicInfo) {
+	mangaID := fmt.Sprintf("local-%s", sanitizeID(info.Series))
+
+	manga, exists := ls.manga[mangaID]
+	if !exists {
+		manga = &Manga{
+			ID:         mangaID,
+			Title:      info.Series,
+			SourceType: SourceTypeLocal,
+			SourceID:   ls.id,
+		}
+		ls.manga[mangaID] = manga
+	}
+
+	// Fill in series details from whichever chapter provides them
+	if manga.Author == "" {
+		manga.Author = info.Writer
+	}
+	if manga.Artist == "" {
+		manga.Artist = info.Penciller
+	}
+	if manga.Description == "" {
+		manga.Description = info.Summary
+	}
+	if len(manga.Genres) == 0 {
+		manga.Genres = info.Genres()
+	}
+
+	if info.Title != "" {
+		title = info.Title
+	}
+
+	chapter := &Chapter{
+		ID:             fmt.Sprintf("local-chapter-%s", sanitizeID(filePath)),
+		MangaID:        mangaID,
+		Title:          title,
+		ChapterNumber:  info.ChapterNumber(),
+		VolumeNumber:   float64(info.Volume),
+		PageCount:      pageCount,
+		ScanlatorGroup: info.Translator,
+		URL:            info.Web,
+		UploadDate:     info.Date(),
+		SourceType:     SourceTypeLocal,
+		SourceID:       ls.id,
+	}
+	ls.files[chapter.ID] = filePath
+
+	// Replace the chapter if the file was scanned before
+	chapters := ls.chapters[mangaID]
+	replaced := false
+	for i, existing := range chapters {
+		if existing.ID == chapter.ID {
+			chapters[i] = chapter
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		chapters = append(chapters, chapter)
+	}
+
+	sort.Slice(chapters, func(i, j int) bool {
+		return chapters[i].ChapterNumber < chapters[j].ChapterNumber
+	})
+	ls.chapters[mangaID] = chapters
+	manga.ChapterCount = len(chapters)
+}
+
 // parseCBR parses a CBR (Comic Book RAR) file
 func (ls *LocalSource) parseCBR(filePath string) error {
 	// Extract metadata from filename
@@ -230,6 +314,7 @@ func (ls *LocalSource) parseCBR(filePath string) error {
 	}
 
 	ls.chapters[mangaID] = []*Chapter{chapter}
+	ls.files[chapterID] = filePath
 
 	return nil
 }
@@ -375,21 +460,108 @@ func (ls *LocalSource) GetPage(chapter *Chapter, pageIndex int) (*Page, error) {
 	return pages[pageIndex], nil
 }
 
-// GetAllPages retrieves all pages from a chapter
+// GetAllPages retrieves all pages from a chapter, reading the images of
+// CBZ and CBR archives in file name order
 func (ls *LocalSource) GetAllPages(chapter *Chapter) ([]*Page, error) {
-	// For now, return placeholder - full implementation would extract images
-	// This would need the original file path stored in the chapter
-	pages := make([]*Page, chapter.PageCount)
-	for i := 0; i < chapter.PageCount; i++ {
+	filePath, ok := ls.files[chapter.ID]
+	if !ok {
+		return nil, fmt.Errorf("chapter not found: %s", chapter.ID)
+	}
+
+	var images map[string][]byte
+	var err error
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".cbz":
+		images, err = readZipImages(filePath)
+	case ".cbr":
+		images, err = readRarImages(filePath)
+	default:
+		return nil, fmt.Errorf("reading pages of %s files is not supported", filepath.Ext(filePath))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pages := make([]*Page, len(names))
+	for i, name := range names {
 		pages[i] = &Page{
-			Index: i,
-			URL:   "", // Local files don't have URLs
+			Index:     i,
+			URL:       "", // Local files don't have URLs
+			ImageData: images[name],
+			ImageType: imageType(name),
 		}
 	}
 
 	return pages, nil
 }
 
+// readZipImages reads the image files of a CBZ archive
+func readZipImages(filePath string) (map[string][]byte, error) {
+	zipReader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CBZ: %w", err)
+	}
+	defer zipReader.Close()
+
+	images := make(map[string][]byte)
+	for _, file := range zipReader.File {
+		if !isImageFile(file.Name) {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		images[file.Name] = data
+	}
+	return images, nil
+}
+
+// readRarImages reads the image files of a CBR archive
+func readRarImages(filePath string) (map[string][]byte, error) {
+	rarFile, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CBR: %w", err)
+	}
+	defer rarFile.Close()
+
+	rarReader, err := rardecode.NewReader(rarFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RAR reader: %w", err)
+	}
+
+	images := make(map[string][]byte)
+	for {
+		header, err := rarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RAR entry: %w", err)
+		}
+		if header.IsDir || !isImageFile(header.Name) {
+			continue
+		}
+		data, err := io.ReadAll(rarReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		images[header.Name] = data
+	}
+	return images, nil
+}
+
 // Search searches for manga by title
 func (ls *LocalSource) Search(query string) ([]*Manga, error) {
 	query = strings.ToLower(query)
@@ -425,6 +597,20 @@ func sanitizeID(s string) string {
 	return s
 }
 
+// imageType returns the MIME type of an image file
+func imageType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
 func isImageFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
 	return ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".gif" || ext == ".webp"
@@ -1,7 +1,12 @@
 package source
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -26,6 +31,25 @@ func NewSuwayomiSource(id, name, baseURL string) *SuwayomiSource {
 	}
 }
 
+// NewSuwayomiSourceWithClient creates a Suwayomi source sharing an existing
+// client, so detected server capabilities apply to both
+func NewSuwayomiSourceWithClient(id, name string, client *suwayomi.Client) *SuwayomiSource {
+	return &SuwayomiSource{
+		id:      id,
+		name:    name,
+		baseURL: client.BaseURL,
+		client:  client,
+	}
+}
+
+// ServerHost returns the host and port of the Suwayomi server
+func (s *SuwayomiSource) ServerHost() string {
+	if u, err := url.Parse(s.baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return s.baseURL
+}
+
 // GetType returns the source type
 func (s *SuwayomiSource) GetType() SourceType {
 	return SourceTypeSuwayomi
@@ -111,9 +135,7 @@ func (s *SuwayomiSource) GetChapter(chapterID string) (*Chapter, error) {
 // GetPage retrieves a specific page from a chapter
 func (s *SuwayomiSource) GetPage(chapter *Chapter, pageIndex int) (*Page, error) {
 	// Use REST API for page image retrieval
-	// Suwayomi API endpoint (try without manga ID first, as some versions don't need it)
-	url := fmt.Sprintf("%s/api/v1/manga/%s/chapter/%s/page/%d",
-		s.client.BaseURL, chapter.MangaID, chapter.ID, pageIndex)
+	url := s.pageURL(chapter, pageIndex)
 
 	// Fetch the image
 	resp, err := s.client.HTTPClient.Get(url)
@@ -123,7 +145,7 @@ func (s *SuwayomiSource) GetPage(chapter *Chapter, pageIndex int) (*Page, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to fetch page from %s: status %d", url, resp.StatusCode)
+		return nil, fmt.Errorf("failed to fetch page from %s: %w", url, &suwayomi.StatusError{StatusCode: resp.StatusCode})
 	}
 
 	// Read image data
@@ -153,6 +175,60 @@ func (s *SuwayomiSource) GetPage(chapter *Chapter, pageIndex int) (*Page, error)
 	}, nil
 }
 
+// pageURL returns the REST endpoint of a page image
+func (s *SuwayomiSource) pageURL(chapter *Chapter, pageIndex int) string {
+	return fmt.Sprintf("%s/api/v1/manga/%s/chapter/%s/page/%d",
+		s.client.BaseURL, chapter.MangaID, chapter.ID, pageIndex)
+}
+
+// OpenPage requests a page image and returns its body as it arrives
+func (s *SuwayomiSource) OpenPage(ctx context.Context, chapter *Chapter, pageIndex int) (io.ReadCloser, error) {
+	url := s.pageURL(chapter, pageIndex)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page from %s: %w", url, err)
+	}
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch page from %s: %w", url, &suwayomi.StatusError{StatusCode: resp.StatusCode})
+	}
+	return resp.Body, nil
+}
+
+// GetCover retrieves a manga's thumbnail, resolving URLs relative to the
+// server
+func (s *SuwayomiSource) GetCover(manga *Manga) ([]byte, error) {
+	if manga.CoverURL == "" {
+		return nil, fmt.Errorf("manga %s has no cover", manga.ID)
+	}
+
+	coverURL := manga.CoverURL
+	if u, err := url.Parse(coverURL); err == nil && !u.IsAbs() {
+		coverURL = s.client.BaseURL + coverURL
+	}
+
+	resp, err := s.client.HTTPClient.Get(coverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cover from %s: %w", coverURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to fetch cover from %s: %w", coverURL, &suwayomi.StatusError{StatusCode: resp.StatusCode})
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cover: %w", err)
+	}
+	return data, nil
+}
+
 // GetAllPages retrieves all pages from a chapter
 func (s *SuwayomiSource) GetAllPages(chapter *Chapter) ([]*Page, error) {
 	// Validate chapter data
@@ -166,22 +242,13 @@ func (s *SuwayomiSource) GetAllPages(chapter *Chapter) ([]*Page, error) {
 		return nil, fmt.Errorf("chapter MangaID is empty")
 	}
 
-	// CRITICAL: Trigger Suwayomi to fetch pages from the manga source
-	// Suwayomi lazily loads pages, so we need to explicitly request them
-	// This mutation "primes" the chapter, making pages available via REST API
-	chapterIDInt, err := strconv.Atoi(chapter.ID)
+	maxPages, err := s.PreparePages(chapter)
 	if err != nil {
-		return nil, fmt.Errorf("invalid chapter ID %s: %w", chapter.ID, err)
-	}
-
-	// Fetch chapter pages from source (this may take a moment)
-	if err := s.client.GraphQL.FetchChapterPages(chapterIDInt); err != nil {
-		return nil, fmt.Errorf("failed to fetch chapter pages from source: %w", err)
+		return nil, err
 	}
 
 	// Use the PageCount from chapter metadata if available
 	// Note: Suwayomi returns -1 for unknown page count
-	maxPages := chapter.PageCount
 	if maxPages <= 0 {
 		// Fallback to trying up to 500 pages if count is unknown
 		maxPages = 500
@@ -217,6 +284,43 @@ func (s *SuwayomiSource) GetAllPages(chapter *Chapter) ([]*Page, error) {
 	return pages, nil
 }
 
+// PreparePages makes the server load a chapter's page list so pages can be
+// requested individually, and returns the page count (0 if unknown)
+func (s *SuwayomiSource) PreparePages(chapter *Chapter) (int, error) {
+	// CRITICAL: Trigger Suwayomi to fetch pages from the manga source
+	// Suwayomi lazily loads pages, so we need to explicitly request them
+	// This mutation "primes" the chapter, making pages available via REST API
+	chapterIDInt, err := strconv.Atoi(chapter.ID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid chapter ID %s: %w", chapter.ID, err)
+	}
+
+	// Fetch chapter pages from source (this may take a moment)
+	pageCount := chapter.PageCount
+	pageURLs, err := s.client.GraphQL.FetchChapterPageList(chapterIDInt)
+	if err != nil {
+		if !errors.Is(err, suwayomi.ErrFeatureUnsupported) {
+			return 0, fmt.Errorf("failed to fetch chapter pages from source: %w", err)
+		}
+
+		// Older servers load the page list when the chapter is requested over REST
+		restCount, err := s.client.FetchChapterPagesREST(chapter.MangaID, chapter.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch chapter pages from source: %w", err)
+		}
+		if pageCount <= 0 {
+			pageCount = restCount
+		}
+	} else if len(pageURLs) > 0 {
+		pageCount = len(pageURLs)
+	}
+
+	if pageCount < 0 {
+		pageCount = 0
+	}
+	return pageCount, nil
+}
+
 // Search searches for manga on the Suwayomi server
 func (s *SuwayomiSource) Search(query string) ([]*Manga, error) {
 	// GraphQL search would require a custom query
@@ -238,10 +342,10 @@ func (s *SuwayomiSource) Search(query string) ([]*Manga, error) {
 	return results, nil
 }
 
-// IsAvailable checks if the Suwayomi server is accessible
+// IsAvailable checks if the Suwayomi server is accessible, using the
+// client's cached availability rather than a request per call
 func (s *SuwayomiSource) IsAvailable() bool {
-	info, err := s.client.HealthCheck()
-	return err == nil && info.IsHealthy
+	return s.client.IsAvailable()
 }
 
 // Helper functions
@@ -289,8 +393,9 @@ func (s *SuwayomiSource) convertChapterNode(node *suwayomi.ChapterNode, mangaID
 		MangaID:        mangaID,
 		Title:          node.Name,
 		ChapterNumber:  node.ChapterNumber,
-		VolumeNumber:   0,  // Not in basic query
-		ScanlatorGroup: "", // Not in basic query
+		VolumeNumber:   0, // Not in basic query
+		ScanlatorGroup: node.Scanlator,
+		URL:            node.RealURL,
 		UploadDate:     uploadDate,
 		IsRead:         node.IsRead,
 		IsBookmarked:   node.IsBookmarked,
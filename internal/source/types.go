@@ -1,7 +1,10 @@
 package source
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"time"
 )
 
@@ -43,6 +46,7 @@ type Chapter struct {
 	VolumeNumber   float64
 	PageCount      int
 	ScanlatorGroup string
+	URL            string
 	UploadDate     time.Time
 	SourceType     SourceType
 	SourceID       string
@@ -95,6 +99,33 @@ type Source interface {
 	IsAvailable() bool
 }
 
+// PagePreparer is implemented by sources that can load a chapter's page
+// list without fetching every page image
+type PagePreparer interface {
+	// PreparePages returns the chapter's page count, or 0 if unknown
+	PreparePages(chapter *Chapter) (int, error)
+}
+
+// CoverFetcher is implemented by sources that can provide a manga's cover
+// image
+type CoverFetcher interface {
+	// GetCover returns the image data of the manga's cover
+	GetCover(manga *Manga) ([]byte, error)
+}
+
+// PageOpener is implemented by sources that can stream a page's image data
+type PageOpener interface {
+	// OpenPage returns the page's image data as it arrives. The caller
+	// closes it.
+	OpenPage(ctx context.Context, chapter *Chapter, pageIndex int) (io.ReadCloser, error)
+}
+
+// ServerSource is implemented by sources backed by a remote server
+type ServerSource interface {
+	// ServerHost returns the server's host and port, e.g. "localhost:4567"
+	ServerHost() string
+}
+
 // SourceManager manages multiple manga sources
 type SourceManager struct {
 	sources []Source
@@ -199,6 +230,39 @@ func (sm *SourceManager) GetAllPages(chapter *Chapter) ([]*Page, error) {
 	return nil, fmt.Errorf("source not found for chapter %s (source ID: %s)", chapter.ID, chapter.SourceID)
 }
 
+// PreparePages readies a chapter for page-by-page fetching and returns its
+// page count. Sources that cannot list pages report the chapter's PageCount.
+func (sm *SourceManager) PreparePages(chapter *Chapter) (int, error) {
+	for _, source := range sm.sources {
+		if source.GetID() != chapter.SourceID {
+			continue
+		}
+		if !source.IsAvailable() {
+			continue
+		}
+		if preparer, ok := source.(PagePreparer); ok {
+			return preparer.PreparePages(chapter)
+		}
+		return chapter.PageCount, nil
+	}
+	return 0, fmt.Errorf("source not found for chapter %s (source ID: %s)", chapter.ID, chapter.SourceID)
+}
+
+// GetCover retrieves a manga's cover image from its source
+func (sm *SourceManager) GetCover(manga *Manga) ([]byte, error) {
+	for _, source := range sm.sources {
+		if source.GetID() != manga.SourceID {
+			continue
+		}
+		fetcher, ok := source.(CoverFetcher)
+		if !ok {
+			return nil, fmt.Errorf("source %s does not provide covers", source.GetName())
+		}
+		return fetcher.GetCover(manga)
+	}
+	return nil, fmt.Errorf("source not found for manga %s (source ID: %s)", manga.ID, manga.SourceID)
+}
+
 // GetPage retrieves a specific page from a chapter
 func (sm *SourceManager) GetPage(chapter *Chapter, pageIndex int) ([]byte, error) {
 	// Find the source that matches this chapter's source ID
@@ -210,15 +274,33 @@ func (sm *SourceManager) GetPage(chapter *Chapter, pageIndex int) ([]byte, error
 			continue
 		}
 		page, err := source.GetPage(chapter, pageIndex)
-		if err == nil && page != nil {
-			// If ImageData is already loaded, return it
-			if len(page.ImageData) > 0 {
-				return page.ImageData, nil
-			}
-			// Otherwise, would need to fetch from URL
-			// For now, return empty if only URL is available
-			return page.ImageData, nil
+		if err != nil {
+			return nil, err
+		}
+		if page == nil || len(page.ImageData) == 0 {
+			return nil, fmt.Errorf("page %d of chapter %s has no image data", pageIndex, chapter.ID)
 		}
+		return page.ImageData, nil
 	}
 	return nil, fmt.Errorf("source not found for chapter %s (source ID: %s)", chapter.ID, chapter.SourceID)
 }
+
+// OpenPage streams a specific page's image data from the appropriate
+// source. Sources that cannot stream deliver the whole page at once.
+func (sm *SourceManager) OpenPage(ctx context.Context, chapter *Chapter, pageIndex int) (io.ReadCloser, error) {
+	for _, source := range sm.sources {
+		if source.GetID() != chapter.SourceID || !source.IsAvailable() {
+			continue
+		}
+		if opener, ok := source.(PageOpener); ok {
+			return opener.OpenPage(ctx, chapter, pageIndex)
+		}
+		break
+	}
+
+	data, err := sm.GetPage(chapter, pageIndex)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
@@ -15,6 +15,8 @@ type Category struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	MangaCount int // Number of manga in this category
+
+	UpdateSchedule UpdateSchedule // How often its manga are checked for new chapters
 }
 
 // CategoryManager manages manga categories
@@ -75,10 +77,11 @@ func (cm *CategoryManager) Create(name string, isDefault bool) (*Category, error
 func (cm *CategoryManager) GetByID(id int) (*Category, error) {
 	var cat Category
 	err := cm.db.conn.QueryRow(`
-		SELECT id, name, sort_order, is_default, created_at, updated_at
+		SELECT id, name, sort_order, is_default, created_at, updated_at, update_mode, update_interval_hours
 		FROM categories
 		WHERE id = ?
-	`, id).Scan(&cat.ID, &cat.Name, &cat.SortOrder, &cat.IsDefault, &cat.CreatedAt, &cat.UpdatedAt)
+	`, id).Scan(&cat.ID, &cat.Name, &cat.SortOrder, &cat.IsDefault, &cat.CreatedAt, &cat.UpdatedAt,
+		&cat.UpdateSchedule.Mode, (*scheduleHours)(&cat.UpdateSchedule.Interval))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -109,10 +112,10 @@ func (cm *CategoryManager) GetAll() ([]*Category, error) {
 func (cm *CategoryManager) GetAllPaginated(limit, offset int) ([]*Category, error) {
 	query := `
 		SELECT c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at,
-		       COUNT(mc.manga_id) as manga_count
+		       COUNT(mc.manga_id) as manga_count, c.update_mode, c.update_interval_hours
 		FROM categories c
 		LEFT JOIN manga_categories mc ON c.id = mc.category_id
-		GROUP BY c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at
+		GROUP BY c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at, c.update_mode, c.update_interval_hours
 		ORDER BY c.sort_order
 	`
 
@@ -135,7 +138,8 @@ func (cm *CategoryManager) GetAllPaginated(limit, offset int) ([]*Category, erro
 	for rows.Next() {
 		var cat Category
 		err := rows.Scan(&cat.ID, &cat.Name, &cat.SortOrder, &cat.IsDefault,
-			&cat.CreatedAt, &cat.UpdatedAt, &cat.MangaCount)
+			&cat.CreatedAt, &cat.UpdatedAt, &cat.MangaCount,
+			&cat.UpdateSchedule.Mode, (*scheduleHours)(&cat.UpdateSchedule.Interval))
 		if err != nil {
 			return nil, err
 		}
@@ -269,13 +273,14 @@ func (cm *CategoryManager) GetDefault() (*Category, error) {
 	var cat Category
 	err := cm.db.conn.QueryRow(`
 		SELECT c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at,
-		       COUNT(mc.manga_id) as manga_count
+		       COUNT(mc.manga_id) as manga_count, c.update_mode, c.update_interval_hours
 		FROM categories c
 		LEFT JOIN manga_categories mc ON c.id = mc.category_id
 		WHERE c.is_default = TRUE
-		GROUP BY c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at
+		GROUP BY c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at, c.update_mode, c.update_interval_hours
 	`).Scan(&cat.ID, &cat.Name, &cat.SortOrder, &cat.IsDefault,
-		&cat.CreatedAt, &cat.UpdatedAt, &cat.MangaCount)
+		&cat.CreatedAt, &cat.UpdatedAt, &cat.MangaCount,
+		&cat.UpdateSchedule.Mode, (*scheduleHours)(&cat.UpdateSchedule.Interval))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -384,7 +389,7 @@ func (cm *CategoryManager) UnassignMangaBatch(mangaIDs []string, categoryID int)
 func (cm *CategoryManager) GetMangaCategories(mangaID string) ([]*Category, error) {
 	rows, err := cm.db.conn.Query(`
 		SELECT c.id, c.name, c.sort_order, c.is_default, c.created_at, c.updated_at,
-		       0 as manga_count
+		       0 as manga_count, c.update_mode, c.update_interval_hours
 		FROM categories c
 		INNER JOIN manga_categories mc ON c.id = mc.category_id
 		WHERE mc.manga_id = ?
@@ -400,7 +405,8 @@ func (cm *CategoryManager) GetMangaCategories(mangaID string) ([]*Category, erro
 	for rows.Next() {
 		var cat Category
 		err := rows.Scan(&cat.ID, &cat.Name, &cat.SortOrder, &cat.IsDefault,
-			&cat.CreatedAt, &cat.UpdatedAt, &cat.MangaCount)
+			&cat.CreatedAt, &cat.UpdatedAt, &cat.MangaCount,
+			&cat.UpdateSchedule.Mode, (*scheduleHours)(&cat.UpdateSchedule.Interval))
 		if err != nil {
 			return nil, err
 		}
@@ -107,7 +107,7 @@ func (db *DB) initSchema() error {
 	}
 
 	// If schema is already at latest version, skip
-	const latestVersion = 3
+	const latestVersion = 11
 	if currentVersion >= latestVersion {
 		return nil
 	}
@@ -149,6 +149,102 @@ func (db *DB) initSchema() error {
 		}
 	}
 
+	if currentVersion < 4 {
+		if err := db.applySchemaV4(); err != nil {
+			return fmt.Errorf("failed to apply schema v4: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 4)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 5 {
+		if err := db.applySchemaV5(); err != nil {
+			return fmt.Errorf("failed to apply schema v5: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 5)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 6 {
+		if err := db.applySchemaV6(); err != nil {
+			return fmt.Errorf("failed to apply schema v6: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 6)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 7 {
+		if err := db.applySchemaV7(); err != nil {
+			return fmt.Errorf("failed to apply schema v7: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 7)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 8 {
+		if err := db.applySchemaV8(); err != nil {
+			return fmt.Errorf("failed to apply schema v8: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 8)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 9 {
+		if err := db.applySchemaV9(); err != nil {
+			return fmt.Errorf("failed to apply schema v9: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 9)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 10 {
+		if err := db.applySchemaV10(); err != nil {
+			return fmt.Errorf("failed to apply schema v10: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 10)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
+	if currentVersion < 11 {
+		if err := db.applySchemaV11(); err != nil {
+			return fmt.Errorf("failed to apply schema v11: %w", err)
+		}
+
+		// Record schema version
+		_, err = db.conn.Exec("INSERT INTO schema_version (version) VALUES (?)", 11)
+		if err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -293,6 +389,184 @@ func (db *DB) applySchemaV3() error {
 	return err
 }
 
+// applySchemaV4 adds the persistent download queue and downloaded chapter index (version 4)
+func (db *DB) applySchemaV4() error {
+	schema := `
+	-- Download queue, restored when the download manager starts
+	CREATE TABLE IF NOT EXISTS download_queue (
+		id TEXT PRIMARY KEY,
+		manga_id TEXT NOT NULL,
+		manga_title TEXT NOT NULL,
+		chapter_id TEXT NOT NULL UNIQUE,
+		chapter_name TEXT,
+		chapter_number REAL DEFAULT 0,
+		page_count INTEGER DEFAULT 0,
+		source_type TEXT NOT NULL,
+		source_id TEXT,
+		status TEXT NOT NULL,
+		priority INTEGER DEFAULT 0,
+		retry_count INTEGER DEFAULT 0,
+		current_page INTEGER DEFAULT 0,
+		total_pages INTEGER DEFAULT 0,
+		error TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Chapters downloaded to disk
+	CREATE TABLE IF NOT EXISTS downloaded_chapters (
+		chapter_id TEXT PRIMARY KEY,
+		manga_id TEXT NOT NULL,
+		manga_title TEXT NOT NULL,
+		chapter_name TEXT,
+		chapter_number REAL DEFAULT 0,
+		source_type TEXT NOT NULL,
+		source_id TEXT,
+		path TEXT NOT NULL,
+		page_count INTEGER DEFAULT 0,
+		size_bytes INTEGER DEFAULT 0,
+		downloaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_download_queue_priority ON download_queue(priority, created_at);
+	CREATE INDEX IF NOT EXISTS idx_downloaded_chapters_manga ON downloaded_chapters(manga_id);
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV5 adds the chapters seen by library updates (version 5)
+func (db *DB) applySchemaV5() error {
+	schema := `
+	-- Chapters seen by library updates, to tell new chapters from known ones
+	CREATE TABLE IF NOT EXISTS manga_known_chapters (
+		manga_id TEXT NOT NULL,
+		chapter_id TEXT NOT NULL,
+		chapter_number REAL DEFAULT 0,
+		first_seen TIMESTAMP NOT NULL,
+		PRIMARY KEY (manga_id, chapter_id)
+	);
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV6 adds the feed of chapters found by library updates (version 6)
+func (db *DB) applySchemaV6() error {
+	schema := `
+	-- Chapters found by library updates, shown in the Updates view
+	CREATE TABLE IF NOT EXISTS chapter_updates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		manga_id TEXT NOT NULL,
+		manga_title TEXT NOT NULL,
+		chapter_id TEXT NOT NULL UNIQUE,
+		chapter_title TEXT,
+		chapter_number REAL DEFAULT 0,
+		page_count INTEGER DEFAULT 0,
+		source_type TEXT NOT NULL,
+		source_id TEXT,
+		found_at TIMESTAMP NOT NULL,
+		dismissed BOOLEAN DEFAULT FALSE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_chapter_updates_found_at ON chapter_updates(found_at);
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV7 adds the notification store (version 7)
+func (db *DB) applySchemaV7() error {
+	schema := `
+	-- Notifications shown in the notification center
+	CREATE TABLE IF NOT EXISTS notifications (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		severity INTEGER DEFAULT 0,
+		title TEXT NOT NULL,
+		message TEXT,
+		suggestion TEXT,
+		manga_id TEXT,
+		chapter_id TEXT,
+		created_at TIMESTAMP NOT NULL,
+		is_read BOOLEAN DEFAULT FALSE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_notifications_created_at ON notifications(created_at);
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV8 adds the log of webhook and command hook deliveries (version 8)
+func (db *DB) applySchemaV8() error {
+	schema := `
+	-- Recent deliveries of library events to hooks
+	CREATE TABLE IF NOT EXISTS hook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		hook TEXT NOT NULL,
+		event TEXT NOT NULL,
+		attempts INTEGER DEFAULT 1,
+		success BOOLEAN DEFAULT FALSE,
+		status TEXT,
+		error TEXT,
+		duration_ms INTEGER DEFAULT 0,
+		delivered_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_hook_deliveries_delivered_at ON hook_deliveries(delivered_at);
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV9 adds update schedules of categories and manga (version 9)
+func (db *DB) applySchemaV9() error {
+	schema := `
+	-- How often the manga of a category are checked for new chapters
+	ALTER TABLE categories ADD COLUMN update_mode TEXT NOT NULL DEFAULT '';
+	ALTER TABLE categories ADD COLUMN update_interval_hours INTEGER NOT NULL DEFAULT 0;
+
+	-- Schedules of single manga, overriding their categories
+	CREATE TABLE IF NOT EXISTS manga_update_schedules (
+		manga_id TEXT PRIMARY KEY,
+		update_mode TEXT NOT NULL,
+		update_interval_hours INTEGER NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV10 adds what release predictions need: upload dates of
+// known chapters and titles of tracked manga (version 10)
+func (db *DB) applySchemaV10() error {
+	schema := `
+	ALTER TABLE manga_known_chapters ADD COLUMN upload_date TIMESTAMP;
+	ALTER TABLE manga_update_tracking ADD COLUMN manga_title TEXT NOT NULL DEFAULT '';
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
+// applySchemaV11 adds when queued downloads are retried (version 11)
+func (db *DB) applySchemaV11() error {
+	schema := `
+	ALTER TABLE download_queue ADD COLUMN next_attempt TIMESTAMP;
+	`
+
+	_, err := db.conn.Exec(schema)
+	return err
+}
+
 // GetConnection returns the underlying database connection
 func (db *DB) GetConnection() *sql.DB {
 	return db.conn
@@ -46,15 +46,15 @@ func TestDB_SchemaVersioning(t *testing.T) {
 		t.Error("Expected at least one schema version entry")
 	}
 
-	// Verify current version is 3
+	// Verify current version is 10
 	var version int
 	err = db.conn.QueryRow("SELECT MAX(version) FROM schema_version").Scan(&version)
 	if err != nil {
 		t.Fatalf("Failed to get schema version: %v", err)
 	}
 
-	if version != 3 {
-		t.Errorf("Expected schema version 3, got %d", version)
+	if version != 11 {
+		t.Errorf("Expected schema version 11, got %d", version)
 	}
 }
 
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// QueuedDownload is a persisted download queue entry
+type QueuedDownload struct {
+	ID            string
+	MangaID       string
+	MangaTitle    string
+	ChapterID     string
+	ChapterName   string
+	ChapterNumber float64
+	PageCount     int
+	SourceType    string
+	SourceID      string
+	Status        string
+	Priority      int
+	RetryCount    int
+	NextAttempt   time.Time // When a failed attempt is retried, zero if not waiting
+	CurrentPage   int
+	TotalPages    int
+	Error         string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// DownloadedChapter is a chapter stored on disk
+type DownloadedChapter struct {
+	ChapterID     string
+	MangaID       string
+	MangaTitle    string
+	ChapterName   string
+	ChapterNumber float64
+	SourceType    string
+	SourceID      string
+	Path          string
+	PageCount     int
+	SizeBytes     int64
+	DownloadedAt  time.Time
+}
+
+// DownloadManager persists the download queue and the index of downloaded chapters
+type DownloadManager struct {
+	db *DB
+}
+
+// NewDownloadManager creates a new download manager
+func NewDownloadManager(db *DB) *DownloadManager {
+	return &DownloadManager{db: db}
+}
+
+// SaveQueued inserts or updates a download queue entry
+func (dm *DownloadManager) SaveQueued(item *QueuedDownload) error {
+	now := time.Now()
+	createdAt := item.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+
+	var nextAttempt interface{}
+	if !item.NextAttempt.IsZero() {
+		nextAttempt = item.NextAttempt
+	}
+
+	_, err := dm.db.conn.Exec(`
+		INSERT INTO download_queue
+		(id, manga_id, manga_title, chapter_id, chapter_name, chapter_number, page_count, source_type, source_id,
+		 status, priority, retry_count, next_attempt, current_page, total_pages, error, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(chapter_id) DO UPDATE SET
+			id = excluded.id,
+			status = excluded.status,
+			priority = excluded.priority,
+			retry_count = excluded.retry_count,
+			next_attempt = excluded.next_attempt,
+			current_page = excluded.current_page,
+			total_pages = excluded.total_pages,
+			error = excluded.error,
+			updated_at = excluded.updated_at
+	`,
+		item.ID, item.MangaID, item.MangaTitle, item.ChapterID, item.ChapterName, item.ChapterNumber,
+		item.PageCount, item.SourceType, item.SourceID, item.Status, item.Priority, item.RetryCount, nextAttempt,
+		item.CurrentPage, item.TotalPages, item.Error, createdAt, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save queued download: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveQueued deletes a download queue entry by chapter ID
+func (dm *DownloadManager) RemoveQueued(chapterID string) error {
+	_, err := dm.db.conn.Exec("DELETE FROM download_queue WHERE chapter_id = ?", chapterID)
+	if err != nil {
+		return fmt.Errorf("failed to remove queued download: %w", err)
+	}
+	return nil
+}
+
+// GetQueued returns all download queue entries ordered by priority and age
+func (dm *DownloadManager) GetQueued() ([]*QueuedDownload, error) {
+	rows, err := dm.db.conn.Query(`
+		SELECT id, manga_id, manga_title, chapter_id, chapter_name, chapter_number, page_count, source_type,
+		       source_id, status, priority, retry_count, next_attempt, current_page, total_pages, error, created_at,
+		       updated_at
+		FROM download_queue
+		ORDER BY priority, created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query download queue: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*QueuedDownload
+	for rows.Next() {
+		var item QueuedDownload
+		var chapterName, sourceID, errText sql.NullString
+		var nextAttempt sql.NullTime
+		if err := rows.Scan(
+			&item.ID, &item.MangaID, &item.MangaTitle, &item.ChapterID, &chapterName, &item.ChapterNumber,
+			&item.PageCount, &item.SourceType, &sourceID, &item.Status, &item.Priority, &item.RetryCount, &nextAttempt,
+			&item.CurrentPage, &item.TotalPages, &errText, &item.CreatedAt, &item.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan queued download: %w", err)
+		}
+		item.ChapterName = chapterName.String
+		item.SourceID = sourceID.String
+		item.Error = errText.String
+		item.NextAttempt = nextAttempt.Time
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+// MarkDownloaded records a chapter as stored on disk
+func (dm *DownloadManager) MarkDownloaded(chapter *DownloadedChapter) error {
+	downloadedAt := chapter.DownloadedAt
+	if downloadedAt.IsZero() {
+		downloadedAt = time.Now()
+	}
+
+	_, err := dm.db.conn.Exec(`
+		INSERT INTO downloaded_chapters
+		(chapter_id, manga_id, manga_title, chapter_name, chapter_number, source_type, source_id, path,
+		 page_count, size_bytes, downloaded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(chapter_id) DO UPDATE SET
+			path = excluded.path,
+			page_count = excluded.page_count,
+			size_bytes = excluded.size_bytes,
+			downloaded_at = excluded.downloaded_at
+	`,
+		chapter.ChapterID, chapter.MangaID, chapter.MangaTitle, chapter.ChapterName, chapter.ChapterNumber,
+		chapter.SourceType, chapter.SourceID, chapter.Path, chapter.PageCount, chapter.SizeBytes, downloadedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record downloaded chapter: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveDownloaded removes a chapter from the downloaded index
+func (dm *DownloadManager) RemoveDownloaded(chapterID string) error {
+	_, err := dm.db.conn.Exec("DELETE FROM downloaded_chapters WHERE chapter_id = ?", chapterID)
+	if err != nil {
+		return fmt.Errorf("failed to remove downloaded chapter: %w", err)
+	}
+	return nil
+}
+
+// GetDownloaded returns a downloaded chapter, or nil if it is not on disk
+func (dm *DownloadManager) GetDownloaded(chapterID string) (*DownloadedChapter, error) {
+	chapters, err := dm.queryDownloaded("WHERE chapter_id = ?", chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chapters) == 0 {
+		return nil, nil
+	}
+	return chapters[0], nil
+}
+
+// GetDownloadedForManga returns the downloaded chapters of a manga
+func (dm *DownloadManager) GetDownloadedForManga(mangaID string) ([]*DownloadedChapter, error) {
+	return dm.queryDownloaded("WHERE manga_id = ? ORDER BY chapter_number", mangaID)
+}
+
+// GetAllDownloaded returns every downloaded chapter, oldest first
+func (dm *DownloadManager) GetAllDownloaded() ([]*DownloadedChapter, error) {
+	return dm.queryDownloaded("ORDER BY downloaded_at")
+}
+
+// GetDownloadedChapterIDs returns the set of downloaded chapter IDs of a manga
+func (dm *DownloadManager) GetDownloadedChapterIDs(mangaID string) (map[string]bool, error) {
+	chapters, err := dm.GetDownloadedForManga(mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(chapters))
+	for _, c := range chapters {
+		ids[c.ChapterID] = true
+	}
+	return ids, nil
+}
+
+func (dm *DownloadManager) queryDownloaded(clause string, args ...interface{}) ([]*DownloadedChapter, error) {
+	rows, err := dm.db.conn.Query(`
+		SELECT chapter_id, manga_id, manga_title, chapter_name, chapter_number, source_type, source_id, path,
+		       page_count, size_bytes, downloaded_at
+		FROM downloaded_chapters
+		`+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query downloaded chapters: %w", err)
+	}
+	defer rows.Close()
+
+	var chapters []*DownloadedChapter
+	for rows.Next() {
+		var c DownloadedChapter
+		var chapterName, sourceID sql.NullString
+		if err := rows.Scan(
+			&c.ChapterID, &c.MangaID, &c.MangaTitle, &chapterName, &c.ChapterNumber, &c.SourceType,
+			&sourceID, &c.Path, &c.PageCount, &c.SizeBytes, &c.DownloadedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan downloaded chapter: %w", err)
+		}
+		c.ChapterName = chapterName.String
+		c.SourceID = sourceID.String
+		chapters = append(chapters, &c)
+	}
+
+	return chapters, rows.Err()
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadManager_Queue(t *testing.T) {
+	db := NewTestDB(t)
+	dm := NewDownloadManager(db)
+
+	now := time.Now()
+	require.NoError(t, dm.SaveQueued(&QueuedDownload{
+		ID: "m1-c2", MangaID: "m1", MangaTitle: "Manga", ChapterID: "c2", ChapterName: "Chapter 2",
+		ChapterNumber: 2, SourceType: "suwayomi", SourceID: "suwayomi-default",
+		Status: "queued", Priority: 5, CreatedAt: now,
+	}))
+	require.NoError(t, dm.SaveQueued(&QueuedDownload{
+		ID: "m1-c1", MangaID: "m1", MangaTitle: "Manga", ChapterID: "c1", ChapterName: "Chapter 1",
+		ChapterNumber: 1, PageCount: 20, SourceType: "suwayomi", SourceID: "suwayomi-default",
+		Status: "downloading", Priority: 1, CreatedAt: now.Add(time.Second),
+	}))
+
+	items, err := dm.GetQueued()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "c1", items[0].ChapterID, "ordered by priority")
+	assert.Equal(t, 20, items[0].PageCount)
+	assert.Equal(t, "suwayomi-default", items[0].SourceID)
+
+	assert.True(t, items[0].NextAttempt.IsZero())
+
+	// Saving again updates the entry in place
+	items[0].Status = "queued"
+	items[0].RetryCount = 2
+	items[0].NextAttempt = now.Add(time.Minute)
+	items[0].Error = "timeout"
+	require.NoError(t, dm.SaveQueued(items[0]))
+
+	items, err = dm.GetQueued()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "queued", items[0].Status)
+	assert.Equal(t, 2, items[0].RetryCount)
+	assert.True(t, now.Add(time.Minute).Equal(items[0].NextAttempt))
+	assert.Equal(t, "timeout", items[0].Error)
+
+	require.NoError(t, dm.RemoveQueued("c1"))
+	items, err = dm.GetQueued()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "c2", items[0].ChapterID)
+}
+
+func TestDownloadManager_DownloadedIndex(t *testing.T) {
+	db := NewTestDB(t)
+	dm := NewDownloadManager(db)
+
+	for _, c := range []*DownloadedChapter{
+		{ChapterID: "c1", MangaID: "m1", MangaTitle: "Manga", ChapterNumber: 1, SourceType: "suwayomi", Path: "/dl/Manga/Chapter 1", PageCount: 10, SizeBytes: 1000},
+		{ChapterID: "c2", MangaID: "m1", MangaTitle: "Manga", ChapterNumber: 2, SourceType: "suwayomi", Path: "/dl/Manga/Chapter 2", PageCount: 12, SizeBytes: 1200},
+		{ChapterID: "c9", MangaID: "m2", MangaTitle: "Other", ChapterNumber: 1, SourceType: "suwayomi", Path: "/dl/Other/Chapter 1"},
+	} {
+		require.NoError(t, dm.MarkDownloaded(c))
+	}
+
+	ids, err := dm.GetDownloadedChapterIDs("m1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"c1": true, "c2": true}, ids)
+
+	chapter, err := dm.GetDownloaded("c2")
+	require.NoError(t, err)
+	require.NotNil(t, chapter)
+	assert.Equal(t, int64(1200), chapter.SizeBytes)
+	assert.Equal(t, "/dl/Manga/Chapter 2", chapter.Path)
+
+	require.NoError(t, dm.RemoveDownloaded("c2"))
+	chapter, err = dm.GetDownloaded("c2")
+	require.NoError(t, err)
+	assert.Nil(t, chapter)
+
+	all, err := dm.GetAllDownloaded()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
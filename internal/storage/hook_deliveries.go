@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxHookDeliveries is the number of deliveries kept in the log
+const maxHookDeliveries = 500
+
+// HookDelivery is the result of sending a library event to a hook
+type HookDelivery struct {
+	ID          int64
+	Hook        string // Name of the hook
+	Event       string
+	Attempts    int
+	Success     bool
+	Status      string // HTTP status or exit code of the last attempt
+	Error       string // Error of the last attempt, if it failed
+	Duration    time.Duration
+	DeliveredAt time.Time
+}
+
+// HookDeliveryManager keeps a log of recent hook deliveries
+type HookDeliveryManager struct {
+	db *DB
+}
+
+// NewHookDeliveryManager creates a new hook delivery manager
+func NewHookDeliveryManager(db *DB) *HookDeliveryManager {
+	return &HookDeliveryManager{db: db}
+}
+
+// RecordDelivery adds a delivery to the log, dropping the oldest beyond
+// the most recent 500
+func (hm *HookDeliveryManager) RecordDelivery(d *HookDelivery) error {
+	result, err := hm.db.conn.Exec(`
+		INSERT INTO hook_deliveries (hook, event, attempts, success, status, error, duration_ms, delivered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, d.Hook, d.Event, d.Attempts, d.Success, d.Status, d.Error, d.Duration.Milliseconds(), d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record hook delivery: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		d.ID = id
+	}
+
+	_, err = hm.db.conn.Exec(`
+		DELETE FROM hook_deliveries WHERE id NOT IN (
+			SELECT id FROM hook_deliveries ORDER BY delivered_at DESC, id DESC LIMIT ?
+		)
+	`, maxHookDeliveries)
+	if err != nil {
+		return fmt.Errorf("failed to prune hook deliveries: %w", err)
+	}
+	return nil
+}
+
+// GetRecentDeliveries returns up to limit deliveries, most recent first
+func (hm *HookDeliveryManager) GetRecentDeliveries(limit int) ([]*HookDelivery, error) {
+	rows, err := hm.db.conn.Query(`
+		SELECT id, hook, event, attempts, success, COALESCE(status, ''), COALESCE(error, ''), duration_ms, delivered_at
+		FROM hook_deliveries
+		ORDER BY delivered_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*HookDelivery
+	for rows.Next() {
+		d := &HookDelivery{}
+		var durationMS int64
+		err := rows.Scan(&d.ID, &d.Hook, &d.Event, &d.Attempts, &d.Success, &d.Status, &d.Error, &durationMS, &d.DeliveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hook delivery: %w", err)
+		}
+		d.Duration = time.Duration(durationMS) * time.Millisecond
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookDeliveryManager(t *testing.T) {
+	db := NewTestDB(t)
+	hm := NewHookDeliveryManager(db)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < maxHookDeliveries+5; i++ {
+		require.NoError(t, hm.RecordDelivery(&HookDelivery{
+			Hook:        "bot",
+			Event:       "new_chapters",
+			Attempts:    1,
+			Success:     true,
+			Status:      "200 OK",
+			Duration:    120 * time.Millisecond,
+			DeliveredAt: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	failed := &HookDelivery{
+		Hook:        "dashboard",
+		Event:       "download_failed",
+		Attempts:    3,
+		Status:      "exit status 1",
+		Error:       "command failed",
+		DeliveredAt: start.AddDate(0, 0, 1),
+	}
+	require.NoError(t, hm.RecordDelivery(failed))
+	assert.NotZero(t, failed.ID)
+
+	recent, err := hm.GetRecentDeliveries(2)
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Equal(t, "dashboard", recent[0].Hook)
+	assert.False(t, recent[0].Success)
+	assert.Equal(t, 3, recent[0].Attempts)
+	assert.Equal(t, "command failed", recent[0].Error)
+	assert.Equal(t, "bot", recent[1].Hook)
+	assert.Equal(t, 120*time.Millisecond, recent[1].Duration)
+	assert.True(t, recent[1].DeliveredAt.Equal(start.Add(time.Duration(maxHookDeliveries+4)*time.Minute)))
+
+	// Only the most recent deliveries are kept
+	all, err := hm.GetRecentDeliveries(1000)
+	require.NoError(t, err)
+	assert.Len(t, all, maxHookDeliveries)
+}
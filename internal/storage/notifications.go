@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NotificationKind is the event a notification reports
+type NotificationKind string
+
+const (
+	NotifyNewChapters      NotificationKind = "new_chapters"
+	NotifyUpdateFailed     NotificationKind = "update_failed"
+	NotifyDownloadComplete NotificationKind = "download_complete"
+	NotifyDownloadFailed   NotificationKind = "download_failed"
+	NotifyServerStatus     NotificationKind = "server_status"
+	NotifyExtensionUpdate  NotificationKind = "extension_update"
+	NotifyError            NotificationKind = "error" // Problems found by the app itself, e.g. at startup
+)
+
+// NotificationKinds lists every notification kind, in display order
+var NotificationKinds = []NotificationKind{
+	NotifyNewChapters,
+	NotifyUpdateFailed,
+	NotifyDownloadComplete,
+	NotifyDownloadFailed,
+	NotifyServerStatus,
+	NotifyExtensionUpdate,
+	NotifyError,
+}
+
+// NotificationSeverity is how urgent a notification is
+type NotificationSeverity int
+
+const (
+	SeverityInfo NotificationSeverity = iota
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// Notification is an event shown in the notification center
+type Notification struct {
+	ID         int64
+	Kind       NotificationKind
+	Severity   NotificationSeverity
+	Title      string
+	Message    string
+	Suggestion string // What the user can do about it
+	MangaID    string // Manga the notification is about, if any
+	ChapterID  string // Chapter the notification is about, if any
+	CreatedAt  time.Time
+	Read       bool
+}
+
+// NotificationFilter selects notifications to list
+type NotificationFilter struct {
+	Kind       NotificationKind // Empty for every kind
+	UnreadOnly bool
+	Limit      int // 0 for no limit
+}
+
+// NotificationsManager stores notifications from every part of the app
+type NotificationsManager struct {
+	db *DB
+}
+
+// NewNotificationsManager creates a new notifications manager
+func NewNotificationsManager(db *DB) *NotificationsManager {
+	return &NotificationsManager{db: db}
+}
+
+// Add stores a notification and sets its ID
+func (nm *NotificationsManager) Add(n *Notification) error {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	result, err := nm.db.conn.Exec(`
+		INSERT INTO notifications (kind, severity, title, message, suggestion, manga_id, chapter_id, created_at, is_read)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, n.Kind, n.Severity, n.Title, n.Message, n.Suggestion, n.MangaID, n.ChapterID, n.CreatedAt, n.Read)
+	if err != nil {
+		return fmt.Errorf("failed to add notification: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get notification ID: %w", err)
+	}
+	n.ID = id
+	return nil
+}
+
+// AddUnlessUnread stores a notification unless an unread one of the same
+// kind with the same title and message exists, so problems reported on
+// every start are listed once. It reports whether it was added.
+func (nm *NotificationsManager) AddUnlessUnread(n *Notification) (bool, error) {
+	var exists bool
+	err := nm.db.conn.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM notifications
+			WHERE kind = ? AND title = ? AND COALESCE(message, '') = ? AND NOT is_read
+		)
+	`, n.Kind, n.Title, n.Message).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check notifications: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+	if err := nm.Add(n); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List returns the notifications matching a filter, newest first
+func (nm *NotificationsManager) List(filter NotificationFilter) ([]*Notification, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Kind != "" {
+		conditions = append(conditions, "kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if filter.UnreadOnly {
+		conditions = append(conditions, "NOT is_read")
+	}
+
+	query := `
+		SELECT id, kind, severity, title, COALESCE(message, ''), COALESCE(suggestion, ''),
+		       COALESCE(manga_id, ''), COALESCE(chapter_id, ''), created_at, is_read
+		FROM notifications`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := nm.db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*Notification
+	for rows.Next() {
+		n := &Notification{}
+		err := rows.Scan(&n.ID, &n.Kind, &n.Severity, &n.Title, &n.Message, &n.Suggestion,
+			&n.MangaID, &n.ChapterID, &n.CreatedAt, &n.Read)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+// CountUnread returns the number of unread notifications
+func (nm *NotificationsManager) CountUnread() (int, error) {
+	var count int
+	err := nm.db.conn.QueryRow("SELECT COUNT(*) FROM notifications WHERE NOT is_read").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// SetRead marks a notification read or unread
+func (nm *NotificationsManager) SetRead(id int64, read bool) error {
+	_, err := nm.db.conn.Exec("UPDATE notifications SET is_read = ? WHERE id = ?", read, id)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of a kind read, or every
+// notification if kind is empty
+func (nm *NotificationsManager) MarkAllRead(kind NotificationKind) error {
+	var err error
+	if kind == "" {
+		_, err = nm.db.conn.Exec("UPDATE notifications SET is_read = TRUE WHERE NOT is_read")
+	} else {
+		_, err = nm.db.conn.Exec("UPDATE notifications SET is_read = TRUE WHERE NOT is_read AND kind = ?", kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a notification
+func (nm *NotificationsManager) Delete(id int64) error {
+	_, err := nm.db.conn.Exec("DELETE FROM notifications WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification: %w", err)
+	}
+	return nil
+}
+
+// Prune removes notifications created before the cutoff and all but the
+// newest maxCount. A zero cutoff or maxCount disables that limit. It
+// returns the number of notifications removed.
+func (nm *NotificationsManager) Prune(before time.Time, maxCount int) (int64, error) {
+	var removed int64
+	err := nm.db.WithTransaction(func(tx *sql.Tx) error {
+		if !before.IsZero() {
+			result, err := tx.Exec("DELETE FROM notifications WHERE created_at < ?", before)
+			if err != nil {
+				return fmt.Errorf("failed to prune old notifications: %w", err)
+			}
+			n, _ := result.RowsAffected()
+			removed += n
+		}
+		if maxCount > 0 {
+			result, err := tx.Exec(`
+				DELETE FROM notifications WHERE id NOT IN (
+					SELECT id FROM notifications ORDER BY created_at DESC, id DESC LIMIT ?
+				)
+			`, maxCount)
+			if err != nil {
+				return fmt.Errorf("failed to prune notifications: %w", err)
+			}
+			n, _ := result.RowsAffected()
+			removed += n
+		}
+		return nil
+	})
+	return removed, err
+}
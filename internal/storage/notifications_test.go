@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationsManager(t *testing.T) {
+	db := NewTestDB(t)
+	nm := NewNotificationsManager(db)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	chapters := &Notification{Kind: NotifyNewChapters, Title: "Alpha", Message: "2 new chapters", MangaID: "m1", CreatedAt: start}
+	require.NoError(t, nm.Add(chapters))
+	assert.NotZero(t, chapters.ID)
+	require.NoError(t, nm.Add(&Notification{Kind: NotifyDownloadFailed, Severity: SeverityError, Title: "Beta", MangaID: "m2", ChapterID: "c9", CreatedAt: start.Add(time.Hour)}))
+
+	// Problems reported again while unread are stored once
+	serverDown := &Notification{Kind: NotifyError, Severity: SeverityWarning, Title: "No Server Configured", Suggestion: "Add a server", CreatedAt: start.Add(2 * time.Hour)}
+	added, err := nm.AddUnlessUnread(serverDown)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = nm.AddUnlessUnread(&Notification{Kind: NotifyError, Title: "No Server Configured", CreatedAt: start.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	all, err := nm.List(NotificationFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "No Server Configured", all[0].Title, "newest first")
+	assert.Equal(t, "Add a server", all[0].Suggestion)
+	assert.Equal(t, SeverityError, all[1].Severity)
+	assert.Equal(t, "c9", all[1].ChapterID)
+	assert.True(t, all[2].CreatedAt.Equal(start))
+
+	failed, err := nm.List(NotificationFilter{Kind: NotifyDownloadFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "Beta", failed[0].Title)
+
+	// Read state
+	require.NoError(t, nm.SetRead(chapters.ID, true))
+	unread, err := nm.List(NotificationFilter{UnreadOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, unread, 2)
+	count, err := nm.CountUnread()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, nm.MarkAllRead(NotifyError))
+	count, err = nm.CountUnread()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Once read, the same problem is stored again
+	added, err = nm.AddUnlessUnread(&Notification{Kind: NotifyError, Title: "No Server Configured", CreatedAt: start.Add(4 * time.Hour)})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	require.NoError(t, nm.MarkAllRead(""))
+	count, err = nm.CountUnread()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	require.NoError(t, nm.Delete(chapters.ID))
+	all, err = nm.List(NotificationFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestNotificationsManager_Prune(t *testing.T) {
+	db := NewTestDB(t)
+	nm := NewNotificationsManager(db)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for day := 0; day < 10; day++ {
+		require.NoError(t, nm.Add(&Notification{
+			Kind:      NotifyNewChapters,
+			Title:     "Manga",
+			CreatedAt: start.AddDate(0, 0, day),
+		}))
+	}
+
+	// Older than day 3
+	removed, err := nm.Prune(start.AddDate(0, 0, 3), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), removed)
+
+	// Keep the newest 5
+	removed, err = nm.Prune(time.Time{}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed)
+
+	all, err := nm.List(NotificationFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	assert.True(t, all[0].CreatedAt.Equal(start.AddDate(0, 0, 9)))
+	assert.True(t, all[4].CreatedAt.Equal(start.AddDate(0, 0, 5)))
+
+	limited, err := nm.List(NotificationFilter{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, limited, 2)
+}
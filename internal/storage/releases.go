@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// releaseGap merges chapters released this close together into one
+	// release, since series often drop several chapters at once
+	releaseGap = 12 * time.Hour
+
+	// maxReleaseIntervals is the number of recent intervals estimates are
+	// based on, so a changed release pattern is picked up
+	maxReleaseIntervals = 12
+
+	// minReleaseIntervals is the number of intervals needed for an estimate
+	minReleaseIntervals = 2
+)
+
+// ReleaseConfidence is how much a release estimate can be trusted
+type ReleaseConfidence int
+
+const (
+	ConfidenceLow    ReleaseConfidence = iota // Few or irregular releases
+	ConfidenceMedium                          // Some variation between releases
+	ConfidenceHigh                            // A steady schedule
+)
+
+// String returns the name of the confidence
+func (c ReleaseConfidence) String() string {
+	switch c {
+	case ConfidenceHigh:
+		return "high"
+	case ConfidenceMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// ReleaseEstimate predicts the next chapter release of a manga. The next
+// release is expected at Expected, most likely between Earliest and Latest.
+type ReleaseEstimate struct {
+	MangaID    string
+	MangaTitle string
+
+	LastRelease time.Time
+	Interval    time.Duration // Typical time between releases
+	Expected    time.Time
+	Earliest    time.Time
+	Latest      time.Time
+	Confidence  ReleaseConfidence
+	Releases    int // Releases the estimate is based on
+}
+
+// IsOverdue reports whether the release is later than expected at now
+func (e *ReleaseEstimate) IsOverdue(now time.Time) bool {
+	return now.After(e.Latest)
+}
+
+// EstimateNextRelease predicts the next release from the release times of
+// a manga's chapters. The typical interval is the median of the recent
+// intervals between releases, the band spans their middle half. It
+// returns nil without at least three releases.
+func EstimateNextRelease(released []time.Time) *ReleaseEstimate {
+	releases := groupReleases(released)
+	if len(releases)-1 < minReleaseIntervals {
+		return nil
+	}
+
+	intervals := make([]time.Duration, 0, len(releases)-1)
+	for i := 1; i < len(releases); i++ {
+		intervals = append(intervals, releases[i].Sub(releases[i-1]))
+	}
+	if len(intervals) > maxReleaseIntervals {
+		intervals = intervals[len(intervals)-maxReleaseIntervals:]
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+
+	median := percentile(intervals, 0.5)
+	low, high := percentile(intervals, 0.25), percentile(intervals, 0.75)
+
+	// A steady schedule still varies by some hours
+	minSpread := max(releaseGap, median/10)
+	if median-low < minSpread {
+		low = median - minSpread
+	}
+	if high-median < minSpread {
+		high = median + minSpread
+	}
+
+	last := releases[len(releases)-1]
+	estimate := &ReleaseEstimate{
+		LastRelease: last,
+		Interval:    median,
+		Expected:    last.Add(median),
+		Earliest:    last.Add(max(low, 0)),
+		Latest:      last.Add(high),
+		Releases:    len(intervals) + 1,
+	}
+
+	spread := float64(percentile(intervals, 0.75)-percentile(intervals, 0.25)) / float64(median)
+	switch {
+	case len(intervals) >= 6 && spread <= 0.2:
+		estimate.Confidence = ConfidenceHigh
+	case len(intervals) >= 3 && spread <= 0.5:
+		estimate.Confidence = ConfidenceMedium
+	default:
+		estimate.Confidence = ConfidenceLow
+	}
+	return estimate
+}
+
+// groupReleases sorts release times and merges those within releaseGap of
+// the first chapter of a release
+func groupReleases(released []time.Time) []time.Time {
+	sorted := make([]time.Time, 0, len(released))
+	for _, t := range released {
+		if !t.IsZero() {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var releases []time.Time
+	for _, t := range sorted {
+		if len(releases) == 0 || t.Sub(releases[len(releases)-1]) >= releaseGap {
+			releases = append(releases, t)
+		}
+	}
+	return releases
+}
+
+// percentile interpolates the p-th percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	frac := pos - float64(lower)
+	return sorted[lower] + time.Duration(frac*float64(sorted[upper]-sorted[lower]))
+}
+
+// GetReleaseEstimates predicts the next release of every tracked manga
+// that is not completed and has enough release history, soonest first
+func (utm *UpdateTrackingManager) GetReleaseEstimates() ([]*ReleaseEstimate, error) {
+	rows, err := utm.db.conn.Query(`
+		SELECT k.manga_id, t.manga_title, k.first_seen, k.upload_date
+		FROM manga_known_chapters k
+		INNER JOIN manga_update_tracking t ON t.manga_id = k.manga_id
+		WHERE t.is_completed = FALSE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query release history: %w", err)
+	}
+	defer rows.Close()
+
+	titles := make(map[string]string)
+	released := make(map[string][]time.Time)
+	for rows.Next() {
+		var mangaID, title string
+		var c KnownChapter
+		var uploadDate sql.NullTime
+		if err := rows.Scan(&mangaID, &title, &c.FirstSeen, &uploadDate); err != nil {
+			return nil, fmt.Errorf("failed to scan release: %w", err)
+		}
+		c.UploadDate = uploadDate.Time
+		titles[mangaID] = title
+		released[mangaID] = append(released[mangaID], c.ReleasedAt())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var estimates []*ReleaseEstimate
+	for mangaID, times := range released {
+		estimate := EstimateNextRelease(times)
+		if estimate == nil {
+			continue
+		}
+		estimate.MangaID = mangaID
+		estimate.MangaTitle = titles[mangaID]
+		estimates = append(estimates, estimate)
+	}
+	sort.Slice(estimates, func(i, j int) bool {
+		if !estimates[i].Expected.Equal(estimates[j].Expected) {
+			return estimates[i].Expected.Before(estimates[j].Expected)
+		}
+		return estimates[i].MangaTitle < estimates[j].MangaTitle
+	})
+	return estimates, nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var releaseStart = time.Date(2025, 1, 6, 15, 0, 0, 0, time.UTC)
+
+// weekly returns n weekly releases, each a few hours off schedule
+func weekly(n int) []time.Time {
+	jitter := []time.Duration{0, 2 * time.Hour, -time.Hour, 3 * time.Hour, time.Hour}
+	var times []time.Time
+	for i := 0; i < n; i++ {
+		times = append(times, releaseStart.AddDate(0, 0, 7*i).Add(jitter[i%len(jitter)]))
+	}
+	return times
+}
+
+func TestEstimateNextRelease(t *testing.T) {
+	// Too little history
+	assert.Nil(t, EstimateNextRelease(nil))
+	assert.Nil(t, EstimateNextRelease(weekly(2)))
+
+	// A steady weekly series
+	times := weekly(9)
+	estimate := EstimateNextRelease(times)
+	require.NotNil(t, estimate)
+	last := times[len(times)-1]
+	assert.True(t, estimate.LastRelease.Equal(last))
+	assert.InDelta(t, 7*24, estimate.Interval.Hours(), 3)
+	assert.True(t, estimate.Expected.Equal(last.Add(estimate.Interval)))
+	assert.True(t, estimate.Earliest.Before(estimate.Expected))
+	assert.True(t, estimate.Latest.After(estimate.Expected))
+	assert.LessOrEqual(t, estimate.Latest.Sub(estimate.Earliest), 2*24*time.Hour, "steady series have a narrow band")
+	assert.Equal(t, ConfidenceHigh, estimate.Confidence)
+	assert.Equal(t, 9, estimate.Releases)
+	assert.False(t, estimate.IsOverdue(last.AddDate(0, 0, 7)))
+	assert.True(t, estimate.IsOverdue(last.AddDate(0, 0, 9)))
+
+	// Chapters dropped together count as one release, in any order
+	batched := append(weekly(4), releaseStart.AddDate(0, 0, 21).Add(10*time.Minute), releaseStart.AddDate(0, 0, 14).Add(time.Hour))
+	estimate = EstimateNextRelease(batched)
+	require.NotNil(t, estimate)
+	assert.Equal(t, 4, estimate.Releases)
+	assert.Equal(t, ConfidenceMedium, estimate.Confidence, "three intervals are not enough for high confidence")
+
+	// Irregular releases give a wide band and low confidence
+	irregular := []time.Time{
+		releaseStart,
+		releaseStart.AddDate(0, 0, 3),
+		releaseStart.AddDate(0, 0, 20),
+		releaseStart.AddDate(0, 0, 26),
+		releaseStart.AddDate(0, 0, 60),
+	}
+	estimate = EstimateNextRelease(irregular)
+	require.NotNil(t, estimate)
+	assert.Equal(t, ConfidenceLow, estimate.Confidence)
+	assert.Greater(t, estimate.Latest.Sub(estimate.Earliest), 7*24*time.Hour)
+	assert.False(t, estimate.Earliest.Before(estimate.LastRelease))
+}
+
+func TestUpdateTrackingManager_GetReleaseEstimates(t *testing.T) {
+	db := NewTestDB(t)
+	utm := NewUpdateTrackingManager(db)
+	checked := releaseStart.AddDate(0, 3, 0)
+
+	known := func(times []time.Time, withDates bool) []KnownChapter {
+		chapters := make([]KnownChapter, len(times))
+		for i, released := range times {
+			chapters[i] = KnownChapter{ChapterID: released.Format(time.RFC3339), ChapterNumber: float64(i + 1), FirstSeen: checked}
+			if withDates {
+				chapters[i].UploadDate = released
+			}
+		}
+		return chapters
+	}
+
+	// m1 releases weekly, m2 every three days starting later, m3 is
+	// completed and m4 has too little history
+	for _, id := range []string{"m1", "m2", "m3", "m4"} {
+		require.NoError(t, utm.RecordUpdateCheck(id, checked, false, 5))
+	}
+	require.NoError(t, utm.SetMangaTitle("m1", "Weekly"))
+	require.NoError(t, utm.SetMangaTitle("m2", "Frequent"))
+
+	// Chapters seen without upload dates gain them on a later check
+	require.NoError(t, utm.AddKnownChapters("m1", known(weekly(6), false)))
+	require.NoError(t, utm.AddKnownChapters("m1", known(weekly(6), true)))
+	chapters, err := utm.GetKnownChapters("m1")
+	require.NoError(t, err)
+	require.Len(t, chapters, 6)
+	assert.True(t, chapters[0].FirstSeen.Equal(checked), "the first sighting is kept")
+	assert.True(t, chapters[0].UploadDate.Equal(weekly(1)[0]))
+
+	var frequent []time.Time
+	for i := 0; i < 5; i++ {
+		frequent = append(frequent, releaseStart.AddDate(0, 0, 30+3*i))
+	}
+	require.NoError(t, utm.AddKnownChapters("m2", known(frequent, true)))
+	require.NoError(t, utm.AddKnownChapters("m3", known(weekly(6), true)))
+	require.NoError(t, utm.MarkAsCompleted("m3", true))
+	require.NoError(t, utm.AddKnownChapters("m4", known(weekly(2), true)))
+
+	estimates, err := utm.GetReleaseEstimates()
+	require.NoError(t, err)
+	require.Len(t, estimates, 2)
+	assert.Equal(t, "m1", estimates[0].MangaID)
+	assert.Equal(t, "Weekly", estimates[0].MangaTitle)
+	assert.Equal(t, "m2", estimates[1].MangaID)
+	assert.Equal(t, "Frequent", estimates[1].MangaTitle)
+	assert.InDelta(t, 72, estimates[1].Interval.Hours(), 0.01)
+	assert.True(t, estimates[1].Expected.Equal(frequent[4].AddDate(0, 0, 3)))
+}
@@ -13,6 +13,10 @@ type Storage struct {
 	Stats         *StatsManager
 	Categories    *CategoryManager
 	UpdateTracking *UpdateTrackingManager
+	Downloads      *DownloadManager
+	Updates        *UpdatesManager
+	Notifications  *NotificationsManager
+	HookDeliveries *HookDeliveryManager
 }
 
 // NewStorage creates a new storage instance with all managers
@@ -31,6 +35,10 @@ func NewStorage(dbPath string) (*Storage, error) {
 		Stats:          NewStatsManager(db),
 		Categories:     NewCategoryManager(db),
 		UpdateTracking: NewUpdateTrackingManager(db),
+		Downloads:      NewDownloadManager(db),
+		Updates:        NewUpdatesManager(db),
+		Notifications:  NewNotificationsManager(db),
+		HookDeliveries: NewHookDeliveryManager(db),
 	}
 
 	// Initialize default categories if needed
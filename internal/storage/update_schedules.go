@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// UpdateMode is how the manga of a category, or a single manga, are
+// checked for new chapters
+type UpdateMode string
+
+const (
+	UpdateModeDefault UpdateMode = ""      // Follow the global update settings
+	UpdateModeEvery   UpdateMode = "every" // Check once the interval has passed since the last check
+	UpdateModeNever   UpdateMode = "never" // Never check in library updates
+)
+
+// UpdateSchedule is how often manga are checked for new chapters
+type UpdateSchedule struct {
+	Mode     UpdateMode
+	Interval time.Duration // Time between checks for UpdateModeEvery, in whole hours
+}
+
+// IsDefault reports whether the schedule follows the global update settings
+func (s UpdateSchedule) IsDefault() bool {
+	return s.Mode == UpdateModeDefault
+}
+
+// String describes the schedule, e.g. "every 6h"
+func (s UpdateSchedule) String() string {
+	switch s.Mode {
+	case UpdateModeEvery:
+		hours := int(s.Interval.Hours())
+		if hours%24 == 0 {
+			return fmt.Sprintf("every %dd", hours/24)
+		}
+		return fmt.Sprintf("every %dh", hours)
+	case UpdateModeNever:
+		return "never"
+	default:
+		return "default"
+	}
+}
+
+// Validate checks that an interval schedule has an interval of whole hours
+func (s UpdateSchedule) Validate() error {
+	switch s.Mode {
+	case UpdateModeDefault, UpdateModeNever:
+		return nil
+	case UpdateModeEvery:
+		if s.Interval < time.Hour || s.Interval%time.Hour != 0 {
+			return fmt.Errorf("invalid update interval: %s (must be whole hours)", s.Interval)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown update mode: %s", s.Mode)
+	}
+}
+
+// moreFrequent reports whether schedule a checks more often than b. An
+// interval beats the global settings, which beat never.
+func (s UpdateSchedule) moreFrequent(other UpdateSchedule) bool {
+	rank := func(s UpdateSchedule) int {
+		switch s.Mode {
+		case UpdateModeEvery:
+			return 0
+		case UpdateModeNever:
+			return 2
+		default:
+			return 1
+		}
+	}
+	if rank(s) != rank(other) {
+		return rank(s) < rank(other)
+	}
+	return s.Mode == UpdateModeEvery && s.Interval < other.Interval
+}
+
+// scheduleHours scans an interval stored in hours
+type scheduleHours time.Duration
+
+// Scan implements sql.Scanner
+func (h *scheduleHours) Scan(value interface{}) error {
+	hours, ok := value.(int64)
+	if !ok {
+		return fmt.Errorf("invalid update interval: %v", value)
+	}
+	*h = scheduleHours(time.Duration(hours) * time.Hour)
+	return nil
+}
+
+// SetUpdateSchedule sets how often the manga of a category are checked
+func (cm *CategoryManager) SetUpdateSchedule(categoryID int, schedule UpdateSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	result, err := cm.db.conn.Exec(`
+		UPDATE categories
+		SET update_mode = ?, update_interval_hours = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, schedule.Mode, int(schedule.Interval.Hours()), categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to set update schedule: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("category not found")
+	}
+	return nil
+}
+
+// SetMangaUpdateSchedule sets how often a manga is checked, overriding its
+// categories. The default schedule removes the override.
+func (cm *CategoryManager) SetMangaUpdateSchedule(mangaID string, schedule UpdateSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	if schedule.IsDefault() {
+		_, err := cm.db.conn.Exec("DELETE FROM manga_update_schedules WHERE manga_id = ?", mangaID)
+		if err != nil {
+			return fmt.Errorf("failed to clear update schedule: %w", err)
+		}
+		return nil
+	}
+
+	_, err := cm.db.conn.Exec(`
+		INSERT INTO manga_update_schedules (manga_id, update_mode, update_interval_hours, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(manga_id) DO UPDATE SET
+			update_mode = excluded.update_mode,
+			update_interval_hours = excluded.update_interval_hours,
+			updated_at = excluded.updated_at
+	`, mangaID, schedule.Mode, int(schedule.Interval.Hours()))
+	if err != nil {
+		return fmt.Errorf("failed to set update schedule: %w", err)
+	}
+	return nil
+}
+
+// GetMangaUpdateSchedule returns the schedule set for a manga itself, or
+// the default schedule if it follows its categories
+func (cm *CategoryManager) GetMangaUpdateSchedule(mangaID string) (UpdateSchedule, error) {
+	schedules, err := cm.querySchedules(`
+		SELECT manga_id, update_mode, update_interval_hours
+		FROM manga_update_schedules
+		WHERE manga_id = ?
+	`, mangaID)
+	if err != nil {
+		return UpdateSchedule{}, err
+	}
+	return schedules[mangaID], nil
+}
+
+// GetUpdateSchedules returns the schedule of every manga that does not
+// follow the global update settings. A manga's own schedule overrides its
+// categories; in several categories, the most frequent schedule wins, so a
+// manga is only never checked if all its categories say so.
+func (cm *CategoryManager) GetUpdateSchedules() (map[string]UpdateSchedule, error) {
+	fromCategories, err := cm.querySchedules(`
+		SELECT mc.manga_id, c.update_mode, c.update_interval_hours
+		FROM manga_categories mc
+		INNER JOIN categories c ON c.id = mc.category_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := cm.querySchedules(`
+		SELECT manga_id, update_mode, update_interval_hours
+		FROM manga_update_schedules
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make(map[string]UpdateSchedule)
+	for mangaID, schedule := range fromCategories {
+		if !schedule.IsDefault() {
+			schedules[mangaID] = schedule
+		}
+	}
+	for mangaID, schedule := range overrides {
+		schedules[mangaID] = schedule
+	}
+	return schedules, nil
+}
+
+// querySchedules runs a query of manga IDs and schedules, keeping the most
+// frequent schedule of each manga
+func (cm *CategoryManager) querySchedules(query string, args ...interface{}) (map[string]UpdateSchedule, error) {
+	rows, err := cm.db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query update schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make(map[string]UpdateSchedule)
+	for rows.Next() {
+		var mangaID string
+		var schedule UpdateSchedule
+		if err := rows.Scan(&mangaID, &schedule.Mode, (*scheduleHours)(&schedule.Interval)); err != nil {
+			return nil, fmt.Errorf("failed to scan update schedule: %w", err)
+		}
+		if current, ok := schedules[mangaID]; !ok || schedule.moreFrequent(current) {
+			schedules[mangaID] = schedule
+		}
+	}
+	return schedules, rows.Err()
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryManager_UpdateSchedules(t *testing.T) {
+	db := NewTestDB(t)
+	cm := NewCategoryManager(db)
+
+	simulpubs, err := cm.Create("Weekly Simulpubs", false)
+	require.NoError(t, err)
+	completed, err := cm.Create("Completed", false)
+	require.NoError(t, err)
+	dropped, err := cm.Create("Dropped", false)
+	require.NoError(t, err)
+	reading, err := cm.Create("Reading", false)
+	require.NoError(t, err)
+
+	every6h := UpdateSchedule{Mode: UpdateModeEvery, Interval: 6 * time.Hour}
+	never := UpdateSchedule{Mode: UpdateModeNever}
+	require.NoError(t, cm.SetUpdateSchedule(simulpubs.ID, every6h))
+	require.NoError(t, cm.SetUpdateSchedule(completed.ID, never))
+	require.NoError(t, cm.SetUpdateSchedule(dropped.ID, never))
+
+	assert.Error(t, cm.SetUpdateSchedule(reading.ID, UpdateSchedule{Mode: UpdateModeEvery, Interval: 90 * time.Minute}))
+	assert.Error(t, cm.SetUpdateSchedule(reading.ID, UpdateSchedule{Mode: "sometimes"}))
+	assert.Error(t, cm.SetUpdateSchedule(9999, never))
+
+	// Schedules are loaded with the categories
+	cat, err := cm.GetByID(simulpubs.ID)
+	require.NoError(t, err)
+	assert.Equal(t, every6h, cat.UpdateSchedule)
+	all, err := cm.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, never, all[1].UpdateSchedule)
+	assert.True(t, all[3].UpdateSchedule.IsDefault())
+
+	require.NoError(t, cm.AssignManga("simulpub", simulpubs.ID))
+	require.NoError(t, cm.AssignManga("finished", completed.ID))
+	require.NoError(t, cm.AssignManga("finished", dropped.ID))
+	require.NoError(t, cm.AssignManga("rereading", completed.ID))
+	require.NoError(t, cm.AssignManga("rereading", reading.ID))
+	require.NoError(t, cm.AssignManga("both", completed.ID))
+	require.NoError(t, cm.AssignManga("both", simulpubs.ID))
+	require.NoError(t, cm.AssignManga("excluded", reading.ID))
+	require.NoError(t, cm.AssignManga("daily", completed.ID))
+
+	// Single manga override their categories
+	daily := UpdateSchedule{Mode: UpdateModeEvery, Interval: 24 * time.Hour}
+	require.NoError(t, cm.SetMangaUpdateSchedule("excluded", never))
+	require.NoError(t, cm.SetMangaUpdateSchedule("daily", daily))
+
+	schedules, err := cm.GetUpdateSchedules()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]UpdateSchedule{
+		"simulpub": every6h,
+		"finished": never,
+		"both":     every6h, // The most frequent category wins
+		"excluded": never,
+		"daily":    daily,
+	}, schedules, "rereading follows the global settings through Reading")
+
+	schedule, err := cm.GetMangaUpdateSchedule("daily")
+	require.NoError(t, err)
+	assert.Equal(t, daily, schedule)
+	assert.Equal(t, "every 1d", schedule.String())
+
+	// Resetting an override falls back to the categories
+	require.NoError(t, cm.SetMangaUpdateSchedule("daily", UpdateSchedule{}))
+	schedule, err = cm.GetMangaUpdateSchedule("daily")
+	require.NoError(t, err)
+	assert.True(t, schedule.IsDefault())
+	schedules, err = cm.GetUpdateSchedules()
+	require.NoError(t, err)
+	assert.Equal(t, never, schedules["daily"])
+}
+
+func TestUpdateSchedule_String(t *testing.T) {
+	assert.Equal(t, "default", UpdateSchedule{}.String())
+	assert.Equal(t, "never", UpdateSchedule{Mode: UpdateModeNever}.String())
+	assert.Equal(t, "every 6h", UpdateSchedule{Mode: UpdateModeEvery, Interval: 6 * time.Hour}.String())
+	assert.Equal(t, "every 7d", UpdateSchedule{Mode: UpdateModeEvery, Interval: 7 * 24 * time.Hour}.String())
+}
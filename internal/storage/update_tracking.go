@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -30,65 +31,40 @@ func NewUpdateTrackingManager(db *DB) *UpdateTrackingManager {
 	return &UpdateTrackingManager{db: db}
 }
 
-// RecordUpdateCheck records an update check for a manga
-func (utm *UpdateTrackingManager) RecordUpdateCheck(mangaID string, foundNewChapters bool, newChapterCount int) error {
-	var existing MangaUpdateTracking
-	err := utm.db.conn.QueryRow(`
-		SELECT manga_id, last_check, last_chapter_found, chapter_count, avg_update_interval_days,
-		       fetch_count, consecutive_failures, is_completed, is_ongoing, created_at, updated_at
-		FROM manga_update_tracking
-		WHERE manga_id = ?
-	`, mangaID).Scan(
-		&existing.MangaID,
-		&existing.LastCheck,
-		&existing.LastChapterFound,
-		&existing.ChapterCount,
-		&existing.AvgUpdateIntervalDays,
-		&existing.FetchCount,
-		&existing.ConsecutiveFailures,
-		&existing.IsCompleted,
-		&existing.IsOngoing,
-		&existing.CreatedAt,
-		&existing.UpdatedAt,
-	)
-
-	now := time.Now()
+// RecordUpdateCheck records a successful update check of a manga at
+// checkedAt, with its current chapter count. Finding new chapters updates
+// the average interval between releases.
+func (utm *UpdateTrackingManager) RecordUpdateCheck(mangaID string, checkedAt time.Time, foundNewChapters bool, chapterCount int) error {
+	existing, err := utm.GetTracking(mangaID)
+	if err != nil {
+		return fmt.Errorf("failed to get update tracking: %w", err)
+	}
 
-	if err == sql.ErrNoRows {
+	if existing == nil {
 		// First time tracking this manga
 		var lastChapterFound *time.Time
 		if foundNewChapters {
-			lastChapterFound = &now
+			lastChapterFound = &checkedAt
 		}
 
 		_, err = utm.db.conn.Exec(`
 			INSERT INTO manga_update_tracking
 			(manga_id, last_check, last_chapter_found, chapter_count, fetch_count, consecutive_failures, created_at, updated_at)
 			VALUES (?, ?, ?, ?, 1, 0, ?, ?)
-		`, mangaID, now, lastChapterFound, newChapterCount, now, now)
-		return err
-	} else if err != nil {
-		return err
-	}
-
-	// Update existing tracking
-	consecutiveFailures := existing.ConsecutiveFailures
-	if foundNewChapters {
-		consecutiveFailures = 0
-	} else {
-		consecutiveFailures++
+		`, mangaID, checkedAt, lastChapterFound, chapterCount, checkedAt, checkedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record update check: %w", err)
+		}
+		return nil
 	}
 
-	var avgInterval *float64
-	var lastChapterFound *time.Time
-
-	if foundNewChapters && newChapterCount > existing.ChapterCount {
-		// New chapters found
-		lastChapterFound = &now
+	lastChapterFound := existing.LastChapterFound
+	avgInterval := existing.AvgUpdateIntervalDays
 
+	if foundNewChapters {
 		// Calculate average update interval
 		if existing.LastChapterFound != nil {
-			daysSinceLastChapter := now.Sub(*existing.LastChapterFound).Hours() / 24
+			daysSinceLastChapter := checkedAt.Sub(*existing.LastChapterFound).Hours() / 24
 			if existing.AvgUpdateIntervalDays != nil {
 				// Running average
 				newAvg := (*existing.AvgUpdateIntervalDays + daysSinceLastChapter) / 2.0
@@ -97,9 +73,7 @@ func (utm *UpdateTrackingManager) RecordUpdateCheck(mangaID string, foundNewChap
 				avgInterval = &daysSinceLastChapter
 			}
 		}
-	} else {
-		lastChapterFound = existing.LastChapterFound
-		avgInterval = existing.AvgUpdateIntervalDays
+		lastChapterFound = &checkedAt
 	}
 
 	_, err = utm.db.conn.Exec(`
@@ -109,12 +83,122 @@ func (utm *UpdateTrackingManager) RecordUpdateCheck(mangaID string, foundNewChap
 		    chapter_count = ?,
 		    avg_update_interval_days = ?,
 		    fetch_count = fetch_count + 1,
-		    consecutive_failures = ?,
+		    consecutive_failures = 0,
 		    updated_at = ?
 		WHERE manga_id = ?
-	`, now, lastChapterFound, newChapterCount, avgInterval, consecutiveFailures, now, mangaID)
+	`, checkedAt, lastChapterFound, chapterCount, avgInterval, checkedAt, mangaID)
+	if err != nil {
+		return fmt.Errorf("failed to record update check: %w", err)
+	}
+	return nil
+}
 
-	return err
+// RecordUpdateFailure records an update check of a manga that failed at
+// checkedAt. Manga failing too often in a row are checked less often by
+// smart updates.
+func (utm *UpdateTrackingManager) RecordUpdateFailure(mangaID string, checkedAt time.Time) error {
+	_, err := utm.db.conn.Exec(`
+		INSERT INTO manga_update_tracking
+		(manga_id, last_check, chapter_count, fetch_count, consecutive_failures, created_at, updated_at)
+		VALUES (?, ?, 0, 1, 1, ?, ?)
+		ON CONFLICT(manga_id) DO UPDATE SET
+		    last_check = excluded.last_check,
+		    fetch_count = fetch_count + 1,
+		    consecutive_failures = consecutive_failures + 1,
+		    updated_at = excluded.updated_at
+	`, mangaID, checkedAt, checkedAt, checkedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record update failure: %w", err)
+	}
+	return nil
+}
+
+// KnownChapter is a chapter seen by a library update
+type KnownChapter struct {
+	ChapterID     string
+	ChapterNumber float64
+	FirstSeen     time.Time
+	UploadDate    time.Time // When the source released it, zero if unknown
+}
+
+// ReleasedAt returns when the chapter was released: its upload date, or
+// when it was first seen without one
+func (c KnownChapter) ReleasedAt() time.Time {
+	if c.UploadDate.IsZero() {
+		return c.FirstSeen
+	}
+	return c.UploadDate
+}
+
+// GetKnownChapters returns the chapters of a manga seen by library updates,
+// in the order they were first seen
+func (utm *UpdateTrackingManager) GetKnownChapters(mangaID string) ([]KnownChapter, error) {
+	rows, err := utm.db.conn.Query(`
+		SELECT chapter_id, chapter_number, first_seen, upload_date
+		FROM manga_known_chapters
+		WHERE manga_id = ?
+		ORDER BY first_seen, chapter_number
+	`, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query known chapters: %w", err)
+	}
+	defer rows.Close()
+
+	var chapters []KnownChapter
+	for rows.Next() {
+		var c KnownChapter
+		var uploadDate sql.NullTime
+		if err := rows.Scan(&c.ChapterID, &c.ChapterNumber, &c.FirstSeen, &uploadDate); err != nil {
+			return nil, fmt.Errorf("failed to scan known chapter: %w", err)
+		}
+		c.UploadDate = uploadDate.Time
+		chapters = append(chapters, c)
+	}
+	return chapters, rows.Err()
+}
+
+// AddKnownChapters records chapters seen by a library update. Chapters
+// already known keep the time they were first seen, and gain an upload
+// date if they had none.
+func (utm *UpdateTrackingManager) AddKnownChapters(mangaID string, chapters []KnownChapter) error {
+	if len(chapters) == 0 {
+		return nil
+	}
+	return utm.db.WithTransaction(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`
+			INSERT INTO manga_known_chapters (manga_id, chapter_id, chapter_number, first_seen, upload_date)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(manga_id, chapter_id) DO UPDATE SET
+				upload_date = COALESCE(manga_known_chapters.upload_date, excluded.upload_date)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare known chapters: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, c := range chapters {
+			var uploadDate interface{}
+			if !c.UploadDate.IsZero() {
+				uploadDate = c.UploadDate
+			}
+			if _, err := stmt.Exec(mangaID, c.ChapterID, c.ChapterNumber, c.FirstSeen, uploadDate); err != nil {
+				return fmt.Errorf("failed to add known chapter: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// SetMangaTitle stores the title of a tracked manga, shown with its
+// release estimate
+func (utm *UpdateTrackingManager) SetMangaTitle(mangaID, title string) error {
+	_, err := utm.db.conn.Exec(`
+		UPDATE manga_update_tracking SET manga_title = ? WHERE manga_id = ?
+	`, title, mangaID)
+	if err != nil {
+		return fmt.Errorf("failed to set manga title: %w", err)
+	}
+	return nil
 }
 
 // MarkAsCompleted marks a manga as completed (no more updates expected)
@@ -159,12 +243,32 @@ func (utm *UpdateTrackingManager) GetTracking(mangaID string) (*MangaUpdateTrack
 	return &tracking, nil
 }
 
+// GetLastChecks returns when each tracked manga was last checked
+func (utm *UpdateTrackingManager) GetLastChecks() (map[string]time.Time, error) {
+	rows, err := utm.db.conn.Query("SELECT manga_id, last_check FROM manga_update_tracking")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last checks: %w", err)
+	}
+	defer rows.Close()
+
+	lastChecks := make(map[string]time.Time)
+	for rows.Next() {
+		var mangaID string
+		var lastCheck time.Time
+		if err := rows.Scan(&mangaID, &lastCheck); err != nil {
+			return nil, fmt.Errorf("failed to scan last check: %w", err)
+		}
+		lastChecks[mangaID] = lastCheck
+	}
+	return lastChecks, rows.Err()
+}
+
 // SmartUpdateConfig configures smart update behavior
 type SmartUpdateConfig struct {
 	MinIntervalHours       int     // Minimum hours between checks (default: 12)
 	UpdateOnlyOngoing      bool    // Only update ongoing series (default: true)
 	UpdateOnlyStarted      bool    // Only update series that have been read (default: false)
-	MaxConsecutiveFailures int     // Skip after this many failures (default: 10)
+	MaxConsecutiveFailures int     // Back off after this many failures (default: 10)
 	MultiplyIntervalBy     float64 // Multiply expected interval by this (default: 1.5 for safety margin)
 }
 
@@ -180,13 +284,12 @@ func DefaultSmartUpdateConfig() *SmartUpdateConfig {
 }
 
 // GetMangaForSmartUpdate returns manga IDs that should be checked for updates
-// based on smart update logic (similar to Mihon/Tachiyomi)
-func (utm *UpdateTrackingManager) GetMangaForSmartUpdate(config *SmartUpdateConfig, allMangaIDs []string) ([]string, error) {
+// at now based on smart update logic (similar to Mihon/Tachiyomi)
+func (utm *UpdateTrackingManager) GetMangaForSmartUpdate(config *SmartUpdateConfig, allMangaIDs []string, now time.Time) ([]string, error) {
 	if config == nil {
 		config = DefaultSmartUpdateConfig()
 	}
 
-	now := time.Now()
 	minCheckTime := now.Add(-time.Duration(config.MinIntervalHours) * time.Hour)
 
 	var smartUpdateIDs []string
@@ -219,8 +322,12 @@ func (utm *UpdateTrackingManager) GetMangaForSmartUpdate(config *SmartUpdateConf
 			continue
 		}
 
-		// Skip if too many consecutive failures
-		if tracking.ConsecutiveFailures >= config.MaxConsecutiveFailures {
+		// Manga failing too often in a row are checked less often rather
+		// than dropped, so they are found again once their source recovers
+		if config.MaxConsecutiveFailures > 0 && tracking.ConsecutiveFailures >= config.MaxConsecutiveFailures {
+			if now.Sub(tracking.LastCheck) >= failureRetryDelay(config, tracking.ConsecutiveFailures) {
+				smartUpdateIDs = append(smartUpdateIDs, mangaID)
+			}
 			continue
 		}
 
@@ -253,6 +360,20 @@ func (utm *UpdateTrackingManager) GetMangaForSmartUpdate(config *SmartUpdateConf
 	return smartUpdateIDs, nil
 }
 
+// maxFailureRetryDelay caps the time between checks of a failing manga
+const maxFailureRetryDelay = 7 * 24 * time.Hour
+
+// failureRetryDelay returns the time to wait after the last check of a
+// manga that failed failures times in a row. It starts at the minimum
+// interval and doubles with every failure past MaxConsecutiveFailures.
+func failureRetryDelay(config *SmartUpdateConfig, failures int) time.Duration {
+	delay := time.Duration(max(config.MinIntervalHours, 1)) * time.Hour
+	for i := config.MaxConsecutiveFailures; i < failures && delay < maxFailureRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxFailureRetryDelay)
+}
+
 // hasMangaBeenRead checks if a manga has any reading history
 func (utm *UpdateTrackingManager) hasMangaBeenRead(mangaID string) (bool, error) {
 	var count int
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTrackingManager_ReleaseHistory(t *testing.T) {
+	db := NewTestDB(t)
+	utm := NewUpdateTrackingManager(db)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+
+	// Checked daily; chapters are released every 7 days
+	require.NoError(t, utm.RecordUpdateCheck("m1", day(0), false, 10))
+	chapters := 10
+	for d := 1; d <= 14; d++ {
+		found := d%7 == 0
+		if found {
+			chapters++
+		}
+		require.NoError(t, utm.RecordUpdateCheck("m1", day(d), found, chapters))
+	}
+
+	tracking, err := utm.GetTracking("m1")
+	require.NoError(t, err)
+	require.NotNil(t, tracking)
+	assert.Equal(t, 12, tracking.ChapterCount)
+	assert.Equal(t, 15, tracking.FetchCount)
+	assert.Equal(t, 0, tracking.ConsecutiveFailures, "checks without new chapters are not failures")
+	require.NotNil(t, tracking.LastChapterFound)
+	assert.True(t, tracking.LastChapterFound.Equal(day(14)))
+	require.NotNil(t, tracking.AvgUpdateIntervalDays)
+	assert.InDelta(t, 7.0, *tracking.AvgUpdateIntervalDays, 0.001)
+
+	config := DefaultSmartUpdateConfig()
+
+	// A release is expected 7 days after the last one, checked 1.5x later
+	ids, err := utm.GetMangaForSmartUpdate(config, []string{"m1", "new"}, day(15))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new"}, ids, "untracked manga are always checked")
+
+	ids, err = utm.GetMangaForSmartUpdate(config, []string{"m1"}, day(25))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1"}, ids)
+
+	// Within the minimum interval of the last check nothing is selected
+	require.NoError(t, utm.RecordUpdateCheck("m1", day(25), false, 12))
+	ids, err = utm.GetMangaForSmartUpdate(config, []string{"m1"}, day(25).Add(6*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestUpdateTrackingManager_Failures(t *testing.T) {
+	db := NewTestDB(t)
+	utm := NewUpdateTrackingManager(db)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := DefaultSmartUpdateConfig()
+	config.MaxConsecutiveFailures = 3
+
+	// Failures are recorded for manga never checked successfully
+	for i := 0; i < 3; i++ {
+		require.NoError(t, utm.RecordUpdateFailure("m1", start.AddDate(0, 0, i)))
+	}
+	tracking, err := utm.GetTracking("m1")
+	require.NoError(t, err)
+	require.NotNil(t, tracking)
+	assert.Equal(t, 3, tracking.ConsecutiveFailures)
+	assert.Equal(t, 3, tracking.FetchCount)
+	assert.True(t, tracking.LastCheck.Equal(start.AddDate(0, 0, 2)))
+
+	// Manga failing too often are checked after a delay doubling with every
+	// further failure
+	lastCheck := start.AddDate(0, 0, 2)
+	ids, err := utm.GetMangaForSmartUpdate(config, []string{"m1"}, lastCheck.Add(11*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, ids, "skipped right after too many failures")
+	ids, err = utm.GetMangaForSmartUpdate(config, []string{"m1"}, lastCheck.Add(12*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1"}, ids)
+
+	require.NoError(t, utm.RecordUpdateFailure("m1", lastCheck))
+	ids, err = utm.GetMangaForSmartUpdate(config, []string{"m1"}, lastCheck.Add(12*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	ids, err = utm.GetMangaForSmartUpdate(config, []string{"m1"}, lastCheck.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1"}, ids)
+
+	assert.Equal(t, 48*time.Hour, failureRetryDelay(config, 5))
+	assert.Equal(t, maxFailureRetryDelay, failureRetryDelay(config, 40), "the delay is capped")
+
+	// A successful check resets the failures
+	require.NoError(t, utm.RecordUpdateCheck("m1", start.AddDate(0, 0, 3), false, 5))
+	tracking, err = utm.GetTracking("m1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, tracking.ConsecutiveFailures)
+	assert.Equal(t, 5, tracking.ChapterCount)
+
+	// Completed series are skipped when only ongoing ones are updated
+	require.NoError(t, utm.MarkAsCompleted("m1", true))
+	ids, err = utm.GetMangaForSmartUpdate(config, []string{"m1"}, start.AddDate(0, 0, 10))
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestUpdateTrackingManager_KnownChapters(t *testing.T) {
+	db := NewTestDB(t)
+	utm := NewUpdateTrackingManager(db)
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := first.AddDate(0, 0, 7)
+
+	require.NoError(t, utm.AddKnownChapters("m1", []KnownChapter{
+		{ChapterID: "c1", ChapterNumber: 1, FirstSeen: first},
+		{ChapterID: "c2", ChapterNumber: 2, FirstSeen: first},
+	}))
+	require.NoError(t, utm.AddKnownChapters("m1", []KnownChapter{
+		{ChapterID: "c2", ChapterNumber: 2, FirstSeen: later},
+		{ChapterID: "c3", ChapterNumber: 3, FirstSeen: later},
+	}))
+	require.NoError(t, utm.AddKnownChapters("m2", []KnownChapter{
+		{ChapterID: "x1", ChapterNumber: 1, FirstSeen: later},
+	}))
+
+	chapters, err := utm.GetKnownChapters("m1")
+	require.NoError(t, err)
+	require.Len(t, chapters, 3)
+	assert.Equal(t, "c1", chapters[0].ChapterID)
+	assert.True(t, chapters[1].FirstSeen.Equal(first), "known chapters keep when they were first seen")
+	assert.Equal(t, "c3", chapters[2].ChapterID)
+	assert.True(t, chapters[2].FirstSeen.Equal(later))
+
+	chapters, err = utm.GetKnownChapters("missing")
+	require.NoError(t, err)
+	assert.Empty(t, chapters)
+}
+
+func TestUpdateTrackingManager_GetLastChecks(t *testing.T) {
+	db := NewTestDB(t)
+	utm := NewUpdateTrackingManager(db)
+	checked := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, utm.RecordUpdateCheck("m1", checked, false, 10))
+	require.NoError(t, utm.RecordUpdateFailure("m2", checked.Add(time.Hour)))
+
+	lastChecks, err := utm.GetLastChecks()
+	require.NoError(t, err)
+	require.Len(t, lastChecks, 2)
+	assert.True(t, lastChecks["m1"].Equal(checked))
+	assert.True(t, lastChecks["m2"].Equal(checked.Add(time.Hour)))
+}
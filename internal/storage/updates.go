@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ChapterUpdate is a chapter found by a library update
+type ChapterUpdate struct {
+	ID            int64
+	MangaID       string
+	MangaTitle    string
+	ChapterID     string
+	ChapterTitle  string
+	ChapterNumber float64
+	PageCount     int
+	SourceType    string
+	SourceID      string
+	FoundAt       time.Time
+	Dismissed     bool
+	IsRead        bool // Completed in reading progress
+}
+
+// UpdatesManager stores the feed of chapters found by library updates
+type UpdatesManager struct {
+	db *DB
+}
+
+// NewUpdatesManager creates a new updates manager
+func NewUpdatesManager(db *DB) *UpdatesManager {
+	return &UpdatesManager{db: db}
+}
+
+// AddUpdates adds chapters to the feed. Chapters already in the feed are
+// left as they are.
+func (um *UpdatesManager) AddUpdates(updates []*ChapterUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	return um.db.WithTransaction(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`
+			INSERT OR IGNORE INTO chapter_updates
+			(manga_id, manga_title, chapter_id, chapter_title, chapter_number, page_count, source_type, source_id, found_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare chapter updates: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, u := range updates {
+			_, err := stmt.Exec(u.MangaID, u.MangaTitle, u.ChapterID, u.ChapterTitle, u.ChapterNumber,
+				u.PageCount, u.SourceType, u.SourceID, u.FoundAt)
+			if err != nil {
+				return fmt.Errorf("failed to add chapter update: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetUpdates returns up to limit chapters of the feed that were not
+// dismissed, most recently found first
+func (um *UpdatesManager) GetUpdates(limit int) ([]*ChapterUpdate, error) {
+	rows, err := um.db.conn.Query(`
+		SELECT u.id, u.manga_id, u.manga_title, u.chapter_id, COALESCE(u.chapter_title, ''), u.chapter_number,
+		       u.page_count, u.source_type, COALESCE(u.source_id, ''), u.found_at, u.dismissed,
+		       EXISTS (SELECT 1 FROM reading_progress p WHERE p.chapter_id = u.chapter_id AND p.is_completed)
+		FROM chapter_updates u
+		WHERE NOT u.dismissed
+		ORDER BY u.found_at DESC, u.manga_title, u.chapter_number DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chapter updates: %w", err)
+	}
+	defer rows.Close()
+
+	var updates []*ChapterUpdate
+	for rows.Next() {
+		u := &ChapterUpdate{}
+		err := rows.Scan(&u.ID, &u.MangaID, &u.MangaTitle, &u.ChapterID, &u.ChapterTitle, &u.ChapterNumber,
+			&u.PageCount, &u.SourceType, &u.SourceID, &u.FoundAt, &u.Dismissed, &u.IsRead)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chapter update: %w", err)
+		}
+		updates = append(updates, u)
+	}
+	return updates, rows.Err()
+}
+
+// CountUnread returns the number of chapters in the feed that are neither
+// dismissed nor read
+func (um *UpdatesManager) CountUnread() (int, error) {
+	var count int
+	err := um.db.conn.QueryRow(`
+		SELECT COUNT(*)
+		FROM chapter_updates u
+		WHERE NOT u.dismissed
+		  AND NOT EXISTS (SELECT 1 FROM reading_progress p WHERE p.chapter_id = u.chapter_id AND p.is_completed)
+	`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread updates: %w", err)
+	}
+	return count, nil
+}
+
+// Dismiss removes a chapter from the feed
+func (um *UpdatesManager) Dismiss(id int64) error {
+	_, err := um.db.conn.Exec("UPDATE chapter_updates SET dismissed = TRUE WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss chapter update: %w", err)
+	}
+	return nil
+}
+
+// DismissRead removes every read chapter from the feed
+func (um *UpdatesManager) DismissRead() (int64, error) {
+	result, err := um.db.conn.Exec(`
+		UPDATE chapter_updates SET dismissed = TRUE
+		WHERE NOT dismissed
+		  AND EXISTS (SELECT 1 FROM reading_progress p WHERE p.chapter_id = chapter_updates.chapter_id AND p.is_completed)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dismiss read updates: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatesManager_Feed(t *testing.T) {
+	db := NewTestDB(t)
+	um := NewUpdatesManager(db)
+	pm := NewProgressManager(db)
+
+	monday := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	require.NoError(t, um.AddUpdates([]*ChapterUpdate{
+		{MangaID: "m1", MangaTitle: "Alpha", ChapterID: "c1", ChapterNumber: 1, PageCount: 20, SourceType: "suwayomi", FoundAt: monday},
+		{MangaID: "m2", MangaTitle: "Beta", ChapterID: "c2", ChapterTitle: "Start", ChapterNumber: 5, SourceType: "suwayomi", SourceID: "src", FoundAt: tuesday},
+		{MangaID: "m2", MangaTitle: "Beta", ChapterID: "c3", ChapterNumber: 6, SourceType: "suwayomi", FoundAt: tuesday},
+	}))
+	// Chapters already in the feed are not added twice
+	require.NoError(t, um.AddUpdates([]*ChapterUpdate{
+		{MangaID: "m1", MangaTitle: "Alpha", ChapterID: "c1", ChapterNumber: 1, SourceType: "suwayomi", FoundAt: tuesday},
+	}))
+
+	updates, err := um.GetUpdates(10)
+	require.NoError(t, err)
+	require.Len(t, updates, 3)
+	assert.Equal(t, "c3", updates[0].ChapterID, "newest first, then by chapter number")
+	assert.Equal(t, "c2", updates[1].ChapterID)
+	assert.Equal(t, "Start", updates[1].ChapterTitle)
+	assert.Equal(t, "src", updates[1].SourceID)
+	assert.Equal(t, "c1", updates[2].ChapterID)
+	assert.True(t, updates[2].FoundAt.Equal(monday))
+
+	count, err := um.CountUnread()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// Reading a chapter marks its update read
+	require.NoError(t, pm.MarkAsCompleted("m1", "c1", 20))
+	count, err = um.CountUnread()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	updates, err = um.GetUpdates(10)
+	require.NoError(t, err)
+	assert.True(t, updates[2].IsRead)
+	assert.False(t, updates[0].IsRead)
+
+	// Dismissed updates leave the feed
+	require.NoError(t, um.Dismiss(updates[0].ID))
+	dismissed, err := um.DismissRead()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), dismissed)
+
+	updates, err = um.GetUpdates(10)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	assert.Equal(t, "c2", updates[0].ChapterID)
+
+	count, err = um.CountUnread()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
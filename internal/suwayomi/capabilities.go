@@ -0,0 +1,187 @@
+package suwayomi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrFeatureUnsupported is returned when the connected server lacks a feature
+var ErrFeatureUnsupported = errors.New("not supported by this Suwayomi server")
+
+// Feature is an optional server capability the client adapts to
+type Feature string
+
+const (
+	FeatureFetchChapterPages     Feature = "fetchChapterPages"
+	FeatureLatestUploadedChapter Feature = "latestUploadedChapter"
+	FeatureExtensionSources      Feature = "extensionSources"
+	FeatureSourcePreferences     Feature = "sourcePreferences"
+	FeatureServerSettings        Feature = "serverSettings"
+)
+
+// FeatureInfo describes a feature and what happens without it
+type FeatureInfo struct {
+	Feature     Feature
+	Description string
+	Fallback    string   // Behaviour when the server lacks the feature
+	Requires    []string // Schema coordinates such as "MangaType.latestUploadedChapter"
+}
+
+// knownFeatures lists the optional features and the schema elements they need.
+// Query and Mutation refer to the schema's root types.
+var knownFeatures = []FeatureInfo{
+	{
+		Feature:     FeatureFetchChapterPages,
+		Description: "Fetch chapter pages via GraphQL",
+		Fallback:    "pages are loaded through the REST chapter endpoint",
+		Requires:    []string{"Mutation.fetchChapterPages"},
+	},
+	{
+		Feature:     FeatureLatestUploadedChapter,
+		Description: "Latest uploaded chapter",
+		Fallback:    "library entries omit the latest chapter",
+		Requires:    []string{"MangaType.latestUploadedChapter"},
+	},
+	{
+		Feature:     FeatureExtensionSources,
+		Description: "Extension source list",
+		Fallback:    "sources of an extension cannot be listed",
+		Requires:    []string{"Query.extension", "ExtensionType.source"},
+	},
+	{
+		Feature:     FeatureSourcePreferences,
+		Description: "Source settings",
+		Fallback:    "source preferences cannot be edited",
+		Requires:    []string{"Query.source", "SourceType.preferences", "Mutation.updateSourcePreference"},
+	},
+	{
+		Feature:     FeatureServerSettings,
+		Description: "Server settings",
+		Fallback:    "server settings cannot be edited",
+		Requires:    []string{"Query.settings", "Mutation.setSettings"},
+	},
+}
+
+// Capabilities records which optional features a server supports
+type Capabilities struct {
+	ServerInfo string
+	DetectedAt time.Time
+	supported  map[Feature]bool
+}
+
+// DetectCapabilities checks an introspected schema for every known feature
+func DetectCapabilities(schema *IntrospectionResult) *Capabilities {
+	caps := &Capabilities{
+		ServerInfo: schema.ServerInfo,
+		DetectedAt: time.Now(),
+		supported:  make(map[Feature]bool, len(knownFeatures)),
+	}
+
+	for _, info := range knownFeatures {
+		supported := true
+		for _, coordinate := range info.Requires {
+			if !schemaHasCoordinate(schema, coordinate) {
+				supported = false
+				break
+			}
+		}
+		caps.supported[info.Feature] = supported
+	}
+
+	return caps
+}
+
+// schemaHasCoordinate reports whether a Type.field coordinate exists
+func schemaHasCoordinate(schema *IntrospectionResult, coordinate string) bool {
+	typeName, fieldName, _ := strings.Cut(coordinate, ".")
+
+	switch typeName {
+	case "Query":
+		if schema.Schema.QueryType == nil {
+			return false
+		}
+		typeName = schema.Schema.QueryType.Name
+	case "Mutation":
+		if schema.Schema.MutationType == nil {
+			return false
+		}
+		typeName = schema.Schema.MutationType.Name
+	}
+
+	t := schema.GetTypeByName(typeName)
+	if t == nil {
+		return false
+	}
+	return fieldName == "" || t.GetFieldByName(fieldName) != nil
+}
+
+// Supports reports whether a feature is available. Before detection has
+// run every feature is assumed to be available.
+func (c *Capabilities) Supports(feature Feature) bool {
+	if c == nil {
+		return true
+	}
+	supported, known := c.supported[feature]
+	return !known || supported
+}
+
+// Disabled returns the features the server lacks
+func (c *Capabilities) Disabled() []FeatureInfo {
+	if c == nil {
+		return nil
+	}
+
+	var disabled []FeatureInfo
+	for _, info := range knownFeatures {
+		if !c.Supports(info.Feature) {
+			disabled = append(disabled, info)
+		}
+	}
+	return disabled
+}
+
+// featureInfo returns the description of a known feature
+func featureInfo(feature Feature) FeatureInfo {
+	for _, info := range knownFeatures {
+		if info.Feature == feature {
+			return info
+		}
+	}
+	return FeatureInfo{Feature: feature, Description: string(feature)}
+}
+
+// unsupportedError wraps ErrFeatureUnsupported for a feature
+func unsupportedError(feature Feature) error {
+	return fmt.Errorf("%s: %w", featureInfo(feature).Description, ErrFeatureUnsupported)
+}
+
+// DetectCapabilities introspects the server and remembers its capabilities.
+// Queries adapt to the result; until it runs, the latest schema is assumed.
+func (c *Client) DetectCapabilities() (*Capabilities, error) {
+	schema, err := c.IntrospectSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect server capabilities: %w", err)
+	}
+
+	caps := DetectCapabilities(schema)
+
+	c.capsMu.Lock()
+	c.capabilities = caps
+	c.capsMu.Unlock()
+
+	return caps, nil
+}
+
+// Capabilities returns the detected capabilities, or nil before detection
+func (c *Client) Capabilities() *Capabilities {
+	c.capsMu.RLock()
+	defer c.capsMu.RUnlock()
+	return c.capabilities
+}
+
+// Supports reports whether the server supports a feature
+func (c *Client) Supports(feature Feature) bool {
+	return c.Capabilities().Supports(feature)
+}
@@ -0,0 +1,150 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutField returns a copy of the schema without a field of a type
+func withoutField(t *testing.T, schema *IntrospectionResult, typeName, fieldName string) *IntrospectionResult {
+	t.Helper()
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	var clone IntrospectionResult
+	require.NoError(t, json.Unmarshal(data, &clone))
+
+	for i := range clone.Schema.Types {
+		if clone.Schema.Types[i].Name != typeName {
+			continue
+		}
+		fields := clone.Schema.Types[i].Fields[:0]
+		for _, f := range clone.Schema.Types[i].Fields {
+			if f.Name != fieldName {
+				fields = append(fields, f)
+			}
+		}
+		clone.Schema.Types[i].Fields = fields
+	}
+	return &clone
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	schema, err := LoadSchemaFromFile("../../schema/suwayomi_schema.json")
+	require.NoError(t, err)
+
+	caps := DetectCapabilities(schema)
+	for _, info := range knownFeatures {
+		assert.True(t, caps.Supports(info.Feature), "baseline schema should support %s", info.Feature)
+	}
+	assert.Empty(t, caps.Disabled())
+
+	old := withoutField(t, schema, "Mutation", "fetchChapterPages")
+	old = withoutField(t, old, "MangaType", "latestUploadedChapter")
+
+	caps = DetectCapabilities(old)
+	assert.False(t, caps.Supports(FeatureFetchChapterPages))
+	assert.False(t, caps.Supports(FeatureLatestUploadedChapter))
+	assert.True(t, caps.Supports(FeatureServerSettings))
+
+	disabled := caps.Disabled()
+	require.Len(t, disabled, 2)
+	assert.Equal(t, FeatureFetchChapterPages, disabled[0].Feature)
+	assert.NotEmpty(t, disabled[0].Fallback)
+}
+
+func TestCapabilities_UndetectedSupportsEverything(t *testing.T) {
+	var caps *Capabilities
+	assert.True(t, caps.Supports(FeatureFetchChapterPages))
+	assert.Nil(t, caps.Disabled())
+
+	client := NewClient("http://localhost:4567")
+	assert.Nil(t, client.Capabilities())
+	assert.True(t, client.Supports(FeatureLatestUploadedChapter))
+}
+
+func TestGraphQLClient_AdaptsToCapabilities(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Query)
+		w.Write([]byte(`{"data": {"mangas": {"totalCount": 0, "nodes": []}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	// Latest schema assumed before detection
+	_, err := client.GraphQL.GetMangaList(true, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "latestUploadedChapter")
+
+	client.capabilities = &Capabilities{supported: map[Feature]bool{
+		FeatureLatestUploadedChapter: false,
+		FeatureFetchChapterPages:     false,
+		FeatureServerSettings:        false,
+	}}
+
+	_, err = client.GraphQL.GetMangaList(true, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.NotContains(t, queries[1], "latestUploadedChapter")
+	assert.Contains(t, queries[1], "thumbnailUrl")
+
+	// Unsupported operations fail without contacting the server
+	err = client.GraphQL.FetchChapterPages(1)
+	assert.ErrorIs(t, err, ErrFeatureUnsupported)
+
+	_, err = client.GetServerSettings()
+	assert.ErrorIs(t, err, ErrFeatureUnsupported)
+	assert.Len(t, queries, 2)
+}
+
+func TestClient_DetectCapabilities(t *testing.T) {
+	schema, err := LoadSchemaFromFile("../../schema/suwayomi_schema.json")
+	require.NoError(t, err)
+	old := withoutField(t, schema, "Mutation", "fetchChapterPages")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.True(t, strings.Contains(req.Query, "__schema"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"__schema": old.Schema},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	caps, err := client.DetectCapabilities()
+	require.NoError(t, err)
+
+	assert.Same(t, caps, client.Capabilities())
+	assert.False(t, client.Supports(FeatureFetchChapterPages))
+	assert.True(t, client.Supports(FeatureLatestUploadedChapter))
+}
+
+func TestClient_FetchChapterPagesREST(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/manga/12/chapter/34", r.URL.Path)
+		w.Write([]byte(`{"id": 34, "name": "Chapter 1", "pageCount": 18}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	pageCount, err := client.FetchChapterPagesREST("12", "34")
+	require.NoError(t, err)
+	assert.Equal(t, 18, pageCount)
+}
@@ -0,0 +1,36 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// restChapter is the subset of the REST chapter response used for page loading
+type restChapter struct {
+	PageCount int `json:"pageCount"`
+}
+
+// FetchChapterPagesREST loads the page list of a chapter through the REST API.
+// It is the fallback for servers without the fetchChapterPages mutation and
+// returns the chapter's page count.
+func (c *Client) FetchChapterPagesREST(mangaID, chapterID string) (int, error) {
+	url := fmt.Sprintf("%s/api/v1/manga/%s/chapter/%s", c.BaseURL, mangaID, chapterID)
+
+	resp, err := c.HTTPClient.Get(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch chapter from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to fetch chapter from %s: %w", url, &StatusError{StatusCode: resp.StatusCode})
+	}
+
+	var chapter restChapter
+	if err := json.NewDecoder(resp.Body).Decode(&chapter); err != nil {
+		return 0, fmt.Errorf("failed to parse chapter response: %w", err)
+	}
+
+	return chapter.PageCount, nil
+}
@@ -0,0 +1,169 @@
+// Package codegen parses GraphQL operation documents, validates them against
+// an introspected Suwayomi schema and generates typed Go client code.
+package codegen
+
+import "fmt"
+
+// Position is a location in an operation document
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String formats the position as file:line:column
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Document is a parsed GraphQL executable document
+type Document struct {
+	Operations []*Operation
+	Fragments  []*Fragment
+}
+
+// Operation is a query, mutation or subscription definition
+type Operation struct {
+	Type         string // "query", "mutation" or "subscription"
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+	Source       string // Original text of the operation, including used fragments
+	Pos          Position
+}
+
+// Fragment is a named fragment definition
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+	Source        string
+	Pos           Position
+}
+
+// VariableDefinition declares an operation variable
+type VariableDefinition struct {
+	Name         string
+	Type         *TypeRef
+	DefaultValue *Value
+	Pos          Position
+}
+
+// TypeRef is a type reference such as [String!]!
+type TypeRef struct {
+	Name    string   // Set for named types
+	Elem    *TypeRef // Set for list types
+	NonNull bool
+}
+
+// String formats the type reference in GraphQL syntax
+func (t *TypeRef) String() string {
+	var s string
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	} else {
+		s = t.Name
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// NamedType returns the innermost named type
+func (t *TypeRef) NamedType() string {
+	if t.Elem != nil {
+		return t.Elem.NamedType()
+	}
+	return t.Name
+}
+
+// Selection is a field, inline fragment or fragment spread
+type Selection interface {
+	position() Position
+}
+
+// Field selects a field, optionally aliased
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Pos          Position
+}
+
+// ResponseKey returns the key the field has in the response
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// InlineFragment selects fields for a type condition
+type InlineFragment struct {
+	TypeCondition string // Empty when the fragment has no type condition
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Pos           Position
+}
+
+// FragmentSpread includes a named fragment
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Pos        Position
+}
+
+func (f *Field) position() Position          { return f.Pos }
+func (f *InlineFragment) position() Position { return f.Pos }
+func (f *FragmentSpread) position() Position { return f.Pos }
+
+// Argument is a field argument
+type Argument struct {
+	Name  string
+	Value *Value
+	Pos   Position
+}
+
+// Directive is a directive such as @include(if: $flag)
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Pos       Position
+}
+
+// ValueKind identifies the kind of a literal value
+type ValueKind int
+
+const (
+	ValueVariable ValueKind = iota
+	ValueInt
+	ValueFloat
+	ValueString
+	ValueBoolean
+	ValueNull
+	ValueEnum
+	ValueList
+	ValueObject
+)
+
+// Value is an argument or default value
+type Value struct {
+	Kind   ValueKind
+	Raw    string         // Variable name, enum name or scalar literal
+	List   []*Value       // ValueList items
+	Fields []*ObjectField // ValueObject fields
+	Pos    Position
+}
+
+// ObjectField is a field of an input object literal
+type ObjectField struct {
+	Name  string
+	Value *Value
+	Pos   Position
+}
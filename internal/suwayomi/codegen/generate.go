@@ -0,0 +1,536 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+)
+
+// Options configures code generation
+type Options struct {
+	Package string   // Go package name of the generated file
+	Sources []string // Operation files, listed in the file header
+}
+
+// scalarTypes maps GraphQL scalars to Go types
+var scalarTypes = map[string]string{
+	"Int":        "int",
+	"Float":      "float64",
+	"String":     "string",
+	"Boolean":    "bool",
+	"ID":         "string",
+	"LongString": "string",
+	"Cursor":     "string",
+}
+
+// initialisms are written in upper case in Go identifiers
+var initialisms = map[string]bool{
+	"api": true, "cbz": true, "html": true, "http": true, "id": true, "ip": true,
+	"json": true, "nsfw": true, "ttl": true, "ui": true, "uri": true, "url": true,
+}
+
+// Merge combines several parsed documents into one
+func Merge(docs ...*Document) *Document {
+	merged := &Document{}
+	for _, doc := range docs {
+		merged.Operations = append(merged.Operations, doc.Operations...)
+		merged.Fragments = append(merged.Fragments, doc.Fragments...)
+	}
+	return merged
+}
+
+// generator holds state while emitting Go code
+type generator struct {
+	idx       *schemaIndex
+	fragments map[string]*Fragment
+	buf       bytes.Buffer
+
+	typeNames  map[string]bool // Declared Go type names
+	enums      map[string]bool // Enum types to declare
+	inputs     map[string]bool // Input object types to declare
+	needsJSON  bool
+	structDefs []string // Response struct declarations, in order
+}
+
+// fieldInfo is a merged field of a selection set
+type fieldInfo struct {
+	key        string
+	def        *suwayomi.SchemaField // nil for __typename
+	typeName   string                // GraphQL type string, used to detect conflicts
+	selections []Selection
+	optional   bool // Only present for some runtime types or skipped by directive
+}
+
+// Generate validates doc against the schema and returns formatted Go source
+// with request and response types and one function per operation.
+func Generate(doc *Document, schema *suwayomi.Schema, opts Options) ([]byte, error) {
+	if err := Validate(doc, schema); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		idx:       newSchemaIndex(schema),
+		fragments: make(map[string]*Fragment),
+		typeNames: make(map[string]bool),
+		enums:     make(map[string]bool),
+		inputs:    make(map[string]bool),
+	}
+	for _, frag := range doc.Fragments {
+		g.fragments[frag.Name] = frag
+	}
+
+	var ops bytes.Buffer
+	for _, op := range doc.Operations {
+		if op.Name == "" {
+			return nil, &ValidationError{Pos: op.Pos, Message: "operations must be named for code generation"}
+		}
+		code, err := g.operation(op)
+		if err != nil {
+			return nil, err
+		}
+		ops.WriteString(code)
+	}
+
+	// Input objects may reference further enums, so they are collected first
+	inputCode, err := g.inputDecls()
+	if err != nil {
+		return nil, err
+	}
+	enumCode, err := g.enumDecls()
+	if err != nil {
+		return nil, err
+	}
+
+	g.header(opts)
+	g.buf.WriteString(enumCode)
+	g.buf.WriteString(inputCode)
+	g.buf.Write(ops.Bytes())
+
+	formatted, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// header writes the file header, imports and the Client interface
+func (g *generator) header(opts Options) {
+	fmt.Fprintf(&g.buf, "// Code generated by schema-tool codegen; DO NOT EDIT.\n")
+	if len(opts.Sources) > 0 {
+		fmt.Fprintf(&g.buf, "// Sources: %s\n", strings.Join(opts.Sources, ", "))
+	}
+	fmt.Fprintf(&g.buf, "\npackage %s\n\n", opts.Package)
+
+	if g.needsJSON {
+		g.buf.WriteString("import \"encoding/json\"\n\n")
+	}
+
+	g.buf.WriteString(`// Client executes GraphQL documents. *suwayomi.GraphQLClient implements it.
+type Client interface {
+	Query(query string, variables map[string]interface{}, result interface{}) error
+	Mutate(mutation string, variables map[string]interface{}, result interface{}) error
+}
+
+`)
+}
+
+// declare reserves a Go type name
+func (g *generator) declare(name string) error {
+	if g.typeNames[name] {
+		return fmt.Errorf("generated type name %s is used twice; rename an operation or alias a field", name)
+	}
+	g.typeNames[name] = true
+	return nil
+}
+
+// operation emits the document constant, response types and function of an operation
+func (g *generator) operation(op *Operation) (string, error) {
+	var b strings.Builder
+
+	// Document with all fragments it uses
+	source := op.Source
+	for _, name := range usedFragments(op.SelectionSet, g.fragments, make(map[string]bool)) {
+		source += "\n\n" + g.fragments[name].Source
+	}
+
+	docName := op.Name + "Document"
+	if err := g.declare(docName); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "// %s is the GraphQL document of the %s %s\n", docName, op.Name, op.Type)
+	fmt.Fprintf(&b, "const %s = %s\n\n", docName, goStringLiteral(source))
+
+	// Response types
+	root := g.idx.rootType(op.Type)
+	responseName := op.Name + "Response"
+	g.structDefs = nil
+	if err := g.structType(responseName, root, op.SelectionSet); err != nil {
+		return "", err
+	}
+	for _, def := range g.structDefs {
+		b.WriteString(def)
+	}
+
+	// Function
+	params := make([]string, 0, len(op.Variables))
+	for _, v := range op.Variables {
+		goType, err := g.inputGoType(v.Type, v.DefaultValue != nil)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, fmt.Sprintf("%s %s", paramName(v.Name), goType))
+	}
+
+	method := "Query"
+	if op.Type == "mutation" {
+		method = "Mutate"
+	}
+
+	if err := g.declare(op.Name); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "// %s executes the %s %s\n", op.Name, op.Name, op.Type)
+	fmt.Fprintf(&b, "func %s(c Client", op.Name)
+	for _, p := range params {
+		b.WriteString(", " + p)
+	}
+	fmt.Fprintf(&b, ") (*%s, error) {\n", responseName)
+
+	b.WriteString("\tvariables := map[string]interface{}{")
+	required := 0
+	for _, v := range op.Variables {
+		if v.Type.NonNull {
+			if required == 0 {
+				b.WriteString("\n")
+			}
+			required++
+			fmt.Fprintf(&b, "\t\t%q: %s,\n", v.Name, paramName(v.Name))
+		}
+	}
+	b.WriteString("}\n")
+	for _, v := range op.Variables {
+		if !v.Type.NonNull {
+			// Omit unset optional variables so server defaults apply
+			fmt.Fprintf(&b, "\tif %s != nil {\n\t\tvariables[%q] = %s\n\t}\n", paramName(v.Name), v.Name, paramName(v.Name))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n\tvar result %s\n", responseName)
+	fmt.Fprintf(&b, "\tif err := c.%s(%s, variables, &result); err != nil {\n\t\treturn nil, err\n\t}\n", method, docName)
+	b.WriteString("\treturn &result, nil\n}\n\n")
+
+	return b.String(), nil
+}
+
+// structType declares a response struct for a selection set on parent
+func (g *generator) structType(name string, parent *suwayomi.SchemaType, selections []Selection) error {
+	if err := g.declare(name); err != nil {
+		return err
+	}
+
+	fields := make([]*fieldInfo, 0)
+	byKey := make(map[string]*fieldInfo)
+	if err := g.collect(parent, selections, false, &fields, byKey); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s is a selection of %s\n", name, parent.Name)
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+
+	// Nested types are declared after this one
+	var nested []func() error
+	for _, f := range fields {
+		goName := exportName(f.key)
+
+		if f.def == nil {
+			fmt.Fprintf(&b, "\t%s string `json:%q`\n", goName, f.key)
+			continue
+		}
+
+		named := namedType(f.def.Type)
+		fieldType := g.idx.types[named.Name]
+
+		var elemType string
+		if isCompositeKind(fieldType.Kind) {
+			elemType = name + goName
+			selections := f.selections
+			nested = append(nested, func() error {
+				return g.structType(elemType, fieldType, selections)
+			})
+		} else {
+			var err error
+			if elemType, err = g.leafGoType(fieldType); err != nil {
+				return err
+			}
+		}
+
+		goType := wrapGoType(f.def.Type, elemType, f.optional)
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", goName, goType, f.key)
+	}
+	b.WriteString("}\n\n")
+
+	g.structDefs = append(g.structDefs, b.String())
+
+	for _, declare := range nested {
+		if err := declare(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect flattens a selection set into fields, merging repeated response keys
+func (g *generator) collect(parent *suwayomi.SchemaType, selections []Selection, optional bool, fields *[]*fieldInfo, byKey map[string]*fieldInfo) error {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			info := &fieldInfo{
+				key:        sel.ResponseKey(),
+				typeName:   "String!",
+				selections: sel.SelectionSet,
+				optional:   optional || len(sel.Directives) > 0,
+			}
+			if sel.Name != "__typename" {
+				info.def = parent.GetFieldByName(sel.Name)
+				info.typeName = schemaTypeString(info.def.Type)
+			}
+
+			if existing, ok := byKey[info.key]; ok {
+				if existing.typeName != info.typeName || (existing.def == nil) != (info.def == nil) {
+					return &ValidationError{
+						Pos:     sel.Pos,
+						Message: fmt.Sprintf("%q is selected as both %s and %s; use different aliases", info.key, existing.typeName, info.typeName),
+					}
+				}
+				existing.selections = append(append([]Selection{}, existing.selections...), info.selections...)
+				existing.optional = existing.optional && info.optional
+				continue
+			}
+
+			byKey[info.key] = info
+			*fields = append(*fields, info)
+
+		case *InlineFragment:
+			target := parent
+			if sel.TypeCondition != "" {
+				target = g.idx.types[sel.TypeCondition]
+			}
+			narrower := target.Name != parent.Name
+			if err := g.collect(target, sel.SelectionSet, optional || narrower || len(sel.Directives) > 0, fields, byKey); err != nil {
+				return err
+			}
+
+		case *FragmentSpread:
+			frag := g.fragments[sel.Name]
+			target := g.idx.types[frag.TypeCondition]
+			narrower := target.Name != parent.Name
+			if err := g.collect(target, frag.SelectionSet, optional || narrower || len(sel.Directives) > 0, fields, byKey); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// leafGoType returns the Go type of a scalar or enum
+func (g *generator) leafGoType(t *suwayomi.SchemaType) (string, error) {
+	switch t.Kind {
+	case "SCALAR":
+		if goType, ok := scalarTypes[t.Name]; ok {
+			return goType, nil
+		}
+		g.needsJSON = true
+		return "json.RawMessage", nil
+	case "ENUM":
+		g.enums[t.Name] = true
+		return t.Name, nil
+	case "INPUT_OBJECT":
+		g.inputs[t.Name] = true
+		return t.Name, nil
+	}
+	return "", fmt.Errorf("type %s of kind %s is not a leaf or input type", t.Name, t.Kind)
+}
+
+// wrapGoType applies list and nullability wrappers of a schema type to a Go element type
+func wrapGoType(ref suwayomi.SchemaTypeRef, elem string, optional bool) string {
+	nonNull := ref.Kind == "NON_NULL"
+	if nonNull && ref.OfType != nil {
+		ref = *ref.OfType
+	}
+
+	if ref.Kind == "LIST" && ref.OfType != nil {
+		// Slices are already nillable
+		return "[]" + wrapGoType(*ref.OfType, elem, false)
+	}
+
+	if !nonNull || optional {
+		return "*" + elem
+	}
+	return elem
+}
+
+// inputGoType returns the Go parameter type of a variable
+func (g *generator) inputGoType(t *TypeRef, hasDefault bool) (string, error) {
+	named, ok := g.idx.types[t.NamedType()]
+	if !ok {
+		return "", fmt.Errorf("unknown type %s", t.NamedType())
+	}
+	elem, err := g.leafGoType(named)
+	if err != nil {
+		return "", err
+	}
+	ref := toSchemaRef(t, g.idx)
+	if hasDefault && ref.Kind == "NON_NULL" {
+		ref = *ref.OfType
+	}
+	return wrapGoType(ref, elem, false), nil
+}
+
+// enumDecls declares the enum types used by the operations
+func (g *generator) enumDecls() (string, error) {
+	var b strings.Builder
+	for _, name := range sortedKeys(g.enums) {
+		if err := g.declare(name); err != nil {
+			return "", err
+		}
+		t := g.idx.types[name]
+		fmt.Fprintf(&b, "// %s is the GraphQL %s enum\ntype %s string\n\n", name, name, name)
+		b.WriteString("const (\n")
+		for _, v := range t.EnumValues {
+			fmt.Fprintf(&b, "\t%s%s %s = %q\n", name, enumValueName(v.Name), name, v.Name)
+		}
+		b.WriteString(")\n\n")
+	}
+	return b.String(), nil
+}
+
+// inputDecls declares the input object types used by the operations,
+// including input types they reference
+func (g *generator) inputDecls() (string, error) {
+	var b strings.Builder
+	done := make(map[string]bool)
+
+	for {
+		pending := make([]string, 0)
+		for _, name := range sortedKeys(g.inputs) {
+			if !done[name] {
+				pending = append(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		for _, name := range pending {
+			done[name] = true
+			if err := g.declare(name); err != nil {
+				return "", err
+			}
+			t := g.idx.types[name]
+			fmt.Fprintf(&b, "// %s is the GraphQL %s input\ntype %s struct {\n", name, name, name)
+			for _, field := range t.InputFields {
+				named := g.idx.types[namedType(field.Type).Name]
+				elem, err := g.leafGoType(named)
+				if err != nil {
+					return "", err
+				}
+				tag := field.Name
+				if field.Type.Kind != "NON_NULL" {
+					tag += ",omitempty"
+				}
+				fmt.Fprintf(&b, "\t%s %s `json:%q`\n", exportName(field.Name), wrapGoType(field.Type, elem, false), tag)
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+
+	return b.String(), nil
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// goStringLiteral quotes s as a raw string when possible
+func goStringLiteral(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// splitWords splits a camelCase or snake_case name into lower-case words
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// exportName converts a GraphQL name to an exported Go identifier
+func exportName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if initialisms[word] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// enumValueName converts an enum value such as DESC_NULLS_FIRST to DescNullsFirst
+func enumValueName(value string) string {
+	return exportName(strings.ToLower(value))
+}
+
+// paramName converts a variable name to a Go parameter name
+func paramName(name string) string {
+	exported := exportName(name)
+	words := splitWords(name)
+	if len(words) > 0 && initialisms[words[0]] {
+		return strings.ToLower(exported[:len(words[0])]) + exported[len(words[0]):]
+	}
+	param := strings.ToLower(exported[:1]) + exported[1:]
+	switch param {
+	case "c", "variables", "result", "err", "type", "func", "range", "map", "interface":
+		return param + "_"
+	}
+	return param
+}
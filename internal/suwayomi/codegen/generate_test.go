@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	goparser "go/parser"
+	gotoken "go/token"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := loadSchema(t)
+
+	doc, err := Parse("test.graphql", `
+query Library($first: Int, $order: [MangaOrderInput!]) {
+  mangas(first: $first, order: $order) {
+    nodes {
+      ...MangaInfo
+      thumbnailUrl
+    }
+  }
+}
+
+query Prefs($id: LongString!) {
+  source(id: $id) {
+    preferences {
+      __typename
+      ... on SwitchPreference { key switchValue: currentValue }
+      ... on ListPreference { key listValue: currentValue }
+    }
+  }
+}
+
+fragment MangaInfo on MangaType {
+  id
+  title
+  status
+}`)
+	require.NoError(t, err)
+
+	code, err := Generate(doc, schema, Options{Package: "ops", Sources: []string{"test.graphql"}})
+	require.NoError(t, err)
+
+	_, err = goparser.ParseFile(gotoken.NewFileSet(), "ops_gen.go", code, 0)
+	require.NoError(t, err, "generated code should parse:\n%s", code)
+
+	src := string(code)
+	assert.Contains(t, src, "// Code generated by schema-tool codegen; DO NOT EDIT.")
+	assert.Contains(t, src, "package ops")
+
+	// Operation functions take variables as parameters
+	assert.Contains(t, src, "func Library(c Client, first *int, order []MangaOrderInput) (*LibraryResponse, error)")
+	assert.Contains(t, src, "func Prefs(c Client, id string) (*PrefsResponse, error)")
+	assert.Contains(t, src, `if first != nil {`)
+
+	// Fragments are appended to the document and expanded into the struct
+	assert.Contains(t, src, "fragment MangaInfo on MangaType")
+	assert.Regexp(t, `Status\s+MangaStatus\s+`+"`"+`json:"status"`, src)
+	assert.Regexp(t, `ThumbnailURL\s+\*string`, src)
+
+	// Enums and input objects used by the operations are declared
+	assert.Contains(t, src, "type MangaStatus string")
+	assert.Regexp(t, `MangaStatusOngoing\s+MangaStatus = "ONGOING"`, src)
+	assert.Contains(t, src, "type SortOrder string")
+	assert.Regexp(t, `SortOrderDescNullsFirst\s+SortOrder = "DESC_NULLS_FIRST"`, src)
+	assert.Contains(t, src, "type MangaOrderInput struct")
+	assert.Regexp(t, `ByType\s+\*SortOrder\s+`+"`"+`json:"byType,omitempty"`, src)
+
+	// Fields that depend on the union member are merged and optional
+	assert.Regexp(t, `Typename\s+string\s+`+"`"+`json:"__typename"`, src)
+	assert.Regexp(t, `Key\s+\*string\s+`+"`"+`json:"key"`, src)
+	assert.Regexp(t, `SwitchValue\s+\*bool`, src)
+	assert.Regexp(t, `ListValue\s+\*string`, src)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	schema := loadSchema(t)
+
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{"anonymous operation", "{ aboutServer { name } }", "must be named"},
+		{"invalid operation", "query Q { aboutServer { nope } }", `field "nope" does not exist`},
+		{"conflicting union fields", `query Q { source(id: "1") { preferences {
+			... on SwitchPreference { value: currentValue }
+			... on ListPreference { value: currentValue }
+		} } }`, "use different aliases"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse("test.graphql", tt.input)
+			require.NoError(t, err)
+
+			_, err = Generate(doc, schema, Options{Package: "ops"})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+// TestGenerate_OperationsUpToDate fails when the checked-in operations code
+// no longer matches its .graphql files or the saved schema
+func TestGenerate_OperationsUpToDate(t *testing.T) {
+	schema := loadSchema(t)
+
+	doc, files, err := ParseDir("../operations")
+	require.NoError(t, err)
+
+	code, err := Generate(doc, schema, Options{Package: "operations", Sources: files})
+	require.NoError(t, err)
+
+	existing, err := os.ReadFile("../operations/operations_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(code), string(existing), "run go generate ./internal/suwayomi/operations")
+}
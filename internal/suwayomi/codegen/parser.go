@@ -0,0 +1,719 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind identifies a lexical token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token with its position and byte offsets
+type token struct {
+	kind  tokenKind
+	value string
+	pos   Position
+	start int
+	end   int
+}
+
+// lexer splits a GraphQL document into tokens
+type lexer struct {
+	file   string
+	input  string
+	offset int
+	line   int
+	col    int
+}
+
+// ParseError is a syntax error in an operation document
+type ParseError struct {
+	Pos     Position
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// next returns the next token, skipping whitespace, commas and comments
+func (l *lexer) next() (token, error) {
+	for l.offset < len(l.input) {
+		c := l.input[l.offset]
+		switch {
+		case c == '\n':
+			l.offset++
+			l.line++
+			l.col = 1
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.offset < len(l.input) && l.input[l.offset] != '\n' {
+				l.advance(1)
+			}
+		default:
+			return l.scan()
+		}
+	}
+
+	return token{kind: tokenEOF, pos: l.pos(), start: l.offset, end: l.offset}, nil
+}
+
+func (l *lexer) pos() Position {
+	return Position{File: l.file, Line: l.line, Column: l.col}
+}
+
+func (l *lexer) advance(n int) {
+	l.offset += n
+	l.col += n
+}
+
+// scan reads a single token at the current offset
+func (l *lexer) scan() (token, error) {
+	start := l.offset
+	pos := l.pos()
+	c := l.input[l.offset]
+
+	switch {
+	case strings.ContainsRune("!$()&:=@[]{}|", rune(c)):
+		l.advance(1)
+		return token{kind: tokenPunct, value: string(c), pos: pos, start: start, end: l.offset}, nil
+
+	case c == '.':
+		if strings.HasPrefix(l.input[l.offset:], "...") {
+			l.advance(3)
+			return token{kind: tokenPunct, value: "...", pos: pos, start: start, end: l.offset}, nil
+		}
+		return token{}, &ParseError{Pos: pos, Message: "unexpected '.'"}
+
+	case c == '_' || isLetter(c):
+		for l.offset < len(l.input) && (l.input[l.offset] == '_' || isLetter(l.input[l.offset]) || isDigit(l.input[l.offset])) {
+			l.advance(1)
+		}
+		return token{kind: tokenName, value: l.input[start:l.offset], pos: pos, start: start, end: l.offset}, nil
+
+	case c == '-' || isDigit(c):
+		return l.scanNumber(start, pos)
+
+	case c == '"':
+		return l.scanString(start, pos)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.input[l.offset:])
+	return token{}, &ParseError{Pos: pos, Message: fmt.Sprintf("unexpected character %q", r)}
+}
+
+// scanNumber reads an int or float literal
+func (l *lexer) scanNumber(start int, pos Position) (token, error) {
+	kind := tokenInt
+	if l.input[l.offset] == '-' {
+		l.advance(1)
+	}
+	digits := func() int {
+		n := 0
+		for l.offset < len(l.input) && isDigit(l.input[l.offset]) {
+			l.advance(1)
+			n++
+		}
+		return n
+	}
+
+	if digits() == 0 {
+		return token{}, &ParseError{Pos: pos, Message: "invalid number"}
+	}
+	if l.offset < len(l.input) && l.input[l.offset] == '.' {
+		kind = tokenFloat
+		l.advance(1)
+		if digits() == 0 {
+			return token{}, &ParseError{Pos: pos, Message: "invalid number"}
+		}
+	}
+	if l.offset < len(l.input) && (l.input[l.offset] == 'e' || l.input[l.offset] == 'E') {
+		kind = tokenFloat
+		l.advance(1)
+		if l.offset < len(l.input) && (l.input[l.offset] == '+' || l.input[l.offset] == '-') {
+			l.advance(1)
+		}
+		if digits() == 0 {
+			return token{}, &ParseError{Pos: pos, Message: "invalid number"}
+		}
+	}
+
+	return token{kind: kind, value: l.input[start:l.offset], pos: pos, start: start, end: l.offset}, nil
+}
+
+// scanString reads a string or block string literal, keeping it quoted
+func (l *lexer) scanString(start int, pos Position) (token, error) {
+	if strings.HasPrefix(l.input[l.offset:], `"""`) {
+		end := strings.Index(l.input[l.offset+3:], `"""`)
+		if end < 0 {
+			return token{}, &ParseError{Pos: pos, Message: "unterminated block string"}
+		}
+		text := l.input[l.offset : l.offset+3+end+3]
+		for _, r := range text {
+			if r == '\n' {
+				l.line++
+				l.col = 1
+			} else {
+				l.col++
+			}
+		}
+		l.offset += len(text)
+		return token{kind: tokenString, value: text, pos: pos, start: start, end: l.offset}, nil
+	}
+
+	l.advance(1)
+	for l.offset < len(l.input) {
+		switch l.input[l.offset] {
+		case '\\':
+			l.advance(2)
+		case '"':
+			l.advance(1)
+			return token{kind: tokenString, value: l.input[start:l.offset], pos: pos, start: start, end: l.offset}, nil
+		case '\n':
+			return token{}, &ParseError{Pos: pos, Message: "unterminated string"}
+		default:
+			l.advance(1)
+		}
+	}
+
+	return token{}, &ParseError{Pos: pos, Message: "unterminated string"}
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// parser builds a Document from tokens
+type parser struct {
+	lex     *lexer
+	tok     token
+	prevEnd int // End offset of the last consumed token
+	input   string
+}
+
+// ParseFile parses an operation document from a file
+func ParseFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return Parse(path, string(data))
+}
+
+// ParseDir parses every .graphql file in a directory and merges them into
+// one document. It also returns the parsed file names in sorted order.
+func ParseDir(dir string) (*Document, []string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list operations in %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no .graphql files found in %s", dir)
+	}
+	sort.Strings(files)
+
+	docs := make([]*Document, 0, len(files))
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		doc, err := ParseFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		docs = append(docs, doc)
+		names = append(names, filepath.Base(file))
+	}
+	return Merge(docs...), names, nil
+}
+
+// Parse parses a GraphQL executable document
+func Parse(file, input string) (*Document, error) {
+	p := &parser{
+		lex:   &lexer{file: file, input: input, line: 1, col: 1},
+		input: input,
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	for p.tok.kind != tokenEOF {
+		start := p.tok.start
+
+		switch {
+		case p.peek(tokenPunct, "{"):
+			// Anonymous query shorthand
+			op := &Operation{Type: "query", Pos: p.tok.pos}
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			op.SelectionSet = selections
+			op.Source = input[start:p.prevEnd]
+			doc.Operations = append(doc.Operations, op)
+
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			op.Source = input[start:p.prevEnd]
+			doc.Operations = append(doc.Operations, op)
+
+		case p.peek(tokenName, "fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			frag.Source = input[start:p.prevEnd]
+			doc.Fragments = append(doc.Fragments, frag)
+
+		default:
+			return nil, p.errorf("expected operation or fragment definition, got %q", p.tok.value)
+		}
+	}
+
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.prevEnd = p.tok.end
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Pos: p.tok.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// expect consumes a punctuator
+func (p *parser) expect(value string) error {
+	if !p.peek(tokenPunct, value) {
+		return p.errorf("expected %q, got %q", value, p.tok.value)
+	}
+	return p.advance()
+}
+
+// name consumes a name token
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected name, got %q", p.tok.value)
+	}
+	value := p.tok.value
+	return value, p.advance()
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value, Pos: p.tok.pos}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek(tokenPunct, "(") {
+		vars, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.Variables = vars
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = selections
+
+	return op, nil
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	frag := &Fragment{Pos: p.tok.pos}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	frag.Name = name
+
+	if !p.peek(tokenName, "on") {
+		return nil, p.errorf("expected \"on\" in fragment %s", name)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if frag.TypeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	if frag.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return frag, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var vars []*VariableDefinition
+	for !p.peek(tokenPunct, ")") {
+		def := &VariableDefinition{Pos: p.tok.pos}
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		def.Name = name
+
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if def.Type, err = p.parseType(); err != nil {
+			return nil, err
+		}
+
+		if p.peek(tokenPunct, "=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if def.DefaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+
+		vars = append(vars, def)
+	}
+
+	return vars, p.expect(")")
+}
+
+func (p *parser) parseType() (*TypeRef, error) {
+	var ref *TypeRef
+
+	if p.peek(tokenPunct, "[") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Elem: elem}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Name: name}
+	}
+
+	if p.peek(tokenPunct, "!") {
+		ref.NonNull = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ref, nil
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+	for !p.peek(tokenPunct, "}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.errorf("unexpected end of document in selection set")
+		}
+
+		if p.peek(tokenPunct, "...") {
+			sel, err := p.parseFragmentSelection()
+			if err != nil {
+				return nil, err
+			}
+			selections = append(selections, sel)
+			continue
+		}
+
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, field)
+	}
+
+	if len(selections) == 0 {
+		return nil, p.errorf("selection set cannot be empty")
+	}
+
+	return selections, p.expect("}")
+}
+
+func (p *parser) parseFragmentSelection() (Selection, error) {
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	// Fragment spread
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		spread := &FragmentSpread{Name: p.tok.value, Pos: pos}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		spread.Directives = directives
+		return spread, nil
+	}
+
+	// Inline fragment
+	inline := &InlineFragment{Pos: pos}
+	if p.peek(tokenName, "on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		inline.TypeCondition = name
+	}
+
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+	inline.Directives = directives
+
+	if inline.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return inline, nil
+}
+
+func (p *parser) parseField() (*Field, error) {
+	field := &Field{Pos: p.tok.pos}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek(tokenPunct, ":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		field.Alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+
+	if p.peek(tokenPunct, "(") {
+		if field.Arguments, err = p.parseArguments(false); err != nil {
+			return nil, err
+		}
+	}
+
+	if field.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	if p.peek(tokenPunct, "{") {
+		if field.SelectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return field, nil
+}
+
+func (p *parser) parseArguments(constant bool) ([]*Argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []*Argument
+	for !p.peek(tokenPunct, ")") {
+		arg := &Argument{Pos: p.tok.pos}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = name
+
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if arg.Value, err = p.parseValue(constant); err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	return args, p.expect(")")
+}
+
+func (p *parser) parseDirectives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek(tokenPunct, "@") {
+		dir := &Directive{Pos: p.tok.pos}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		dir.Name = name
+
+		if p.peek(tokenPunct, "(") {
+			if dir.Arguments, err = p.parseArguments(false); err != nil {
+				return nil, err
+			}
+		}
+
+		directives = append(directives, dir)
+	}
+	return directives, nil
+}
+
+// parseValue parses a value literal; constant disallows variables
+func (p *parser) parseValue(constant bool) (*Value, error) {
+	value := &Value{Pos: p.tok.pos}
+
+	switch p.tok.kind {
+	case tokenPunct:
+		switch p.tok.value {
+		case "$":
+			if constant {
+				return nil, p.errorf("variables are not allowed in constant values")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			value.Kind = ValueVariable
+			value.Raw = name
+			return value, nil
+
+		case "[":
+			value.Kind = ValueList
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for !p.peek(tokenPunct, "]") {
+				item, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				value.List = append(value.List, item)
+			}
+			return value, p.expect("]")
+
+		case "{":
+			value.Kind = ValueObject
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for !p.peek(tokenPunct, "}") {
+				field := &ObjectField{Pos: p.tok.pos}
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				field.Name = name
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if field.Value, err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+				value.Fields = append(value.Fields, field)
+			}
+			return value, p.expect("}")
+		}
+		return nil, p.errorf("expected value, got %q", p.tok.value)
+
+	case tokenInt:
+		value.Kind = ValueInt
+	case tokenFloat:
+		value.Kind = ValueFloat
+	case tokenString:
+		value.Kind = ValueString
+	case tokenName:
+		switch p.tok.value {
+		case "true", "false":
+			value.Kind = ValueBoolean
+		case "null":
+			value.Kind = ValueNull
+		default:
+			value.Kind = ValueEnum
+		}
+	default:
+		return nil, p.errorf("expected value, got %q", p.tok.value)
+	}
+
+	value.Raw = p.tok.value
+	return value, p.advance()
+}
@@ -0,0 +1,88 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Operation(t *testing.T) {
+	input := `# Library listing
+query Library($first: Int = 10, $order: [MangaOrderInput!]) {
+  mangas(first: $first, order: $order, condition: {inLibrary: true}) {
+    nodes {
+      id
+      cover: thumbnailUrl
+      ...MangaInfo @include(if: true)
+    }
+  }
+}
+
+fragment MangaInfo on MangaType {
+  title
+  status
+}`
+
+	doc, err := Parse("library.graphql", input)
+	require.NoError(t, err)
+	require.Len(t, doc.Operations, 1)
+	require.Len(t, doc.Fragments, 1)
+
+	op := doc.Operations[0]
+	assert.Equal(t, "query", op.Type)
+	assert.Equal(t, "Library", op.Name)
+	assert.Equal(t, 2, op.Pos.Line)
+	assert.True(t, len(op.Source) > 0 && op.Source[0] == 'q', "source should start at the operation keyword")
+	assert.Contains(t, op.Source, "...MangaInfo")
+	assert.NotContains(t, op.Source, "fragment MangaInfo")
+
+	require.Len(t, op.Variables, 2)
+	assert.Equal(t, "first", op.Variables[0].Name)
+	assert.Equal(t, "Int", op.Variables[0].Type.String())
+	require.NotNil(t, op.Variables[0].DefaultValue)
+	assert.Equal(t, ValueInt, op.Variables[0].DefaultValue.Kind)
+	assert.Equal(t, "[MangaOrderInput!]", op.Variables[1].Type.String())
+	assert.Equal(t, "MangaOrderInput", op.Variables[1].Type.NamedType())
+
+	mangas := op.SelectionSet[0].(*Field)
+	assert.Equal(t, "mangas", mangas.Name)
+	require.Len(t, mangas.Arguments, 3)
+	assert.Equal(t, ValueVariable, mangas.Arguments[0].Value.Kind)
+	assert.Equal(t, ValueObject, mangas.Arguments[2].Value.Kind)
+
+	nodes := mangas.SelectionSet[0].(*Field)
+	cover := nodes.SelectionSet[1].(*Field)
+	assert.Equal(t, "cover", cover.ResponseKey())
+	assert.Equal(t, "thumbnailUrl", cover.Name)
+
+	spread := nodes.SelectionSet[2].(*FragmentSpread)
+	assert.Equal(t, "MangaInfo", spread.Name)
+	require.Len(t, spread.Directives, 1)
+	assert.Equal(t, "include", spread.Directives[0].Name)
+
+	assert.Equal(t, "MangaType", doc.Fragments[0].TypeCondition)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{"empty selection", "query Q { }", "selection set cannot be empty"},
+		{"unterminated string", `query Q { manga(id: "x) { id } }`, "unterminated string"},
+		{"variable in default", "query Q($a: Int = $b) { aboutServer { name } }", "variables are not allowed"},
+		{"missing on", "fragment F MangaType { id }", `expected "on"`},
+		{"unknown definition", "schema { query: Query }", "expected operation or fragment definition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("test.graphql", tt.input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.Contains(t, err.Error(), "test.graphql:1:")
+		})
+	}
+}
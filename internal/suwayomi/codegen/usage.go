@@ -0,0 +1,412 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+)
+
+// ClientOperation is a GraphQL operation used by client code
+type ClientOperation struct {
+	Name      string // Go function or generated operation using the operation
+	Operation *Operation
+	Fragments map[string]*Fragment
+}
+
+// DocumentOperations wraps the operations of a parsed document, naming
+// each one with prefix followed by the operation name
+func DocumentOperations(doc *Document, prefix string) []*ClientOperation {
+	fragments := make(map[string]*Fragment, len(doc.Fragments))
+	for _, frag := range doc.Fragments {
+		fragments[frag.Name] = frag
+	}
+
+	ops := make([]*ClientOperation, 0, len(doc.Operations))
+	for _, op := range doc.Operations {
+		ops = append(ops, &ClientOperation{Name: prefix + op.Name, Operation: op, Fragments: fragments})
+	}
+	return ops
+}
+
+// UnresolvedError lists operations in Go source that ExtractGoOperations
+// found but could not read, such as queries built at runtime. Their schema
+// usage is unknown.
+type UnresolvedError struct {
+	Operations []string // "file:line (Func)" of each operation
+}
+
+func (e *UnresolvedError) Error() string {
+	return fmt.Sprintf("%d operations could not be evaluated, use string constants instead: %s",
+		len(e.Operations), strings.Join(e.Operations, ", "))
+}
+
+// ExtractGoOperations finds GraphQL operations embedded as string literals in
+// a Go source file, such as the queries in internal/suwayomi/graphql.go.
+// Strings concatenated with package-level string constants, and those
+// constants used on their own, are resolved. Each operation is named after
+// the function or method containing it. Operations that cannot be
+// evaluated or parsed are returned in an *UnresolvedError along with the
+// others.
+func ExtractGoOperations(path string) ([]*ClientOperation, error) {
+	fset := gotoken.NewFileSet()
+	file, err := goparser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Package-level string constants, such as shared selection sets
+	consts := make(map[string]string)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != gotoken.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i < len(vs.Values) {
+					if value, ok := evalString(vs.Values[i], consts); ok {
+						consts[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	var ops []*ClientOperation
+	unresolved := &UnresolvedError{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		name := funcName(fn)
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			switch e := expr.(type) {
+			case *ast.BasicLit, *ast.BinaryExpr:
+			case *ast.Ident:
+				if _, ok := consts[e.Name]; !ok {
+					return true
+				}
+			default:
+				return true
+			}
+
+			pos := fset.Position(expr.Pos())
+			where := fmt.Sprintf("%s:%d", pos.Filename, pos.Line)
+			value, ok := evalString(expr, consts)
+			if !ok {
+				// Part of the string is only known at runtime
+				if prefix := leadingString(expr, consts); isOperationPrefix(prefix) {
+					unresolved.Operations = append(unresolved.Operations, fmt.Sprintf("%s (%s)", where, name))
+					return false
+				}
+				return true
+			}
+			if !looksLikeOperation(value) {
+				return true
+			}
+
+			doc, err := Parse(where, value)
+			if err != nil || len(doc.Operations) == 0 {
+				if isOperationPrefix(value) {
+					unresolved.Operations = append(unresolved.Operations, fmt.Sprintf("%s (%s)", where, name))
+				}
+				return false
+			}
+			ops = append(ops, DocumentOperations(doc, name+" ")...)
+			return false
+		})
+	}
+
+	// Name operations by function only, unless a function holds several.
+	// Variants of one operation, such as queries for different server
+	// versions, share the function's name.
+	names := make(map[string]map[string]bool)
+	for _, op := range ops {
+		fn := strings.SplitN(op.Name, " ", 2)
+		if names[fn[0]] == nil {
+			names[fn[0]] = make(map[string]bool)
+		}
+		names[fn[0]][fn[1]] = true
+	}
+	for _, op := range ops {
+		fn := strings.SplitN(op.Name, " ", 2)
+		if len(names[fn[0]]) == 1 {
+			op.Name = fn[0]
+		} else {
+			op.Name = fmt.Sprintf("%s (%s)", fn[0], fn[1])
+		}
+	}
+	if len(unresolved.Operations) > 0 {
+		return ops, unresolved
+	}
+	return ops, nil
+}
+
+// funcName formats a function declaration as Func or Type.Method
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// evalString evaluates a string literal or a concatenation of literals and constants
+func evalString(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != gotoken.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.Ident:
+		value, ok := consts[e.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return evalString(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op != gotoken.ADD {
+			return "", false
+		}
+		left, ok := evalString(e.X, consts)
+		if !ok {
+			return "", false
+		}
+		right, ok := evalString(e.Y, consts)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
+	}
+	return "", false
+}
+
+// leadingString evaluates as much of the start of a string concatenation
+// as is known without running the code
+func leadingString(expr ast.Expr, consts map[string]string) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return leadingString(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op != gotoken.ADD {
+			return ""
+		}
+		if left, ok := evalString(e.X, consts); ok {
+			return left + leadingString(e.Y, consts)
+		}
+		return leadingString(e.X, consts)
+	}
+	value, _ := evalString(expr, consts)
+	return value
+}
+
+// isOperationPrefix reports whether a string starts like an operation with
+// a selection set, telling operations apart from messages such as
+// "query failed: %w"
+func isOperationPrefix(s string) bool {
+	return looksLikeOperation(s) && strings.Contains(s, "{")
+}
+
+// looksLikeOperation reports whether a string starts with an operation keyword
+func looksLikeOperation(s string) bool {
+	s = strings.TrimSpace(s)
+	for _, keyword := range []string{"query", "mutation", "subscription"} {
+		if strings.HasPrefix(s, keyword) {
+			rest := strings.TrimLeft(s[len(keyword):], " \t\r\n")
+			return strings.HasPrefix(rest, "{") || strings.HasPrefix(rest, "(") || (rest != "" && isNameStart(rest[0]))
+		}
+	}
+	return false
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Coordinates returns the schema coordinates an operation depends on: type
+// names ("MangaType"), fields ("MangaType.title"), arguments
+// ("Query.mangas(first:)"), input fields and enum values ("SortOrder.ASC").
+// Input types passed through variables contribute all their fields and enum
+// values, since the Go code building them is not analysed.
+func Coordinates(op *ClientOperation, schema *suwayomi.Schema) map[string]bool {
+	w := &usageWalker{
+		idx:       newSchemaIndex(schema),
+		fragments: op.Fragments,
+		coords:    make(map[string]bool),
+		visited:   make(map[string]bool),
+	}
+
+	for _, v := range op.Operation.Variables {
+		w.addInputClosure(v.Type.NamedType())
+	}
+
+	if root := w.idx.rootType(op.Operation.Type); root != nil {
+		w.coords[root.Name] = true
+		w.selections(root, op.Operation.SelectionSet)
+	}
+	return w.coords
+}
+
+// UsageIndex maps each schema coordinate to the names of the operations using it
+func UsageIndex(ops []*ClientOperation, schema *suwayomi.Schema) map[string][]string {
+	uses := make(map[string][]string)
+	for _, op := range ops {
+		for coord := range Coordinates(op, schema) {
+			uses[coord] = append(uses[coord], op.Name)
+		}
+	}
+	for coord := range uses {
+		sort.Strings(uses[coord])
+		uses[coord] = slices.Compact(uses[coord])
+	}
+	return uses
+}
+
+// usageWalker collects coordinates while walking an operation
+type usageWalker struct {
+	idx       *schemaIndex
+	fragments map[string]*Fragment
+	coords    map[string]bool
+	visited   map[string]bool // Input types already expanded
+	spreads   []string        // Fragment spreads being walked, to stop cycles
+}
+
+func (w *usageWalker) selections(parent *suwayomi.SchemaType, selections []Selection) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			w.field(parent, sel)
+		case *InlineFragment:
+			target := parent
+			if sel.TypeCondition != "" {
+				w.coords[sel.TypeCondition] = true
+				if t, ok := w.idx.types[sel.TypeCondition]; ok {
+					target = t
+				}
+			}
+			w.selections(target, sel.SelectionSet)
+		case *FragmentSpread:
+			frag, ok := w.fragments[sel.Name]
+			if !ok || w.inSpread(sel.Name) {
+				continue
+			}
+			w.coords[frag.TypeCondition] = true
+			if t, ok := w.idx.types[frag.TypeCondition]; ok {
+				w.spreads = append(w.spreads, sel.Name)
+				w.selections(t, frag.SelectionSet)
+				w.spreads = w.spreads[:len(w.spreads)-1]
+			}
+		}
+	}
+}
+
+func (w *usageWalker) inSpread(name string) bool {
+	for _, s := range w.spreads {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *usageWalker) field(parent *suwayomi.SchemaType, field *Field) {
+	if field.Name == "__typename" {
+		return
+	}
+
+	coord := parent.Name + "." + field.Name
+	w.coords[coord] = true
+
+	def := parent.GetFieldByName(field.Name)
+	if def == nil {
+		return
+	}
+
+	for _, arg := range field.Arguments {
+		w.coords[fmt.Sprintf("%s(%s:)", coord, arg.Name)] = true
+		if argDef := findInputValue(def.Args, arg.Name); argDef != nil {
+			w.value(arg.Value, argDef.Type)
+		}
+	}
+
+	named := namedType(def.Type).Name
+	w.coords[named] = true
+	if t, ok := w.idx.types[named]; ok && len(field.SelectionSet) > 0 {
+		w.selections(t, field.SelectionSet)
+	}
+}
+
+// value records input fields and enum values used by a literal
+func (w *usageWalker) value(value *Value, expected suwayomi.SchemaTypeRef) {
+	named := namedType(expected).Name
+	w.coords[named] = true
+
+	switch value.Kind {
+	case ValueVariable:
+		w.addInputClosure(named)
+	case ValueList:
+		for _, item := range value.List {
+			w.value(item, expected)
+		}
+	case ValueEnum:
+		w.coords[named+"."+value.Raw] = true
+	case ValueObject:
+		t, ok := w.idx.types[named]
+		if !ok {
+			return
+		}
+		for _, f := range value.Fields {
+			w.coords[named+"."+f.Name] = true
+			if def := findInputValue(t.InputFields, f.Name); def != nil {
+				w.value(f.Value, def.Type)
+			}
+		}
+	}
+}
+
+// addInputClosure records an input type with all its fields, nested input
+// types and enum values
+func (w *usageWalker) addInputClosure(name string) {
+	if w.visited[name] {
+		return
+	}
+	w.visited[name] = true
+	w.coords[name] = true
+
+	t, ok := w.idx.types[name]
+	if !ok {
+		return
+	}
+	switch t.Kind {
+	case "ENUM":
+		for _, v := range t.EnumValues {
+			w.coords[name+"."+v.Name] = true
+		}
+	case "INPUT_OBJECT":
+		for _, f := range t.InputFields {
+			w.coords[name+"."+f.Name] = true
+			w.addInputClosure(namedType(f.Type).Name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clientSource = `package client
+
+const mangaFields = ` + "`" + `
+	id
+	title
+` + "`" + `
+
+func (gc *GraphQLClient) GetManga(id int) error {
+	query := ` + "`" + `
+		query GetManga($id: Int!) {
+			manga(id: $id) {` + "` + mangaFields + `" + `}
+		}
+	` + "`" + `
+	return gc.Query(query, nil, nil)
+}
+
+func (gc *GraphQLClient) ListManga() error {
+	query := "query { mangas(orderBy: TITLE, condition: {inLibrary: true}) { nodes { id } } }"
+	return gc.Query(query, nil, nil)
+}
+
+func (gc *GraphQLClient) Fail(err error) string {
+	return "query failed: " + err.Error()
+}
+`
+
+func TestExtractGoOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client.go")
+	require.NoError(t, os.WriteFile(path, []byte(clientSource), 0644))
+
+	ops, err := ExtractGoOperations(path)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	assert.Equal(t, "GraphQLClient.GetManga", ops[0].Name)
+	assert.Equal(t, "GetManga", ops[0].Operation.Name)
+	assert.Equal(t, "GraphQLClient.ListManga", ops[1].Name)
+
+	schema := loadSchema(t)
+
+	coords := Coordinates(ops[0], schema)
+	for _, want := range []string{"Query", "Query.manga", "Query.manga(id:)", "MangaType", "MangaType.id", "MangaType.title", "Int"} {
+		assert.True(t, coords[want], "expected coordinate %s", want)
+	}
+	assert.False(t, coords["MangaType.thumbnailUrl"])
+
+	coords = Coordinates(ops[1], schema)
+	for _, want := range []string{"Query.mangas(orderBy:)", "MangaOrderBy.TITLE", "Query.mangas(condition:)", "MangaConditionInput.inLibrary", "MangaNodeList.nodes"} {
+		assert.True(t, coords[want], "expected coordinate %s", want)
+	}
+	assert.False(t, coords["MangaOrderBy.ID"], "only literal enum values are used")
+}
+
+const runtimeSource = `package client
+
+const listQuery = "query List { mangas { nodes { id } } }"
+
+func (gc *GraphQLClient) List(latest bool) error {
+	query := listQuery
+	if latest {
+		query = "query ListLatest { mangas { nodes { id " + extra() + "} } }"
+	}
+	return gc.Query(query, nil, nil)
+}
+
+func (gc *GraphQLClient) Broken() error {
+	return gc.Query("query Broken { mangas { nodes { id }", nil, nil)
+}
+
+func (gc *GraphQLClient) Fail(err error) error {
+	return fmt.Errorf("query failed: " + err.Error())
+}
+`
+
+func TestExtractGoOperations_Unresolved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client.go")
+	require.NoError(t, os.WriteFile(path, []byte(runtimeSource), 0644))
+
+	ops, err := ExtractGoOperations(path)
+
+	// Constants used on their own are read, runtime strings are reported
+	require.Len(t, ops, 1)
+	assert.Equal(t, "GraphQLClient.List", ops[0].Name)
+	var unresolved *UnresolvedError
+	require.ErrorAs(t, err, &unresolved)
+	require.Len(t, unresolved.Operations, 2)
+	assert.Contains(t, unresolved.Operations[0], "(GraphQLClient.List)")
+	assert.Contains(t, unresolved.Operations[1], "(GraphQLClient.Broken)")
+}
+
+func TestExtractGoOperations_Client(t *testing.T) {
+	ops, err := ExtractGoOperations(filepath.Join("..", "graphql.go"))
+	require.NoError(t, err, "every client operation is readable")
+
+	// Both variants of the library query are read and named after the
+	// function, since they share the operation name
+	schema := loadSchema(t)
+	var variants []map[string]bool
+	for _, op := range ops {
+		if op.Name == "GraphQLClient.GetMangaList" {
+			variants = append(variants, Coordinates(op, schema))
+		}
+	}
+	require.Len(t, variants, 2)
+	assert.True(t, variants[0]["MangaType.unreadCount"])
+	assert.True(t, variants[1]["MangaType.unreadCount"])
+	assert.NotEqual(t, variants[0]["MangaType.latestUploadedChapter"], variants[1]["MangaType.latestUploadedChapter"])
+
+	uses := UsageIndex(ops, schema)
+	assert.Equal(t, []string{"GraphQLClient.GetMangaList"}, filterPrefix(uses["MangaType.unreadCount"], "GraphQLClient.GetMangaList"))
+	assert.Equal(t, []string{"GraphQLClient.GetMangaList"}, filterPrefix(uses["MangaType.latestUploadedChapter"], "GraphQLClient.GetMangaList"))
+}
+
+func TestExtractGoOperations_ClientValid(t *testing.T) {
+	ops, err := ExtractGoOperations(filepath.Join("..", "graphql.go"))
+	require.NoError(t, err)
+	require.NotEmpty(t, ops)
+
+	// Every operation the client sends must match the checked-in schema
+	schema := loadSchema(t)
+	for _, op := range ops {
+		doc := &Document{Operations: []*Operation{op.Operation}}
+		for _, name := range usedFragments(op.Operation.SelectionSet, op.Fragments, make(map[string]bool)) {
+			if frag, ok := op.Fragments[name]; ok {
+				doc.Fragments = append(doc.Fragments, frag)
+			}
+		}
+		assert.NoError(t, Validate(doc, schema), "operation of %s", op.Name)
+	}
+}
+
+// filterPrefix returns the names starting with prefix
+func filterPrefix(names []string, prefix string) []string {
+	var filtered []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
+func TestUsageIndex_VariableInputs(t *testing.T) {
+	doc, err := Parse("ops.graphql", `
+query Library($order: [MangaOrderInput!]) {
+  mangas(order: $order) { nodes { ...Info } }
+}
+fragment Info on MangaType { title }`)
+	require.NoError(t, err)
+
+	uses := UsageIndex(DocumentOperations(doc, "operations."), loadSchema(t))
+
+	// Input types passed as variables contribute all fields and enum values
+	assert.Equal(t, []string{"operations.Library"}, uses["MangaOrderInput.byType"])
+	assert.Equal(t, []string{"operations.Library"}, uses["SortOrder.DESC"])
+	assert.Equal(t, []string{"operations.Library"}, uses["MangaType.title"])
+	assert.Empty(t, uses["MangaType.id"])
+}
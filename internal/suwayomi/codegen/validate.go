@@ -0,0 +1,577 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+)
+
+// ValidationError is an operation that does not match the schema
+type ValidationError struct {
+	Pos       Position
+	Operation string
+	Message   string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Operation == "" {
+		return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Pos, e.Operation, e.Message)
+}
+
+// ValidationErrors collects all validation errors of a document
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// schemaIndex provides lookups over an introspected schema
+type schemaIndex struct {
+	schema *suwayomi.Schema
+	types  map[string]*suwayomi.SchemaType
+}
+
+func newSchemaIndex(schema *suwayomi.Schema) *schemaIndex {
+	idx := &schemaIndex{
+		schema: schema,
+		types:  make(map[string]*suwayomi.SchemaType, len(schema.Types)),
+	}
+	for i := range schema.Types {
+		idx.types[schema.Types[i].Name] = &schema.Types[i]
+	}
+	return idx
+}
+
+// rootType returns the root type for an operation type
+func (s *schemaIndex) rootType(opType string) *suwayomi.SchemaType {
+	var ref *suwayomi.SchemaTypeRef
+	switch opType {
+	case "query":
+		ref = s.schema.QueryType
+	case "mutation":
+		ref = s.schema.MutationType
+	case "subscription":
+		ref = s.schema.SubscriptionType
+	}
+	if ref == nil {
+		return nil
+	}
+	return s.types[ref.Name]
+}
+
+// possibleTypes returns the concrete object types of a composite type
+func (s *schemaIndex) possibleTypes(t *suwayomi.SchemaType) map[string]bool {
+	result := make(map[string]bool)
+	if t.Kind == "OBJECT" {
+		result[t.Name] = true
+		return result
+	}
+	for _, ref := range t.PossibleTypes {
+		result[ref.Name] = true
+	}
+	return result
+}
+
+// fragmentPossible reports whether a fragment on cond can apply within parent
+func (s *schemaIndex) fragmentPossible(parent, cond *suwayomi.SchemaType) bool {
+	condTypes := s.possibleTypes(cond)
+	for name := range s.possibleTypes(parent) {
+		if condTypes[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// namedType unwraps NON_NULL and LIST wrappers
+func namedType(ref suwayomi.SchemaTypeRef) suwayomi.SchemaTypeRef {
+	for ref.OfType != nil && (ref.Kind == "NON_NULL" || ref.Kind == "LIST") {
+		ref = *ref.OfType
+	}
+	return ref
+}
+
+// schemaTypeString formats a schema type reference in GraphQL syntax
+func schemaTypeString(ref suwayomi.SchemaTypeRef) string {
+	switch ref.Kind {
+	case "NON_NULL":
+		if ref.OfType != nil {
+			return schemaTypeString(*ref.OfType) + "!"
+		}
+	case "LIST":
+		if ref.OfType != nil {
+			return "[" + schemaTypeString(*ref.OfType) + "]"
+		}
+	}
+	return ref.Name
+}
+
+func isCompositeKind(kind string) bool {
+	return kind == "OBJECT" || kind == "INTERFACE" || kind == "UNION"
+}
+
+func isInputKind(kind string) bool {
+	return kind == "SCALAR" || kind == "ENUM" || kind == "INPUT_OBJECT"
+}
+
+// validator walks a document and records validation errors
+type validator struct {
+	idx       *schemaIndex
+	fragments map[string]*Fragment
+	errors    ValidationErrors
+
+	// Per-operation state
+	op            *Operation
+	variables     map[string]*VariableDefinition
+	usedVariables map[string]bool
+	usedFragments map[string]bool
+	visiting      map[string]bool
+}
+
+// Validate checks every operation and fragment of doc against the schema.
+// It returns nil or ValidationErrors.
+func Validate(doc *Document, schema *suwayomi.Schema) error {
+	v := &validator{
+		idx:       newSchemaIndex(schema),
+		fragments: make(map[string]*Fragment),
+	}
+
+	for _, frag := range doc.Fragments {
+		if _, exists := v.fragments[frag.Name]; exists {
+			v.errorf(frag.Pos, "duplicate fragment %q", frag.Name)
+		}
+		v.fragments[frag.Name] = frag
+	}
+
+	opNames := make(map[string]bool)
+	allUsedFragments := make(map[string]bool)
+	for _, op := range doc.Operations {
+		if op.Name != "" {
+			if opNames[op.Name] {
+				v.errorf(op.Pos, "duplicate operation %q", op.Name)
+			}
+			opNames[op.Name] = true
+		}
+
+		v.validateOperation(op)
+		for name := range v.usedFragments {
+			allUsedFragments[name] = true
+		}
+	}
+
+	v.op = nil
+	for _, frag := range doc.Fragments {
+		if !allUsedFragments[frag.Name] {
+			v.errorf(frag.Pos, "fragment %q is never used", frag.Name)
+		}
+	}
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
+
+func (v *validator) errorf(pos Position, format string, args ...interface{}) {
+	name := ""
+	if v.op != nil {
+		name = v.op.Name
+	}
+	v.errors = append(v.errors, &ValidationError{
+		Pos:       pos,
+		Operation: name,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validateOperation(op *Operation) {
+	v.op = op
+	v.variables = make(map[string]*VariableDefinition)
+	v.usedVariables = make(map[string]bool)
+	v.usedFragments = make(map[string]bool)
+	v.visiting = make(map[string]bool)
+
+	root := v.idx.rootType(op.Type)
+	if root == nil {
+		v.errorf(op.Pos, "schema does not support %s operations", op.Type)
+		return
+	}
+
+	for _, def := range op.Variables {
+		if _, exists := v.variables[def.Name]; exists {
+			v.errorf(def.Pos, "duplicate variable $%s", def.Name)
+			continue
+		}
+		v.variables[def.Name] = def
+
+		t, ok := v.idx.types[def.Type.NamedType()]
+		if !ok {
+			v.errorf(def.Pos, "variable $%s has unknown type %s", def.Name, def.Type.NamedType())
+			continue
+		}
+		if !isInputKind(t.Kind) {
+			v.errorf(def.Pos, "variable $%s must have an input type, %s is %s", def.Name, t.Name, t.Kind)
+			continue
+		}
+		if def.DefaultValue != nil {
+			v.validateValue(def.DefaultValue, toSchemaRef(def.Type, v.idx))
+		}
+	}
+
+	v.validateSelectionSet(root, op.SelectionSet)
+
+	for _, def := range op.Variables {
+		if !v.usedVariables[def.Name] {
+			v.errorf(def.Pos, "variable $%s is never used", def.Name)
+		}
+	}
+}
+
+// toSchemaRef converts a document type reference to a schema type reference
+func toSchemaRef(t *TypeRef, idx *schemaIndex) suwayomi.SchemaTypeRef {
+	var ref suwayomi.SchemaTypeRef
+	if t.Elem != nil {
+		elem := toSchemaRef(t.Elem, idx)
+		ref = suwayomi.SchemaTypeRef{Kind: "LIST", OfType: &elem}
+	} else {
+		kind := ""
+		if named, ok := idx.types[t.Name]; ok {
+			kind = named.Kind
+		}
+		ref = suwayomi.SchemaTypeRef{Kind: kind, Name: t.Name}
+	}
+	if t.NonNull {
+		inner := ref
+		ref = suwayomi.SchemaTypeRef{Kind: "NON_NULL", OfType: &inner}
+	}
+	return ref
+}
+
+// validateSelectionSet validates selections made on parent
+func (v *validator) validateSelectionSet(parent *suwayomi.SchemaType, selections []Selection) {
+	seen := make(map[string]*Field)
+
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			if prev, ok := seen[sel.ResponseKey()]; ok && prev.Name != sel.Name {
+				v.errorf(sel.Pos, "%q selects both %s and %s; use an alias", sel.ResponseKey(), prev.Name, sel.Name)
+			}
+			seen[sel.ResponseKey()] = sel
+			v.validateField(parent, sel)
+
+		case *InlineFragment:
+			v.validateDirectives(sel.Directives)
+			target := parent
+			if sel.TypeCondition != "" {
+				target = v.fragmentType(parent, sel.TypeCondition, sel.Pos)
+				if target == nil {
+					continue
+				}
+			}
+			v.validateSelectionSet(target, sel.SelectionSet)
+
+		case *FragmentSpread:
+			v.validateDirectives(sel.Directives)
+			frag, ok := v.fragments[sel.Name]
+			if !ok {
+				v.errorf(sel.Pos, "unknown fragment %q", sel.Name)
+				continue
+			}
+			v.usedFragments[sel.Name] = true
+			if v.visiting[sel.Name] {
+				v.errorf(sel.Pos, "fragment %q spreads itself", sel.Name)
+				continue
+			}
+			target := v.fragmentType(parent, frag.TypeCondition, sel.Pos)
+			if target == nil {
+				continue
+			}
+			v.visiting[sel.Name] = true
+			v.validateSelectionSet(target, frag.SelectionSet)
+			delete(v.visiting, sel.Name)
+		}
+	}
+}
+
+// fragmentType resolves and checks a fragment type condition
+func (v *validator) fragmentType(parent *suwayomi.SchemaType, name string, pos Position) *suwayomi.SchemaType {
+	cond, ok := v.idx.types[name]
+	if !ok {
+		v.errorf(pos, "unknown type %q in fragment", name)
+		return nil
+	}
+	if !isCompositeKind(cond.Kind) {
+		v.errorf(pos, "fragment cannot condition on %s type %s", cond.Kind, name)
+		return nil
+	}
+	if !v.idx.fragmentPossible(parent, cond) {
+		v.errorf(pos, "fragment on %s can never apply to %s", name, parent.Name)
+		return nil
+	}
+	return cond
+}
+
+// validateField validates a single field selection
+func (v *validator) validateField(parent *suwayomi.SchemaType, field *Field) {
+	v.validateDirectives(field.Directives)
+
+	if field.Name == "__typename" {
+		if len(field.Arguments) > 0 || field.SelectionSet != nil {
+			v.errorf(field.Pos, "__typename takes no arguments or selections")
+		}
+		return
+	}
+
+	def := parent.GetFieldByName(field.Name)
+	if def == nil {
+		v.errorf(field.Pos, "field %q does not exist on type %s", field.Name, parent.Name)
+		return
+	}
+
+	// Arguments
+	given := make(map[string]bool)
+	for _, arg := range field.Arguments {
+		given[arg.Name] = true
+		argDef := findInputValue(def.Args, arg.Name)
+		if argDef == nil {
+			v.errorf(arg.Pos, "unknown argument %q on %s.%s", arg.Name, parent.Name, field.Name)
+			continue
+		}
+		v.validateValue(arg.Value, argDef.Type)
+	}
+	for _, argDef := range def.Args {
+		if argDef.Type.Kind == "NON_NULL" && argDef.DefaultValue == "" && !given[argDef.Name] {
+			v.errorf(field.Pos, "missing required argument %q (%s) on %s.%s",
+				argDef.Name, schemaTypeString(argDef.Type), parent.Name, field.Name)
+		}
+	}
+
+	// Selections
+	named := namedType(def.Type)
+	fieldType, ok := v.idx.types[named.Name]
+	if !ok {
+		v.errorf(field.Pos, "field %s.%s has unknown type %s", parent.Name, field.Name, named.Name)
+		return
+	}
+
+	if isCompositeKind(fieldType.Kind) {
+		if field.SelectionSet == nil {
+			v.errorf(field.Pos, "field %q of type %s must have a selection set", field.Name, schemaTypeString(def.Type))
+			return
+		}
+		v.validateSelectionSet(fieldType, field.SelectionSet)
+	} else if field.SelectionSet != nil {
+		v.errorf(field.Pos, "field %q of type %s cannot have a selection set", field.Name, schemaTypeString(def.Type))
+	}
+}
+
+// validateDirectives accepts the executable directives @include and @skip
+func (v *validator) validateDirectives(directives []*Directive) {
+	boolType := suwayomi.SchemaTypeRef{
+		Kind:   "NON_NULL",
+		OfType: &suwayomi.SchemaTypeRef{Kind: "SCALAR", Name: "Boolean"},
+	}
+
+	for _, dir := range directives {
+		if dir.Name != "include" && dir.Name != "skip" {
+			v.errorf(dir.Pos, "unsupported directive @%s", dir.Name)
+			continue
+		}
+		if len(dir.Arguments) != 1 || dir.Arguments[0].Name != "if" {
+			v.errorf(dir.Pos, "@%s requires exactly the argument \"if\"", dir.Name)
+			continue
+		}
+		v.validateValue(dir.Arguments[0].Value, boolType)
+	}
+}
+
+// findInputValue finds an argument or input field by name
+func findInputValue(values []suwayomi.SchemaInputValue, name string) *suwayomi.SchemaInputValue {
+	for i := range values {
+		if values[i].Name == name {
+			return &values[i]
+		}
+	}
+	return nil
+}
+
+// validateValue checks a literal or variable against the expected type
+func (v *validator) validateValue(value *Value, expected suwayomi.SchemaTypeRef) {
+	if value.Kind == ValueVariable {
+		v.usedVariables[value.Raw] = true
+		def, ok := v.variables[value.Raw]
+		if !ok {
+			v.errorf(value.Pos, "undefined variable $%s", value.Raw)
+			return
+		}
+		varType := def.Type
+		if expected.Kind == "NON_NULL" && !varType.NonNull && def.DefaultValue != nil {
+			// A default makes a nullable variable usable in a non-null position
+			stricter := *varType
+			stricter.NonNull = true
+			varType = &stricter
+		}
+		if !variableCompatible(varType, expected) {
+			v.errorf(value.Pos, "variable $%s of type %s cannot be used where %s is expected",
+				value.Raw, def.Type, schemaTypeString(expected))
+		}
+		return
+	}
+
+	if value.Kind == ValueNull {
+		if expected.Kind == "NON_NULL" {
+			v.errorf(value.Pos, "null is not allowed for %s", schemaTypeString(expected))
+		}
+		return
+	}
+
+	if expected.Kind == "NON_NULL" && expected.OfType != nil {
+		expected = *expected.OfType
+	}
+
+	if expected.Kind == "LIST" && expected.OfType != nil {
+		if value.Kind == ValueList {
+			for _, item := range value.List {
+				v.validateValue(item, *expected.OfType)
+			}
+			return
+		}
+		// Single values are coerced to a list
+		v.validateValue(value, *expected.OfType)
+		return
+	}
+
+	if value.Kind == ValueList {
+		v.errorf(value.Pos, "list is not allowed for %s", schemaTypeString(expected))
+		return
+	}
+
+	t, ok := v.idx.types[expected.Name]
+	if !ok {
+		v.errorf(value.Pos, "unknown type %s", expected.Name)
+		return
+	}
+
+	switch t.Kind {
+	case "SCALAR":
+		if !scalarAccepts(t.Name, value.Kind) {
+			v.errorf(value.Pos, "%s is not a valid %s", value.Raw, t.Name)
+		}
+
+	case "ENUM":
+		if value.Kind != ValueEnum {
+			v.errorf(value.Pos, "%s is not a valid %s value", value.Raw, t.Name)
+			return
+		}
+		for _, ev := range t.EnumValues {
+			if ev.Name == value.Raw {
+				return
+			}
+		}
+		v.errorf(value.Pos, "%s is not a value of enum %s", value.Raw, t.Name)
+
+	case "INPUT_OBJECT":
+		if value.Kind != ValueObject {
+			v.errorf(value.Pos, "expected an object for %s", t.Name)
+			return
+		}
+		given := make(map[string]bool)
+		for _, field := range value.Fields {
+			given[field.Name] = true
+			def := findInputValue(t.InputFields, field.Name)
+			if def == nil {
+				v.errorf(field.Pos, "unknown field %q on input %s", field.Name, t.Name)
+				continue
+			}
+			v.validateValue(field.Value, def.Type)
+		}
+		for _, def := range t.InputFields {
+			if def.Type.Kind == "NON_NULL" && def.DefaultValue == "" && !given[def.Name] {
+				v.errorf(value.Pos, "missing required field %q (%s) on input %s",
+					def.Name, schemaTypeString(def.Type), t.Name)
+			}
+		}
+
+	default:
+		v.errorf(value.Pos, "%s is not an input type", t.Name)
+	}
+}
+
+// scalarAccepts reports whether a literal kind is valid for a scalar
+func scalarAccepts(scalar string, kind ValueKind) bool {
+	switch scalar {
+	case "Int":
+		return kind == ValueInt
+	case "Float":
+		return kind == ValueInt || kind == ValueFloat
+	case "Boolean":
+		return kind == ValueBoolean
+	case "String", "Cursor":
+		return kind == ValueString
+	case "ID", "LongString":
+		return kind == ValueString || kind == ValueInt
+	}
+	// Custom scalars accept any literal
+	return kind != ValueObject
+}
+
+// variableCompatible reports whether a variable type fits an expected location type
+func variableCompatible(varType *TypeRef, expected suwayomi.SchemaTypeRef) bool {
+	if expected.Kind == "NON_NULL" {
+		if !varType.NonNull || expected.OfType == nil {
+			return false
+		}
+		inner := *varType
+		inner.NonNull = false
+		return variableCompatible(&inner, *expected.OfType)
+	}
+
+	if varType.NonNull {
+		// A non-null variable can be used in a nullable position
+		inner := *varType
+		inner.NonNull = false
+		return variableCompatible(&inner, expected)
+	}
+
+	if expected.Kind == "LIST" {
+		return varType.Elem != nil && expected.OfType != nil && variableCompatible(varType.Elem, *expected.OfType)
+	}
+
+	return varType.Elem == nil && varType.Name == expected.Name
+}
+
+// usedFragments returns the fragments an operation uses, directly or transitively, sorted by name
+func usedFragments(selections []Selection, fragments map[string]*Fragment, seen map[string]bool) []string {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			usedFragments(sel.SelectionSet, fragments, seen)
+		case *InlineFragment:
+			usedFragments(sel.SelectionSet, fragments, seen)
+		case *FragmentSpread:
+			if seen[sel.Name] {
+				continue
+			}
+			seen[sel.Name] = true
+			if frag, ok := fragments[sel.Name]; ok {
+				usedFragments(frag.SelectionSet, fragments, seen)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+)
+
+const schemaPath = "../../../schema/suwayomi_schema.json"
+
+func loadSchema(t *testing.T) *suwayomi.Schema {
+	t.Helper()
+	result, err := suwayomi.LoadSchemaFromFile(schemaPath)
+	require.NoError(t, err)
+	return &result.Schema
+}
+
+func TestValidate_Valid(t *testing.T) {
+	schema := loadSchema(t)
+
+	doc, err := Parse("valid.graphql", `
+query Library($first: Int, $order: [MangaOrderInput!]) {
+  mangas(first: $first, order: $order, condition: {inLibrary: true}) {
+    nodes {
+      __typename
+      ...MangaInfo
+    }
+  }
+}
+
+query Prefs($id: LongString!) {
+  source(id: $id) {
+    preferences {
+      ... on SwitchPreference { key switchValue: currentValue }
+      ... on ListPreference { key listValue: currentValue }
+    }
+  }
+}
+
+fragment MangaInfo on MangaType {
+  id
+  title
+  status
+}`)
+	require.NoError(t, err)
+	assert.NoError(t, Validate(doc, schema))
+}
+
+func TestValidate_Errors(t *testing.T) {
+	schema := loadSchema(t)
+
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{"unknown field", "query Q { manga(id: 1) { nope } }", `field "nope" does not exist on type MangaType`},
+		{"missing argument", "query Q { manga { id } }", `missing required argument "id"`},
+		{"unknown argument", "query Q { manga(id: 1, foo: 2) { id } }", `unknown argument "foo"`},
+		{"leaf without selection", "query Q { manga(id: 1) }", "must have a selection set"},
+		{"scalar with selection", "query Q { manga(id: 1) { id { x } } }", "cannot have a selection set"},
+		{"wrong literal", `query Q { manga(id: "1") { id } }`, "is not a valid Int"},
+		{"bad enum", "query Q { mangas(orderBy: NAME) { nodes { id } } }", "is not a value of enum MangaOrderBy"},
+		{"unknown input field", "query Q { mangas(condition: {colour: 1}) { nodes { id } } }", `unknown field "colour"`},
+		{"undefined variable", "query Q { manga(id: $id) { id } }", "undefined variable $id"},
+		{"unused variable", "query Q($x: Int) { aboutServer { name } }", "variable $x is never used"},
+		{"nullable variable", "query Q($id: Int) { manga(id: $id) { id } }", "cannot be used where Int! is expected"},
+		{"output variable type", "query Q($m: MangaType) { aboutServer { name } }", "must have an input type"},
+		{"impossible fragment", "query Q { manga(id: 1) { ... on ChapterType { id } } }", "can never apply to MangaType"},
+		{"unused fragment", "query Q { aboutServer { name } }\nfragment F on MangaType { id }", `fragment "F" is never used`},
+		{"fragment cycle", "query Q { manga(id: 1) { ...A } }\nfragment A on MangaType { ...B }\nfragment B on MangaType { ...A }", "spreads itself"},
+		{"conflicting alias", "query Q { manga(id: 1) { title: author title } }", "use an alias"},
+		{"unsupported directive", "query Q { aboutServer @defer { name } }", "unsupported directive @defer"},
+		{"duplicate operation", "query Q { aboutServer { name } }\nquery Q { aboutServer { name } }", `duplicate operation "Q"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse("test.graphql", tt.input)
+			require.NoError(t, err)
+
+			err = Validate(doc, schema)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+
+			var errs ValidationErrors
+			require.ErrorAs(t, err, &errs)
+			assert.Equal(t, "test.graphql", errs[0].Pos.File)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -28,6 +29,7 @@ type ExtensionSource struct {
 	Language    string
 	IsNSFW      bool
 	DisplayName string
+	IsConfigurable bool
 }
 
 // Client represents a Suwayomi server client
@@ -35,10 +37,22 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	GraphQL    *GraphQLClient
+
+	capsMu       sync.RWMutex
+	capabilities *Capabilities // Detected server features, nil until detected
+
+	transportConfig TransportConfig
+	availability    availability
 }
 
-// NewClient creates a new Suwayomi client
+// NewClient creates a new Suwayomi client with the default transport
 func NewClient(baseURL string) *Client {
+	return NewClientWithConfig(baseURL, DefaultTransportConfig())
+}
+
+// NewClientWithConfig creates a new Suwayomi client with custom retry,
+// circuit breaker and connection pool settings
+func NewClientWithConfig(baseURL string, config TransportConfig) *Client {
 	// Ensure baseURL has http:// or https:// prefix
 	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
 		baseURL = "http://" + baseURL
@@ -48,10 +62,10 @@ func NewClient(baseURL string) *Client {
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
 	client := &Client{
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		BaseURL:         baseURL,
+		HTTPClient:      NewHTTPClient(config),
+		transportConfig: config,
+		availability:    availability{ttl: config.AvailabilityTTL},
 	}
 
 	// Initialize GraphQL client
@@ -126,10 +140,24 @@ func (c *Client) UpdateExtension(packageName string) error {
 
 // GetExtensionSources gets all sources provided by an extension
 func (c *Client) GetExtensionSources(packageName string) ([]*ExtensionSource, error) {
-	// For now, we could use GraphQL to query sources
-	// This would require a custom GraphQL query
-	// For now, return empty list
-	return []*ExtensionSource{}, nil
+	nodes, err := c.GraphQL.GetExtensionSourceList(packageName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch extension sources: %w", err)
+	}
+
+	sources := make([]*ExtensionSource, 0, len(nodes))
+	for _, node := range nodes {
+		sources = append(sources, &ExtensionSource{
+			ID:             node.ID,
+			Name:           node.Name,
+			Language:       node.Lang,
+			IsNSFW:         node.IsNsfw,
+			DisplayName:    node.DisplayName,
+			IsConfigurable: node.IsConfigurable,
+		})
+	}
+
+	return sources, nil
 }
 
 // ServerInfo represents information about the Suwayomi server
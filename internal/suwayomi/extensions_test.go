@@ -248,11 +248,12 @@ func TestClient_InstallExtension(t *testing.T) {
 
 		// Verify package name
 		input := req.Variables["input"].(map[string]interface{})
-		assert.Equal(t, "test.extension.pkg", input["pkgName"])
+		assert.Equal(t, "test.extension.pkg", input["id"])
+		assert.Equal(t, map[string]interface{}{"install": true}, input["patch"])
 
 		mockResponse := GraphQLResponse{
 			Data: json.RawMessage(`{
-				"installExternalExtension": {
+				"updateExtension": {
 					"extension": {
 						"pkgName": "test.extension.pkg",
 						"isInstalled": true
@@ -281,11 +282,12 @@ func TestClient_UninstallExtension(t *testing.T) {
 
 		// Verify package name
 		input := req.Variables["input"].(map[string]interface{})
-		assert.Equal(t, "test.extension.pkg", input["pkgName"])
+		assert.Equal(t, "test.extension.pkg", input["id"])
+		assert.Equal(t, map[string]interface{}{"uninstall": true}, input["patch"])
 
 		mockResponse := GraphQLResponse{
 			Data: json.RawMessage(`{
-				"uninstallExtension": {
+				"updateExtension": {
 					"extension": {
 						"pkgName": "test.extension.pkg",
 						"isInstalled": false
@@ -314,7 +316,8 @@ func TestClient_UpdateExtension(t *testing.T) {
 
 		// Verify package name
 		input := req.Variables["input"].(map[string]interface{})
-		assert.Equal(t, "test.extension.pkg", input["pkgName"])
+		assert.Equal(t, "test.extension.pkg", input["id"])
+		assert.Equal(t, map[string]interface{}{"update": true}, input["patch"])
 
 		mockResponse := GraphQLResponse{
 			Data: json.RawMessage(`{
@@ -338,14 +341,45 @@ func TestClient_UpdateExtension(t *testing.T) {
 }
 
 func TestClient_GetExtensionSources(t *testing.T) {
-	client := NewClient("http://localhost:4567")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, "test.extension", req.Variables["pkgName"])
+
+		mockResponse := GraphQLResponse{
+			Data: json.RawMessage(`{
+				"extension": {
+					"source": {
+						"nodes": [
+							{
+								"id": "1234567890",
+								"name": "Test Source",
+								"displayName": "Test Source (EN)",
+								"lang": "en",
+								"iconUrl": "/icon.png",
+								"isNsfw": false,
+								"isConfigurable": true
+							}
+						]
+					}
+				}
+			}`),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
 
-	// Currently returns empty list (not implemented)
+	client := NewClient(server.URL)
 	sources, err := client.GetExtensionSources("test.extension")
 
 	require.NoError(t, err)
-	assert.NotNil(t, sources)
-	assert.Len(t, sources, 0)
+	require.Len(t, sources, 1)
+	assert.Equal(t, "1234567890", sources[0].ID)
+	assert.Equal(t, "Test Source", sources[0].Name)
+	assert.Equal(t, "Test Source (EN)", sources[0].DisplayName)
+	assert.Equal(t, "en", sources[0].Language)
+	assert.True(t, sources[0].IsConfigurable)
 }
 
 func TestClient_HealthCheck(t *testing.T) {
@@ -2,9 +2,12 @@ package suwayomi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/operations"
 )
 
 // GraphQLClient represents a GraphQL client for Suwayomi
@@ -55,7 +58,8 @@ func (gc *GraphQLClient) Query(query string, variables map[string]interface{}, r
 		Variables: variables,
 	}
 
-	return gc.execute(req, result)
+	// Queries have no side effects, so the transport may retry them
+	return gc.execute(WithIdempotent(context.Background()), req, result)
 }
 
 // Mutate executes a GraphQL mutation
@@ -65,17 +69,17 @@ func (gc *GraphQLClient) Mutate(mutation string, variables map[string]interface{
 		Variables: variables,
 	}
 
-	return gc.execute(req, result)
+	return gc.execute(context.Background(), req, result)
 }
 
 // execute performs the GraphQL request
-func (gc *GraphQLClient) execute(req GraphQLRequest, result interface{}) error {
+func (gc *GraphQLClient) execute(ctx context.Context, req GraphQLRequest, result interface{}) error {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", gc.endpoint, bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", gc.endpoint, bytes.NewReader(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -90,7 +94,7 @@ func (gc *GraphQLClient) execute(req GraphQLRequest, result interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var gqlResp GraphQLResponse
@@ -149,6 +153,8 @@ type ChapterNode struct {
 	IsBookmarked  bool    `json:"isBookmarked"`
 	IsDownloaded  bool    `json:"isDownloaded"`
 	PageCount     int     `json:"pageCount"`
+	Scanlator     string  `json:"scanlator"`
+	RealURL       string  `json:"realUrl"`
 }
 
 // SourceNode represents a source in the GraphQL response
@@ -158,6 +164,38 @@ type SourceNode struct {
 	Lang        string `json:"lang"`
 	IconURL     string `json:"iconUrl"`
 	IsNsfw      bool   `json:"isNsfw"`
+	DisplayName string `json:"displayName"`
+	IsConfigurable bool `json:"isConfigurable"`
+}
+
+// PreferenceNode represents a source preference in the GraphQL response.
+// Preference is a union, so the differently typed currentValue/default fields
+// are aliased per member type to avoid field conflicts.
+type PreferenceNode struct {
+	Typename      string   `json:"__typename"`
+	Key           string   `json:"key"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary"`
+	Enabled       bool     `json:"enabled"`
+	Visible       bool     `json:"visible"`
+	DialogTitle   string   `json:"dialogTitle"`
+	DialogMessage string   `json:"dialogMessage"`
+	Entries       []string `json:"entries"`
+	EntryValues   []string `json:"entryValues"`
+
+	// Aliased current values
+	SwitchValue   *bool    `json:"switchValue"`
+	CheckBoxValue *bool    `json:"checkBoxValue"`
+	ListValue     *string  `json:"listValue"`
+	TextValue     *string  `json:"textValue"`
+	MultiValue    []string `json:"multiValue"`
+
+	// Aliased defaults
+	SwitchDefault   bool     `json:"switchDefault"`
+	CheckBoxDefault bool     `json:"checkBoxDefault"`
+	ListDefault     *string  `json:"listDefault"`
+	TextDefault     *string  `json:"textDefault"`
+	MultiDefault    []string `json:"multiDefault"`
 }
 
 // ExtensionNode represents an extension in the GraphQL response
@@ -173,13 +211,8 @@ type ExtensionNode struct {
 	IconURL      string `json:"iconUrl"`
 }
 
-// GetMangaList retrieves the manga library using GraphQL
-func (gc *GraphQLClient) GetMangaList(inLibrary bool, limit int, offset int) (*MangaListResponse, error) {
-	query := `
-		query GetMangaList($inLibrary: Boolean, $first: Int, $offset: Int) {
-			mangas(condition: {inLibrary: $inLibrary}, first: $first, offset: $offset) {
-				totalCount
-				nodes {
+// mangaListFields selects the library fields every server supports
+const mangaListFields = `
 					id
 					title
 					thumbnailUrl
@@ -189,24 +222,50 @@ func (gc *GraphQLClient) GetMangaList(inLibrary bool, limit int, offset int) (*M
 					chapters {
 						totalCount
 					}
-					latestUploadedChapter {
-						id
-						name
-						chapterNumber
-						uploadDate
-					}
 					source {
 						id
 						name
 						lang
 						iconUrl
 						isNsfw
+					}`
+
+// mangaListQuery lists the library on servers without latestUploadedChapter
+const mangaListQuery = `
+		query GetMangaList($inLibrary: Boolean, $first: Int, $offset: Int) {
+			mangas(condition: {inLibrary: $inLibrary}, first: $first, offset: $offset) {
+				totalCount
+				nodes {` + mangaListFields + `
+				}
+			}
+		}
+	`
+
+// mangaListLatestQuery lists the library with each manga's latest chapter
+const mangaListLatestQuery = `
+		query GetMangaList($inLibrary: Boolean, $first: Int, $offset: Int) {
+			mangas(condition: {inLibrary: $inLibrary}, first: $first, offset: $offset) {
+				totalCount
+				nodes {` + mangaListFields + `
+					latestUploadedChapter {
+						id
+						name
+						chapterNumber
+						uploadDate
 					}
 				}
 			}
 		}
 	`
 
+// GetMangaList retrieves the manga library using GraphQL
+func (gc *GraphQLClient) GetMangaList(inLibrary bool, limit int, offset int) (*MangaListResponse, error) {
+	// Older servers lack latestUploadedChapter
+	query := mangaListQuery
+	if gc.client.Supports(FeatureLatestUploadedChapter) {
+		query = mangaListLatestQuery
+	}
+
 	variables := map[string]interface{}{
 		"inLibrary": inLibrary,
 		"first":     limit,
@@ -275,6 +334,8 @@ func (gc *GraphQLClient) GetChapterList(mangaID int) ([]ChapterNode, error) {
 					isBookmarked
 					isDownloaded
 					pageCount
+					scanlator
+					realUrl
 				}
 			}
 		}
@@ -429,8 +490,8 @@ func (gc *GraphQLClient) GetExtensionList() ([]ExtensionNode, error) {
 // InstallExtension installs an extension
 func (gc *GraphQLClient) InstallExtension(pkgName string) error {
 	mutation := `
-		mutation InstallExtension($input: InstallExternalExtensionInput!) {
-			installExternalExtension(input: $input) {
+		mutation InstallExtension($input: UpdateExtensionInput!) {
+			updateExtension(input: $input) {
 				extension {
 					pkgName
 					isInstalled
@@ -441,7 +502,8 @@ func (gc *GraphQLClient) InstallExtension(pkgName string) error {
 
 	variables := map[string]interface{}{
 		"input": map[string]interface{}{
-			"pkgName": pkgName,
+			"id":    pkgName,
+			"patch": map[string]interface{}{"install": true},
 		},
 	}
 
@@ -451,8 +513,8 @@ func (gc *GraphQLClient) InstallExtension(pkgName string) error {
 // UninstallExtension uninstalls an extension
 func (gc *GraphQLClient) UninstallExtension(pkgName string) error {
 	mutation := `
-		mutation UninstallExtension($input: UninstallExtensionInput!) {
-			uninstallExtension(input: $input) {
+		mutation UninstallExtension($input: UpdateExtensionInput!) {
+			updateExtension(input: $input) {
 				extension {
 					pkgName
 					isInstalled
@@ -463,7 +525,8 @@ func (gc *GraphQLClient) UninstallExtension(pkgName string) error {
 
 	variables := map[string]interface{}{
 		"input": map[string]interface{}{
-			"pkgName": pkgName,
+			"id":    pkgName,
+			"patch": map[string]interface{}{"uninstall": true},
 		},
 	}
 
@@ -485,7 +548,8 @@ func (gc *GraphQLClient) UpdateExtension(pkgName string) error {
 
 	variables := map[string]interface{}{
 		"input": map[string]interface{}{
-			"pkgName": pkgName,
+			"id":    pkgName,
+			"patch": map[string]interface{}{"update": true},
 		},
 	}
 
@@ -496,25 +560,273 @@ func (gc *GraphQLClient) UpdateExtension(pkgName string) error {
 // This must be called before accessing individual page images via the REST API.
 // Suwayomi lazily loads pages only when requested, so this mutation "primes" the chapter.
 func (gc *GraphQLClient) FetchChapterPages(chapterID int) error {
+	_, err := gc.FetchChapterPageList(chapterID)
+	return err
+}
+
+// FetchChapterPageList primes a chapter like FetchChapterPages and returns
+// the URLs of its pages
+func (gc *GraphQLClient) FetchChapterPageList(chapterID int) ([]string, error) {
+	if !gc.client.Supports(FeatureFetchChapterPages) {
+		return nil, unsupportedError(FeatureFetchChapterPages)
+	}
+
+	result, err := operations.FetchChapterPages(gc, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if result.FetchChapterPages == nil {
+		return nil, nil
+	}
+	return result.FetchChapterPages.Pages, nil
+}
+
+// preferenceFields selects every member of the Preference union
+const preferenceFields = `
+	__typename
+	... on SwitchPreference {
+		key
+		title
+		summary
+		enabled
+		visible
+		switchValue: currentValue
+		switchDefault: default
+	}
+	... on CheckBoxPreference {
+		key
+		title
+		summary
+		enabled
+		visible
+		checkBoxValue: currentValue
+		checkBoxDefault: default
+	}
+	... on ListPreference {
+		key
+		title
+		summary
+		enabled
+		visible
+		entries
+		entryValues
+		listValue: currentValue
+		listDefault: default
+	}
+	... on MultiSelectListPreference {
+		key
+		title
+		summary
+		enabled
+		visible
+		dialogTitle
+		dialogMessage
+		entries
+		entryValues
+		multiValue: currentValue
+		multiDefault: default
+	}
+	... on EditTextPreference {
+		key
+		title
+		summary
+		enabled
+		visible
+		dialogTitle
+		dialogMessage
+		textValue: currentValue
+		textDefault: default
+	}
+`
+
+// GetExtensionSourceList retrieves the sources provided by an extension
+func (gc *GraphQLClient) GetExtensionSourceList(pkgName string) ([]SourceNode, error) {
+	if !gc.client.Supports(FeatureExtensionSources) {
+		return nil, unsupportedError(FeatureExtensionSources)
+	}
+
+	result, err := operations.ExtensionSources(gc, pkgName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]SourceNode, 0, len(result.Extension.Source.Nodes))
+	for _, node := range result.Extension.Source.Nodes {
+		nodes = append(nodes, SourceNode{
+			ID:             node.ID,
+			Name:           node.Name,
+			Lang:           node.Lang,
+			IconURL:        node.IconURL,
+			IsNsfw:         node.IsNSFW,
+			DisplayName:    node.DisplayName,
+			IsConfigurable: node.IsConfigurable,
+		})
+	}
+
+	return nodes, nil
+}
+
+// GetSourcePreferences retrieves the preferences of a source.
+// Source IDs are LongString in the schema, so they are passed as strings.
+func (gc *GraphQLClient) GetSourcePreferences(sourceID string) ([]PreferenceNode, error) {
+	if !gc.client.Supports(FeatureSourcePreferences) {
+		return nil, unsupportedError(FeatureSourcePreferences)
+	}
+
+	query := `
+		query GetSourcePreferences($id: LongString!) {
+			source(id: $id) {
+				preferences {` + preferenceFields + `}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"id": sourceID,
+	}
+
+	var result struct {
+		Source struct {
+			Preferences []PreferenceNode `json:"preferences"`
+		} `json:"source"`
+	}
+
+	if err := gc.Query(query, variables, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Source.Preferences, nil
+}
+
+// UpdateSourcePreference changes a single source preference and returns the
+// updated preference list. change must contain "position" and exactly one
+// of the *State fields of SourcePreferenceChangeInput.
+func (gc *GraphQLClient) UpdateSourcePreference(sourceID string, change map[string]interface{}) ([]PreferenceNode, error) {
+	if !gc.client.Supports(FeatureSourcePreferences) {
+		return nil, unsupportedError(FeatureSourcePreferences)
+	}
+
+	mutation := `
+		mutation UpdateSourcePreference($input: UpdateSourcePreferenceInput!) {
+			updateSourcePreference(input: $input) {
+				preferences {` + preferenceFields + `}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"source": sourceID,
+			"change": change,
+		},
+	}
+
+	var result struct {
+		UpdateSourcePreference struct {
+			Preferences []PreferenceNode `json:"preferences"`
+		} `json:"updateSourcePreference"`
+	}
+
+	if err := gc.Mutate(mutation, variables, &result); err != nil {
+		return nil, err
+	}
+
+	return result.UpdateSourcePreference.Preferences, nil
+}
+
+// serverSettingsFields selects the SettingsType fields mapped by ServerSettings
+const serverSettingsFields = `
+	ip
+	port
+	authMode
+	authUsername
+	authPassword
+	downloadsPath
+	downloadAsCbz
+	autoDownloadNewChapters
+	autoDownloadNewChaptersLimit
+	autoDownloadIgnoreReUploads
+	updateMangas
+	globalUpdateInterval
+	excludeCompleted
+	excludeNotStarted
+	excludeUnreadChapters
+	excludeEntryWithUnreadChapters
+	maxSourcesInParallel
+	extensionRepos
+	localSourcePath
+	flareSolverrEnabled
+	flareSolverrUrl
+	flareSolverrTimeout
+	flareSolverrSessionName
+	flareSolverrSessionTtl
+	flareSolverrAsResponseFallback
+	socksProxyEnabled
+	socksProxyVersion
+	socksProxyHost
+	socksProxyPort
+	socksProxyUsername
+	socksProxyPassword
+	backupPath
+	backupTime
+	backupInterval
+	backupTTL
+	debugLogsEnabled
+`
+
+// GetSettings retrieves the server-wide settings
+func (gc *GraphQLClient) GetSettings() (*ServerSettings, error) {
+	if !gc.client.Supports(FeatureServerSettings) {
+		return nil, unsupportedError(FeatureServerSettings)
+	}
+
+	query := `
+		query GetSettings {
+			settings {` + serverSettingsFields + `}
+		}
+	`
+
+	var result struct {
+		Settings ServerSettings `json:"settings"`
+	}
+
+	if err := gc.Query(query, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Settings, nil
+}
+
+// SetSettings updates server-wide settings. settings is a
+// PartialSettingsTypeInput; only the given fields are changed.
+func (gc *GraphQLClient) SetSettings(settings map[string]interface{}) (*ServerSettings, error) {
+	if !gc.client.Supports(FeatureServerSettings) {
+		return nil, unsupportedError(FeatureServerSettings)
+	}
+
 	mutation := `
-		mutation FetchChapterPages($input: FetchChapterPagesInput!) {
-			fetchChapterPages(input: $input) {
-				pages
+		mutation SetSettings($input: SetSettingsInput!) {
+			setSettings(input: $input) {
+				settings {` + serverSettingsFields + `}
 			}
 		}
 	`
 
 	variables := map[string]interface{}{
 		"input": map[string]interface{}{
-			"chapterId": chapterID,
+			"settings": settings,
 		},
 	}
 
 	var result struct {
-		FetchChapterPages struct {
-			Pages []string `json:"pages"`
-		} `json:"fetchChapterPages"`
+		SetSettings struct {
+			Settings ServerSettings `json:"settings"`
+		} `json:"setSettings"`
+	}
+
+	if err := gc.Mutate(mutation, variables, &result); err != nil {
+		return nil, err
 	}
 
-	return gc.Mutate(mutation, variables, &result)
+	return &result.SetSettings.Settings, nil
 }
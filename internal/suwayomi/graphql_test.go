@@ -555,10 +555,11 @@ func TestGraphQLClient_ExtensionManagement(t *testing.T) {
 
 			assert.Contains(t, req.Query, "InstallExtension")
 			input := req.Variables["input"].(map[string]interface{})
-			assert.Equal(t, "test.extension.pkg", input["pkgName"])
+			assert.Equal(t, "test.extension.pkg", input["id"])
+			assert.Equal(t, map[string]interface{}{"install": true}, input["patch"])
 
 			mockResponse := GraphQLResponse{
-				Data: json.RawMessage(`{"installExternalExtension":{"extension":{"pkgName":"test.extension.pkg","isInstalled":true}}}`),
+				Data: json.RawMessage(`{"updateExtension":{"extension":{"pkgName":"test.extension.pkg","isInstalled":true}}}`),
 			}
 			w.WriteHeader(http.StatusOK)
 			json.NewEncoder(w).Encode(mockResponse)
@@ -579,10 +580,11 @@ func TestGraphQLClient_ExtensionManagement(t *testing.T) {
 
 			assert.Contains(t, req.Query, "UninstallExtension")
 			input := req.Variables["input"].(map[string]interface{})
-			assert.Equal(t, "test.extension.pkg", input["pkgName"])
+			assert.Equal(t, "test.extension.pkg", input["id"])
+			assert.Equal(t, map[string]interface{}{"uninstall": true}, input["patch"])
 
 			mockResponse := GraphQLResponse{
-				Data: json.RawMessage(`{"uninstallExtension":{"extension":{"pkgName":"test.extension.pkg","isInstalled":false}}}`),
+				Data: json.RawMessage(`{"updateExtension":{"extension":{"pkgName":"test.extension.pkg","isInstalled":false}}}`),
 			}
 			w.WriteHeader(http.StatusOK)
 			json.NewEncoder(w).Encode(mockResponse)
@@ -603,7 +605,8 @@ func TestGraphQLClient_ExtensionManagement(t *testing.T) {
 
 			assert.Contains(t, req.Query, "UpdateExtension")
 			input := req.Variables["input"].(map[string]interface{})
-			assert.Equal(t, "test.extension.pkg", input["pkgName"])
+			assert.Equal(t, "test.extension.pkg", input["id"])
+			assert.Equal(t, map[string]interface{}{"update": true}, input["patch"])
 
 			mockResponse := GraphQLResponse{
 				Data: json.RawMessage(`{"updateExtension":{"extension":{"pkgName":"test.extension.pkg","hasUpdate":false}}}`),
@@ -0,0 +1,8 @@
+// Package operations contains typed GraphQL operations generated from the
+// .graphql files in this directory. The operations are validated against
+// schema/suwayomi_schema.json at generation time, so a schema change that
+// breaks an operation fails `go generate` and any Go code relying on a
+// removed field fails to compile.
+package operations
+
+//go:generate go run ../../../cmd/schema-tool codegen -schema ../../../schema/suwayomi_schema.json -ops . -out operations_gen.go -package operations
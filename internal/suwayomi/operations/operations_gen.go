@@ -0,0 +1,96 @@
+// Code generated by schema-tool codegen; DO NOT EDIT.
+// Sources: extension_sources.graphql, fetch_chapter_pages.graphql
+
+package operations
+
+// Client executes GraphQL documents. *suwayomi.GraphQLClient implements it.
+type Client interface {
+	Query(query string, variables map[string]interface{}, result interface{}) error
+	Mutate(mutation string, variables map[string]interface{}, result interface{}) error
+}
+
+// ExtensionSourcesDocument is the GraphQL document of the ExtensionSources query
+const ExtensionSourcesDocument = `query ExtensionSources($pkgName: String!) {
+  extension(pkgName: $pkgName) {
+    source {
+      nodes {
+        id
+        name
+        displayName
+        lang
+        iconUrl
+        isNsfw
+        isConfigurable
+      }
+    }
+  }
+}`
+
+// ExtensionSourcesResponse is a selection of Query
+type ExtensionSourcesResponse struct {
+	Extension ExtensionSourcesResponseExtension `json:"extension"`
+}
+
+// ExtensionSourcesResponseExtension is a selection of ExtensionType
+type ExtensionSourcesResponseExtension struct {
+	Source ExtensionSourcesResponseExtensionSource `json:"source"`
+}
+
+// ExtensionSourcesResponseExtensionSource is a selection of SourceNodeList
+type ExtensionSourcesResponseExtensionSource struct {
+	Nodes []ExtensionSourcesResponseExtensionSourceNodes `json:"nodes"`
+}
+
+// ExtensionSourcesResponseExtensionSourceNodes is a selection of SourceType
+type ExtensionSourcesResponseExtensionSourceNodes struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	DisplayName    string `json:"displayName"`
+	Lang           string `json:"lang"`
+	IconURL        string `json:"iconUrl"`
+	IsNSFW         bool   `json:"isNsfw"`
+	IsConfigurable bool   `json:"isConfigurable"`
+}
+
+// ExtensionSources executes the ExtensionSources query
+func ExtensionSources(c Client, pkgName string) (*ExtensionSourcesResponse, error) {
+	variables := map[string]interface{}{
+		"pkgName": pkgName,
+	}
+
+	var result ExtensionSourcesResponse
+	if err := c.Query(ExtensionSourcesDocument, variables, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FetchChapterPagesDocument is the GraphQL document of the FetchChapterPages mutation
+const FetchChapterPagesDocument = `mutation FetchChapterPages($chapterId: Int!) {
+  fetchChapterPages(input: {chapterId: $chapterId}) {
+    pages
+  }
+}`
+
+// FetchChapterPagesResponse is a selection of Mutation
+type FetchChapterPagesResponse struct {
+	FetchChapterPages *FetchChapterPagesResponseFetchChapterPages `json:"fetchChapterPages"`
+}
+
+// FetchChapterPagesResponseFetchChapterPages is a selection of FetchChapterPagesPayload
+type FetchChapterPagesResponseFetchChapterPages struct {
+	Pages []string `json:"pages"`
+}
+
+// FetchChapterPages executes the FetchChapterPages mutation
+func FetchChapterPages(c Client, chapterID int) (*FetchChapterPagesResponse, error) {
+	variables := map[string]interface{}{
+		"chapterId": chapterID,
+	}
+
+	var result FetchChapterPagesResponse
+	if err := c.Mutate(FetchChapterPagesDocument, variables, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,211 @@
+package suwayomi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PreferenceType identifies the kind of a source preference
+type PreferenceType string
+
+const (
+	PreferenceSwitch      PreferenceType = "switch"
+	PreferenceCheckBox    PreferenceType = "checkbox"
+	PreferenceList        PreferenceType = "list"
+	PreferenceMultiSelect PreferenceType = "multiselect"
+	PreferenceEditText    PreferenceType = "edittext"
+)
+
+// SourcePreference represents a configurable setting of a source.
+// Position is the index of the preference in the source's preference list,
+// which is what the server uses to identify it when changing a value.
+type SourcePreference struct {
+	Position      int
+	Type          PreferenceType
+	Key           string
+	Title         string
+	Summary       string
+	Enabled       bool
+	Visible       bool
+	DialogTitle   string
+	DialogMessage string
+
+	// Entries are the display labels for list preferences, EntryValues the
+	// values stored on the server
+	Entries     []string
+	EntryValues []string
+
+	// Current values; only the one matching Type is meaningful
+	BoolValue   bool
+	StringValue string
+	MultiValue  []string
+}
+
+// EntryLabel returns the display label for a list entry value
+func (p *SourcePreference) EntryLabel(value string) string {
+	for i, v := range p.EntryValues {
+		if v == value && i < len(p.Entries) {
+			return p.Entries[i]
+		}
+	}
+	return value
+}
+
+// DisplayValue returns the current value formatted for display
+func (p *SourcePreference) DisplayValue() string {
+	switch p.Type {
+	case PreferenceSwitch, PreferenceCheckBox:
+		if p.BoolValue {
+			return "On"
+		}
+		return "Off"
+	case PreferenceList:
+		return p.EntryLabel(p.StringValue)
+	case PreferenceMultiSelect:
+		if len(p.MultiValue) == 0 {
+			return "None"
+		}
+		labels := make([]string, 0, len(p.MultiValue))
+		for _, v := range p.MultiValue {
+			labels = append(labels, p.EntryLabel(v))
+		}
+		return strings.Join(labels, ", ")
+	default:
+		return p.StringValue
+	}
+}
+
+// SourcePreferenceChange describes a new value for a single preference
+type SourcePreferenceChange struct {
+	Position    int
+	Type        PreferenceType
+	BoolValue   bool
+	StringValue string
+	MultiValue  []string
+}
+
+// toInput converts the change to a SourcePreferenceChangeInput
+func (c SourcePreferenceChange) toInput() (map[string]interface{}, error) {
+	input := map[string]interface{}{
+		"position": c.Position,
+	}
+
+	switch c.Type {
+	case PreferenceSwitch:
+		input["switchState"] = c.BoolValue
+	case PreferenceCheckBox:
+		input["checkBoxState"] = c.BoolValue
+	case PreferenceList:
+		input["listState"] = c.StringValue
+	case PreferenceEditText:
+		input["editTextState"] = c.StringValue
+	case PreferenceMultiSelect:
+		values := c.MultiValue
+		if values == nil {
+			values = []string{}
+		}
+		input["multiSelectState"] = values
+	default:
+		return nil, fmt.Errorf("unsupported preference type: %s", c.Type)
+	}
+
+	return input, nil
+}
+
+// preferenceFromNode converts a GraphQL preference node to a SourcePreference
+func preferenceFromNode(position int, node PreferenceNode) *SourcePreference {
+	pref := &SourcePreference{
+		Position:      position,
+		Key:           node.Key,
+		Title:         node.Title,
+		Summary:       node.Summary,
+		Enabled:       node.Enabled,
+		Visible:       node.Visible,
+		DialogTitle:   node.DialogTitle,
+		DialogMessage: node.DialogMessage,
+		Entries:       node.Entries,
+		EntryValues:   node.EntryValues,
+	}
+
+	switch node.Typename {
+	case "SwitchPreference":
+		pref.Type = PreferenceSwitch
+		pref.BoolValue = node.SwitchDefault
+		if node.SwitchValue != nil {
+			pref.BoolValue = *node.SwitchValue
+		}
+	case "CheckBoxPreference":
+		pref.Type = PreferenceCheckBox
+		pref.BoolValue = node.CheckBoxDefault
+		if node.CheckBoxValue != nil {
+			pref.BoolValue = *node.CheckBoxValue
+		}
+	case "ListPreference":
+		pref.Type = PreferenceList
+		if node.ListDefault != nil {
+			pref.StringValue = *node.ListDefault
+		}
+		if node.ListValue != nil {
+			pref.StringValue = *node.ListValue
+		}
+	case "MultiSelectListPreference":
+		pref.Type = PreferenceMultiSelect
+		pref.MultiValue = node.MultiDefault
+		if node.MultiValue != nil {
+			pref.MultiValue = node.MultiValue
+		}
+	case "EditTextPreference":
+		pref.Type = PreferenceEditText
+		if node.TextDefault != nil {
+			pref.StringValue = *node.TextDefault
+		}
+		if node.TextValue != nil {
+			pref.StringValue = *node.TextValue
+		}
+	default:
+		return nil
+	}
+
+	return pref
+}
+
+// preferencesFromNodes converts GraphQL preference nodes, keeping their positions
+func preferencesFromNodes(nodes []PreferenceNode) []*SourcePreference {
+	prefs := make([]*SourcePreference, 0, len(nodes))
+	for i, node := range nodes {
+		if pref := preferenceFromNode(i, node); pref != nil {
+			prefs = append(prefs, pref)
+		}
+	}
+	return prefs
+}
+
+// GetSourcePreferences gets the configurable preferences of a source
+func (c *Client) GetSourcePreferences(sourceID string) ([]*SourcePreference, error) {
+	nodes, err := c.GraphQL.GetSourcePreferences(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch source preferences: %w", err)
+	}
+
+	return preferencesFromNodes(nodes), nil
+}
+
+// SetSourcePreference changes a source preference and returns the updated preferences
+func (c *Client) SetSourcePreference(sourceID string, change SourcePreferenceChange) ([]*SourcePreference, error) {
+	if _, err := strconv.ParseInt(sourceID, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid source ID %q: %w", sourceID, err)
+	}
+
+	input, err := change.toInput()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := c.GraphQL.UpdateSourcePreference(sourceID, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update source preference: %w", err)
+	}
+
+	return preferencesFromNodes(nodes), nil
+}
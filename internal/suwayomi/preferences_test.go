@@ -0,0 +1,145 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockPreferencesJSON = `[
+	{
+		"__typename": "SwitchPreference",
+		"key": "show_nsfw",
+		"title": "Show NSFW",
+		"summary": "",
+		"enabled": true,
+		"visible": true,
+		"switchValue": null,
+		"switchDefault": true
+	},
+	{
+		"__typename": "ListPreference",
+		"key": "quality",
+		"title": "Image quality",
+		"summary": "%s",
+		"enabled": true,
+		"visible": true,
+		"entries": ["Low", "High"],
+		"entryValues": ["low", "high"],
+		"listValue": "high",
+		"listDefault": "low"
+	},
+	{
+		"__typename": "MultiSelectListPreference",
+		"key": "langs",
+		"title": "Languages",
+		"summary": "",
+		"enabled": true,
+		"visible": true,
+		"entries": ["English", "French"],
+		"entryValues": ["en", "fr"],
+		"multiValue": ["en", "fr"],
+		"multiDefault": ["en"]
+	},
+	{
+		"__typename": "EditTextPreference",
+		"key": "mirror",
+		"title": "Mirror",
+		"summary": "",
+		"enabled": true,
+		"visible": true,
+		"dialogTitle": "Mirror URL",
+		"textValue": null,
+		"textDefault": "https://example.com"
+	}
+]`
+
+func TestClient_GetSourcePreferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, "1234567890", req.Variables["id"])
+		assert.Contains(t, req.Query, "preferences")
+
+		mockResponse := GraphQLResponse{
+			Data: json.RawMessage(`{"source": {"preferences": ` + mockPreferencesJSON + `}}`),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	prefs, err := client.GetSourcePreferences("1234567890")
+
+	require.NoError(t, err)
+	require.Len(t, prefs, 4)
+
+	// Switch falls back to default when no current value is set
+	assert.Equal(t, PreferenceSwitch, prefs[0].Type)
+	assert.Equal(t, 0, prefs[0].Position)
+	assert.True(t, prefs[0].BoolValue)
+	assert.Equal(t, "On", prefs[0].DisplayValue())
+
+	assert.Equal(t, PreferenceList, prefs[1].Type)
+	assert.Equal(t, "high", prefs[1].StringValue)
+	assert.Equal(t, "High", prefs[1].DisplayValue())
+
+	assert.Equal(t, PreferenceMultiSelect, prefs[2].Type)
+	assert.Equal(t, []string{"en", "fr"}, prefs[2].MultiValue)
+	assert.Equal(t, "English, French", prefs[2].DisplayValue())
+
+	assert.Equal(t, PreferenceEditText, prefs[3].Type)
+	assert.Equal(t, 3, prefs[3].Position)
+	assert.Equal(t, "https://example.com", prefs[3].StringValue)
+	assert.Equal(t, "Mirror URL", prefs[3].DialogTitle)
+}
+
+func TestClient_SetSourcePreference(t *testing.T) {
+	t.Run("sends change input", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req GraphQLRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Contains(t, req.Query, "updateSourcePreference")
+
+			input := req.Variables["input"].(map[string]interface{})
+			assert.Equal(t, "1234567890", input["source"])
+			change := input["change"].(map[string]interface{})
+			assert.Equal(t, float64(1), change["position"])
+			assert.Equal(t, "low", change["listState"])
+
+			mockResponse := GraphQLResponse{
+				Data: json.RawMessage(`{"updateSourcePreference": {"preferences": ` + mockPreferencesJSON + `}}`),
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(mockResponse)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		prefs, err := client.SetSourcePreference("1234567890", SourcePreferenceChange{
+			Position:    1,
+			Type:        PreferenceList,
+			StringValue: "low",
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, prefs, 4)
+	})
+
+	t.Run("invalid source ID", func(t *testing.T) {
+		client := NewClient("http://localhost:4567")
+		_, err := client.SetSourcePreference("abc", SourcePreferenceChange{Type: PreferenceSwitch})
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		client := NewClient("http://localhost:4567")
+		_, err := client.SetSourcePreference("1", SourcePreferenceChange{Type: "unknown"})
+		assert.Error(t, err)
+	})
+}
@@ -153,6 +153,8 @@ const (
 	CompatibilityWarn
 	// CompatibilityIgnore skips validation entirely
 	CompatibilityIgnore
+	// CompatibilityAdapt detects server capabilities and disables unsupported features
+	CompatibilityAdapt
 )
 
 // ValidateWithMode validates schema with specified compatibility mode
@@ -161,6 +163,18 @@ func ValidateWithMode(client *Client, schemaPath string, mode SchemaCompatibilit
 		return nil
 	}
 
+	if mode == CompatibilityAdapt {
+		caps, err := client.DetectCapabilities()
+		if err != nil {
+			log.Printf("⚠️  %v; assuming the latest schema", err)
+			return nil
+		}
+		for _, feature := range caps.Disabled() {
+			log.Printf("⚠️  %s unavailable on this server: %s", feature.Description, feature.Fallback)
+		}
+		return nil
+	}
+
 	validator := NewRuntimeValidator(client)
 	err := validator.ValidateOnStartup(schemaPath)
 
@@ -185,9 +199,11 @@ func GetSchemaCompatibilityMode() SchemaCompatibilityMode {
 		return CompatibilityWarn
 	case "ignore":
 		return CompatibilityIgnore
+	case "adapt":
+		return CompatibilityAdapt
 	default:
-		// Default to warn mode for development
-		return CompatibilityWarn
+		// Default to adapting to the connected server
+		return CompatibilityAdapt
 	}
 }
 
@@ -213,6 +229,8 @@ func modeString(mode SchemaCompatibilityMode) string {
 		return "warn"
 	case CompatibilityIgnore:
 		return "ignore"
+	case CompatibilityAdapt:
+		return "adapt"
 	default:
 		return "unknown"
 	}
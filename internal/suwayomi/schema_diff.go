@@ -0,0 +1,449 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaChangeKind identifies the kind of a schema change
+type SchemaChangeKind string
+
+const (
+	ChangeTypeRemoved          SchemaChangeKind = "TYPE_REMOVED"
+	ChangeTypeAdded            SchemaChangeKind = "TYPE_ADDED"
+	ChangeTypeKindChanged      SchemaChangeKind = "TYPE_KIND_CHANGED"
+	ChangeRootTypeChanged      SchemaChangeKind = "ROOT_TYPE_CHANGED"
+	ChangeFieldRemoved         SchemaChangeKind = "FIELD_REMOVED"
+	ChangeFieldAdded           SchemaChangeKind = "FIELD_ADDED"
+	ChangeFieldTypeChanged     SchemaChangeKind = "FIELD_TYPE_CHANGED"
+	ChangeFieldDeprecated      SchemaChangeKind = "FIELD_DEPRECATED"
+	ChangeArgRemoved           SchemaChangeKind = "ARG_REMOVED"
+	ChangeArgAdded             SchemaChangeKind = "ARG_ADDED"
+	ChangeArgTypeChanged       SchemaChangeKind = "ARG_TYPE_CHANGED"
+	ChangeArgDefaultChanged    SchemaChangeKind = "ARG_DEFAULT_CHANGED"
+	ChangeInputFieldRemoved    SchemaChangeKind = "INPUT_FIELD_REMOVED"
+	ChangeInputFieldAdded      SchemaChangeKind = "INPUT_FIELD_ADDED"
+	ChangeInputFieldTypeChange SchemaChangeKind = "INPUT_FIELD_TYPE_CHANGED"
+	ChangeEnumValueRemoved     SchemaChangeKind = "ENUM_VALUE_REMOVED"
+	ChangeEnumValueAdded       SchemaChangeKind = "ENUM_VALUE_ADDED"
+	ChangeEnumValueDeprecated  SchemaChangeKind = "ENUM_VALUE_DEPRECATED"
+	ChangeUnionMemberRemoved   SchemaChangeKind = "UNION_MEMBER_REMOVED"
+	ChangeUnionMemberAdded     SchemaChangeKind = "UNION_MEMBER_ADDED"
+)
+
+// SchemaChange is a single difference between two schema snapshots
+type SchemaChange struct {
+	Kind     SchemaChangeKind `json:"kind"`
+	Breaking bool             `json:"breaking"`
+	// Coordinate is the schema element the change applies to, such as
+	// "MangaType", "MangaType.title", "MangaType.chapters(first:)" or "SortOrder.ASC"
+	Coordinate string `json:"coordinate"`
+	Message    string `json:"message"`
+	// AffectedOperations lists client operations that use the coordinate
+	AffectedOperations []string `json:"affectedOperations,omitempty"`
+}
+
+// SchemaDiff is the result of comparing two schema snapshots
+type SchemaDiff struct {
+	OldServer   string         `json:"oldServer,omitempty"`
+	NewServer   string         `json:"newServer,omitempty"`
+	Breaking    []SchemaChange `json:"breaking"`
+	NonBreaking []SchemaChange `json:"nonBreaking"`
+}
+
+// DiffSchemas compares an old schema snapshot with a new one
+func DiffSchemas(oldSchema, newSchema *IntrospectionResult) *SchemaDiff {
+	d := &schemaDiffer{
+		diff: &SchemaDiff{OldServer: oldSchema.ServerInfo, NewServer: newSchema.ServerInfo, Breaking: []SchemaChange{}, NonBreaking: []SchemaChange{}},
+	}
+
+	d.diffRootType("query", oldSchema.Schema.QueryType, newSchema.Schema.QueryType)
+	d.diffRootType("mutation", oldSchema.Schema.MutationType, newSchema.Schema.MutationType)
+	d.diffRootType("subscription", oldSchema.Schema.SubscriptionType, newSchema.Schema.SubscriptionType)
+
+	for i := range oldSchema.Schema.Types {
+		oldType := &oldSchema.Schema.Types[i]
+		if strings.HasPrefix(oldType.Name, "__") {
+			continue
+		}
+
+		newType := newSchema.GetTypeByName(oldType.Name)
+		if newType == nil {
+			d.add(ChangeTypeRemoved, true, oldType.Name, "%s %s was removed", strings.ToLower(oldType.Kind), oldType.Name)
+			continue
+		}
+		if oldType.Kind != newType.Kind {
+			d.add(ChangeTypeKindChanged, true, oldType.Name, "%s changed from %s to %s", oldType.Name, oldType.Kind, newType.Kind)
+			continue
+		}
+
+		switch oldType.Kind {
+		case "OBJECT", "INTERFACE":
+			d.diffFields(oldType, newType)
+		case "INPUT_OBJECT":
+			d.diffInputFields(oldType, newType)
+		case "ENUM":
+			d.diffEnumValues(oldType, newType)
+		case "UNION":
+			d.diffPossibleTypes(oldType, newType)
+		}
+	}
+
+	for _, newType := range newSchema.Schema.Types {
+		if strings.HasPrefix(newType.Name, "__") {
+			continue
+		}
+		if oldSchema.GetTypeByName(newType.Name) == nil {
+			d.add(ChangeTypeAdded, false, newType.Name, "%s %s was added", strings.ToLower(newType.Kind), newType.Name)
+		}
+	}
+
+	sortChanges(d.diff.Breaking)
+	sortChanges(d.diff.NonBreaking)
+	return d.diff
+}
+
+// schemaDiffer collects changes while comparing two schemas
+type schemaDiffer struct {
+	diff *SchemaDiff
+}
+
+func (d *schemaDiffer) add(kind SchemaChangeKind, breaking bool, coordinate, format string, args ...interface{}) {
+	change := SchemaChange{
+		Kind:       kind,
+		Breaking:   breaking,
+		Coordinate: coordinate,
+		Message:    fmt.Sprintf(format, args...),
+	}
+	if breaking {
+		d.diff.Breaking = append(d.diff.Breaking, change)
+	} else {
+		d.diff.NonBreaking = append(d.diff.NonBreaking, change)
+	}
+}
+
+func (d *schemaDiffer) diffRootType(operation string, oldRef, newRef *SchemaTypeRef) {
+	switch {
+	case oldRef == nil:
+		return
+	case newRef == nil:
+		d.add(ChangeRootTypeChanged, true, oldRef.Name, "%s root type was removed", operation)
+	case oldRef.Name != newRef.Name:
+		d.add(ChangeRootTypeChanged, true, oldRef.Name, "%s root type changed from %s to %s", operation, oldRef.Name, newRef.Name)
+	}
+}
+
+func (d *schemaDiffer) diffFields(oldType, newType *SchemaType) {
+	for i := range oldType.Fields {
+		oldField := &oldType.Fields[i]
+		coordinate := oldType.Name + "." + oldField.Name
+
+		newField := newType.GetFieldByName(oldField.Name)
+		if newField == nil {
+			d.add(ChangeFieldRemoved, true, coordinate, "field %s was removed", coordinate)
+			continue
+		}
+
+		oldTypeStr, newTypeStr := typeRefString(oldField.Type), typeRefString(newField.Type)
+		if oldTypeStr != newTypeStr {
+			// Output types may only become stricter
+			breaking := !isSafeOutputChange(oldField.Type, newField.Type)
+			d.add(ChangeFieldTypeChanged, breaking, coordinate, "field %s changed type from %s to %s", coordinate, oldTypeStr, newTypeStr)
+		}
+
+		if !oldField.IsDeprecated && newField.IsDeprecated {
+			d.add(ChangeFieldDeprecated, false, coordinate, "field %s was deprecated: %s", coordinate, newField.DeprecationReason)
+		}
+
+		d.diffArgs(coordinate, oldField, newField)
+	}
+
+	for _, newField := range newType.Fields {
+		if oldType.GetFieldByName(newField.Name) == nil {
+			coordinate := newType.Name + "." + newField.Name
+			d.add(ChangeFieldAdded, false, coordinate, "field %s was added", coordinate)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffArgs(fieldCoordinate string, oldField, newField *SchemaField) {
+	for _, oldArg := range oldField.Args {
+		coordinate := fmt.Sprintf("%s(%s:)", fieldCoordinate, oldArg.Name)
+
+		newArg := findSchemaInputValue(newField.Args, oldArg.Name)
+		if newArg == nil {
+			d.add(ChangeArgRemoved, true, coordinate, "argument %s was removed", coordinate)
+			continue
+		}
+
+		oldTypeStr, newTypeStr := typeRefString(oldArg.Type), typeRefString(newArg.Type)
+		if oldTypeStr != newTypeStr {
+			breaking := !isSafeInputChange(oldArg.Type, newArg.Type)
+			d.add(ChangeArgTypeChanged, breaking, coordinate, "argument %s changed type from %s to %s", coordinate, oldTypeStr, newTypeStr)
+		}
+		if oldArg.DefaultValue != newArg.DefaultValue {
+			d.add(ChangeArgDefaultChanged, false, coordinate, "argument %s default changed from %q to %q", coordinate, oldArg.DefaultValue, newArg.DefaultValue)
+		}
+	}
+
+	for _, newArg := range newField.Args {
+		if findSchemaInputValue(oldField.Args, newArg.Name) != nil {
+			continue
+		}
+		if isRequiredInput(newArg) {
+			// Every existing selection of the field now lacks the argument
+			d.add(ChangeArgAdded, true, fieldCoordinate, "required argument %s(%s:) of type %s was added", fieldCoordinate, newArg.Name, typeRefString(newArg.Type))
+		} else {
+			d.add(ChangeArgAdded, false, fmt.Sprintf("%s(%s:)", fieldCoordinate, newArg.Name), "optional argument %s(%s:) was added", fieldCoordinate, newArg.Name)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffInputFields(oldType, newType *SchemaType) {
+	for _, oldField := range oldType.InputFields {
+		coordinate := oldType.Name + "." + oldField.Name
+
+		newField := findSchemaInputValue(newType.InputFields, oldField.Name)
+		if newField == nil {
+			d.add(ChangeInputFieldRemoved, true, coordinate, "input field %s was removed", coordinate)
+			continue
+		}
+
+		oldTypeStr, newTypeStr := typeRefString(oldField.Type), typeRefString(newField.Type)
+		if oldTypeStr != newTypeStr {
+			breaking := !isSafeInputChange(oldField.Type, newField.Type)
+			d.add(ChangeInputFieldTypeChange, breaking, coordinate, "input field %s changed type from %s to %s", coordinate, oldTypeStr, newTypeStr)
+		}
+	}
+
+	for _, newField := range newType.InputFields {
+		if findSchemaInputValue(oldType.InputFields, newField.Name) != nil {
+			continue
+		}
+		if isRequiredInput(newField) {
+			d.add(ChangeInputFieldAdded, true, newType.Name, "required input field %s.%s of type %s was added", newType.Name, newField.Name, typeRefString(newField.Type))
+		} else {
+			d.add(ChangeInputFieldAdded, false, newType.Name+"."+newField.Name, "optional input field %s.%s was added", newType.Name, newField.Name)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffEnumValues(oldType, newType *SchemaType) {
+	newValues := make(map[string]SchemaEnumValue, len(newType.EnumValues))
+	for _, v := range newType.EnumValues {
+		newValues[v.Name] = v
+	}
+	oldValues := make(map[string]bool, len(oldType.EnumValues))
+
+	for _, oldValue := range oldType.EnumValues {
+		oldValues[oldValue.Name] = true
+		coordinate := oldType.Name + "." + oldValue.Name
+
+		newValue, ok := newValues[oldValue.Name]
+		if !ok {
+			d.add(ChangeEnumValueRemoved, true, coordinate, "enum value %s was removed", coordinate)
+			continue
+		}
+		if !oldValue.IsDeprecated && newValue.IsDeprecated {
+			d.add(ChangeEnumValueDeprecated, false, coordinate, "enum value %s was deprecated: %s", coordinate, newValue.DeprecationReason)
+		}
+	}
+
+	for _, newValue := range newType.EnumValues {
+		if !oldValues[newValue.Name] {
+			coordinate := newType.Name + "." + newValue.Name
+			d.add(ChangeEnumValueAdded, false, coordinate, "enum value %s was added", coordinate)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffPossibleTypes(oldType, newType *SchemaType) {
+	oldMembers := make(map[string]bool)
+	for _, ref := range oldType.PossibleTypes {
+		oldMembers[ref.Name] = true
+	}
+	newMembers := make(map[string]bool)
+	for _, ref := range newType.PossibleTypes {
+		newMembers[ref.Name] = true
+	}
+
+	for _, ref := range oldType.PossibleTypes {
+		if !newMembers[ref.Name] {
+			d.add(ChangeUnionMemberRemoved, true, oldType.Name, "%s was removed from union %s", ref.Name, oldType.Name)
+		}
+	}
+	for _, ref := range newType.PossibleTypes {
+		if !oldMembers[ref.Name] {
+			d.add(ChangeUnionMemberAdded, false, newType.Name, "%s was added to union %s", ref.Name, newType.Name)
+		}
+	}
+}
+
+// isSafeOutputChange reports whether a field type change keeps existing
+// queries working, which is only the case when nullable types become non-null
+func isSafeOutputChange(from, to SchemaTypeRef) bool {
+	if to.Kind == "NON_NULL" && to.OfType != nil {
+		if from.Kind == "NON_NULL" && from.OfType != nil {
+			return isSafeOutputChange(*from.OfType, *to.OfType)
+		}
+		return isSafeOutputChange(from, *to.OfType)
+	}
+	if from.Kind != to.Kind {
+		return false
+	}
+	if from.Kind == "LIST" && from.OfType != nil && to.OfType != nil {
+		return isSafeOutputChange(*from.OfType, *to.OfType)
+	}
+	return from.Name == to.Name
+}
+
+// isSafeInputChange reports whether an argument or input field type change
+// keeps existing requests valid, which is only the case when non-null types
+// become nullable
+func isSafeInputChange(from, to SchemaTypeRef) bool {
+	if from.Kind == "NON_NULL" && from.OfType != nil {
+		if to.Kind == "NON_NULL" && to.OfType != nil {
+			return isSafeInputChange(*from.OfType, *to.OfType)
+		}
+		return isSafeInputChange(*from.OfType, to)
+	}
+	if from.Kind != to.Kind {
+		return false
+	}
+	if from.Kind == "LIST" && from.OfType != nil && to.OfType != nil {
+		return isSafeInputChange(*from.OfType, *to.OfType)
+	}
+	return from.Name == to.Name
+}
+
+// isRequiredInput reports whether an argument or input field must be provided
+func isRequiredInput(v SchemaInputValue) bool {
+	return v.Type.Kind == "NON_NULL" && v.DefaultValue == ""
+}
+
+func findSchemaInputValue(values []SchemaInputValue, name string) *SchemaInputValue {
+	for i := range values {
+		if values[i].Name == name {
+			return &values[i]
+		}
+	}
+	return nil
+}
+
+// typeRefString formats a type reference in GraphQL syntax
+func typeRefString(ref SchemaTypeRef) string {
+	switch ref.Kind {
+	case "NON_NULL":
+		if ref.OfType != nil {
+			return typeRefString(*ref.OfType) + "!"
+		}
+	case "LIST":
+		if ref.OfType != nil {
+			return "[" + typeRefString(*ref.OfType) + "]"
+		}
+	}
+	return ref.Name
+}
+
+func sortChanges(changes []SchemaChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Coordinate != changes[j].Coordinate {
+			return changes[i].Coordinate < changes[j].Coordinate
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+}
+
+// HasBreakingChanges reports whether any breaking change was found
+func (d *SchemaDiff) HasBreakingChanges() bool {
+	return len(d.Breaking) > 0
+}
+
+// MapOperations records which client operations each breaking change affects.
+// uses maps a schema coordinate to the operations that depend on it.
+func (d *SchemaDiff) MapOperations(uses map[string][]string) {
+	for i := range d.Breaking {
+		ops := uses[d.Breaking[i].Coordinate]
+		if len(ops) == 0 {
+			d.Breaking[i].AffectedOperations = nil
+			continue
+		}
+		affected := append([]string{}, ops...)
+		sort.Strings(affected)
+		d.Breaking[i].AffectedOperations = affected
+	}
+}
+
+// AffectedOperations returns every client operation affected by a breaking change
+func (d *SchemaDiff) AffectedOperations() []string {
+	seen := make(map[string]bool)
+	for _, change := range d.Breaking {
+		for _, op := range change.AffectedOperations {
+			seen[op] = true
+		}
+	}
+	ops := make([]string, 0, len(seen))
+	for op := range seen {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// JSON returns the diff as indented JSON
+func (d *SchemaDiff) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema diff: %w", err)
+	}
+	return data, nil
+}
+
+// Report generates a human-readable compatibility report
+func (d *SchemaDiff) Report() string {
+	var report strings.Builder
+
+	if d.OldServer != "" || d.NewServer != "" {
+		report.WriteString(fmt.Sprintf("Old: %s\nNew: %s\n\n", valueOr(d.OldServer, "unknown"), valueOr(d.NewServer, "unknown")))
+	}
+
+	if len(d.Breaking) == 0 {
+		report.WriteString("✅ No breaking changes\n\n")
+	} else {
+		report.WriteString(fmt.Sprintf("❌ Breaking Changes (%d):\n", len(d.Breaking)))
+		for _, change := range d.Breaking {
+			report.WriteString(fmt.Sprintf("  • [%s] %s\n", change.Kind, change.Message))
+			if len(change.AffectedOperations) > 0 {
+				report.WriteString(fmt.Sprintf("      affects: %s\n", strings.Join(change.AffectedOperations, ", ")))
+			}
+		}
+		report.WriteString("\n")
+	}
+
+	if len(d.NonBreaking) > 0 {
+		report.WriteString(fmt.Sprintf("Non-Breaking Changes (%d):\n", len(d.NonBreaking)))
+		for _, change := range d.NonBreaking {
+			report.WriteString(fmt.Sprintf("  • [%s] %s\n", change.Kind, change.Message))
+		}
+		report.WriteString("\n")
+	}
+
+	if ops := d.AffectedOperations(); len(ops) > 0 {
+		report.WriteString(fmt.Sprintf("Affected Client Operations (%d):\n", len(ops)))
+		for _, op := range ops {
+			report.WriteString(fmt.Sprintf("  • %s\n", op))
+		}
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
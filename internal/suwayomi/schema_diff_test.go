@@ -0,0 +1,176 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func named(kind, name string) SchemaTypeRef {
+	return SchemaTypeRef{Kind: kind, Name: name}
+}
+
+func nonNull(ref SchemaTypeRef) SchemaTypeRef {
+	return SchemaTypeRef{Kind: "NON_NULL", OfType: &ref}
+}
+
+func listOf(ref SchemaTypeRef) SchemaTypeRef {
+	return SchemaTypeRef{Kind: "LIST", OfType: &ref}
+}
+
+func testSchema(types ...SchemaType) *IntrospectionResult {
+	return &IntrospectionResult{Schema: Schema{
+		QueryType: &SchemaTypeRef{Name: "Query"},
+		Types:     types,
+	}}
+}
+
+func findChange(changes []SchemaChange, kind SchemaChangeKind, coordinate string) *SchemaChange {
+	for i := range changes {
+		if changes[i].Kind == kind && changes[i].Coordinate == coordinate {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestDiffSchemas(t *testing.T) {
+	str := named("SCALAR", "String")
+	integer := named("SCALAR", "Int")
+
+	old := testSchema(
+		SchemaType{Kind: "OBJECT", Name: "Query", Fields: []SchemaField{
+			{Name: "manga", Type: nonNull(named("OBJECT", "MangaType")), Args: []SchemaInputValue{
+				{Name: "id", Type: nonNull(integer)},
+			}},
+			{Name: "search", Type: listOf(named("OBJECT", "MangaType")), Args: []SchemaInputValue{
+				{Name: "query", Type: nonNull(str)},
+				{Name: "order", Type: named("ENUM", "SortOrder")},
+			}},
+		}},
+		SchemaType{Kind: "OBJECT", Name: "MangaType", Fields: []SchemaField{
+			{Name: "id", Type: nonNull(integer)},
+			{Name: "title", Type: nonNull(str)},
+			{Name: "description", Type: str},
+			{Name: "thumbnail", Type: str},
+		}},
+		SchemaType{Kind: "ENUM", Name: "SortOrder", EnumValues: []SchemaEnumValue{{Name: "ASC"}, {Name: "DESC"}}},
+		SchemaType{Kind: "INPUT_OBJECT", Name: "MangaFilter", InputFields: []SchemaInputValue{
+			{Name: "title", Type: nonNull(str)},
+			{Name: "genre", Type: str},
+		}},
+		SchemaType{Kind: "OBJECT", Name: "Legacy"},
+		SchemaType{Kind: "UNION", Name: "Preference", PossibleTypes: []SchemaTypeRef{named("OBJECT", "A"), named("OBJECT", "B")}},
+		SchemaType{Kind: "SCALAR", Name: "__Internal"},
+	)
+
+	updated := testSchema(
+		SchemaType{Kind: "OBJECT", Name: "Query", Fields: []SchemaField{
+			{Name: "manga", Type: nonNull(named("OBJECT", "MangaType")), Args: []SchemaInputValue{
+				{Name: "id", Type: nonNull(integer)},
+				{Name: "source", Type: nonNull(str)},
+				{Name: "includeRead", Type: named("SCALAR", "Boolean")},
+			}},
+			{Name: "search", Type: listOf(named("OBJECT", "MangaType")), Args: []SchemaInputValue{
+				{Name: "query", Type: str},
+			}},
+		}},
+		SchemaType{Kind: "OBJECT", Name: "MangaType", Fields: []SchemaField{
+			{Name: "id", Type: nonNull(str)},
+			{Name: "title", Type: str},
+			{Name: "description", Type: nonNull(str), IsDeprecated: true, DeprecationReason: "use summary"},
+			{Name: "summary", Type: str},
+		}},
+		SchemaType{Kind: "ENUM", Name: "SortOrder", EnumValues: []SchemaEnumValue{{Name: "ASC"}, {Name: "ASC_NULLS_LAST"}}},
+		SchemaType{Kind: "INPUT_OBJECT", Name: "MangaFilter", InputFields: []SchemaInputValue{
+			{Name: "title", Type: str},
+			{Name: "genre", Type: nonNull(str)},
+			{Name: "status", Type: nonNull(str)},
+			{Name: "author", Type: str},
+		}},
+		SchemaType{Kind: "UNION", Name: "Preference", PossibleTypes: []SchemaTypeRef{named("OBJECT", "A"), named("OBJECT", "C")}},
+		SchemaType{Kind: "OBJECT", Name: "NewType"},
+	)
+
+	diff := DiffSchemas(old, updated)
+
+	breaking := []struct {
+		kind       SchemaChangeKind
+		coordinate string
+	}{
+		{ChangeTypeRemoved, "Legacy"},
+		{ChangeFieldRemoved, "MangaType.thumbnail"},
+		{ChangeFieldTypeChanged, "MangaType.id"},    // Int! -> String!
+		{ChangeFieldTypeChanged, "MangaType.title"}, // String! -> String
+		{ChangeArgAdded, "Query.manga"},             // required source argument
+		{ChangeArgRemoved, "Query.search(order:)"},
+		{ChangeEnumValueRemoved, "SortOrder.DESC"},
+		{ChangeInputFieldTypeChange, "MangaFilter.genre"}, // String -> String!
+		{ChangeInputFieldAdded, "MangaFilter"},            // required status field
+		{ChangeUnionMemberRemoved, "Preference"},
+	}
+	for _, want := range breaking {
+		assert.NotNil(t, findChange(diff.Breaking, want.kind, want.coordinate), "expected breaking %s %s", want.kind, want.coordinate)
+	}
+	assert.Len(t, diff.Breaking, len(breaking))
+
+	nonBreaking := []struct {
+		kind       SchemaChangeKind
+		coordinate string
+	}{
+		{ChangeTypeAdded, "NewType"},
+		{ChangeFieldAdded, "MangaType.summary"},
+		{ChangeFieldTypeChanged, "MangaType.description"}, // String -> String!
+		{ChangeFieldDeprecated, "MangaType.description"},
+		{ChangeArgAdded, "Query.manga(includeRead:)"},
+		{ChangeArgTypeChanged, "Query.search(query:)"},    // String! -> String
+		{ChangeInputFieldTypeChange, "MangaFilter.title"}, // String! -> String
+		{ChangeInputFieldAdded, "MangaFilter.author"},
+		{ChangeEnumValueAdded, "SortOrder.ASC_NULLS_LAST"},
+		{ChangeUnionMemberAdded, "Preference"},
+	}
+	for _, want := range nonBreaking {
+		assert.NotNil(t, findChange(diff.NonBreaking, want.kind, want.coordinate), "expected non-breaking %s %s", want.kind, want.coordinate)
+	}
+	assert.Len(t, diff.NonBreaking, len(nonBreaking))
+	assert.True(t, diff.HasBreakingChanges())
+}
+
+func TestDiffSchemas_Identical(t *testing.T) {
+	schema := testSchema(SchemaType{Kind: "OBJECT", Name: "Query", Fields: []SchemaField{
+		{Name: "ok", Type: named("SCALAR", "Boolean")},
+	}})
+
+	diff := DiffSchemas(schema, schema)
+	assert.False(t, diff.HasBreakingChanges())
+	assert.Empty(t, diff.NonBreaking)
+	assert.Contains(t, diff.Report(), "No breaking changes")
+}
+
+func TestSchemaDiff_MapOperations(t *testing.T) {
+	diff := &SchemaDiff{Breaking: []SchemaChange{
+		{Kind: ChangeFieldRemoved, Breaking: true, Coordinate: "MangaType.thumbnail", Message: "field MangaType.thumbnail was removed"},
+		{Kind: ChangeTypeRemoved, Breaking: true, Coordinate: "Legacy", Message: "object Legacy was removed"},
+	}}
+
+	diff.MapOperations(map[string][]string{
+		"MangaType.thumbnail": {"GraphQLClient.GetMangaList", "GraphQLClient.GetMangaDetails"},
+		"MangaType.title":     {"GraphQLClient.GetMangaList"},
+	})
+
+	assert.Equal(t, []string{"GraphQLClient.GetMangaDetails", "GraphQLClient.GetMangaList"}, diff.Breaking[0].AffectedOperations)
+	assert.Empty(t, diff.Breaking[1].AffectedOperations)
+	assert.Equal(t, []string{"GraphQLClient.GetMangaDetails", "GraphQLClient.GetMangaList"}, diff.AffectedOperations())
+
+	report := diff.Report()
+	assert.Contains(t, report, "Breaking Changes (2)")
+	assert.Contains(t, report, "affects: GraphQLClient.GetMangaDetails, GraphQLClient.GetMangaList")
+
+	data, err := diff.JSON()
+	require.NoError(t, err)
+	var decoded SchemaDiff
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, diff.Breaking, decoded.Breaking)
+}
@@ -0,0 +1,259 @@
+package suwayomi
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Server authentication modes (AuthMode enum)
+const (
+	AuthModeNone        = "NONE"
+	AuthModeBasicAuth   = "BASIC_AUTH"
+	AuthModeSimpleLogin = "SIMPLE_LOGIN"
+)
+
+// ServerSettings holds the server-wide settings of a Suwayomi server.
+// Field names follow the GraphQL SettingsType; deprecated fields are omitted.
+type ServerSettings struct {
+	// Server
+	IP   string `json:"ip"`
+	Port int    `json:"port"`
+
+	// Authentication
+	AuthMode     string `json:"authMode"`
+	AuthUsername string `json:"authUsername"`
+	AuthPassword string `json:"authPassword"`
+
+	// Downloads
+	DownloadsPath                string `json:"downloadsPath"`
+	DownloadAsCbz                bool   `json:"downloadAsCbz"`
+	AutoDownloadNewChapters      bool   `json:"autoDownloadNewChapters"`
+	AutoDownloadNewChaptersLimit int    `json:"autoDownloadNewChaptersLimit"`
+	AutoDownloadIgnoreReUploads  bool   `json:"autoDownloadIgnoreReUploads"`
+
+	// Library updates
+	UpdateMangas                   bool    `json:"updateMangas"`
+	GlobalUpdateInterval           float64 `json:"globalUpdateInterval"`
+	ExcludeCompleted               bool    `json:"excludeCompleted"`
+	ExcludeNotStarted              bool    `json:"excludeNotStarted"`
+	ExcludeUnreadChapters          bool    `json:"excludeUnreadChapters"`
+	ExcludeEntryWithUnreadChapters bool    `json:"excludeEntryWithUnreadChapters"`
+	MaxSourcesInParallel           int     `json:"maxSourcesInParallel"`
+
+	// Extensions and local source
+	ExtensionRepos  []string `json:"extensionRepos"`
+	LocalSourcePath string   `json:"localSourcePath"`
+
+	// FlareSolverr
+	FlareSolverrEnabled            bool   `json:"flareSolverrEnabled"`
+	FlareSolverrURL                string `json:"flareSolverrUrl"`
+	FlareSolverrTimeout            int    `json:"flareSolverrTimeout"`
+	FlareSolverrSessionName        string `json:"flareSolverrSessionName"`
+	FlareSolverrSessionTTL         int    `json:"flareSolverrSessionTtl"`
+	FlareSolverrAsResponseFallback bool   `json:"flareSolverrAsResponseFallback"`
+
+	// SOCKS proxy
+	SocksProxyEnabled  bool   `json:"socksProxyEnabled"`
+	SocksProxyVersion  int    `json:"socksProxyVersion"`
+	SocksProxyHost     string `json:"socksProxyHost"`
+	SocksProxyPort     string `json:"socksProxyPort"`
+	SocksProxyUsername string `json:"socksProxyUsername"`
+	SocksProxyPassword string `json:"socksProxyPassword"`
+
+	// Backups
+	BackupPath     string `json:"backupPath"`
+	BackupTime     string `json:"backupTime"`
+	BackupInterval int    `json:"backupInterval"`
+	BackupTTL      int    `json:"backupTTL"`
+
+	// Logging
+	DebugLogsEnabled bool `json:"debugLogsEnabled"`
+}
+
+// ServerSettingChange describes a single changed server setting
+type ServerSettingChange struct {
+	Field    string // GraphQL field name
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// Clone returns a deep copy of the settings
+func (s *ServerSettings) Clone() *ServerSettings {
+	clone := *s
+	if s.ExtensionRepos != nil {
+		clone.ExtensionRepos = append([]string{}, s.ExtensionRepos...)
+	}
+	return &clone
+}
+
+// Validate checks the settings for values the server would reject or that
+// would leave it unusable
+func (s *ServerSettings) Validate() error {
+	if s.Port < 1 || s.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", s.Port)
+	}
+
+	switch s.AuthMode {
+	case AuthModeNone:
+	case AuthModeBasicAuth, AuthModeSimpleLogin:
+		if strings.TrimSpace(s.AuthUsername) == "" || s.AuthPassword == "" {
+			return fmt.Errorf("auth mode %s requires a username and password", s.AuthMode)
+		}
+	default:
+		return fmt.Errorf("unknown auth mode: %s", s.AuthMode)
+	}
+
+	if strings.TrimSpace(s.DownloadsPath) == "" {
+		return fmt.Errorf("downloads path cannot be empty")
+	}
+
+	if s.AutoDownloadNewChaptersLimit < 0 {
+		return fmt.Errorf("auto-download limit cannot be negative")
+	}
+
+	// The server enforces a minimum of 6 hours; 0 disables automatic updates
+	if s.GlobalUpdateInterval != 0 && s.GlobalUpdateInterval < 6 {
+		return fmt.Errorf("global update interval must be 0 (disabled) or at least 6 hours")
+	}
+
+	if s.MaxSourcesInParallel < 1 || s.MaxSourcesInParallel > 20 {
+		return fmt.Errorf("max sources in parallel must be between 1 and 20, got %d", s.MaxSourcesInParallel)
+	}
+
+	for _, repo := range s.ExtensionRepos {
+		if err := validateHTTPURL(repo); err != nil {
+			return fmt.Errorf("invalid extension repository %q: %w", repo, err)
+		}
+	}
+
+	if s.FlareSolverrEnabled {
+		if err := validateHTTPURL(s.FlareSolverrURL); err != nil {
+			return fmt.Errorf("invalid FlareSolverr URL: %w", err)
+		}
+		if s.FlareSolverrTimeout <= 0 {
+			return fmt.Errorf("FlareSolverr timeout must be positive")
+		}
+		if s.FlareSolverrSessionTTL <= 0 {
+			return fmt.Errorf("FlareSolverr session TTL must be positive")
+		}
+	}
+
+	if s.SocksProxyEnabled {
+		if s.SocksProxyVersion != 4 && s.SocksProxyVersion != 5 {
+			return fmt.Errorf("SOCKS proxy version must be 4 or 5, got %d", s.SocksProxyVersion)
+		}
+		if strings.TrimSpace(s.SocksProxyHost) == "" {
+			return fmt.Errorf("SOCKS proxy host cannot be empty")
+		}
+		if s.SocksProxyPort == "" {
+			return fmt.Errorf("SOCKS proxy port cannot be empty")
+		}
+	}
+
+	if s.BackupTime != "" {
+		if _, err := time.Parse("15:04", s.BackupTime); err != nil {
+			return fmt.Errorf("backup time must be in HH:MM format, got %q", s.BackupTime)
+		}
+	}
+
+	if s.BackupInterval < 0 {
+		return fmt.Errorf("backup interval cannot be negative")
+	}
+
+	if s.BackupTTL < 0 {
+		return fmt.Errorf("backup TTL cannot be negative")
+	}
+
+	return nil
+}
+
+// validateHTTPURL checks that a string is an absolute http(s) URL
+func validateHTTPURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("must use http or https scheme")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("must have a host")
+	}
+	return nil
+}
+
+// DiffServerSettings returns the settings that differ between old and new,
+// in field declaration order
+func DiffServerSettings(old, new *ServerSettings) []ServerSettingChange {
+	changes := make([]ServerSettingChange, 0)
+	if old == nil || new == nil {
+		return changes
+	}
+
+	oldValue := reflect.ValueOf(*old)
+	newValue := reflect.ValueOf(*new)
+	settingsType := oldValue.Type()
+
+	for i := 0; i < settingsType.NumField(); i++ {
+		before := oldValue.Field(i).Interface()
+		after := newValue.Field(i).Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+
+		// Treat nil and empty lists as equal
+		if oldValue.Field(i).Kind() == reflect.Slice && oldValue.Field(i).Len() == 0 && newValue.Field(i).Len() == 0 {
+			continue
+		}
+
+		changes = append(changes, ServerSettingChange{
+			Field:    settingsType.Field(i).Tag.Get("json"),
+			OldValue: before,
+			NewValue: after,
+		})
+	}
+
+	return changes
+}
+
+// GetServerSettings retrieves the server-wide settings
+func (c *Client) GetServerSettings() (*ServerSettings, error) {
+	settings, err := c.GraphQL.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch server settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SetServerSettings validates updated and sends the fields that differ from
+// current to the server. It returns the settings as stored by the server.
+func (c *Client) SetServerSettings(current, updated *ServerSettings) (*ServerSettings, error) {
+	if current == nil || updated == nil {
+		return nil, fmt.Errorf("current and updated settings are required")
+	}
+
+	if err := updated.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid server settings: %w", err)
+	}
+
+	changes := DiffServerSettings(current, updated)
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	input := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		input[change.Field] = change.NewValue
+	}
+
+	settings, err := c.GraphQL.SetSettings(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update server settings: %w", err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,188 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validServerSettings() *ServerSettings {
+	return &ServerSettings{
+		IP:                      "0.0.0.0",
+		Port:                    4567,
+		AuthMode:                AuthModeNone,
+		DownloadsPath:           "/data/downloads",
+		GlobalUpdateInterval:    12,
+		MaxSourcesInParallel:    6,
+		ExtensionRepos:          []string{"https://example.com/index.min.json"},
+		FlareSolverrURL:         "http://localhost:8191",
+		FlareSolverrTimeout:     60,
+		FlareSolverrSessionName: "suwayomi",
+		FlareSolverrSessionTTL:  15,
+		SocksProxyVersion:       5,
+		BackupTime:              "00:00",
+		BackupInterval:          1,
+		BackupTTL:               14,
+	}
+}
+
+func TestServerSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(s *ServerSettings)
+		wantErr bool
+	}{
+		{"valid settings", func(s *ServerSettings) {}, false},
+		{"invalid port", func(s *ServerSettings) { s.Port = 70000 }, true},
+		{"basic auth without credentials", func(s *ServerSettings) { s.AuthMode = AuthModeBasicAuth }, true},
+		{"basic auth with credentials", func(s *ServerSettings) {
+			s.AuthMode = AuthModeBasicAuth
+			s.AuthUsername = "user"
+			s.AuthPassword = "pass"
+		}, false},
+		{"unknown auth mode", func(s *ServerSettings) { s.AuthMode = "TOKEN" }, true},
+		{"empty downloads path", func(s *ServerSettings) { s.DownloadsPath = " " }, true},
+		{"update interval too short", func(s *ServerSettings) { s.GlobalUpdateInterval = 2 }, true},
+		{"update interval disabled", func(s *ServerSettings) { s.GlobalUpdateInterval = 0 }, false},
+		{"invalid extension repo", func(s *ServerSettings) { s.ExtensionRepos = []string{"not a url"} }, true},
+		{"flaresolverr without url", func(s *ServerSettings) {
+			s.FlareSolverrEnabled = true
+			s.FlareSolverrURL = ""
+		}, true},
+		{"socks proxy bad version", func(s *ServerSettings) {
+			s.SocksProxyEnabled = true
+			s.SocksProxyHost = "localhost"
+			s.SocksProxyPort = "1080"
+			s.SocksProxyVersion = 3
+		}, true},
+		{"bad backup time", func(s *ServerSettings) { s.BackupTime = "25:99" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validServerSettings()
+			tt.modify(s)
+			err := s.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiffServerSettings(t *testing.T) {
+	old := validServerSettings()
+	updated := old.Clone()
+
+	assert.Empty(t, DiffServerSettings(old, updated))
+
+	updated.DownloadAsCbz = true
+	updated.GlobalUpdateInterval = 24
+	updated.ExtensionRepos = append(updated.ExtensionRepos, "https://example.org/index.min.json")
+
+	changes := DiffServerSettings(old, updated)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "downloadAsCbz", changes[0].Field)
+	assert.Equal(t, false, changes[0].OldValue)
+	assert.Equal(t, true, changes[0].NewValue)
+	assert.Equal(t, "globalUpdateInterval", changes[1].Field)
+	assert.Equal(t, "extensionRepos", changes[2].Field)
+
+	// Clone must not share the repository slice
+	assert.Len(t, old.ExtensionRepos, 1)
+}
+
+func TestClient_GetServerSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Contains(t, req.Query, "settings")
+
+		mockResponse := GraphQLResponse{
+			Data: json.RawMessage(`{
+				"settings": {
+					"port": 4567,
+					"authMode": "NONE",
+					"downloadsPath": "/data/downloads",
+					"autoDownloadNewChapters": true,
+					"globalUpdateInterval": 12.0,
+					"extensionRepos": ["https://example.com/index.min.json"],
+					"flareSolverrUrl": "http://localhost:8191"
+				}
+			}`),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	settings, err := client.GetServerSettings()
+
+	require.NoError(t, err)
+	assert.Equal(t, 4567, settings.Port)
+	assert.Equal(t, "/data/downloads", settings.DownloadsPath)
+	assert.True(t, settings.AutoDownloadNewChapters)
+	assert.Equal(t, 12.0, settings.GlobalUpdateInterval)
+	assert.Equal(t, "http://localhost:8191", settings.FlareSolverrURL)
+	assert.Len(t, settings.ExtensionRepos, 1)
+}
+
+func TestClient_SetServerSettings(t *testing.T) {
+	t.Run("sends only changed fields", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			var req GraphQLRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Contains(t, req.Query, "setSettings")
+
+			input := req.Variables["input"].(map[string]interface{})
+			settings := input["settings"].(map[string]interface{})
+			assert.Len(t, settings, 1)
+			assert.Equal(t, true, settings["downloadAsCbz"])
+
+			mockResponse := GraphQLResponse{
+				Data: json.RawMessage(`{"setSettings": {"settings": {"port": 4567, "downloadAsCbz": true}}}`),
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(mockResponse)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		current := validServerSettings()
+		updated := current.Clone()
+		updated.DownloadAsCbz = true
+
+		result, err := client.SetServerSettings(current, updated)
+		require.NoError(t, err)
+		assert.True(t, result.DownloadAsCbz)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("no changes skips request", func(t *testing.T) {
+		client := NewClient("http://localhost:1")
+		current := validServerSettings()
+
+		result, err := client.SetServerSettings(current, current.Clone())
+		require.NoError(t, err)
+		assert.Equal(t, current, result)
+	})
+
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		client := NewClient("http://localhost:1")
+		current := validServerSettings()
+		updated := current.Clone()
+		updated.Port = 0
+
+		_, err := client.SetServerSettings(current, updated)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,478 @@
+package suwayomitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/codegen"
+)
+
+// resolver computes a field value from its arguments
+type resolver func(args map[string]interface{}) (interface{}, error)
+
+// graphQLError is an error reported in the response's errors list
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// execution holds the state of a single GraphQL request
+type execution struct {
+	variables map[string]interface{}
+	fragments map[string]*codegen.Fragment
+}
+
+// parseOperation parses a request and picks the operation to run
+func parseOperation(query, operationName string) (*codegen.Document, *codegen.Operation, error) {
+	doc, err := codegen.Parse("request", query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, op := range doc.Operations {
+		if operationName == "" || op.Name == operationName {
+			return doc, op, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unknown operation %q", operationName)
+}
+
+// execute runs an operation against the root object and returns the data
+func (s *Server) execute(doc *codegen.Document, op *codegen.Operation, variables map[string]interface{}) (interface{}, error) {
+	ex := &execution{
+		variables: variables,
+		fragments: make(map[string]*codegen.Fragment, len(doc.Fragments)),
+	}
+	for _, frag := range doc.Fragments {
+		ex.fragments[frag.Name] = frag
+	}
+
+	var root map[string]interface{}
+	switch op.Type {
+	case "query":
+		root = s.queryRoot()
+	case "mutation":
+		root = s.mutationRoot()
+	default:
+		return nil, fmt.Errorf("%s operations are not supported", op.Type)
+	}
+
+	return ex.object(root, op.SelectionSet)
+}
+
+// object resolves a selection set against an object
+func (ex *execution) object(obj map[string]interface{}, selections []codegen.Selection) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *codegen.Field:
+			value, err := ex.field(obj, sel)
+			if err != nil {
+				return nil, err
+			}
+			out[sel.ResponseKey()] = value
+
+		case *codegen.InlineFragment:
+			if err := ex.spread(obj, sel.TypeCondition, sel.SelectionSet, out); err != nil {
+				return nil, err
+			}
+
+		case *codegen.FragmentSpread:
+			frag, ok := ex.fragments[sel.Name]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q", sel.Name)
+			}
+			if err := ex.spread(obj, frag.TypeCondition, frag.SelectionSet, out); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// spread merges a fragment's fields when the type condition matches
+func (ex *execution) spread(obj map[string]interface{}, typeCondition string, selections []codegen.Selection, out map[string]interface{}) error {
+	if typeCondition != "" && typeCondition != obj["__typename"] {
+		return nil
+	}
+	fields, err := ex.object(obj, selections)
+	if err != nil {
+		return err
+	}
+	for k, v := range fields {
+		out[k] = v
+	}
+	return nil
+}
+
+// field resolves a single field, calling resolvers with evaluated arguments
+func (ex *execution) field(obj map[string]interface{}, field *codegen.Field) (interface{}, error) {
+	if field.Name == "__typename" {
+		return obj["__typename"], nil
+	}
+
+	// Library nodes are typed and reject unknown fields; untyped objects,
+	// such as the introspection schema, omit empty fields
+	value, ok := obj[field.Name]
+	if typeName, typed := obj["__typename"].(string); !ok && typed {
+		return nil, fmt.Errorf("cannot query field %q on type %q", field.Name, typeName)
+	}
+
+	if resolve, ok := value.(resolver); ok {
+		args := make(map[string]interface{}, len(field.Arguments))
+		for _, arg := range field.Arguments {
+			v, err := ex.value(arg.Value)
+			if err != nil {
+				return nil, err
+			}
+			args[arg.Name] = v
+		}
+
+		var err error
+		if value, err = resolve(args); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(field.SelectionSet) == 0 {
+		return value, nil
+	}
+	return ex.selectValue(value, field.SelectionSet)
+}
+
+// selectValue applies a selection set to an object or a list of objects
+func (ex *execution) selectValue(value interface{}, selections []codegen.Selection) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return ex.object(v, selections)
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			selected, err := ex.selectValue(item, selections)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, selected)
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+// value evaluates an argument literal, substituting variables
+func (ex *execution) value(v *codegen.Value) (interface{}, error) {
+	switch v.Kind {
+	case codegen.ValueVariable:
+		return ex.variables[v.Raw], nil
+	case codegen.ValueInt, codegen.ValueFloat:
+		return strconv.ParseFloat(v.Raw, 64)
+	case codegen.ValueString:
+		if strings.HasPrefix(v.Raw, `"""`) {
+			return strings.TrimSuffix(strings.TrimPrefix(v.Raw, `"""`), `"""`), nil
+		}
+		return strconv.Unquote(v.Raw)
+	case codegen.ValueBoolean:
+		return v.Raw == "true", nil
+	case codegen.ValueNull:
+		return nil, nil
+	case codegen.ValueEnum:
+		return v.Raw, nil
+	case codegen.ValueList:
+		items := make([]interface{}, 0, len(v.List))
+		for _, item := range v.List {
+			value, err := ex.value(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case codegen.ValueObject:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			value, err := ex.value(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			obj[f.Name] = value
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unsupported value kind %d", v.Kind)
+}
+
+// queryRoot returns the Query type with resolvers over the library
+func (s *Server) queryRoot() map[string]interface{} {
+	lib := s.library
+
+	return map[string]interface{}{
+		"__typename": "Query",
+
+		"mangas": resolver(func(args map[string]interface{}) (interface{}, error) {
+			condition := objectArg(args, "condition")
+			nodes := []interface{}{}
+			for _, m := range lib.sortedManga() {
+				if inLibrary, ok := condition["inLibrary"].(bool); ok && m.InLibrary != inLibrary {
+					continue
+				}
+				nodes = append(nodes, lib.mangaNode(m))
+			}
+			total := len(nodes)
+
+			offset := intArg(args, "offset", 0)
+			if offset > len(nodes) {
+				offset = len(nodes)
+			}
+			nodes = nodes[offset:]
+			if first := intArg(args, "first", -1); first >= 0 && first < len(nodes) {
+				nodes = nodes[:first]
+			}
+			return map[string]interface{}{"__typename": "MangaNodeList", "totalCount": total, "nodes": nodes}, nil
+		}),
+
+		"manga": resolver(func(args map[string]interface{}) (interface{}, error) {
+			id := intArg(args, "id", 0)
+			m, ok := lib.manga[id]
+			if !ok {
+				return nil, fmt.Errorf("manga %d not found", id)
+			}
+			return lib.mangaNode(m), nil
+		}),
+
+		"chapters": resolver(func(args map[string]interface{}) (interface{}, error) {
+			condition := objectArg(args, "condition")
+			nodes := []interface{}{}
+			if mangaID, ok := condition["mangaId"]; ok {
+				if m, ok := lib.manga[toInt(mangaID)]; ok {
+					for _, c := range m.Chapters {
+						nodes = append(nodes, chapterNode(c))
+					}
+				}
+			} else {
+				for _, m := range lib.sortedManga() {
+					for _, c := range m.Chapters {
+						nodes = append(nodes, chapterNode(c))
+					}
+				}
+			}
+			return map[string]interface{}{"__typename": "ChapterNodeList", "totalCount": len(nodes), "nodes": nodes}, nil
+		}),
+
+		"extensions": resolver(func(args map[string]interface{}) (interface{}, error) {
+			nodes := []interface{}{}
+			for _, e := range lib.sortedExtensions() {
+				nodes = append(nodes, extensionNode(e))
+			}
+			return map[string]interface{}{"__typename": "ExtensionNodeList", "totalCount": len(nodes), "nodes": nodes}, nil
+		}),
+
+		"extension": resolver(func(args map[string]interface{}) (interface{}, error) {
+			pkgName, _ := args["pkgName"].(string)
+			e, ok := lib.extensions[pkgName]
+			if !ok {
+				return nil, fmt.Errorf("extension %q not found", pkgName)
+			}
+			return extensionNode(e), nil
+		}),
+
+		"source": resolver(func(args map[string]interface{}) (interface{}, error) {
+			id := fmt.Sprint(args["id"])
+			src := lib.source(id)
+			if src == nil {
+				return nil, fmt.Errorf("source %s not found", id)
+			}
+			return sourceNode(src), nil
+		}),
+
+		"settings": resolver(func(args map[string]interface{}) (interface{}, error) {
+			return settingsNode(lib.settings), nil
+		}),
+
+		"__schema": resolver(func(args map[string]interface{}) (interface{}, error) {
+			if s.schema == nil {
+				return nil, fmt.Errorf("introspection is not enabled on this server")
+			}
+			return s.schema, nil
+		}),
+	}
+}
+
+// mutationRoot returns the Mutation type with resolvers that change the library.
+// Inputs are accepted both with a patch object, as in the schema, and with
+// the fields given directly, as the client sends them.
+func (s *Server) mutationRoot() map[string]interface{} {
+	lib := s.library
+
+	return map[string]interface{}{
+		"__typename": "Mutation",
+
+		"updateChapter": resolver(func(args map[string]interface{}) (interface{}, error) {
+			input, patch := inputArgs(args)
+			id := toInt(input["id"])
+			c, ok := lib.chapters[id]
+			if !ok {
+				return nil, fmt.Errorf("chapter %d not found", id)
+			}
+			if v, ok := patch["isRead"].(bool); ok {
+				c.IsRead = v
+			}
+			if v, ok := patch["isBookmarked"].(bool); ok {
+				c.IsBookmarked = v
+			}
+			return map[string]interface{}{"__typename": "UpdateChapterPayload", "chapter": chapterNode(c)}, nil
+		}),
+
+		"updateManga": resolver(func(args map[string]interface{}) (interface{}, error) {
+			input, patch := inputArgs(args)
+			id := toInt(input["id"])
+			m, ok := lib.manga[id]
+			if !ok {
+				return nil, fmt.Errorf("manga %d not found", id)
+			}
+			if v, ok := patch["inLibrary"].(bool); ok {
+				m.InLibrary = v
+			}
+			return map[string]interface{}{"__typename": "UpdateMangaPayload", "manga": lib.mangaNode(m)}, nil
+		}),
+
+		"installExternalExtension": resolver(func(args map[string]interface{}) (interface{}, error) {
+			return s.changeExtension(args, func(e *Extension) { e.IsInstalled = true })
+		}),
+
+		"updateExtension": resolver(func(args map[string]interface{}) (interface{}, error) {
+			_, patch := inputArgs(args)
+			return s.changeExtension(args, func(e *Extension) {
+				if v, _ := patch["install"].(bool); v {
+					e.IsInstalled = true
+				}
+				if v, _ := patch["uninstall"].(bool); v {
+					e.IsInstalled = false
+					e.HasUpdate = false
+				}
+				if v, _ := patch["update"].(bool); v {
+					e.HasUpdate = false
+				}
+			})
+		}),
+
+		"fetchChapterPages": resolver(func(args map[string]interface{}) (interface{}, error) {
+			input, _ := inputArgs(args)
+			id := toInt(input["chapterId"])
+			c, ok := lib.chapters[id]
+			if !ok {
+				return nil, fmt.Errorf("chapter %d not found", id)
+			}
+			pages := make([]interface{}, 0, c.PageCount)
+			for i := 0; i < c.PageCount; i++ {
+				pages = append(pages, fmt.Sprintf("/api/v1/manga/%d/chapter/%d/page/%d", c.MangaID, c.ID, i))
+			}
+			return map[string]interface{}{
+				"__typename": "FetchChapterPagesPayload",
+				"chapter":    chapterNode(c),
+				"pages":      pages,
+			}, nil
+		}),
+
+		"updateSourcePreference": resolver(func(args map[string]interface{}) (interface{}, error) {
+			input, _ := inputArgs(args)
+			id := fmt.Sprint(input["source"])
+			src := lib.source(id)
+			if src == nil {
+				return nil, fmt.Errorf("source %s not found", id)
+			}
+			change := objectArg(input, "change")
+			position := toInt(change["position"])
+			if position < 0 || position >= len(src.Preferences) {
+				return nil, fmt.Errorf("preference position %d out of range", position)
+			}
+			for _, key := range []string{"switchState", "checkBoxState", "listState", "editTextState", "multiSelectState"} {
+				if v, ok := change[key]; ok {
+					src.Preferences[position].CurrentValue = v
+				}
+			}
+			node := sourceNode(src)
+			return map[string]interface{}{
+				"__typename":  "UpdateSourcePreferencePayload",
+				"source":      node,
+				"preferences": node["preferences"],
+			}, nil
+		}),
+
+		"setSettings": resolver(func(args map[string]interface{}) (interface{}, error) {
+			input, _ := inputArgs(args)
+			for k, v := range objectArg(input, "settings") {
+				lib.settings[k] = v
+			}
+			return map[string]interface{}{"__typename": "SetSettingsPayload", "settings": settingsNode(lib.settings)}, nil
+		}),
+	}
+}
+
+// changeExtension applies a change to the extension named by the input
+func (s *Server) changeExtension(args map[string]interface{}, change func(*Extension)) (interface{}, error) {
+	input, _ := inputArgs(args)
+	pkgName, _ := input["pkgName"].(string)
+	if pkgName == "" {
+		pkgName, _ = input["id"].(string)
+	}
+	e, ok := s.library.extensions[pkgName]
+	if !ok {
+		return nil, fmt.Errorf("extension %q not found", pkgName)
+	}
+	change(e)
+	return map[string]interface{}{"__typename": "ExtensionPayload", "extension": extensionNode(e)}, nil
+}
+
+func settingsNode(settings map[string]interface{}) map[string]interface{} {
+	node := map[string]interface{}{"__typename": "SettingsType"}
+	for k, v := range settings {
+		node[k] = v
+	}
+	return node
+}
+
+// inputArgs returns the input argument and its patch object, which is the
+// input itself when no patch is given
+func inputArgs(args map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	input := objectArg(args, "input")
+	if patch, ok := input["patch"].(map[string]interface{}); ok {
+		return input, patch
+	}
+	return input, input
+}
+
+func objectArg(args map[string]interface{}, name string) map[string]interface{} {
+	obj, _ := args[name].(map[string]interface{})
+	if obj == nil {
+		return map[string]interface{}{}
+	}
+	return obj
+}
+
+func intArg(args map[string]interface{}, name string, fallback int) int {
+	v, ok := args[name]
+	if !ok || v == nil {
+		return fallback
+	}
+	return toInt(v)
+}
+
+// toInt converts a JSON number, literal or numeric string to an int
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
@@ -0,0 +1,345 @@
+package suwayomitest
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Manga is a library entry served by the fake server
+type Manga struct {
+	ID           int
+	Title        string
+	ThumbnailURL string
+	InLibrary    bool
+	SourceID     string // ID of a seeded Source, optional
+	Chapters     []*Chapter
+}
+
+// Chapter is a chapter of a seeded manga. Pages holds the image data served
+// for each page; when empty, PageCount generated images are served instead.
+type Chapter struct {
+	ID            int
+	MangaID       int
+	Name          string
+	ChapterNumber float64
+	UploadDate    time.Time
+	IsRead        bool
+	IsBookmarked  bool
+	IsDownloaded  bool
+	PageCount     int
+	Scanlator     string
+	RealURL       string
+	Pages         [][]byte
+}
+
+// Extension is an extension available from the fake server
+type Extension struct {
+	PkgName     string
+	Name        string
+	VersionName string
+	Lang        string
+	IconURL     string
+	IsInstalled bool
+	HasUpdate   bool
+	IsObsolete  bool
+	IsNSFW      bool
+	Sources     []*Source
+}
+
+// Source is a manga source provided by an extension
+type Source struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Lang        string
+	IconURL     string
+	IsNSFW      bool
+	Preferences []*Preference
+}
+
+// Preference types, matching the members of the schema's Preference union
+const (
+	SwitchPreference          = "SwitchPreference"
+	CheckBoxPreference        = "CheckBoxPreference"
+	ListPreference            = "ListPreference"
+	MultiSelectListPreference = "MultiSelectListPreference"
+	EditTextPreference        = "EditTextPreference"
+)
+
+// Preference is a source setting. CurrentValue and Default hold a bool,
+// string or []string depending on Type.
+type Preference struct {
+	Type          string
+	Key           string
+	Title         string
+	Summary       string
+	DialogTitle   string
+	DialogMessage string
+	Entries       []string
+	EntryValues   []string
+	CurrentValue  interface{}
+	Default       interface{}
+}
+
+// library is the in-memory state behind the fake server; callers hold the
+// server's lock
+type library struct {
+	manga         map[int]*Manga
+	chapters      map[int]*Chapter
+	extensions    map[string]*Extension
+	settings      map[string]interface{}
+	nextMangaID   int
+	nextChapterID int
+}
+
+func newLibrary() *library {
+	return &library{
+		manga:         make(map[int]*Manga),
+		chapters:      make(map[int]*Chapter),
+		extensions:    make(map[string]*Extension),
+		settings:      defaultSettings(),
+		nextMangaID:   1,
+		nextChapterID: 1,
+	}
+}
+
+// defaultSettings returns the server settings a fresh server reports
+func defaultSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"ip":                             "0.0.0.0",
+		"port":                           4567,
+		"authMode":                       "NONE",
+		"authUsername":                   "",
+		"authPassword":                   "",
+		"downloadsPath":                  "/downloads",
+		"downloadAsCbz":                  false,
+		"autoDownloadNewChapters":        false,
+		"autoDownloadNewChaptersLimit":   0,
+		"autoDownloadIgnoreReUploads":    false,
+		"updateMangas":                   false,
+		"globalUpdateInterval":           12.0,
+		"excludeCompleted":               true,
+		"excludeNotStarted":              false,
+		"excludeUnreadChapters":          false,
+		"excludeEntryWithUnreadChapters": false,
+		"maxSourcesInParallel":           6,
+		"extensionRepos":                 []interface{}{},
+		"localSourcePath":                "/local",
+		"flareSolverrEnabled":            false,
+		"flareSolverrUrl":                "http://localhost:8191",
+		"flareSolverrTimeout":            60,
+		"flareSolverrSessionName":        "suwayomi",
+		"flareSolverrSessionTtl":         15,
+		"flareSolverrAsResponseFallback": false,
+		"socksProxyEnabled":              false,
+		"socksProxyVersion":              5,
+		"socksProxyHost":                 "",
+		"socksProxyPort":                 "",
+		"socksProxyUsername":             "",
+		"socksProxyPassword":             "",
+		"backupPath":                     "/backups",
+		"backupTime":                     "00:00",
+		"backupInterval":                 1,
+		"backupTTL":                      14,
+		"debugLogsEnabled":               false,
+	}
+}
+
+// addManga stores a manga and its chapters, assigning missing IDs
+func (l *library) addManga(m *Manga) {
+	if m.ID == 0 {
+		m.ID = l.nextMangaID
+	}
+	if m.ID >= l.nextMangaID {
+		l.nextMangaID = m.ID + 1
+	}
+	l.manga[m.ID] = m
+
+	chapters := m.Chapters
+	m.Chapters = nil
+	for _, c := range chapters {
+		l.addChapter(m, c)
+	}
+}
+
+// addChapter stores a chapter under a manga, assigning a missing ID
+func (l *library) addChapter(m *Manga, c *Chapter) {
+	if c.ID == 0 {
+		c.ID = l.nextChapterID
+	}
+	if c.ID >= l.nextChapterID {
+		l.nextChapterID = c.ID + 1
+	}
+	if c.PageCount == 0 {
+		c.PageCount = len(c.Pages)
+	}
+	c.MangaID = m.ID
+	l.chapters[c.ID] = c
+	m.Chapters = append(m.Chapters, c)
+}
+
+// sortedManga returns the manga in ID order
+func (l *library) sortedManga() []*Manga {
+	result := make([]*Manga, 0, len(l.manga))
+	for _, m := range l.manga {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// sortedExtensions returns the extensions in package name order
+func (l *library) sortedExtensions() []*Extension {
+	result := make([]*Extension, 0, len(l.extensions))
+	for _, e := range l.extensions {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PkgName < result[j].PkgName })
+	return result
+}
+
+// source finds a source by ID across all extensions
+func (l *library) source(id string) *Source {
+	for _, ext := range l.extensions {
+		for _, s := range ext.Sources {
+			if s.ID == id {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// Nodes below are the GraphQL representations of library entries. They
+// include every field the client selects; the executor picks the selected ones.
+
+func (l *library) mangaNode(m *Manga) map[string]interface{} {
+	unread, downloaded := 0, 0
+	var latest *Chapter
+	chapters := make([]interface{}, 0, len(m.Chapters))
+	for _, c := range m.Chapters {
+		if !c.IsRead {
+			unread++
+		}
+		if c.IsDownloaded {
+			downloaded++
+		}
+		if latest == nil || c.UploadDate.After(latest.UploadDate) {
+			latest = c
+		}
+		chapters = append(chapters, chapterNode(c))
+	}
+
+	node := map[string]interface{}{
+		"__typename":    "MangaType",
+		"id":            m.ID,
+		"title":         m.Title,
+		"thumbnailUrl":  m.ThumbnailURL,
+		"inLibrary":     m.InLibrary,
+		"unreadCount":   unread,
+		"downloadCount": downloaded,
+		"chapters": map[string]interface{}{
+			"__typename": "ChapterNodeList",
+			"totalCount": len(m.Chapters),
+			"nodes":      chapters,
+		},
+		"latestUploadedChapter": nil,
+		"source":                nil,
+	}
+	if latest != nil {
+		node["latestUploadedChapter"] = chapterNode(latest)
+	}
+	if s := l.source(m.SourceID); s != nil {
+		node["source"] = sourceNode(s)
+	}
+	return node
+}
+
+func chapterNode(c *Chapter) map[string]interface{} {
+	return map[string]interface{}{
+		"__typename":    "ChapterType",
+		"id":            c.ID,
+		"mangaId":       c.MangaID,
+		"name":          c.Name,
+		"chapterNumber": c.ChapterNumber,
+		"uploadDate":    strconv.FormatInt(c.UploadDate.UnixMilli(), 10),
+		"isRead":        c.IsRead,
+		"isBookmarked":  c.IsBookmarked,
+		"isDownloaded":  c.IsDownloaded,
+		"pageCount":     c.PageCount,
+		"scanlator":     nullable(c.Scanlator),
+		"realUrl":       nullable(c.RealURL),
+	}
+}
+
+func extensionNode(e *Extension) map[string]interface{} {
+	sources := make([]interface{}, 0, len(e.Sources))
+	for _, s := range e.Sources {
+		sources = append(sources, sourceNode(s))
+	}
+	return map[string]interface{}{
+		"__typename":  "ExtensionType",
+		"pkgName":     e.PkgName,
+		"name":        e.Name,
+		"versionName": e.VersionName,
+		"lang":        e.Lang,
+		"iconUrl":     e.IconURL,
+		"isInstalled": e.IsInstalled,
+		"hasUpdate":   e.HasUpdate,
+		"isObsolete":  e.IsObsolete,
+		"isNsfw":      e.IsNSFW,
+		"source": map[string]interface{}{
+			"__typename": "SourceNodeList",
+			"totalCount": len(sources),
+			"nodes":      sources,
+		},
+	}
+}
+
+func sourceNode(s *Source) map[string]interface{} {
+	displayName := s.DisplayName
+	if displayName == "" {
+		displayName = s.Name
+	}
+	prefs := make([]interface{}, 0, len(s.Preferences))
+	for _, p := range s.Preferences {
+		prefs = append(prefs, preferenceNode(p))
+	}
+	return map[string]interface{}{
+		"__typename":     "SourceType",
+		"id":             s.ID,
+		"name":           s.Name,
+		"displayName":    displayName,
+		"lang":           s.Lang,
+		"iconUrl":        s.IconURL,
+		"isNsfw":         s.IsNSFW,
+		"isConfigurable": len(s.Preferences) > 0,
+		"preferences":    prefs,
+	}
+}
+
+func preferenceNode(p *Preference) map[string]interface{} {
+	return map[string]interface{}{
+		"__typename":    p.Type,
+		"key":           p.Key,
+		"title":         p.Title,
+		"summary":       p.Summary,
+		"enabled":       true,
+		"visible":       true,
+		"dialogTitle":   p.DialogTitle,
+		"dialogMessage": p.DialogMessage,
+		"entries":       p.Entries,
+		"entryValues":   p.EntryValues,
+		"currentValue":  p.CurrentValue,
+		"default":       p.Default,
+	}
+}
+
+// nullable maps empty strings to null, as the server does for optional fields
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
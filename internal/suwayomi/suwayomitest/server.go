@@ -0,0 +1,425 @@
+// Package suwayomitest provides an in-process fake Suwayomi server for tests.
+//
+// The server implements the GraphQL operations used by the client (library,
+// chapters, extensions, source preferences, settings and their mutations) and
+// the REST endpoints for server info, chapters and page images. It is backed
+// by an in-memory library that tests seed, and supports injected latency and
+// errors so retry and offline behaviour can be exercised without a real server.
+package suwayomitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+)
+
+// Server is a fake Suwayomi server
+type Server struct {
+	URL string
+
+	httpServer *httptest.Server
+	version    string
+	schema     map[string]interface{}
+
+	mu       sync.Mutex
+	library  *library
+	faults   []*Fault
+	requests map[string]int
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithVersion sets the version reported by the about endpoint
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// WithSchema enables introspection, answering __schema queries with the
+// given schema so capability detection can run against the fake server
+func WithSchema(schema *suwayomi.IntrospectionResult) Option {
+	return func(s *Server) {
+		data, err := json.Marshal(schema.Schema)
+		if err != nil {
+			return
+		}
+		var node map[string]interface{}
+		if err := json.Unmarshal(data, &node); err == nil {
+			s.schema = node
+		}
+	}
+}
+
+// NewServer starts a fake server with an empty library. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		version:  "v2.1.1867",
+		library:  newLibrary(),
+		requests: make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/graphql", s.handleGraphQL)
+	mux.HandleFunc("GET /api/v1/settings/about", s.handleAbout)
+	mux.HandleFunc("GET /api/v1/manga/{mangaId}/thumbnail", s.handleThumbnail)
+	mux.HandleFunc("GET /api/v1/manga/{mangaId}/chapter/{chapterId}", s.handleChapter)
+	mux.HandleFunc("GET /api/v1/manga/{mangaId}/chapter/{chapterId}/page/{index}", s.handlePage)
+
+	s.httpServer = httptest.NewServer(mux)
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client returns a client for the server with short retry delays
+func (s *Server) Client() *suwayomi.Client {
+	config := suwayomi.DefaultTransportConfig()
+	config.BaseDelay = time.Millisecond
+	config.MaxDelay = 10 * time.Millisecond
+	return suwayomi.NewClientWithConfig(s.URL, config)
+}
+
+// Fault makes matching requests slow or fail
+type Fault struct {
+	Match   string        // GraphQL operation name or REST path prefix; empty matches every request
+	Latency time.Duration // Delay before the request is handled
+	Status  int           // HTTP status returned instead of handling the request
+	Error   string        // GraphQL error message returned instead of data
+	Times   int           // Number of requests affected, 0 until cleared
+}
+
+// InjectFault adds a fault. Faults apply in the order they were added.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns how many requests were received for a GraphQL operation
+// name or a REST path such as "/api/v1/settings/about"
+func (s *Server) Requests(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[key]
+}
+
+// fault records a request and returns the fault affecting it, if any
+func (s *Server) fault(key string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[key]++
+	for i, f := range s.faults {
+		if f.Match != "" && f.Match != key && !strings.HasPrefix(key, f.Match) {
+			continue
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// applyFault delays the request and writes a failure status. It reports
+// whether the response has been written.
+func applyFault(w http.ResponseWriter, f *Fault) bool {
+	if f == nil {
+		return false
+	}
+	if f.Latency > 0 {
+		time.Sleep(f.Latency)
+	}
+	if f.Status != 0 {
+		http.Error(w, http.StatusText(f.Status), f.Status)
+		return true
+	}
+	return false
+}
+
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req suwayomi.GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	doc, op, err := parseOperation(req.Query, req.OperationName)
+	if err != nil {
+		writeGraphQL(w, nil, err.Error())
+		return
+	}
+
+	f := s.fault(op.Name)
+	if applyFault(w, f) {
+		return
+	}
+	if f != nil && f.Error != "" {
+		writeGraphQL(w, nil, f.Error)
+		return
+	}
+
+	s.mu.Lock()
+	data, err := s.execute(doc, op, req.Variables)
+	s.mu.Unlock()
+
+	if err != nil {
+		writeGraphQL(w, nil, err.Error())
+		return
+	}
+	writeGraphQL(w, data, "")
+}
+
+func writeGraphQL(w http.ResponseWriter, data interface{}, errMessage string) {
+	resp := map[string]interface{}{"data": data}
+	if errMessage != "" {
+		resp["errors"] = []graphQLError{{Message: errMessage}}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handleAbout(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, s.fault(r.URL.Path)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suwayomi.AboutResponse{
+		Name:      "Suwayomi-Server",
+		Version:   s.version,
+		Revision:  "r0000",
+		BuildType: "Stable",
+		BuildTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+	})
+}
+
+func (s *Server) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, s.fault(r.URL.Path)) {
+		return
+	}
+
+	s.mu.Lock()
+	_, ok := s.library.manga[toInt(r.PathValue("mangaId"))]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(generateImage(0))
+}
+
+// lookupChapter finds the chapter named in the request path
+func (s *Server) lookupChapter(r *http.Request) (*Chapter, bool) {
+	c, ok := s.library.chapters[toInt(r.PathValue("chapterId"))]
+	if !ok || strconv.Itoa(c.MangaID) != r.PathValue("mangaId") {
+		return nil, false
+	}
+	return c, true
+}
+
+func (s *Server) handleChapter(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, s.fault(r.URL.Path)) {
+		return
+	}
+
+	s.mu.Lock()
+	c, ok := s.lookupChapter(r)
+	var node map[string]interface{}
+	if ok {
+		node = chapterNode(c)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(node)
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, s.fault(r.URL.Path)) {
+		return
+	}
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		http.Error(w, "invalid page index", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	c, ok := s.lookupChapter(r)
+	var data []byte
+	if ok && index >= 0 && index < c.PageCount {
+		if index < len(c.Pages) {
+			data = c.Pages[index]
+		} else {
+			data = generateImage(index)
+		}
+	}
+	s.mu.Unlock()
+
+	if data == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
+
+// generateImage returns a small PNG whose colour depends on the page index
+func generateImage(index int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 12))
+	fill := color.RGBA{R: uint8(index * 40), G: uint8(255 - index*20), B: uint8(index * 10), A: 255}
+	for y := 0; y < 12; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// PageImage returns the image served for a generated page, for comparing
+// downloaded data in tests
+func PageImage(index int) []byte {
+	return generateImage(index)
+}
+
+// AddManga adds a manga and its chapters to the library. Missing IDs are
+// assigned; the manga must not be modified afterwards.
+func (s *Server) AddManga(m *Manga) *Manga {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.library.addManga(m)
+	return m
+}
+
+// AddChapter adds a chapter to a manga, e.g. to simulate a new release
+func (s *Server) AddChapter(mangaID int, c *Chapter) (*Chapter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.library.manga[mangaID]
+	if !ok {
+		return nil, fmt.Errorf("manga %d not found", mangaID)
+	}
+	s.library.addChapter(m, c)
+	return c, nil
+}
+
+// AddExtension adds an extension and its sources
+func (s *Server) AddExtension(e *Extension) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.library.extensions[e.PkgName] = e
+}
+
+// SeedLibrary adds mangaCount library manga, each with chapterCount chapters
+// of pageCount generated pages, and returns them
+func (s *Server) SeedLibrary(mangaCount, chapterCount, pageCount int) []*Manga {
+	released := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	result := make([]*Manga, 0, mangaCount)
+	for i := 1; i <= mangaCount; i++ {
+		m := &Manga{
+			Title:     fmt.Sprintf("Manga %d", i),
+			InLibrary: true,
+		}
+		for j := 1; j <= chapterCount; j++ {
+			m.Chapters = append(m.Chapters, &Chapter{
+				Name:          fmt.Sprintf("Chapter %d", j),
+				ChapterNumber: float64(j),
+				UploadDate:    released.AddDate(0, 0, 7*(j-1)),
+				PageCount:     pageCount,
+			})
+		}
+		result = append(result, s.AddManga(m))
+	}
+	return result
+}
+
+// Manga returns a snapshot of a manga, without its chapters
+func (s *Server) Manga(id int) (Manga, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.library.manga[id]
+	if !ok {
+		return Manga{}, false
+	}
+	snapshot := *m
+	snapshot.Chapters = nil
+	return snapshot, true
+}
+
+// Chapter returns a snapshot of a chapter
+func (s *Server) Chapter(id int) (Chapter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.library.chapters[id]
+	if !ok {
+		return Chapter{}, false
+	}
+	return *c, true
+}
+
+// Extension returns a snapshot of an extension, without its sources
+func (s *Server) Extension(pkgName string) (Extension, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.library.extensions[pkgName]
+	if !ok {
+		return Extension{}, false
+	}
+	snapshot := *e
+	snapshot.Sources = nil
+	return snapshot, true
+}
+
+// Setting returns a server setting
+func (s *Server) Setting(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.library.settings[key]
+}
@@ -0,0 +1,204 @@
+package suwayomitest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Library(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.SeedLibrary(3, 2, 4)
+	server.AddManga(&Manga{Title: "Not In Library"})
+	client := server.Client()
+
+	resp, err := client.GraphQL.GetMangaList(true, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Mangas.TotalCount)
+	require.Len(t, resp.Mangas.Nodes, 2)
+	assert.Equal(t, "Manga 1", resp.Mangas.Nodes[0].Title)
+	assert.Equal(t, 2, resp.Mangas.Nodes[0].Chapters.TotalCount)
+	assert.Equal(t, 2, resp.Mangas.Nodes[0].UnreadCount)
+	require.NotNil(t, resp.Mangas.Nodes[0].LatestUploadedChapter)
+	assert.Equal(t, "Chapter 2", resp.Mangas.Nodes[0].LatestUploadedChapter.Name)
+
+	manga, err := client.GraphQL.GetMangaDetails(4)
+	require.NoError(t, err)
+	assert.Equal(t, "Not In Library", manga.Title)
+	assert.False(t, manga.InLibrary)
+
+	_, err = client.GraphQL.GetMangaDetails(99)
+	assert.ErrorContains(t, err, "manga 99 not found")
+
+	chapters, err := client.GraphQL.GetChapterList(1)
+	require.NoError(t, err)
+	require.Len(t, chapters, 2)
+	assert.Equal(t, 2.0, chapters[0].ChapterNumber)
+	assert.Equal(t, 4, chapters[0].PageCount)
+	assert.Empty(t, chapters[0].Scanlator)
+
+	_, err = server.AddChapter(4, &Chapter{Name: "Oneshot", ChapterNumber: 1, Scanlator: "Example Scans", RealURL: "https://example.com/c/1"})
+	require.NoError(t, err)
+	chapters, err = client.GraphQL.GetChapterList(4)
+	require.NoError(t, err)
+	require.Len(t, chapters, 1)
+	assert.Equal(t, "Example Scans", chapters[0].Scanlator)
+	assert.Equal(t, "https://example.com/c/1", chapters[0].RealURL)
+}
+
+func TestServer_Mutations(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	manga := server.SeedLibrary(1, 1, 1)[0]
+	client := server.Client()
+
+	read := true
+	require.NoError(t, client.GraphQL.UpdateChapter(manga.Chapters[0].ID, &read, nil))
+	chapter, _ := server.Chapter(manga.Chapters[0].ID)
+	assert.True(t, chapter.IsRead)
+	assert.False(t, chapter.IsBookmarked)
+
+	require.NoError(t, client.GraphQL.RemoveMangaFromLibrary(manga.ID))
+	snapshot, _ := server.Manga(manga.ID)
+	assert.False(t, snapshot.InLibrary)
+
+	_, err := client.GraphQL.SetSettings(map[string]interface{}{"downloadAsCbz": true})
+	require.NoError(t, err)
+	settings, err := client.GraphQL.GetSettings()
+	require.NoError(t, err)
+	assert.True(t, settings.DownloadAsCbz)
+	assert.Equal(t, 4567, settings.Port)
+}
+
+func TestServer_Extensions(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.AddExtension(&Extension{
+		PkgName:     "eu.kanade.tachiyomi.extension.en.example",
+		Name:        "Example",
+		VersionName: "1.4.2",
+		Lang:        "en",
+		HasUpdate:   true,
+		IsInstalled: true,
+		Sources: []*Source{{
+			ID:   "1234567890123",
+			Name: "Example",
+			Lang: "en",
+			Preferences: []*Preference{{
+				Type:         SwitchPreference,
+				Key:          "hd",
+				Title:        "HD images",
+				CurrentValue: false,
+				Default:      false,
+			}},
+		}},
+	})
+	client := server.Client()
+
+	extensions, err := client.ListAvailableExtensions()
+	require.NoError(t, err)
+	require.Len(t, extensions, 1)
+	assert.True(t, extensions[0].HasUpdate)
+
+	require.NoError(t, client.UpdateExtension("eu.kanade.tachiyomi.extension.en.example"))
+	ext, _ := server.Extension("eu.kanade.tachiyomi.extension.en.example")
+	assert.False(t, ext.HasUpdate)
+
+	require.NoError(t, client.UninstallExtension("eu.kanade.tachiyomi.extension.en.example"))
+	ext, _ = server.Extension("eu.kanade.tachiyomi.extension.en.example")
+	assert.False(t, ext.IsInstalled)
+	require.NoError(t, client.InstallExtension("eu.kanade.tachiyomi.extension.en.example"))
+	ext, _ = server.Extension("eu.kanade.tachiyomi.extension.en.example")
+	assert.True(t, ext.IsInstalled)
+
+	sources, err := client.GraphQL.GetExtensionSourceList("eu.kanade.tachiyomi.extension.en.example")
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.True(t, sources[0].IsConfigurable)
+
+	prefs, err := client.GraphQL.UpdateSourcePreference("1234567890123", map[string]interface{}{
+		"position":    0,
+		"switchState": true,
+	})
+	require.NoError(t, err)
+	require.Len(t, prefs, 1)
+	assert.Equal(t, "SwitchPreference", prefs[0].Typename)
+	require.NotNil(t, prefs[0].SwitchValue)
+	assert.True(t, *prefs[0].SwitchValue)
+}
+
+func TestServer_Pages(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	manga := server.SeedLibrary(1, 1, 3)[0]
+	src := source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client())
+
+	chapters, err := src.ListChapters("1")
+	require.NoError(t, err)
+	require.Len(t, chapters, 1)
+
+	pages, err := src.GetAllPages(chapters[0])
+	require.NoError(t, err)
+	require.Len(t, pages, 3)
+	assert.Equal(t, PageImage(2), pages[2].ImageData)
+	assert.Equal(t, "image/png", pages[2].ImageType)
+	assert.Equal(t, 1, server.Requests("FetchChapterPages"))
+
+	_, err = src.GetPage(chapters[0], 3)
+	assert.Error(t, err)
+	assert.True(t, src.IsAvailable())
+	assert.Equal(t, strconv.Itoa(manga.ID), chapters[0].MangaID)
+}
+
+func TestServer_Faults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.SeedLibrary(1, 0, 0)
+	client := server.Client()
+
+	// Transient failures are retried by the client
+	server.InjectFault(Fault{Match: "GetMangaList", Status: http.StatusServiceUnavailable, Times: 2})
+	_, err := client.GraphQL.GetMangaList(true, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, server.Requests("GetMangaList"))
+
+	server.InjectFault(Fault{Match: "GetMangaList", Error: "source is down", Times: 1})
+	_, err = client.GraphQL.GetMangaList(true, 10, 0)
+	assert.ErrorContains(t, err, "source is down")
+
+	server.InjectFault(Fault{Match: "/api/v1/settings/about", Latency: 50 * time.Millisecond})
+	start := time.Now()
+	assert.True(t, client.Ping())
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	server.ClearFaults()
+	server.InjectFault(Fault{Status: http.StatusInternalServerError})
+	_, err = client.HealthCheck()
+	assert.Error(t, err)
+}
+
+func TestServer_Introspection(t *testing.T) {
+	schema, err := suwayomi.LoadSchemaFromFile("../../../schema/suwayomi_schema.json")
+	require.NoError(t, err)
+
+	server := NewServer(WithSchema(schema))
+	defer server.Close()
+
+	client := server.Client()
+	caps, err := client.DetectCapabilities()
+	require.NoError(t, err)
+	assert.Empty(t, caps.Disabled())
+	assert.Contains(t, caps.ServerInfo, "v2.1.1867")
+}
@@ -0,0 +1,463 @@
+package suwayomi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while its
+// circuit breaker is open after repeated failures
+var ErrCircuitOpen = errors.New("server unavailable: circuit breaker open")
+
+// StatusError is returned when the server answers with an unexpected HTTP status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// TransportConfig tunes retries, the circuit breaker and the connection pool
+type TransportConfig struct {
+	// Retries for idempotent requests
+	MaxRetries int
+	BaseDelay  time.Duration // First backoff delay, doubled on each retry
+	MaxDelay   time.Duration // Upper bound for a single backoff delay
+
+	// Circuit breaker
+	FailureThreshold int           // Consecutive failures that open the breaker
+	OpenTimeout      time.Duration // Time before a trial request is let through
+
+	// Availability cache
+	AvailabilityTTL time.Duration
+
+	// Connection pool
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	RequestTimeout        time.Duration // Overall limit including retries
+}
+
+// DefaultTransportConfig returns the transport settings used by NewClient
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxRetries:            2,
+		BaseDelay:             200 * time.Millisecond,
+		MaxDelay:              2 * time.Second,
+		FailureThreshold:      5,
+		OpenTimeout:           30 * time.Second,
+		AvailabilityTTL:       30 * time.Second,
+		MaxIdleConns:          32,
+		MaxIdleConnsPerHost:   8,
+		MaxConnsPerHost:       16,
+		IdleConnTimeout:       90 * time.Second,
+		DialTimeout:           5 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 8 * time.Second,
+		RequestTimeout:        10 * time.Second,
+	}
+}
+
+// NewHTTPClient builds an HTTP client using the resilient transport
+func NewHTTPClient(config TransportConfig) *http.Client {
+	return &http.Client{
+		Transport: newResilientTransport(config),
+		Timeout:   config.RequestTimeout,
+	}
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent marks a request context as safe to retry even when the
+// method is not idempotent, such as a POST carrying a GraphQL query
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent reports whether a request may be sent more than once
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// resilientTransport retries idempotent requests and guards each server
+// with a circuit breaker
+type resilientTransport struct {
+	base   http.RoundTripper
+	config TransportConfig
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func newResilientTransport(config TransportConfig) *resilientTransport {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &resilientTransport{
+		base: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          config.MaxIdleConns,
+			MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+			MaxConnsPerHost:       config.MaxConnsPerHost,
+			IdleConnTimeout:       config.IdleConnTimeout,
+			TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+			ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		config: config,
+		sleep:  sleepContext,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *resilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := breakerFor(req.URL.Host, t.config)
+
+	attempts := 1
+	if isIdempotent(req) && (req.Body == nil || req.GetBody != nil) {
+		attempts += t.config.MaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if !breaker.Allow() {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, fmt.Errorf("%s: %w", req.URL.Host, ErrCircuitOpen)
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			breaker.RecordSuccess()
+			return resp, nil
+		}
+
+		if err != nil {
+			// A cancelled request says nothing about the server
+			if req.Context().Err() != nil {
+				breaker.Release()
+				return nil, err
+			}
+			breaker.RecordFailure()
+			lastErr = err
+		} else {
+			if resp.StatusCode >= 500 {
+				breaker.RecordFailure()
+			} else {
+				// A rate limited server is up, but a trial request that
+				// was turned away proves nothing either way
+				breaker.Release()
+			}
+			if !retryableStatus(resp.StatusCode) || attempt == attempts-1 {
+				return resp, nil
+			}
+			lastErr = fmt.Errorf("server returned status %d", resp.StatusCode)
+		}
+
+		if attempt == attempts-1 {
+			break
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, t.config.MaxDelay)
+			}
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// backoff returns the jittered delay before retry number attempt+1
+func (t *resilientTransport) backoff(attempt int) time.Duration {
+	delay := t.config.BaseDelay << attempt
+	if delay <= 0 || delay > t.config.MaxDelay {
+		delay = t.config.MaxDelay
+	}
+	// Full jitter over the upper half keeps clients from retrying in lockstep
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Requests flow normally
+	BreakerOpen                         // Requests fail fast
+	BreakerHalfOpen                     // A single trial request is allowed
+)
+
+// String returns the state name
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops requests to a server after repeated failures and
+// lets a trial request through once the open timeout has passed
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool // A half-open trial request is in flight
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(threshold int, openTimeout time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         time.Now,
+	}
+}
+
+// Allow reports whether a request may be sent
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	}
+	return true
+}
+
+// RecordSuccess closes the breaker
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// RecordFailure counts a failure, opening the breaker at the threshold or
+// when a half-open trial request fails
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.probing || b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// Release ends a half-open trial request without counting its outcome,
+// letting the next request try again
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// currentState moves an expired open breaker to half-open; callers hold mu
+func (b *CircuitBreaker) currentState() BreakerState {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		b.state = BreakerHalfOpen
+	}
+	return b.state
+}
+
+// breakerKey identifies a shared circuit breaker. Clients configured with
+// different thresholds get breakers of their own.
+type breakerKey struct {
+	host        string
+	threshold   int
+	openTimeout time.Duration
+}
+
+// breakers holds one circuit breaker per server and breaker settings so
+// every client talking to the same host the same way shares its state
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[breakerKey]*CircuitBreaker)
+)
+
+func breakerFor(host string, config TransportConfig) *CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	key := breakerKey{host: host, threshold: config.FailureThreshold, openTimeout: config.OpenTimeout}
+	b, ok := breakers[key]
+	if !ok {
+		b = NewCircuitBreaker(config.FailureThreshold, config.OpenTimeout)
+		breakers[key] = b
+	}
+	return b
+}
+
+// availability caches whether the server answered recently
+type availability struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	available  bool
+	checkedAt  time.Time
+	refreshing bool
+}
+
+// Breaker returns the circuit breaker guarding this client's server
+func (c *Client) Breaker() *CircuitBreaker {
+	host := c.BaseURL
+	if u, err := url.Parse(c.BaseURL); err == nil {
+		host = u.Host
+	}
+	return breakerFor(host, c.transportConfig)
+}
+
+// IsAvailable reports whether the server is reachable without blocking on
+// the network more than once per TTL. An open circuit breaker reports the
+// server as unavailable immediately; a stale result is returned while a
+// background check refreshes it.
+func (c *Client) IsAvailable() bool {
+	if c.BaseURL == "" {
+		return false
+	}
+	if c.Breaker().State() == BreakerOpen {
+		return false
+	}
+
+	a := &c.availability
+	a.mu.Lock()
+	if a.checkedAt.IsZero() {
+		a.mu.Unlock()
+		return c.refreshAvailability()
+	}
+	available := a.available
+	if time.Since(a.checkedAt) >= a.ttl && !a.refreshing {
+		a.refreshing = true
+		go c.refreshAvailability()
+	}
+	a.mu.Unlock()
+
+	return available
+}
+
+// InvalidateAvailability forces the next IsAvailable call to contact the server
+func (c *Client) InvalidateAvailability() {
+	c.availability.mu.Lock()
+	c.availability.checkedAt = time.Time{}
+	c.availability.mu.Unlock()
+}
+
+// refreshAvailability pings the server and stores the result
+func (c *Client) refreshAvailability() bool {
+	available := c.Ping()
+
+	a := &c.availability
+	a.mu.Lock()
+	a.available = available
+	a.checkedAt = time.Now()
+	a.refreshing = false
+	a.mu.Unlock()
+
+	return available
+}
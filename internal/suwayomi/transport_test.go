@@ -0,0 +1,227 @@
+package suwayomi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastTransportConfig keeps retry delays short for tests
+func fastTransportConfig() TransportConfig {
+	config := DefaultTransportConfig()
+	config.BaseDelay = time.Millisecond
+	config.MaxDelay = 5 * time.Millisecond
+	return config
+}
+
+func TestTransport_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"mangas": {"nodes": []}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(server.URL, fastTransportConfig())
+	_, err := client.GraphQL.GetMangaList(true, 10, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestTransport_DoesNotRetryMutations(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(server.URL, fastTransportConfig())
+	err := client.GraphQL.Mutate(`mutation { updateManga(input: {id: 1}) { clientMutationId } }`, nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestTransport_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(server.URL, fastTransportConfig())
+	assert.False(t, client.Ping())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestTransport_CircuitOpensAfterFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	config := fastTransportConfig()
+	config.MaxRetries = 0
+	config.FailureThreshold = 3
+	client := NewClientWithConfig(server.URL, config)
+
+	for i := 0; i < 3; i++ {
+		client.Ping()
+	}
+	assert.Equal(t, BreakerOpen, client.Breaker().State())
+
+	// Further requests fail fast without reaching the server
+	_, err := client.HealthCheck()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.False(t, client.IsAvailable())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.RecordFailure()
+	assert.Equal(t, BreakerClosed, breaker.State())
+	breaker.RecordFailure()
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.False(t, breaker.Allow())
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.True(t, breaker.Allow(), "one trial request is allowed")
+	assert.False(t, breaker.Allow(), "only one trial request at a time")
+
+	// A failed trial reopens the breaker
+	breaker.RecordFailure()
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.Allow())
+	breaker.RecordSuccess()
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.True(t, breaker.Allow())
+}
+
+// halfOpenBreaker returns the breaker of a server's host with its open
+// timeout already passed, so the next request is a trial request
+func halfOpenBreaker(t *testing.T, serverURL string, config TransportConfig) *CircuitBreaker {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, serverURL, nil)
+	require.NoError(t, err)
+
+	breaker := breakerFor(req.URL.Host, config)
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	for i := 0; i < config.FailureThreshold; i++ {
+		breaker.RecordFailure()
+	}
+	now = now.Add(config.OpenTimeout)
+	require.Equal(t, BreakerHalfOpen, breaker.State())
+	return breaker
+}
+
+func TestTransport_HalfOpenTrialEndings(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		cancel bool
+		want   BreakerState
+	}{
+		{name: "cancelled trial", status: http.StatusOK, cancel: true, want: BreakerHalfOpen},
+		{name: "rate limited trial", status: http.StatusTooManyRequests, want: BreakerHalfOpen},
+		{name: "failed trial", status: http.StatusBadGateway, want: BreakerOpen},
+		{name: "successful trial", status: http.StatusOK, want: BreakerClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.cancel {
+					cancel()
+					<-r.Context().Done()
+					return
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			config := fastTransportConfig()
+			config.MaxRetries = 0
+			config.FailureThreshold = 1
+			breaker := halfOpenBreaker(t, server.URL, config)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			resp, err := newResilientTransport(config).RoundTrip(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			assert.Equal(t, tt.want, breaker.State())
+			if tt.want == BreakerHalfOpen {
+				assert.True(t, breaker.Allow(), "a trial request that proved nothing lets the next one through")
+			}
+		})
+	}
+}
+
+func TestBreakerFor_SeparatesConfigs(t *testing.T) {
+	config := fastTransportConfig()
+	other := config
+	other.FailureThreshold = config.FailureThreshold + 1
+
+	shared := breakerFor("breaker-test:4567", config)
+	assert.Same(t, shared, breakerFor("breaker-test:4567", config), "clients with the same settings share a breaker")
+	assert.NotSame(t, shared, breakerFor("breaker-test:4567", other), "other settings are not ignored")
+	assert.NotSame(t, shared, breakerFor("breaker-test:4568", config))
+}
+
+func TestClient_IsAvailableCachesResult(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"name": "Suwayomi"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(server.URL, fastTransportConfig())
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, client.IsAvailable())
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	client.InvalidateAvailability()
+	assert.True(t, client.IsAvailable())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := retryAfter(resp)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "3")
+	delay, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+}
@@ -2,21 +2,29 @@ package tui
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/Justice-Caban/Miryokusha/internal/config"
 	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/export"
 	"github.com/Justice-Caban/Miryokusha/internal/server"
 	"github.com/Justice-Caban/Miryokusha/internal/source"
 	"github.com/Justice-Caban/Miryokusha/internal/storage"
 	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/calendar"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/categories"
 	tuiDownloads "github.com/Justice-Caban/Miryokusha/internal/tui/downloads"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/extensions"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/history"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/library"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/manga"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/notifications"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/reader"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/settings"
+	tuiUpdates "github.com/Justice-Caban/Miryokusha/internal/tui/updates"
+	"github.com/Justice-Caban/Miryokusha/internal/updates"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -25,16 +33,19 @@ import (
 type ViewType string
 
 const (
-	ViewHome       ViewType = "home"
-	ViewLibrary    ViewType = "library"
-	ViewManga      ViewType = "manga"
-	ViewReader     ViewType = "reader"
-	ViewHistory    ViewType = "history"
-	ViewBrowse     ViewType = "browse"
-	ViewDownloads  ViewType = "downloads"
-	ViewExtensions ViewType = "extensions"
-	ViewSettings   ViewType = "settings"
-	ViewCategories ViewType = "categories"
+	ViewHome          ViewType = "home"
+	ViewLibrary       ViewType = "library"
+	ViewManga         ViewType = "manga"
+	ViewReader        ViewType = "reader"
+	ViewHistory       ViewType = "history"
+	ViewBrowse        ViewType = "browse"
+	ViewDownloads     ViewType = "downloads"
+	ViewExtensions    ViewType = "extensions"
+	ViewSettings      ViewType = "settings"
+	ViewCategories    ViewType = "categories"
+	ViewUpdates       ViewType = "updates"
+	ViewNotifications ViewType = "notifications"
+	ViewCalendar      ViewType = "calendar"
 )
 
 // AppModel is the root model for the entire TUI application
@@ -47,22 +58,39 @@ type AppModel struct {
 	// Error notifications
 	errors ErrorNotificationList
 
+	// Notification center
+	notifyFeed          *notificationFeed // Notifications from background work
+	unreadNotifications int
+	serverChecked       bool // Whether the server's availability is known
+	serverAvailable     bool
+
 	// Dependencies
 	config          *config.Config
 	sourceManager   *source.SourceManager
 	storage         *storage.Storage
 	downloadManager *downloads.Manager
+	exporter        *export.Exporter
 	serverManager   *server.Manager
+	updater         *updates.Updater
+
+	// Library updates
+	updateEvents  chan tea.Msg        // Progress and results from the updater
+	lastTask      *updates.UpdateTask // Last manga checked by the running update
+	updateStatus  string
+	unreadUpdates int // Unread chapters in the Updates feed
 
 	// View models
-	libraryModel     library.Model
-	mangaModel       *manga.Model
-	historyModel     history.Model
-	extensionsModel  extensions.Model
-	downloadsModel   tuiDownloads.Model
-	settingsModel    settings.Model
-	categoriesModel  categories.Model
-	readerModel      *reader.Model
+	libraryModel       library.Model
+	mangaModel         *manga.Model
+	historyModel       history.Model
+	extensionsModel    extensions.Model
+	downloadsModel     tuiDownloads.Model
+	settingsModel      settings.Model
+	categoriesModel    categories.Model
+	updatesModel       tuiUpdates.Model
+	notificationsModel notifications.Model
+	calendarModel      calendar.Model
+	readerModel        *reader.Model
 
 	// Suwayomi client
 	suwayomiClient *suwayomi.Client
@@ -98,6 +126,14 @@ func NewAppModel() AppModel {
 		st = nil
 	}
 
+	// Keep notifications in the notification center, starting with the
+	// problems found so far
+	if st != nil {
+		errors.SetStore(st.Notifications)
+	}
+	notifyFeed := newNotificationFeed(st, cfg.Notifications, &errors)
+	notifyFeed.hooks = newHookDispatcher(cfg, st, &errors)
+
 	// Initialize source manager
 	sm := source.NewSourceManager()
 
@@ -107,10 +143,11 @@ func NewAppModel() AppModel {
 		suwayomiClient = suwayomi.NewClient(defaultServer.URL)
 
 		// Create Suwayomi source and add to manager
-		suwayomiSource := source.NewSuwayomiSource(
+		// The source shares the client so detected capabilities apply to both
+		suwayomiSource := source.NewSuwayomiSourceWithClient(
 			"suwayomi-default",
 			defaultServer.Name,
-			defaultServer.URL,
+			suwayomiClient,
 		)
 		sm.AddSource(suwayomiSource)
 	} else {
@@ -133,11 +170,85 @@ func NewAppModel() AppModel {
 
 	// Initialize download manager
 	downloadConfig := downloads.DefaultDownloadConfig()
+	if cfg.Paths.Downloads != "" {
+		downloadConfig.DownloadPath = cfg.Paths.Downloads
+	}
+	if cfg.Downloads.Format != "" {
+		downloadConfig.OutputFormat = downloads.OutputFormat(cfg.Downloads.Format)
+	}
+	if cfg.Downloads.PageConcurrency > 0 {
+		downloadConfig.PageConcurrency = cfg.Downloads.PageConcurrency
+	}
+	if cfg.Downloads.MaxConnectionsPerServer > 0 {
+		downloadConfig.MaxConnectionsPerServer = cfg.Downloads.MaxConnectionsPerServer
+	}
+	downloadConfig.BandwidthLimit = int64(cfg.Downloads.BandwidthLimitKB) * 1024
+	downloadConfig.ServerConnections = make(map[string]int)
+	for _, server := range cfg.Servers {
+		if server.MaxConnections <= 0 {
+			continue
+		}
+		if u, err := url.Parse(server.URL); err == nil && u.Host != "" {
+			downloadConfig.ServerConnections[u.Host] = server.MaxConnections
+		}
+	}
+	retention := cfg.Downloads.Retention
+	downloadConfig.AutoDeleteRead = retention.DeleteAfterRead
+	downloadConfig.KeepReadChapters = retention.KeepRead
+	downloadConfig.DeleteReadAfter = time.Duration(retention.DeleteReadAfterDays) * 24 * time.Hour
+	downloadConfig.DiskQuota = int64(retention.QuotaMB) * 1024 * 1024
+	downloadConfig.RetentionInterval = time.Duration(retention.IntervalHours) * time.Hour
+	downloadConfig.ConfirmRetention = !retention.AutoApply
+	downloadConfig.CategoryRetention = make(map[string]downloads.RetentionPolicy)
+	for name, policy := range retention.Categories {
+		downloadConfig.CategoryRetention[name] = downloads.RetentionPolicy{
+			DeleteAfterRead: policy.DeleteAfterRead,
+			KeepRead:        policy.KeepRead,
+			DeleteReadAfter: time.Duration(policy.DeleteReadAfterDays) * 24 * time.Hour,
+			Quota:           int64(policy.QuotaMB) * 1024 * 1024,
+		}
+	}
+	downloadProfile, err := cfg.ImageProcessing.Profile(cfg.ImageProcessing.DownloadProfile)
+	if err != nil {
+		errors.AddError(
+			"Image Profile Not Found",
+			fmt.Sprintf("Download image profile: %v", err),
+			"Downloads keep their original pages. Check image_processing in config.yaml.",
+			SeverityWarning,
+		)
+	}
+	downloadConfig.ImageProfile = downloadProfile
 	downloadMgr := downloads.NewManager(downloadConfig, sm)
-	downloadMgr.Start() // Auto-start the download manager
+	if st != nil {
+		// Persist the queue so downloads survive restarts
+		downloadMgr.SetStore(st.Downloads)
+		downloadMgr.SetLibrary(st.Progress, st.Categories)
+	}
+	notifyFeed.watchDownloads(downloadMgr)
+	downloadMgr.Start() // Auto-start the download manager, restoring interrupted downloads
+
+	// Initialize exporter
+	exporter := export.NewExporter(sm, cfg.Paths.Exports)
+	exportProfile, err := cfg.ImageProcessing.Profile(cfg.ImageProcessing.ExportProfile)
+	if err != nil {
+		errors.AddError(
+			"Image Profile Not Found",
+			fmt.Sprintf("Export image profile: %v", err),
+			"Exports keep their original pages. Check image_processing in config.yaml.",
+			SeverityWarning,
+		)
+	}
+	exporter.SetImageProfile(exportProfile)
+
+	// Initialize library updater, with automatic updates if enabled
+	updater, updateEvents := newUpdater(cfg, sm, st, downloadMgr, notifyFeed)
+	updater.Start()
 
 	// Initialize downloads model
 	dlModel := tuiDownloads.NewModel(downloadMgr)
+	updatesModel := tuiUpdates.NewModel(st, downloadMgr)
+	notificationsModel := notifications.NewModel(st)
+	calendarModel := calendar.NewModel(st)
 
 	// Initialize server manager if enabled
 	var serverMgr *server.Manager
@@ -149,6 +260,7 @@ func NewAppModel() AppModel {
 			MaxLogs:        1000,
 		}
 		serverMgr = server.NewManager(serverConfig)
+		notifyFeed.watchServer(serverMgr)
 
 		// Auto-start if configured
 		if cfg.ServerManagement.AutoStart {
@@ -170,26 +282,119 @@ func NewAppModel() AppModel {
 	categoriesModel := categories.NewModel(st)
 
 	return AppModel{
-		currentView:      ViewHome,
-		config:           cfg,
-		sourceManager:    sm,
-		storage:          st,
-		downloadManager:  downloadMgr,
-		serverManager:    serverMgr,
-		suwayomiClient:   suwayomiClient,
-		libraryModel:     libModel,
-		historyModel:     histModel,
-		extensionsModel:  extModel,
-		downloadsModel:   dlModel,
-		settingsModel:    settingsModel,
-		categoriesModel:  categoriesModel,
-		errors:           errors, // Collect all initialization errors
+		currentView:        ViewHome,
+		config:             cfg,
+		sourceManager:      sm,
+		storage:            st,
+		downloadManager:    downloadMgr,
+		exporter:           exporter,
+		serverManager:      serverMgr,
+		updater:            updater,
+		updateEvents:       updateEvents,
+		notifyFeed:         notifyFeed,
+		suwayomiClient:     suwayomiClient,
+		libraryModel:       libModel,
+		historyModel:       histModel,
+		extensionsModel:    extModel,
+		downloadsModel:     dlModel,
+		settingsModel:      settingsModel,
+		categoriesModel:    categoriesModel,
+		updatesModel:       updatesModel,
+		notificationsModel: notificationsModel,
+		calendarModel:      calendarModel,
+		errors:             errors, // Collect all initialization errors
 	}
 }
 
 // Init initializes the application
 func (m AppModel) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{
+		listenForUpdates(m.updateEvents),
+		countUnreadUpdates(m.storage),
+		listenForNotifications(m.notifyFeed.events),
+		countUnreadNotifications(m.storage),
+		pruneNotifications(m.storage, m.config.Notifications),
+	}
+	if m.suwayomiClient != nil {
+		cmds = append(cmds,
+			detectCapabilities(m.suwayomiClient),
+			checkServerStatus(m.suwayomiClient, 0),
+			checkExtensionUpdates(m.suwayomiClient, m.notifyFeed),
+		)
+	}
+	return tea.Batch(cmds...)
+}
+
+// detectCapabilities introspects the server so queries adapt to its version
+func detectCapabilities(client *suwayomi.Client) tea.Cmd {
+	return func() tea.Msg {
+		caps, err := client.DetectCapabilities()
+		return capabilitiesDetectedMsg{capabilities: caps, err: err}
+	}
+}
+
+// runRetention evaluates the download retention policies in the background
+func runRetention(manager *downloads.Manager) tea.Cmd {
+	return func() tea.Msg {
+		manager.RunRetention()
+		return nil
+	}
+}
+
+// openChapter launches the reader for a chapter known only by its IDs, as
+// stored in history and the Updates feed, loading its details from the source
+func (m AppModel) openChapter(mangaID, mangaTitle, chapterID string) (AppModel, tea.Cmd) {
+	var manga *source.Manga
+	var chapter *source.Chapter
+
+	// Get source for this manga
+	src := m.sourceManager.GetSource(mangaID)
+	if src == nil {
+		// Try to find a source by type (assume Suwayomi for now)
+		sources := m.sourceManager.GetSourcesByType(source.SourceTypeSuwayomi)
+		if len(sources) > 0 {
+			src = sources[0]
+		}
+	}
+
+	if src != nil {
+		// Fetch full manga details
+		fetchedManga, err := src.GetManga(mangaID)
+		if err == nil && fetchedManga != nil {
+			manga = fetchedManga
+		}
+
+		// Fetch chapters and find the specific one
+		chapters, err := src.ListChapters(mangaID)
+		if err == nil {
+			for _, ch := range chapters {
+				if ch.ID == chapterID {
+					chapter = ch
+					break
+				}
+			}
+		}
+	}
+
+	// Fallback to minimal objects if fetch failed
+	if manga == nil {
+		manga = &source.Manga{
+			ID:         mangaID,
+			Title:      mangaTitle, // Use cached title
+			SourceType: source.SourceTypeSuwayomi,
+		}
+	}
+	if chapter == nil {
+		chapter = &source.Chapter{
+			ID:      chapterID,
+			MangaID: mangaID,
+		}
+	}
+
+	readerModel := reader.NewModel(manga, chapter, m.sourceManager, m.storage)
+	m.readerModel = &readerModel
+	m.currentView = ViewReader
+	return m, m.readerModel.Init()
 }
 
 // navigateToView handles navigation to a specific view from home
@@ -219,6 +424,15 @@ func (m AppModel) navigateToView(view ViewType) (AppModel, tea.Cmd) {
 		m.settingsModel, cmd = m.settingsModel.Update(sizeMsg)
 	case ViewCategories:
 		m.categoriesModel, cmd = m.categoriesModel.Update(sizeMsg)
+	case ViewUpdates:
+		initCmd = m.updatesModel.Init()
+		m.updatesModel, cmd = m.updatesModel.Update(sizeMsg)
+	case ViewNotifications:
+		initCmd = m.notificationsModel.Init()
+		m.notificationsModel, cmd = m.notificationsModel.Update(sizeMsg)
+	case ViewCalendar:
+		initCmd = m.calendarModel.Init()
+		m.calendarModel, cmd = m.calendarModel.Update(sizeMsg)
 	}
 
 	// Batch the init command and size update command
@@ -230,9 +444,84 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case capabilitiesDetectedMsg:
+		// An unreachable server is reported by the status bar and health check
+		if msg.err == nil {
+			if disabled := msg.capabilities.Disabled(); len(disabled) > 0 {
+				names := make([]string, 0, len(disabled))
+				for _, feature := range disabled {
+					names = append(names, feature.Description)
+				}
+				m.errors.AddError(
+					"Limited Server Features",
+					fmt.Sprintf("This Suwayomi version does not support: %s", strings.Join(names, ", ")),
+					"Update the Suwayomi server to enable them. Details are in Settings → Server Health.",
+					SeverityInfo,
+				)
+			}
+		}
+		return m, nil
+
+	case updateProgressMsg:
+		m.lastTask = msg.task
+		return m, listenForUpdates(m.updateEvents)
+
+	case libraryUpdatedMsg:
+		m.lastTask = nil
+		return m, tea.Batch(listenForUpdates(m.updateEvents), countUnreadUpdates(m.storage))
+
+	case unreadUpdatesMsg:
+		m.unreadUpdates = msg.count
+		return m, nil
+
+	case notificationAddedMsg:
+		return m, tea.Batch(listenForNotifications(m.notifyFeed.events), countUnreadNotifications(m.storage))
+
+	case unreadNotificationsMsg:
+		m.unreadNotifications = msg.count
+		return m, nil
+
+	case serverStatusMsg:
+		return m.handleServerStatus(msg)
+
+	case notifications.ChangedMsg:
+		m.notificationsModel, cmd = m.notificationsModel.Update(msg)
+		return m, tea.Batch(cmd, countUnreadNotifications(m.storage))
+
+	case notifications.JumpMsg:
+		m, cmd = m.jumpTo(msg.Notification)
+		return m, tea.Batch(cmd, countUnreadNotifications(m.storage))
+
+	case tuiUpdates.ChangedMsg:
+		// Reading or dismissing updates changes the unread badge
+		m.updatesModel, cmd = m.updatesModel.Update(msg)
+		return m, tea.Batch(cmd, countUnreadUpdates(m.storage))
+
+	case libraryUpdateFailedMsg:
+		if isUpdateInProgress(msg.err) {
+			m.updateStatus = "A library update is already running"
+		} else {
+			m.updateStatus = fmt.Sprintf("Library update failed: %v", msg.err)
+		}
+		return m, nil
+
+	case reader.ChapterReadMsg:
+		m.notifyFeed.hooks.Fire(chapterCompletedEvent(msg))
+
+		// Retention policies and the unread updates depend on which
+		// chapters are read
+		return m, tea.Batch(runRetention(m.downloadManager), countUnreadUpdates(m.storage))
+
+	case categories.ScheduleChangedMsg, manga.ScheduleChangedMsg:
+		// Shorter schedules may need the scheduler to run more often
+		if m.updater != nil {
+			m.updater.RefreshSchedules()
+		}
+		return m, nil
+
 	case library.OpenMangaMsg:
 		// Open manga details view from library
-		mangaModel := manga.NewModel(msg.Manga, m.sourceManager, m.storage)
+		mangaModel := manga.NewModel(msg.Manga, m.sourceManager, m.storage, m.downloadManager, m.exporter)
 		m.mangaModel = &mangaModel
 		m.currentView = ViewManga
 		return m, m.mangaModel.Init()
@@ -252,61 +541,23 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.readerModel.Init()
 
 	case history.OpenChapterMsg:
-		// Launch reader from history by loading manga and chapter from source
-		// Try to fetch full details from source
-		var manga *source.Manga
-		var chapter *source.Chapter
-
-		// Get source for this manga
-		src := m.sourceManager.GetSource(msg.MangaID)
-		if src == nil {
-			// Try to find a source by type (assume Suwayomi for now)
-			sources := m.sourceManager.GetSourcesByType(source.SourceTypeSuwayomi)
-			if len(sources) > 0 {
-				src = sources[0]
-			}
-		}
+		// Launch reader from history
+		return m.openChapter(msg.MangaID, msg.MangaTitle, msg.ChapterID)
 
-		if src != nil {
-			// Fetch full manga details
-			fetchedManga, err := src.GetManga(msg.MangaID)
-			if err == nil && fetchedManga != nil {
-				manga = fetchedManga
-			}
+	case tuiUpdates.OpenChapterMsg:
+		// Launch reader from the Updates feed
+		return m.openChapter(msg.MangaID, msg.MangaTitle, msg.ChapterID)
 
-			// Fetch chapters and find the specific one
-			chapters, err := src.ListChapters(msg.MangaID)
-			if err == nil {
-				for _, ch := range chapters {
-					if ch.ID == msg.ChapterID {
-						chapter = ch
-						break
-					}
-				}
-			}
-		}
+	case calendar.OpenMangaMsg:
+		// Open manga details from the release calendar
+		return m.openManga(msg.MangaID, msg.MangaTitle)
 
-		// Fallback to minimal objects if fetch failed
-		if manga == nil {
-			manga = &source.Manga{
-				ID:         msg.MangaID,
-				Title:      msg.MangaTitle, // Use cached title from history
-				SourceType: source.SourceTypeSuwayomi,
-			}
-		}
-		if chapter == nil {
-			chapter = &source.Chapter{
-				ID:      msg.ChapterID,
-				MangaID: msg.MangaID,
-			}
+	case tea.KeyMsg:
+		// Let views with active inputs or nested screens handle their own keys
+		if msg.String() != "ctrl+c" && m.viewCapturesKeys() {
+			break
 		}
 
-		readerModel := reader.NewModel(manga, chapter, m.sourceManager, m.storage)
-		m.readerModel = &readerModel
-		m.currentView = ViewReader
-		return m, m.readerModel.Init()
-
-	case tea.KeyMsg:
 		// Handle global shortcuts
 		if m.currentView != ViewHome {
 			switch msg.String() {
@@ -343,10 +594,12 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.currentView == ViewReader && m.readerModel != nil {
 				m.readerModel.SaveSession()
 			}
+			m.shutdown()
 			return m, tea.Quit
 
 		case "q":
 			if m.currentView == ViewHome {
+				m.shutdown()
 				return m, tea.Quit
 			}
 			// Save reader session before going home
@@ -374,6 +627,23 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.navigateToView(ViewSettings)
 		case "8":
 			return m.navigateToView(ViewCategories)
+		case "9":
+			return m.navigateToView(ViewUpdates)
+
+		case "n":
+			if m.currentView == ViewHome {
+				return m.navigateToView(ViewNotifications)
+			}
+
+		case "c":
+			if m.currentView == ViewHome {
+				return m.navigateToView(ViewCalendar)
+			}
+
+		case "u":
+			if m.currentView == ViewHome {
+				return m.startLibraryUpdate()
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -409,12 +679,26 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ViewSettings:
 		m.settingsModel, cmd = m.settingsModel.Update(msg)
+		// Apply auto-update toggles as they change
+		m.syncUpdateSchedule()
 		return m, cmd
 
 	case ViewCategories:
 		m.categoriesModel, cmd = m.categoriesModel.Update(msg)
 		return m, cmd
 
+	case ViewUpdates:
+		m.updatesModel, cmd = m.updatesModel.Update(msg)
+		return m, cmd
+
+	case ViewNotifications:
+		m.notificationsModel, cmd = m.notificationsModel.Update(msg)
+		return m, cmd
+
+	case ViewCalendar:
+		m.calendarModel, cmd = m.calendarModel.Update(msg)
+		return m, cmd
+
 	case ViewReader:
 		if m.readerModel != nil {
 			updated, cmd := m.readerModel.Update(msg)
@@ -426,6 +710,29 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// shutdown stops background work before quitting. Hook deliveries that are
+// still queued are attempted before the program exits.
+func (m AppModel) shutdown() {
+	m.updater.Stop()
+	m.notifyFeed.hooks.Close()
+}
+
+// viewCapturesKeys reports whether the active view wants to handle
+// keys that would otherwise trigger global shortcuts
+func (m AppModel) viewCapturesKeys() bool {
+	switch m.currentView {
+	case ViewExtensions:
+		return m.extensionsModel.CapturesKeys()
+	case ViewSettings:
+		return m.settingsModel.CapturesKeys()
+	case ViewDownloads:
+		return m.downloadsModel.CapturesKeys()
+	case ViewManga:
+		return m.mangaModel != nil && m.mangaModel.CapturesKeys()
+	}
+	return false
+}
+
 // View renders the current view
 func (m AppModel) View() string {
 	if !m.ready {
@@ -464,6 +771,12 @@ func (m AppModel) View() string {
 		content = m.settingsModel.View()
 	case ViewCategories:
 		content = m.categoriesModel.View()
+	case ViewUpdates:
+		content = m.updatesModel.View()
+	case ViewNotifications:
+		content = m.notificationsModel.View()
+	case ViewCalendar:
+		content = m.calendarModel.View()
 	default:
 		content = m.renderHomeView()
 	}
@@ -497,6 +810,26 @@ func (m AppModel) renderHomeView() string {
 		components = append(components, errorSection)
 	}
 
+	if section := m.renderUpdateSection(); section != "" {
+		components = append(components, lipgloss.NewStyle().MarginTop(1).Render(section))
+	}
+
+	updatesItem := "9 - Updates"
+	if m.unreadUpdates > 0 {
+		updatesItem += " " + lipgloss.NewStyle().
+			Foreground(ColorAccent).
+			Bold(true).
+			Render(fmt.Sprintf("(%d new)", m.unreadUpdates))
+	}
+
+	notificationsItem := "n - Notifications"
+	if m.unreadNotifications > 0 {
+		notificationsItem += " " + lipgloss.NewStyle().
+			Foreground(ColorAccent).
+			Bold(true).
+			Render(fmt.Sprintf("(%d unread)", m.unreadNotifications))
+	}
+
 	menu := lipgloss.NewStyle().
 		MarginTop(2).
 		MarginBottom(2).
@@ -510,7 +843,11 @@ Navigation:
   6 - Extensions
   7 - Settings
   8 - Categories
+  ` + updatesItem + `
 
+  ` + notificationsItem + `
+  c - Release Calendar
+  u - Update library now
   q - Quit
 `)
 	components = append(components, menu)
@@ -570,10 +907,14 @@ func (m AppModel) renderStatusBar() string {
 
 	// Server status
 	serverStatus := "Server: "
-	if m.suwayomiClient != nil && m.suwayomiClient.Ping() {
+	if m.suwayomiClient != nil && m.suwayomiClient.IsAvailable() {
 		serverStatus += lipgloss.NewStyle().
 			Foreground(ColorSuccess).
 			Render("✓ Connected")
+	} else if m.suwayomiClient != nil && m.suwayomiClient.Breaker().State() == suwayomi.BreakerOpen {
+		serverStatus += lipgloss.NewStyle().
+			Foreground(ColorWarning).
+			Render("⚠ Unreachable")
 	} else {
 		serverStatus += lipgloss.NewStyle().
 			Foreground(ColorMuted).
@@ -594,13 +935,27 @@ func (m AppModel) renderStatusBar() string {
 			Render(fmt.Sprintf("  ⚠ %d Issue(s)", count))
 	}
 
+	// Unread notifications indicator
+	var notificationIndicator string
+	if m.unreadNotifications > 0 {
+		notificationIndicator = lipgloss.NewStyle().
+			Foreground(ColorAccent).
+			Render(fmt.Sprintf("  🔔 %d", m.unreadNotifications))
+	}
+
 	help := "Press ? for help"
 
-	return GetStatusBarText(viewName, serverStatus+errorIndicator, dimensions, help)
+	return GetStatusBarText(viewName, serverStatus+errorIndicator+notificationIndicator+m.renderUpdateIndicator(), dimensions, help)
 }
 
 // Messages
 
+// capabilitiesDetectedMsg is sent when server capability detection finishes
+type capabilitiesDetectedMsg struct {
+	capabilities *suwayomi.Capabilities
+	err          error
+}
+
 // OpenReaderMsg is sent when we want to open the reader
 type OpenReaderMsg struct {
 	Manga   *source.Manga
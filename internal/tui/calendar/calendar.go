@@ -0,0 +1,376 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Section groups the estimates of the calendar by when they are due
+type Section int
+
+const (
+	SectionOverdue Section = iota
+	SectionToday
+	SectionThisWeek
+	SectionLater
+)
+
+// String returns the header of the section
+func (s Section) String() string {
+	switch s {
+	case SectionOverdue:
+		return "Overdue"
+	case SectionToday:
+		return "Due today"
+	case SectionThisWeek:
+		return "This week"
+	default:
+		return "Later"
+	}
+}
+
+// Model represents the release calendar view model
+type Model struct {
+	width  int
+	height int
+
+	// Data
+	estimates []*storage.ReleaseEstimate // Ordered by section, then expected release
+	now       time.Time
+
+	// UI state
+	cursor int
+	offset int
+
+	// Dependencies
+	storage *storage.Storage
+
+	// Loading state
+	loading bool
+	err     error
+}
+
+// NewModel creates a new release calendar model
+func NewModel(st *storage.Storage) Model {
+	return Model{
+		storage: st,
+		loading: true,
+	}
+}
+
+// Init initializes the release calendar model
+func (m Model) Init() tea.Cmd {
+	return m.loadData
+}
+
+// Update handles messages for the release calendar view
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKeyPress(msg)
+
+	case estimatesLoadedMsg:
+		m.now = msg.now
+		m.estimates = orderBySection(msg.estimates, msg.now)
+		m.loading = false
+		m.err = nil
+		if m.cursor >= len(m.estimates) {
+			m.cursor = max(len(m.estimates)-1, 0)
+		}
+		m.adjustOffset()
+		return m, nil
+
+	case estimatesErrorMsg:
+		m.err = msg.err
+		m.loading = false
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleKeyPress handles keyboard input
+func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.adjustOffset()
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.estimates)-1 {
+			m.cursor++
+			m.adjustOffset()
+		}
+
+	case "g":
+		m.cursor = 0
+		m.offset = 0
+
+	case "G":
+		if len(m.estimates) > 0 {
+			m.cursor = len(m.estimates) - 1
+			m.adjustOffset()
+		}
+
+	case "r":
+		// Refresh data
+		m.loading = true
+		return m, m.loadData
+
+	case "enter":
+		// Open the manga's details
+		if m.cursor < len(m.estimates) {
+			e := m.estimates[m.cursor]
+			return m, func() tea.Msg {
+				return OpenMangaMsg{MangaID: e.MangaID, MangaTitle: e.MangaTitle}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// visibleItems returns the number of estimates that fit on screen, leaving
+// room for the header, footer and section headers
+func (m Model) visibleItems() int {
+	return max(m.height-16, 1)
+}
+
+// adjustOffset adjusts the scroll offset to keep cursor visible
+func (m *Model) adjustOffset() {
+	visible := m.visibleItems()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+// View renders the release calendar view
+func (m Model) View() string {
+	if m.loading {
+		return theme.CenteredText(m.width, m.height, "Loading release estimates...")
+	}
+
+	if m.err != nil {
+		return theme.CenteredText(m.width, m.height, fmt.Sprintf("Error: %v", m.err))
+	}
+
+	var b strings.Builder
+
+	// Header
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderCalendar())
+	b.WriteString("\n")
+
+	// Footer
+	b.WriteString(m.renderFooter())
+
+	// Apply consistent horizontal padding/centering
+	content := b.String()
+	maxWidth := 120
+	if m.width < maxWidth {
+		maxWidth = m.width - 4
+	}
+
+	contentStyle := lipgloss.NewStyle().
+		Width(maxWidth).
+		Padding(0, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Top,
+		contentStyle.Render(content),
+	)
+}
+
+// renderHeader renders the header with the number of releases per section
+func (m Model) renderHeader() string {
+	title := theme.TitleStyle.Render("Release Calendar")
+
+	counts := make(map[Section]int)
+	for _, e := range m.estimates {
+		counts[sectionOf(e, m.now)]++
+	}
+	info := lipgloss.NewStyle().
+		Foreground(theme.ColorMuted).
+		Render(fmt.Sprintf("%d overdue · %d due today · %d this week · %d later",
+			counts[SectionOverdue], counts[SectionToday], counts[SectionThisWeek], counts[SectionLater]))
+
+	return title + "\n" + info
+}
+
+// renderCalendar renders the estimates grouped by section
+func (m Model) renderCalendar() string {
+	if len(m.estimates) == 0 {
+		return theme.CenteredText(m.width, m.height-10, "No release estimates yet\n\nEstimates appear once library updates have seen a few releases of a series")
+	}
+
+	var b strings.Builder
+	visible := m.visibleItems()
+	lastSection := Section(-1)
+
+	for i, e := range m.estimates {
+		if i < m.offset || i >= m.offset+visible {
+			continue
+		}
+
+		// Section header, repeated at the top when scrolled into a section
+		if section := sectionOf(e, m.now); section != lastSection {
+			if lastSection >= 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(theme.SectionStyle.Render(section.String()))
+			b.WriteString("\n")
+			lastSection = section
+		}
+
+		b.WriteString(m.renderEstimate(e, i == m.cursor))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderEstimate renders one estimate line
+func (m Model) renderEstimate(e *storage.ReleaseEstimate, isCursor bool) string {
+	title := e.MangaTitle
+	if title == "" {
+		title = e.MangaID
+	}
+
+	when := fmt.Sprintf("%s ±%s", e.Expected.Local().Format("Mon Jan 2"), formatDays(e.Latest.Sub(e.Earliest)/2))
+	if e.IsOverdue(m.now) {
+		when = fmt.Sprintf("expected %s · %s late", e.Expected.Local().Format("Jan 2"), formatDays(m.now.Sub(e.Expected)))
+	}
+	details := fmt.Sprintf("every ~%s · %s confidence · last %s",
+		formatDays(e.Interval), e.Confidence, e.LastRelease.Local().Format("Jan 2"))
+
+	line := fmt.Sprintf("  %-32s %s  %s",
+		truncate(title, 32),
+		when,
+		lipgloss.NewStyle().Foreground(theme.ColorMuted).Render(details),
+	)
+
+	itemStyle := lipgloss.NewStyle()
+	if isCursor {
+		itemStyle = itemStyle.
+			Background(theme.ColorPrimary).
+			Foreground(lipgloss.Color("#000000")).
+			Bold(true).
+			Width(m.width - 4)
+	} else if e.IsOverdue(m.now) {
+		itemStyle = itemStyle.Foreground(theme.ColorWarning)
+	} else if e.Confidence == storage.ConfidenceLow {
+		itemStyle = itemStyle.Foreground(theme.ColorMuted)
+	}
+	return itemStyle.Render(line)
+}
+
+// renderFooter renders the footer with controls
+func (m Model) renderFooter() string {
+	controls := []string{
+		"↑↓/jk: navigate",
+		"Enter: open manga",
+		"r: refresh",
+		"Esc: back",
+	}
+	return theme.HelpStyle.Render(strings.Join(controls, " • "))
+}
+
+// sectionOf returns the section of an estimate at now. A release is
+// overdue once the end of its band has passed.
+func sectionOf(e *storage.ReleaseEstimate, now time.Time) Section {
+	local := now.Local()
+	tomorrow := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, time.Local)
+
+	switch {
+	case e.IsOverdue(now):
+		return SectionOverdue
+	case e.Expected.Before(tomorrow):
+		return SectionToday
+	case e.Expected.Before(tomorrow.AddDate(0, 0, 6)):
+		return SectionThisWeek
+	default:
+		return SectionLater
+	}
+}
+
+// orderBySection orders estimates by section, keeping the order by
+// expected release within each
+func orderBySection(estimates []*storage.ReleaseEstimate, now time.Time) []*storage.ReleaseEstimate {
+	ordered := make([]*storage.ReleaseEstimate, 0, len(estimates))
+	for section := SectionOverdue; section <= SectionLater; section++ {
+		for _, e := range estimates {
+			if sectionOf(e, now) == section {
+				ordered = append(ordered, e)
+			}
+		}
+	}
+	return ordered
+}
+
+// formatDays formats a duration in days, or hours below two days
+func formatDays(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Round(time.Hour).Hours()))
+	}
+	return fmt.Sprintf("%dd", int((d+12*time.Hour).Hours()/24))
+}
+
+// truncate shortens s to n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// Messages
+
+type estimatesLoadedMsg struct {
+	estimates []*storage.ReleaseEstimate
+	now       time.Time
+}
+
+type estimatesErrorMsg struct {
+	err error
+}
+
+// OpenMangaMsg is sent to open the details of a manga in the calendar
+type OpenMangaMsg struct {
+	MangaID    string
+	MangaTitle string
+}
+
+// Commands
+
+func (m Model) loadData() tea.Msg {
+	if m.storage == nil {
+		return estimatesErrorMsg{err: fmt.Errorf("storage not available")}
+	}
+
+	estimates, err := m.storage.UpdateTracking.GetReleaseEstimates()
+	if err != nil {
+		return estimatesErrorMsg{err: fmt.Errorf("failed to load release estimates: %w", err)}
+	}
+	return estimatesLoadedMsg{estimates: estimates, now: time.Now()}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Justice-Caban/Miryokusha/internal/storage"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	"github.com/Justice-Caban/Miryokusha/internal/updates"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -83,6 +84,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case categoryOperationMsg:
 		m.message = msg.message
 		m.messageType = msg.messageType
+		if msg.scheduleChanged {
+			return m, tea.Batch(m.loadCategories, func() tea.Msg { return ScheduleChangedMsg{} })
+		}
 		return m, m.loadCategories
 
 	case tea.KeyMsg:
@@ -153,6 +157,13 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 			return m, m.moveCategoryDown(m.cursor)
 		}
 
+	case "s", "S":
+		// Cycle how often the category's manga are checked for new chapters
+		if m.cursor < len(m.categories) {
+			cat := m.categories[m.cursor]
+			return m, m.setUpdateSchedule(cat, updates.NextSchedule(cat.UpdateSchedule))
+		}
+
 	case "r", "R":
 		// Refresh
 		m.message = ""
@@ -289,6 +300,9 @@ func (m Model) renderCategoryList() string {
 		} else {
 			line = fmt.Sprintf("  %s (%d)", cat.Name, cat.MangaCount)
 		}
+		if !cat.UpdateSchedule.IsDefault() {
+			line += fmt.Sprintf(" · updates %s", cat.UpdateSchedule)
+		}
 
 		// Apply style
 		if isCursor {
@@ -343,6 +357,7 @@ func (m Model) renderFooter() string {
 			"e: edit",
 			"d: delete",
 			"ctrl+↑↓: reorder",
+			"s: update schedule",
 			"r: refresh",
 			"Esc: back",
 		}
@@ -365,10 +380,14 @@ type categoriesLoadedMsg struct {
 }
 
 type categoryOperationMsg struct {
-	message     string
-	messageType string
+	message         string
+	messageType     string
+	scheduleChanged bool
 }
 
+// ScheduleChangedMsg is sent when the update schedule of a category changed
+type ScheduleChangedMsg struct{}
+
 // Commands
 
 func (m Model) loadCategories() tea.Msg {
@@ -489,3 +508,25 @@ func (m Model) moveCategoryDown(index int) tea.Cmd {
 		return categoryOperationMsg{message: "", messageType: ""}
 	}
 }
+
+func (m Model) setUpdateSchedule(cat *storage.Category, schedule storage.UpdateSchedule) tea.Cmd {
+	return func() tea.Msg {
+		if m.storage == nil {
+			return categoryOperationMsg{message: "Storage not available", messageType: "error"}
+		}
+
+		err := m.storage.Categories.SetUpdateSchedule(cat.ID, schedule)
+		if err != nil {
+			return categoryOperationMsg{message: fmt.Sprintf("Failed to set update schedule: %v", err), messageType: "error"}
+		}
+
+		message := fmt.Sprintf("'%s' is updated %s", cat.Name, schedule)
+		switch {
+		case schedule.IsDefault():
+			message = fmt.Sprintf("'%s' follows the library update settings", cat.Name)
+		case schedule.Mode == storage.UpdateModeNever:
+			message = fmt.Sprintf("'%s' is never updated", cat.Name)
+		}
+		return categoryOperationMsg{message: message, messageType: "success", scheduleChanged: true}
+	}
+}
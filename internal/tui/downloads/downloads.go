@@ -31,6 +31,15 @@ type Model struct {
 
 	// Refresh ticker
 	lastRefresh time.Time
+
+	// Verification sweep
+	verifying    bool
+	verifyStatus string
+
+	// Retention
+	pendingRetention *downloads.RetentionPlan // Automatic run awaiting review
+	retentionReview  *downloads.RetentionPlan // Dry-run report being reviewed
+	retentionStatus  string
 }
 
 // NewModel creates a new downloads model
@@ -67,6 +76,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if msg.stats != nil {
 			m.stats = msg.stats
 		}
+		m.pendingRetention = msg.pendingRetention
 		m.lastRefresh = time.Now()
 
 		// Auto-refresh every second
@@ -76,13 +86,47 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	case tickMsg:
 		return m, m.refreshData
+
+	case verifyDoneMsg:
+		m.verifying = false
+		m.verifyStatus = formatVerifyReport(msg.report, msg.err)
+		return m, m.refreshData
+
+	case retentionPlannedMsg:
+		switch {
+		case msg.err != nil:
+			m.retentionStatus = fmt.Sprintf("Retention failed: %v", msg.err)
+		case len(msg.plan.Candidates) == 0:
+			m.retentionStatus = "Retention: nothing to delete"
+		default:
+			m.retentionStatus = ""
+			m.retentionReview = msg.plan
+		}
+		return m, nil
+
+	case retentionAppliedMsg:
+		m.retentionStatus = fmt.Sprintf("Retention: deleted %d chapters, freed %s", msg.deleted, formatBytes(msg.bytes))
+		if msg.err != nil {
+			m.retentionStatus += fmt.Sprintf(" (errors: %v)", msg.err)
+		}
+		return m, m.refreshData
 	}
 
 	return m, nil
 }
 
+// CapturesKeys reports whether the view needs keys that are otherwise
+// handled globally (esc, q), e.g. while reviewing a retention report
+func (m Model) CapturesKeys() bool {
+	return m.retentionReview != nil
+}
+
 // handleKeyPress handles keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.retentionReview != nil {
+		return m.handleRetentionKey(msg)
+	}
+
 	switch msg.String() {
 	case "tab":
 		// Switch tabs
@@ -140,6 +184,14 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 			return m, m.refreshData
 		}
 
+	case "R":
+		// Retry selected failed download
+		if m.selectedTab == 1 && m.cursor < len(m.queueList) {
+			item := m.queueList[m.cursor]
+			m.manager.Retry(item.ChapterID)
+			return m, m.refreshData
+		}
+
 	case "C":
 		// Clear completed downloads
 		m.manager.ClearCompleted()
@@ -148,6 +200,39 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case "a":
 		// Toggle auto-scroll
 		m.autoScroll = !m.autoScroll
+
+	case "v":
+		// Verify downloaded chapters and re-queue broken ones
+		if !m.verifying {
+			m.verifying = true
+			m.verifyStatus = "Verifying downloads..."
+			return m, m.verifyDownloads
+		}
+
+	case "x":
+		// Review what the retention policies would delete
+		m.retentionStatus = "Planning retention..."
+		return m, m.planRetention
+	}
+
+	return m, nil
+}
+
+// handleRetentionKey handles keys while a retention report is reviewed
+func (m Model) handleRetentionKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		plan := m.retentionReview
+		m.retentionReview = nil
+		m.retentionStatus = "Deleting chapters..."
+		return m, m.applyRetention(plan)
+
+	case "n", "esc", "q":
+		// Declining also discards a plan from an automatic run
+		m.retentionReview = nil
+		m.manager.DismissRetention()
+		m.retentionStatus = "Retention cancelled"
+		return m, m.refreshData
 	}
 
 	return m, nil
@@ -166,12 +251,14 @@ func (m Model) View() string {
 	b.WriteString("\n")
 
 	// Content based on selected tab
-	switch m.selectedTab {
-	case 0:
+	switch {
+	case m.retentionReview != nil:
+		b.WriteString(m.renderRetentionReview())
+	case m.selectedTab == 0:
 		b.WriteString(m.renderActiveDownloads())
-	case 1:
+	case m.selectedTab == 1:
 		b.WriteString(m.renderQueue())
-	case 2:
+	case m.selectedTab == 2:
 		b.WriteString(m.renderCompleted())
 	}
 
@@ -200,6 +287,44 @@ func (m Model) View() string {
 	)
 }
 
+// renderRetentionReview renders the dry-run report of a retention run
+func (m Model) renderRetentionReview() string {
+	plan := m.retentionReview
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(theme.WarningStyle.Render(fmt.Sprintf(
+		"Retention would delete %d chapters (%s):",
+		len(plan.Candidates),
+		formatBytes(plan.Bytes()),
+	)))
+	b.WriteString("\n\n")
+
+	visibleItems := m.height - 15
+	if visibleItems < 1 {
+		visibleItems = 1
+	}
+
+	for i, c := range plan.Candidates {
+		if i >= visibleItems {
+			b.WriteString(theme.MutedStyle.Render(fmt.Sprintf("\n... and %d more", len(plan.Candidates)-visibleItems)))
+			break
+		}
+
+		policy := ""
+		if c.Category != "" {
+			policy = fmt.Sprintf(" [%s]", c.Category)
+		}
+		b.WriteString(fmt.Sprintf("✗ %s - %s  %s\n",
+			c.Chapter.MangaTitle,
+			c.Chapter.ChapterName,
+			theme.MutedStyle.Render(fmt.Sprintf("%s, %s%s", formatBytes(c.Chapter.SizeBytes), c.Reason, policy)),
+		))
+	}
+
+	return b.String()
+}
+
 // renderHeader renders the downloads header
 func (m Model) renderHeader() string {
 	title := theme.TitleStyle.Render("Downloads")
@@ -224,7 +349,32 @@ func (m Model) renderHeader() string {
 			failedDownloads,
 		))
 
-	return title + "\n" + info
+	// Combined rate of all active downloads
+	var throughput float64
+	for _, item := range m.activeList {
+		throughput += item.Throughput
+	}
+	if throughput > 0 {
+		info += lipgloss.NewStyle().
+			Foreground(theme.ColorSecondary).
+			Render(fmt.Sprintf(" | %s/s", formatBytes(int64(throughput))))
+	}
+
+	header := title + "\n" + info
+	if m.verifyStatus != "" {
+		header += "\n" + theme.HelpStyle.Render(m.verifyStatus)
+	}
+	if m.retentionStatus != "" {
+		header += "\n" + theme.HelpStyle.Render(m.retentionStatus)
+	} else if plan := m.pendingRetention; plan != nil && m.retentionReview == nil {
+		header += "\n" + theme.WarningStyle.Render(fmt.Sprintf(
+			"Retention: %d chapters (%s) ready to delete, x to review",
+			len(plan.Candidates),
+			formatBytes(plan.Bytes()),
+		))
+	}
+
+	return header
 }
 
 // renderTabs renders the tab bar
@@ -273,6 +423,15 @@ func (m Model) renderActiveDownloads() string {
 		// Progress indicator
 		progressBar := m.renderProgressBar(item.Progress())
 		speedInfo := fmt.Sprintf("Page %d/%d", item.CurrentPage, item.TotalPages)
+		if item.Throughput > 0 {
+			speedInfo += fmt.Sprintf(" • %s/s", formatBytes(int64(item.Throughput)))
+		}
+		if eta := item.ETA(); eta > 0 {
+			speedInfo += fmt.Sprintf(" • ETA %s", eta.Round(time.Second))
+		}
+		if item.TotalBytes > 0 {
+			speedInfo += fmt.Sprintf(" • %s/%s", formatBytes(item.BytesDownloaded), formatBytes(item.TotalBytes))
+		}
 
 		// Format line
 		line := fmt.Sprintf("▼ %s - %s\n  %s  %s",
@@ -323,10 +482,28 @@ func (m Model) renderQueue() string {
 				Width(m.width - 4)
 		}
 
-		// Status icon
+		// Status icon, with the retry schedule or failure reason
 		statusIcon := "○"
-		if item.Status == downloads.StatusPaused {
+		detail := ""
+		switch {
+		case item.Status == downloads.StatusPaused:
 			statusIcon = "⏸"
+		case item.Status == downloads.StatusFailed:
+			statusIcon = "✗"
+			if item.Error != nil {
+				detail = fmt.Sprintf(" failed: %v", item.Error)
+			}
+		case !item.NextAttempt.IsZero():
+			statusIcon = "↻"
+			wait := time.Until(item.NextAttempt)
+			if wait < 0 {
+				wait = 0
+			}
+			detail = fmt.Sprintf(" retry %d in %s (%s)",
+				item.RetryCount,
+				m.formatDuration(wait),
+				item.NextAttempt.Format("15:04:05"),
+			)
 		}
 
 		// Priority indicator
@@ -336,11 +513,12 @@ func (m Model) renderQueue() string {
 		}
 
 		// Format line
-		line := fmt.Sprintf("%s %s - %s%s",
+		line := fmt.Sprintf("%s %s - %s%s%s",
 			statusIcon,
 			item.MangaTitle,
 			item.ChapterName,
 			theme.MutedStyle.Render(priorityStr),
+			theme.MutedStyle.Render(detail),
 		)
 
 		b.WriteString(itemStyle.Render(line))
@@ -433,6 +611,10 @@ func (m Model) renderProgressBar(progress float64) string {
 
 // renderFooter renders the footer with controls
 func (m Model) renderFooter() string {
+	if m.retentionReview != nil {
+		return theme.HelpStyle.Render("y/Enter: delete • n/Esc: cancel")
+	}
+
 	controls := []string{
 		"↑↓/jk: navigate",
 		"Tab: switch tabs",
@@ -441,7 +623,10 @@ func (m Model) renderFooter() string {
 		"s: start",
 		"S: stop",
 		"c: cancel",
+		"R: retry failed",
 		"C: clear completed",
+		"v: verify",
+		"x: retention",
 		"Esc: back",
 	}
 
@@ -478,10 +663,28 @@ type refreshDataMsg struct {
 	queue     []*downloads.DownloadItem
 	completed []*downloads.DownloadItem
 	stats     *downloads.DownloadStats
+
+	pendingRetention *downloads.RetentionPlan
 }
 
 type tickMsg struct{}
 
+type verifyDoneMsg struct {
+	report *downloads.VerifyReport
+	err    error
+}
+
+type retentionPlannedMsg struct {
+	plan *downloads.RetentionPlan
+	err  error
+}
+
+type retentionAppliedMsg struct {
+	deleted int
+	bytes   int64
+	err     error
+}
+
 // Commands
 
 func (m Model) refreshData() tea.Msg {
@@ -491,5 +694,64 @@ func (m Model) refreshData() tea.Msg {
 		queue:     m.manager.GetQueue(),
 		completed: m.manager.GetCompleted(),
 		stats:     &stats,
+
+		pendingRetention: m.manager.PendingRetention(),
+	}
+}
+
+func (m Model) planRetention() tea.Msg {
+	plan, err := m.manager.PlanRetention()
+	return retentionPlannedMsg{plan: plan, err: err}
+}
+
+func (m Model) applyRetention(plan *downloads.RetentionPlan) tea.Cmd {
+	return func() tea.Msg {
+		deleted, freed, err := m.manager.ApplyRetention(plan)
+		m.manager.DismissRetention()
+		return retentionAppliedMsg{deleted: deleted, bytes: freed, err: err}
+	}
+}
+
+func (m Model) verifyDownloads() tea.Msg {
+	report, err := m.manager.VerifyDownloads()
+	return verifyDoneMsg{report: report, err: err}
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatVerifyReport summarizes a verification sweep for the header
+func formatVerifyReport(report *downloads.VerifyReport, err error) string {
+	if err != nil {
+		return fmt.Sprintf("Verification failed: %v", err)
+	}
+	if len(report.Issues) == 0 {
+		return fmt.Sprintf("Verified %d chapters: all intact", report.Checked)
+	}
+
+	first := report.Issues[0]
+	summary := fmt.Sprintf(
+		"Verified %d chapters: %d with problems, %d re-queued (%s - %s: %s",
+		report.Checked,
+		len(report.Issues),
+		report.Requeued(),
+		first.MangaTitle,
+		first.ChapterName,
+		strings.Join(first.Problems, ", "),
+	)
+	if len(report.Issues) > 1 {
+		summary += fmt.Sprintf("; and %d more", len(report.Issues)-1)
 	}
+	return summary + ")"
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -18,23 +19,51 @@ type ErrorNotification struct {
 	Dismissible bool   // Can be dismissed
 }
 
-type ErrorSeverity int
+// ErrorSeverity is the severity shared with the notification center
+type ErrorSeverity = storage.NotificationSeverity
 
 const (
-	SeverityInfo ErrorSeverity = iota
-	SeverityWarning
-	SeverityError
-	SeverityCritical
+	SeverityInfo     = storage.SeverityInfo
+	SeverityWarning  = storage.SeverityWarning
+	SeverityError    = storage.SeverityError
+	SeverityCritical = storage.SeverityCritical
 )
 
-// ErrorNotificationList manages multiple error notifications
+// ErrorNotificationList manages multiple error notifications. Once a store
+// is set they are also kept in the notification center.
 type ErrorNotificationList struct {
 	notifications []ErrorNotification
+	store         *storage.NotificationsManager
 }
 
 // Add adds a new error notification
 func (e *ErrorNotificationList) Add(notification ErrorNotification) {
 	e.notifications = append(e.notifications, notification)
+	e.persist(notification)
+}
+
+// SetStore keeps the notifications added so far, and later ones, in the
+// notification center
+func (e *ErrorNotificationList) SetStore(store *storage.NotificationsManager) {
+	e.store = store
+	for _, notification := range e.notifications {
+		e.persist(notification)
+	}
+}
+
+// persist stores a notification unless the same one is still unread
+func (e *ErrorNotificationList) persist(notification ErrorNotification) {
+	if e.store == nil {
+		return
+	}
+	// The list is shown on the home screen either way
+	_, _ = e.store.AddUnlessUnread(&storage.Notification{
+		Kind:       storage.NotifyError,
+		Severity:   notification.Severity,
+		Title:      notification.Title,
+		Message:    notification.Message,
+		Suggestion: notification.Suggestion,
+	})
 }
 
 // AddError adds an error with custom fields
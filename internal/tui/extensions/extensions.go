@@ -36,6 +36,9 @@ type Model struct {
 	searchQuery    string
 	searchActive   bool
 
+	// Source detail screen for an installed extension, nil when not shown
+	detail *SourceDetail
+
 	// Dependencies
 	client *suwayomi.Client
 
@@ -70,9 +73,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.detail != nil {
+			detail, _ := m.detail.Update(msg)
+			m.detail = &detail
+		}
+		return m, nil
+
+	case sourceDetailClosedMsg:
+		m.detail = nil
+		return m, nil
+
+	case extensionSourcesLoadedMsg, sourcePreferencesLoadedMsg, sourcePreferenceSavedMsg:
+		if m.detail != nil {
+			detail, cmd := m.detail.Update(msg)
+			m.detail = &detail
+			return m, cmd
+		}
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.detail != nil {
+			detail, cmd := m.detail.Update(msg)
+			m.detail = &detail
+			return m, cmd
+		}
 		if m.searchActive {
 			return m.handleSearchInput(msg)
 		}
@@ -145,6 +169,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		// Install/uninstall
 		return m, m.toggleInstall()
 
+	case "s":
+		// Open source settings (installed only)
+		return m.openSourceDetail()
+
 	case "u":
 		// Update (if installed and has update)
 		return m, m.updateExtension()
@@ -165,6 +193,28 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// CapturesKeys reports whether the view needs keys that are otherwise
+// handled globally (esc, q), e.g. while searching or in the source detail screen
+func (m Model) CapturesKeys() bool {
+	return m.searchActive || m.detail != nil
+}
+
+// openSourceDetail opens the source detail screen for the selected installed extension
+func (m Model) openSourceDetail() (Model, tea.Cmd) {
+	if m.mode != ModeInstalled {
+		return m, nil
+	}
+
+	filtered := m.getFilteredExtensions(m.installed)
+	if m.cursor < 0 || m.cursor >= len(filtered) {
+		return m, nil
+	}
+
+	detail := NewSourceDetail(m.client, filtered[m.cursor], m.width, m.height)
+	m.detail = &detail
+	return m, detail.Init()
+}
+
 // handleSearchInput handles search input
 func (m Model) handleSearchInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.String() {
@@ -266,27 +316,33 @@ func (m Model) View() string {
 		return theme.CenteredText(m.width, m.height, fmt.Sprintf("Error: %v", m.err))
 	}
 
-	var b strings.Builder
-
-	// Header
-	b.WriteString(m.renderHeader())
-	b.WriteString("\n\n")
+	var content string
+	if m.detail != nil {
+		content = m.detail.View()
+	} else {
+		var b strings.Builder
+
+		// Header
+		b.WriteString(m.renderHeader())
+		b.WriteString("\n\n")
+
+		// Content based on mode
+		switch m.mode {
+		case ModeBrowse:
+			b.WriteString(m.renderBrowse())
+		case ModeInstalled:
+			b.WriteString(m.renderInstalled())
+		}
 
-	// Content based on mode
-	switch m.mode {
-	case ModeBrowse:
-		b.WriteString(m.renderBrowse())
-	case ModeInstalled:
-		b.WriteString(m.renderInstalled())
-	}
+		b.WriteString("\n")
 
-	b.WriteString("\n")
+		// Footer
+		b.WriteString(m.renderFooter())
 
-	// Footer
-	b.WriteString(m.renderFooter())
+		content = b.String()
+	}
 
 	// Apply consistent horizontal padding/centering
-	content := b.String()
 	maxWidth := 120
 	if m.width < maxWidth {
 		maxWidth = m.width - 4
@@ -509,7 +565,7 @@ func (m Model) renderFooter() string {
 	if m.mode == ModeBrowse {
 		controls = append(controls, "i/Enter: install")
 	} else {
-		controls = append(controls, "i/Enter: uninstall", "u: update", "U: update all")
+		controls = append(controls, "i/Enter: uninstall", "s: source settings", "u: update", "U: update all")
 	}
 
 	controls = append(controls, "r: refresh", "Esc: back")
@@ -0,0 +1,782 @@
+package extensions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sourceEditMode represents the active form control in the source detail screen
+type sourceEditMode int
+
+const (
+	editNone sourceEditMode = iota
+	editText                // Inline text input for edit text preferences
+	editPick                // Entry picker for list and multi-select preferences
+)
+
+// SourceDetail is the source detail screen for an installed extension.
+// It lists the extension's sources and edits the preferences of the selected one.
+type SourceDetail struct {
+	width  int
+	height int
+
+	client    *suwayomi.Client
+	extension *suwayomi.Extension
+
+	// Sources provided by the extension
+	sources      []*suwayomi.ExtensionSource
+	sourceCursor int
+	source       *suwayomi.ExtensionSource // Selected source, nil while listing sources
+
+	// Preferences of the selected source
+	preferences []*suwayomi.SourcePreference
+	prefCursor  int
+	prefOffset  int
+
+	// Form controls
+	editMode     sourceEditMode
+	inputValue   []rune // Edited as runes so the cursor never splits a character
+	inputCursor  int
+	pickCursor   int
+	pickSelected []bool
+
+	// Status
+	loading     bool
+	saving      bool
+	message     string
+	messageType string // "success", "error", ""
+}
+
+// NewSourceDetail creates a source detail screen for an extension
+func NewSourceDetail(client *suwayomi.Client, ext *suwayomi.Extension, width, height int) SourceDetail {
+	return SourceDetail{
+		width:     width,
+		height:    height,
+		client:    client,
+		extension: ext,
+		loading:   true,
+	}
+}
+
+// Init loads the extension's sources
+func (d SourceDetail) Init() tea.Cmd {
+	return d.loadSources
+}
+
+// Update handles messages for the source detail screen
+func (d SourceDetail) Update(msg tea.Msg) (SourceDetail, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.width = msg.Width
+		d.height = msg.Height
+		return d, nil
+
+	case tea.KeyMsg:
+		switch d.editMode {
+		case editText:
+			return d.handleTextInput(msg)
+		case editPick:
+			return d.handlePickInput(msg)
+		}
+		if d.source != nil {
+			return d.handlePreferenceKeys(msg)
+		}
+		return d.handleSourceKeys(msg)
+
+	case extensionSourcesLoadedMsg:
+		d.loading = false
+		if msg.err != nil {
+			d.setMessage(fmt.Sprintf("Failed to load sources: %v", msg.err), "error")
+			return d, nil
+		}
+		d.sources = msg.sources
+		d.sourceCursor = 0
+
+		// Skip the source list when there is nothing to choose from
+		if len(d.sources) == 1 {
+			return d.openSource(d.sources[0])
+		}
+		return d, nil
+
+	case sourcePreferencesLoadedMsg:
+		if d.source == nil || msg.sourceID != d.source.ID {
+			return d, nil
+		}
+		d.loading = false
+		if msg.err != nil {
+			d.setMessage(fmt.Sprintf("Failed to load preferences: %v", msg.err), "error")
+			return d, nil
+		}
+		d.setPreferences(msg.preferences)
+		return d, nil
+
+	case sourcePreferenceSavedMsg:
+		if d.source == nil || msg.sourceID != d.source.ID {
+			return d, nil
+		}
+		d.saving = false
+		if msg.err != nil {
+			d.setMessage(fmt.Sprintf("Failed to save: %v", msg.err), "error")
+			return d, nil
+		}
+		d.setPreferences(msg.preferences)
+		d.setMessage(fmt.Sprintf("Saved %s", msg.title), "success")
+		return d, nil
+	}
+
+	return d, nil
+}
+
+// handleSourceKeys handles keyboard input while listing sources
+func (d SourceDetail) handleSourceKeys(msg tea.KeyMsg) (SourceDetail, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return d, closeSourceDetail
+
+	case "up", "k":
+		if d.sourceCursor > 0 {
+			d.sourceCursor--
+		}
+
+	case "down", "j":
+		if d.sourceCursor < len(d.sources)-1 {
+			d.sourceCursor++
+		}
+
+	case "enter":
+		if d.sourceCursor >= 0 && d.sourceCursor < len(d.sources) {
+			return d.openSource(d.sources[d.sourceCursor])
+		}
+
+	case "r":
+		d.loading = true
+		d.message = ""
+		return d, d.loadSources
+	}
+
+	return d, nil
+}
+
+// handlePreferenceKeys handles keyboard input while listing preferences
+func (d SourceDetail) handlePreferenceKeys(msg tea.KeyMsg) (SourceDetail, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		if len(d.sources) > 1 {
+			d.source = nil
+			d.preferences = nil
+			d.message = ""
+			return d, nil
+		}
+		return d, closeSourceDetail
+
+	case "up", "k":
+		if d.prefCursor > 0 {
+			d.prefCursor--
+			d.adjustOffset()
+		}
+
+	case "down", "j":
+		if d.prefCursor < len(d.preferences)-1 {
+			d.prefCursor++
+			d.adjustOffset()
+		}
+
+	case "g":
+		d.prefCursor = 0
+		d.prefOffset = 0
+
+	case "G":
+		if len(d.preferences) > 0 {
+			d.prefCursor = len(d.preferences) - 1
+			d.adjustOffset()
+		}
+
+	case "enter", " ":
+		return d.editPreference()
+
+	case "r":
+		d.loading = true
+		d.message = ""
+		return d, d.loadPreferences(d.source.ID)
+	}
+
+	return d, nil
+}
+
+// handleTextInput handles keyboard input for edit text preferences
+func (d SourceDetail) handleTextInput(msg tea.KeyMsg) (SourceDetail, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		d.editMode = editNone
+
+	case "enter":
+		d.editMode = editNone
+		pref := d.currentPreference()
+		if pref == nil || string(d.inputValue) == pref.StringValue {
+			return d, nil
+		}
+		return d.save(pref, suwayomi.SourcePreferenceChange{
+			Position:    pref.Position,
+			Type:        pref.Type,
+			StringValue: string(d.inputValue),
+		})
+
+	case "backspace":
+		if d.inputCursor > 0 {
+			d.inputValue = append(d.inputValue[:d.inputCursor-1:d.inputCursor-1], d.inputValue[d.inputCursor:]...)
+			d.inputCursor--
+		}
+
+	case "left":
+		if d.inputCursor > 0 {
+			d.inputCursor--
+		}
+
+	case "right":
+		if d.inputCursor < len(d.inputValue) {
+			d.inputCursor++
+		}
+
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			text := msg.Runes
+			if msg.Type == tea.KeySpace {
+				text = []rune{' '}
+			}
+			value := make([]rune, 0, len(d.inputValue)+len(text))
+			value = append(value, d.inputValue[:d.inputCursor]...)
+			value = append(value, text...)
+			d.inputValue = append(value, d.inputValue[d.inputCursor:]...)
+			d.inputCursor += len(text)
+		}
+	}
+
+	return d, nil
+}
+
+// handlePickInput handles keyboard input for list and multi-select preferences
+func (d SourceDetail) handlePickInput(msg tea.KeyMsg) (SourceDetail, tea.Cmd) {
+	pref := d.currentPreference()
+	if pref == nil {
+		d.editMode = editNone
+		return d, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		d.editMode = editNone
+
+	case "up", "k":
+		if d.pickCursor > 0 {
+			d.pickCursor--
+		}
+
+	case "down", "j":
+		if d.pickCursor < len(pref.EntryValues)-1 {
+			d.pickCursor++
+		}
+
+	case " ":
+		if pref.Type == suwayomi.PreferenceMultiSelect && d.pickCursor < len(d.pickSelected) {
+			d.pickSelected[d.pickCursor] = !d.pickSelected[d.pickCursor]
+		}
+
+	case "enter":
+		d.editMode = editNone
+		change := suwayomi.SourcePreferenceChange{
+			Position: pref.Position,
+			Type:     pref.Type,
+		}
+
+		if pref.Type == suwayomi.PreferenceMultiSelect {
+			change.MultiValue = make([]string, 0)
+			for i, selected := range d.pickSelected {
+				if selected {
+					change.MultiValue = append(change.MultiValue, pref.EntryValues[i])
+				}
+			}
+		} else {
+			if d.pickCursor >= len(pref.EntryValues) {
+				return d, nil
+			}
+			change.StringValue = pref.EntryValues[d.pickCursor]
+			if change.StringValue == pref.StringValue {
+				return d, nil
+			}
+		}
+
+		return d.save(pref, change)
+	}
+
+	return d, nil
+}
+
+// editPreference activates the form control for the preference under the cursor
+func (d SourceDetail) editPreference() (SourceDetail, tea.Cmd) {
+	pref := d.currentPreference()
+	if pref == nil || d.saving {
+		return d, nil
+	}
+
+	if !pref.Enabled {
+		d.setMessage(fmt.Sprintf("%s is disabled", pref.Title), "error")
+		return d, nil
+	}
+
+	switch pref.Type {
+	case suwayomi.PreferenceSwitch, suwayomi.PreferenceCheckBox:
+		return d.save(pref, suwayomi.SourcePreferenceChange{
+			Position:  pref.Position,
+			Type:      pref.Type,
+			BoolValue: !pref.BoolValue,
+		})
+
+	case suwayomi.PreferenceEditText:
+		d.editMode = editText
+		d.inputValue = []rune(pref.StringValue)
+		d.inputCursor = len(d.inputValue)
+
+	case suwayomi.PreferenceList, suwayomi.PreferenceMultiSelect:
+		if len(pref.EntryValues) == 0 {
+			return d, nil
+		}
+		d.editMode = editPick
+		d.pickCursor = 0
+		d.pickSelected = make([]bool, len(pref.EntryValues))
+		for i, value := range pref.EntryValues {
+			if pref.Type == suwayomi.PreferenceList {
+				if value == pref.StringValue {
+					d.pickCursor = i
+				}
+				continue
+			}
+			for _, v := range pref.MultiValue {
+				if v == value {
+					d.pickSelected[i] = true
+				}
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// openSource selects a source and loads its preferences
+func (d SourceDetail) openSource(src *suwayomi.ExtensionSource) (SourceDetail, tea.Cmd) {
+	d.source = src
+	d.preferences = nil
+	d.prefCursor = 0
+	d.prefOffset = 0
+	d.message = ""
+
+	if !src.IsConfigurable {
+		d.loading = false
+		return d, nil
+	}
+
+	d.loading = true
+	return d, d.loadPreferences(src.ID)
+}
+
+// setPreferences stores the visible preferences and keeps the cursor in range
+func (d *SourceDetail) setPreferences(prefs []*suwayomi.SourcePreference) {
+	d.preferences = make([]*suwayomi.SourcePreference, 0, len(prefs))
+	for _, pref := range prefs {
+		if pref.Visible {
+			d.preferences = append(d.preferences, pref)
+		}
+	}
+
+	if d.prefCursor >= len(d.preferences) {
+		d.prefCursor = len(d.preferences) - 1
+	}
+	if d.prefCursor < 0 {
+		d.prefCursor = 0
+	}
+	d.adjustOffset()
+}
+
+// currentPreference returns the preference under the cursor
+func (d SourceDetail) currentPreference() *suwayomi.SourcePreference {
+	if d.prefCursor >= 0 && d.prefCursor < len(d.preferences) {
+		return d.preferences[d.prefCursor]
+	}
+	return nil
+}
+
+// setMessage sets a status message
+func (d *SourceDetail) setMessage(msg, msgType string) {
+	d.message = msg
+	d.messageType = msgType
+}
+
+// adjustOffset adjusts the scroll offset to keep the cursor visible
+func (d *SourceDetail) adjustOffset() {
+	visibleItems := d.visibleItems()
+	if d.prefCursor < d.prefOffset {
+		d.prefOffset = d.prefCursor
+	} else if d.prefCursor >= d.prefOffset+visibleItems {
+		d.prefOffset = d.prefCursor - visibleItems + 1
+	}
+}
+
+// visibleItems returns how many preferences fit on screen (two lines each)
+func (d SourceDetail) visibleItems() int {
+	visible := (d.height - 14) / 2
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// View renders the source detail screen
+func (d SourceDetail) View() string {
+	var b strings.Builder
+
+	b.WriteString(d.renderHeader())
+	b.WriteString("\n\n")
+
+	switch {
+	case d.loading:
+		b.WriteString(theme.MutedStyle.Render("Loading..."))
+		b.WriteString("\n")
+	case d.source == nil:
+		b.WriteString(d.renderSources())
+	case d.editMode == editPick:
+		b.WriteString(d.renderPicker())
+	default:
+		b.WriteString(d.renderPreferences())
+	}
+
+	if d.editMode == editText {
+		b.WriteString("\n")
+		b.WriteString(d.renderTextInput())
+	}
+
+	if d.message != "" {
+		b.WriteString("\n")
+		switch d.messageType {
+		case "success":
+			b.WriteString(theme.SuccessStyle.Render("✓ " + d.message))
+		case "error":
+			b.WriteString(theme.ErrorStyle.Render("✗ " + d.message))
+		default:
+			b.WriteString(d.message)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(d.renderFooter())
+
+	return b.String()
+}
+
+// renderHeader renders the extension and source title
+func (d SourceDetail) renderHeader() string {
+	title := theme.TitleStyle.Render(d.extension.Name)
+
+	info := fmt.Sprintf("v%s • %s", d.extension.VersionName, d.extension.PkgName)
+	if d.source != nil {
+		info = fmt.Sprintf("Source: %s", sourceLabel(d.source))
+	}
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(theme.ColorMuted).
+		Render(info)
+
+	if d.saving {
+		subtitle += " " + theme.WarningStyle.Render("Saving...")
+	}
+
+	return title + "\n" + subtitle
+}
+
+// renderSources renders the list of sources provided by the extension
+func (d SourceDetail) renderSources() string {
+	if len(d.sources) == 0 {
+		return theme.MutedStyle.Render("This extension provides no sources") + "\n"
+	}
+
+	var b strings.Builder
+	for i, src := range d.sources {
+		itemStyle := lipgloss.NewStyle()
+		if i == d.sourceCursor {
+			itemStyle = itemStyle.
+				Background(theme.ColorPrimary).
+				Foreground(lipgloss.Color("#000000")).
+				Bold(true).
+				Width(d.width - 4)
+		}
+
+		langBadge := lipgloss.NewStyle().
+			Foreground(theme.ColorSecondary).
+			Render(fmt.Sprintf("[%s]", src.Language))
+
+		configurable := theme.MutedStyle.Render("no settings")
+		if src.IsConfigurable {
+			configurable = theme.ValueStyle.Render("configurable")
+		}
+
+		line := fmt.Sprintf("%s %s %s", src.Name, langBadge, configurable)
+		if src.IsNSFW {
+			line += " " + theme.WarningStyle.Render("[NSFW]")
+		}
+
+		b.WriteString(itemStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderPreferences renders the preference form
+func (d SourceDetail) renderPreferences() string {
+	if !d.source.IsConfigurable {
+		return theme.MutedStyle.Render("This source has no configurable settings") + "\n"
+	}
+	if len(d.preferences) == 0 {
+		return theme.MutedStyle.Render("No preferences available") + "\n"
+	}
+
+	var b strings.Builder
+
+	start := d.prefOffset
+	end := start + d.visibleItems()
+	if end > len(d.preferences) {
+		end = len(d.preferences)
+	}
+
+	for i := start; i < end; i++ {
+		pref := d.preferences[i]
+
+		labelStyle := lipgloss.NewStyle()
+		if !pref.Enabled {
+			labelStyle = labelStyle.Foreground(theme.ColorMuted)
+		}
+		if i == d.prefCursor {
+			labelStyle = labelStyle.
+				Background(theme.ColorPrimary).
+				Foreground(lipgloss.Color("#000000")).
+				Bold(true).
+				Width(d.width - 4)
+		}
+
+		line := fmt.Sprintf("%s %s: %s", preferenceControl(pref), pref.Title, pref.DisplayValue())
+		b.WriteString(labelStyle.Render(line))
+		b.WriteString("\n")
+
+		if pref.Summary != "" {
+			b.WriteString(theme.MutedStyle.Render("    " + pref.Summary))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderPicker renders the entry picker for list and multi-select preferences
+func (d SourceDetail) renderPicker() string {
+	pref := d.currentPreference()
+	if pref == nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	title := pref.Title
+	if pref.DialogTitle != "" {
+		title = pref.DialogTitle
+	}
+	b.WriteString(theme.SectionStyle.Render(title))
+	b.WriteString("\n")
+	if pref.DialogMessage != "" {
+		b.WriteString(theme.MutedStyle.Render(pref.DialogMessage))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	for i, value := range pref.EntryValues {
+		var marker string
+		if pref.Type == suwayomi.PreferenceMultiSelect {
+			marker = "[ ]"
+			if d.pickSelected[i] {
+				marker = "[x]"
+			}
+		} else {
+			marker = "( )"
+			if value == pref.StringValue {
+				marker = "(•)"
+			}
+		}
+
+		itemStyle := lipgloss.NewStyle()
+		if i == d.pickCursor {
+			itemStyle = itemStyle.
+				Background(theme.ColorPrimary).
+				Foreground(lipgloss.Color("#000000")).
+				Bold(true).
+				Width(d.width - 4)
+		}
+
+		b.WriteString(itemStyle.Render(fmt.Sprintf("%s %s", marker, pref.EntryLabel(value))))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderTextInput renders the inline text input for edit text preferences
+func (d SourceDetail) renderTextInput() string {
+	pref := d.currentPreference()
+	if pref == nil {
+		return ""
+	}
+
+	label := pref.Title
+	if pref.DialogTitle != "" {
+		label = pref.DialogTitle
+	}
+
+	var b strings.Builder
+	b.WriteString(theme.SectionStyle.Render(label))
+	b.WriteString("\n")
+	if pref.DialogMessage != "" {
+		b.WriteString(theme.MutedStyle.Render(pref.DialogMessage))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("> ")
+	b.WriteString(string(d.inputValue[:d.inputCursor]))
+	b.WriteString(theme.HighlightStyle.Render("_"))
+	b.WriteString(string(d.inputValue[d.inputCursor:]))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderFooter renders the footer with controls
+func (d SourceDetail) renderFooter() string {
+	var controls []string
+
+	switch {
+	case d.editMode == editText:
+		controls = []string{"Enter: save", "←→: move cursor", "Esc: cancel"}
+	case d.editMode == editPick:
+		controls = []string{"↑↓/jk: navigate"}
+		if pref := d.currentPreference(); pref != nil && pref.Type == suwayomi.PreferenceMultiSelect {
+			controls = append(controls, "Space: toggle")
+		}
+		controls = append(controls, "Enter: save", "Esc: cancel")
+	case d.source == nil:
+		controls = []string{"↑↓/jk: navigate", "Enter: settings", "r: refresh", "Esc: back"}
+	default:
+		controls = []string{"↑↓/jk: navigate", "g/G: top/bottom", "Enter/Space: edit", "r: refresh", "Esc: back"}
+	}
+
+	return theme.HelpStyle.Render(strings.Join(controls, " • "))
+}
+
+// preferenceControl returns the control indicator for a preference type
+func preferenceControl(pref *suwayomi.SourcePreference) string {
+	switch pref.Type {
+	case suwayomi.PreferenceSwitch, suwayomi.PreferenceCheckBox:
+		if pref.BoolValue {
+			return "[x]"
+		}
+		return "[ ]"
+	case suwayomi.PreferenceList:
+		return "(▾)"
+	case suwayomi.PreferenceMultiSelect:
+		return "[▾]"
+	case suwayomi.PreferenceEditText:
+		return "[✎]"
+	}
+	return "   "
+}
+
+// sourceLabel returns the display name of a source
+func sourceLabel(src *suwayomi.ExtensionSource) string {
+	if src.DisplayName != "" {
+		return src.DisplayName
+	}
+	return src.Name
+}
+
+// Messages
+
+type extensionSourcesLoadedMsg struct {
+	sources []*suwayomi.ExtensionSource
+	err     error
+}
+
+type sourcePreferencesLoadedMsg struct {
+	sourceID    string
+	preferences []*suwayomi.SourcePreference
+	err         error
+}
+
+type sourcePreferenceSavedMsg struct {
+	sourceID    string
+	title       string
+	preferences []*suwayomi.SourcePreference
+	err         error
+}
+
+type sourceDetailClosedMsg struct{}
+
+// Commands
+
+func closeSourceDetail() tea.Msg {
+	return sourceDetailClosedMsg{}
+}
+
+func (d SourceDetail) loadSources() tea.Msg {
+	if d.client == nil {
+		return extensionSourcesLoadedMsg{sources: []*suwayomi.ExtensionSource{}}
+	}
+
+	sources, err := d.client.GetExtensionSources(d.extension.PkgName)
+	return extensionSourcesLoadedMsg{sources: sources, err: err}
+}
+
+func (d SourceDetail) loadPreferences(sourceID string) tea.Cmd {
+	client := d.client
+	return func() tea.Msg {
+		if client == nil {
+			return sourcePreferencesLoadedMsg{sourceID: sourceID}
+		}
+
+		prefs, err := client.GetSourcePreferences(sourceID)
+		return sourcePreferencesLoadedMsg{sourceID: sourceID, preferences: prefs, err: err}
+	}
+}
+
+// save sends a preference change to the server
+func (d SourceDetail) save(pref *suwayomi.SourcePreference, change suwayomi.SourcePreferenceChange) (SourceDetail, tea.Cmd) {
+	if d.client == nil || d.source == nil {
+		return d, nil
+	}
+
+	d.saving = true
+	d.message = ""
+
+	client := d.client
+	sourceID := d.source.ID
+	title := pref.Title
+
+	return d, func() tea.Msg {
+		prefs, err := client.SetSourcePreference(sourceID, change)
+		return sourcePreferenceSavedMsg{
+			sourceID:    sourceID,
+			title:       title,
+			preferences: prefs,
+			err:         err,
+		}
+	}
+}
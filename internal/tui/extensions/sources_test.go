@@ -0,0 +1,88 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCmd runs cmd and passes its message to the source detail screen
+func runCmd(t *testing.T, d SourceDetail, cmd tea.Cmd) SourceDetail {
+	t.Helper()
+	require.NotNil(t, cmd)
+	d, _ = d.Update(cmd())
+	return d
+}
+
+// typeKeys sends keys to the source detail screen. Named keys such as
+// "left" are sent as such, anything else as typed text.
+func typeKeys(d SourceDetail, keys ...string) SourceDetail {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "left":
+			msg = tea.KeyMsg{Type: tea.KeyLeft}
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		d, _ = d.Update(msg)
+	}
+	return d
+}
+
+func TestSourceDetail_EditTextNonASCII(t *testing.T) {
+	server := suwayomitest.NewServer()
+	defer server.Close()
+	server.AddExtension(&suwayomitest.Extension{
+		PkgName:     "eu.kanade.tachiyomi.extension.ja.example",
+		Name:        "Example",
+		IsInstalled: true,
+		Sources: []*suwayomitest.Source{{
+			ID:   "1234567890123",
+			Name: "Example",
+			Lang: "ja",
+			Preferences: []*suwayomitest.Preference{{
+				Type:         suwayomitest.EditTextPreference,
+				Key:          "title",
+				Title:        "Title",
+				CurrentValue: "café",
+				Default:      "",
+			}},
+		}},
+	})
+
+	d := NewSourceDetail(server.Client(), &suwayomi.Extension{PkgName: "eu.kanade.tachiyomi.extension.ja.example"}, 80, 24)
+	d = runCmd(t, d, d.Init())
+	require.NotNil(t, d.source)
+	d = runCmd(t, d, d.loadPreferences(d.source.ID))
+	require.Len(t, d.preferences, 1)
+
+	d = typeKeys(d, "enter")
+	require.Equal(t, editText, d.editMode)
+	assert.Equal(t, 4, d.inputCursor, "the cursor counts characters, not bytes")
+
+	// Editing before and after multi-byte characters keeps them intact
+	d = typeKeys(d, "left", "backspace", "ü", "right", " ", "日本", "left", "backspace")
+	assert.Equal(t, "caüé 本", string(d.inputValue))
+	assert.Equal(t, 5, d.inputCursor)
+	assert.Contains(t, d.renderTextInput(), "caüé _本")
+
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, editNone, d.editMode)
+
+	// The server stores the edited text
+	d = runCmd(t, d, cmd)
+	assert.Equal(t, "caüé 本", d.preferences[0].StringValue)
+}
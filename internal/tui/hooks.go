@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/hooks"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/reader"
+	"github.com/Justice-Caban/Miryokusha/internal/updates"
+)
+
+// newHookDispatcher creates the configured hooks, logging their deliveries
+// in st if set. Without hooks, or if they cannot be set up, it returns nil,
+// which fires nothing.
+func newHookDispatcher(cfg *config.Config, st *storage.Storage, errors *ErrorNotificationList) *hooks.Dispatcher {
+	if len(cfg.Hooks) == 0 {
+		return nil
+	}
+
+	var log hooks.DeliveryLog
+	if st != nil {
+		log = st.HookDeliveries
+	}
+	dispatcher, err := hooks.NewDispatcher(HooksFromConfig(cfg.Hooks), log)
+	if err != nil {
+		errors.AddError("Hooks Disabled", err.Error(), "Check hooks in config.yaml.", SeverityWarning)
+		return nil
+	}
+	return dispatcher
+}
+
+// HooksFromConfig maps the hooks of the config to the dispatcher
+func HooksFromConfig(hookConfigs []config.HookConfig) []hooks.Hook {
+	hookList := make([]hooks.Hook, 0, len(hookConfigs))
+	for _, hc := range hookConfigs {
+		hook := hooks.Hook{
+			Name:     hc.Name,
+			URL:      hc.URL,
+			Headers:  hc.Headers,
+			Command:  hc.Command,
+			Template: hc.Payload,
+			Retries:  hc.Retries,
+			Timeout:  time.Duration(hc.TimeoutSeconds) * time.Second,
+		}
+		if len(hc.Events) > 0 {
+			hook.Events = make(map[hooks.EventType]bool)
+			for _, event := range hc.Events {
+				hook.Events[hooks.EventType(event)] = true
+			}
+		}
+		hookList = append(hookList, hook)
+	}
+	return hookList
+}
+
+// newChapterEvents returns an event for each manga an update found new
+// chapters of, pointing at the first new chapter
+func newChapterEvents(summary *updates.UpdateSummary) []hooks.Event {
+	var events []hooks.Event
+	for _, task := range summary.Tasks {
+		if !task.HasNewChapters() {
+			continue
+		}
+		event := hooks.Event{
+			Type:       hooks.EventNewChapters,
+			Time:       task.CompletedAt,
+			Title:      task.MangaTitle,
+			Message:    fmt.Sprintf("%d new chapter(s) available", task.GetNewChapters()),
+			MangaID:    task.MangaID,
+			MangaTitle: task.MangaTitle,
+			Count:      task.GetNewChapters(),
+		}
+		for _, ch := range task.NewChapters {
+			if event.ChapterID == "" || ch.ChapterNumber < event.ChapterNumber {
+				event.ChapterID = ch.ID
+				event.ChapterName = ch.Title
+				event.ChapterNumber = ch.ChapterNumber
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// chapterCompletedEvent returns the event of a chapter read to the end
+func chapterCompletedEvent(msg reader.ChapterReadMsg) hooks.Event {
+	return hooks.Event{
+		Type:          hooks.EventChapterCompleted,
+		Title:         msg.MangaTitle,
+		Message:       fmt.Sprintf("Finished %s", msg.ChapterName),
+		MangaID:       msg.MangaID,
+		MangaTitle:    msg.MangaTitle,
+		ChapterID:     msg.ChapterID,
+		ChapterName:   msg.ChapterName,
+		ChapterNumber: msg.ChapterNumber,
+	}
+}
+
+// downloadEvent returns the event of a download that completed, or failed
+// with err
+func downloadEvent(eventType hooks.EventType, item *downloads.DownloadItem, err error) hooks.Event {
+	event := hooks.Event{
+		Type:        eventType,
+		Time:        item.CompletedAt,
+		Title:       item.MangaTitle,
+		Message:     fmt.Sprintf("Downloaded %s", item.ChapterName),
+		MangaID:     item.MangaID,
+		MangaTitle:  item.MangaTitle,
+		ChapterID:   item.ChapterID,
+		ChapterName: item.ChapterName,
+	}
+	if item.Chapter != nil {
+		event.ChapterNumber = item.Chapter.ChapterNumber
+	}
+	if err != nil {
+		event.Message = fmt.Sprintf("Download of %s failed", item.ChapterName)
+		event.Error = err.Error()
+	}
+	return event
+}
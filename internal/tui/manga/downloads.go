@@ -0,0 +1,320 @@
+package manga
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind identifies the input the footer is asking for
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptNextUnread
+	promptRange
+	promptDelete
+	promptExport
+)
+
+// defaultNextUnread is the number of chapters "next N unread" queues when
+// no number is entered
+const defaultNextUnread = 5
+
+// handlePromptKey handles keys while a prompt is open
+func (m Model) handlePromptKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	kind := m.prompt
+	if kind == promptExport {
+		return m.handleExportKey(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.prompt = promptNone
+		m.input = ""
+		return m, nil
+
+	case "enter", "y":
+		if kind == promptDelete || msg.String() == "enter" {
+			m.prompt = promptNone
+			input := m.input
+			m.input = ""
+			return m.submitPrompt(kind, input)
+		}
+
+	case "n":
+		if kind == promptDelete {
+			m.prompt = promptNone
+			return m, nil
+		}
+
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+		return m, nil
+	}
+
+	// Numbers and ranges only
+	if kind != promptDelete && len(msg.String()) == 1 && strings.ContainsAny(msg.String(), "0123456789.-") {
+		m.input += msg.String()
+	}
+	return m, nil
+}
+
+// submitPrompt performs the action of a confirmed prompt
+func (m Model) submitPrompt(kind promptKind, input string) (Model, tea.Cmd) {
+	switch kind {
+	case promptNextUnread:
+		n := defaultNextUnread
+		if input != "" {
+			parsed, err := strconv.Atoi(input)
+			if err != nil || parsed < 1 {
+				m.status = fmt.Sprintf("Invalid number: %s", input)
+				return m, nil
+			}
+			n = parsed
+		}
+		chapters := m.unreadToDownload()
+		if len(chapters) > n {
+			chapters = chapters[:n]
+		}
+		return m, m.queueChapters(chapters)
+
+	case promptRange:
+		from, to, err := parseChapterRange(input)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		var chapters []*source.Chapter
+		for _, c := range m.sortedChapters() {
+			if c.ChapterNumber >= from && c.ChapterNumber <= to && m.canQueue(c) {
+				chapters = append(chapters, c)
+			}
+		}
+		return m, m.queueChapters(chapters)
+
+	case promptDelete:
+		return m, m.deleteChapters(m.targets())
+	}
+
+	return m, nil
+}
+
+// parseChapterRange parses "from-to" or a single chapter number
+func parseChapterRange(input string) (float64, float64, error) {
+	input = strings.TrimSpace(input)
+	fromStr, toStr, isRange := strings.Cut(input, "-")
+	if !isRange {
+		toStr = fromStr
+	}
+
+	from, err := strconv.ParseFloat(strings.TrimSpace(fromStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range: %s", input)
+	}
+	to, err := strconv.ParseFloat(strings.TrimSpace(toStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range: %s", input)
+	}
+	if from > to {
+		from, to = to, from
+	}
+	return from, to, nil
+}
+
+// targets returns the selected chapters, or the one under the cursor if
+// none are selected, in reading order
+func (m Model) targets() []*source.Chapter {
+	if len(m.selected) == 0 {
+		if m.cursor < len(m.chapters) {
+			return []*source.Chapter{m.chapters[m.cursor]}
+		}
+		return nil
+	}
+
+	var chapters []*source.Chapter
+	for _, c := range m.sortedChapters() {
+		if m.selected[c.ID] {
+			chapters = append(chapters, c)
+		}
+	}
+	return chapters
+}
+
+// sortedChapters returns the chapters in reading order
+func (m Model) sortedChapters() []*source.Chapter {
+	chapters := make([]*source.Chapter, len(m.chapters))
+	copy(chapters, m.chapters)
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].ChapterNumber < chapters[j].ChapterNumber
+	})
+	return chapters
+}
+
+// unreadToDownload returns the unread chapters not yet downloaded or
+// queued, in reading order
+func (m Model) unreadToDownload() []*source.Chapter {
+	var chapters []*source.Chapter
+	for _, c := range m.sortedChapters() {
+		if !m.isRead(c) && m.canQueue(c) {
+			chapters = append(chapters, c)
+		}
+	}
+	return chapters
+}
+
+// isRead reports whether a chapter is read on the source or locally
+func (m Model) isRead(c *source.Chapter) bool {
+	return c.IsRead || m.readLocal[c.ID]
+}
+
+// canQueue reports whether a chapter is neither on disk nor in the queue.
+// Failed downloads stay in the queue and can be queued again.
+func (m Model) canQueue(c *source.Chapter) bool {
+	if m.onDisk[c.ID] {
+		return false
+	}
+	item, queued := m.queued[c.ID]
+	return !queued || item.Status == downloads.StatusFailed
+}
+
+// hasFailed reports whether a chapter's download failed
+func (m Model) hasFailed(c *source.Chapter) bool {
+	item, ok := m.queued[c.ID]
+	return ok && item.Status == downloads.StatusFailed
+}
+
+// downloadIndicator returns the download state shown on a chapter row
+func (m Model) downloadIndicator(c *source.Chapter) string {
+	if item, ok := m.queued[c.ID]; ok {
+		switch {
+		case item.Status == downloads.StatusFailed:
+			return theme.ErrorStyle.Render(" ✗ failed")
+		case item.Status == downloads.StatusDownloading:
+			return theme.SuccessStyle.Render(fmt.Sprintf(" ⬇ %.0f%%", item.Progress()))
+		case !item.NextAttempt.IsZero():
+			return theme.WarningStyle.Render(" ↻ retrying")
+		default:
+			return theme.MutedStyle.Render(" ⏳ queued")
+		}
+	}
+	if m.onDisk[c.ID] {
+		return " 💾"
+	}
+	return ""
+}
+
+// Messages
+
+type downloadStatesMsg struct {
+	queued map[string]*downloads.DownloadItem
+	onDisk map[string]bool
+}
+
+type downloadTickMsg struct{}
+
+type bulkDoneMsg struct {
+	status string
+}
+
+// Commands
+
+// loadDownloadStates reads the queue and the download index for this manga
+func (m Model) loadDownloadStates() tea.Msg {
+	queued := make(map[string]*downloads.DownloadItem)
+	if m.downloadManager != nil {
+		items := append(m.downloadManager.GetQueue(), m.downloadManager.GetActive()...)
+		for _, item := range items {
+			if item.MangaID == m.manga.ID {
+				queued[item.ChapterID] = item
+			}
+		}
+	}
+
+	var onDisk map[string]bool
+	if m.storage != nil {
+		onDisk, _ = m.storage.Downloads.GetDownloadedChapterIDs(m.manga.ID)
+	}
+
+	return downloadStatesMsg{queued: queued, onDisk: onDisk}
+}
+
+// queueChapters adds chapters to the download queue. Failed downloads are
+// retried instead.
+func (m Model) queueChapters(chapters []*source.Chapter) tea.Cmd {
+	manager := m.downloadManager
+	manga := m.manga
+	failed := make(map[string]bool)
+	for _, c := range chapters {
+		if m.hasFailed(c) {
+			failed[c.ID] = true
+		}
+	}
+	return func() tea.Msg {
+		if manager == nil {
+			return bulkDoneMsg{status: "Downloads are unavailable"}
+		}
+		if len(chapters) == 0 {
+			return bulkDoneMsg{status: "Nothing to download"}
+		}
+
+		queued := 0
+		for _, c := range chapters {
+			var err error
+			if failed[c.ID] {
+				err = manager.Retry(c.ID)
+			} else {
+				err = manager.Add(manga, c, downloads.PriorityNormal)
+			}
+			if err == nil {
+				queued++
+			}
+		}
+		return bulkDoneMsg{status: fmt.Sprintf("Queued %d chapters for download", queued)}
+	}
+}
+
+// deleteChapters removes downloaded chapters from disk
+func (m Model) deleteChapters(chapters []*source.Chapter) tea.Cmd {
+	manager := m.downloadManager
+	onDisk := m.onDisk
+	return func() tea.Msg {
+		if manager == nil {
+			return bulkDoneMsg{status: "Downloads are unavailable"}
+		}
+
+		deleted, failed := 0, 0
+		for _, c := range chapters {
+			if !onDisk[c.ID] {
+				continue
+			}
+			if err := manager.DeleteDownloaded(c.ID); err != nil {
+				failed++
+				continue
+			}
+			deleted++
+		}
+
+		status := fmt.Sprintf("Deleted %d downloaded chapters", deleted)
+		if failed > 0 {
+			status += fmt.Sprintf(", %d failed", failed)
+		}
+		return bulkDoneMsg{status: status}
+	}
+}
+
+// tickDownloads schedules a refresh of the download states
+func tickDownloads() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return downloadTickMsg{}
+	})
+}
@@ -0,0 +1,215 @@
+package manga
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModel returns a model of a manga with chapters 1 to n listed newest
+// first, as sources return them. Chapter IDs are "c<number>".
+func testModel(n int) Model {
+	m := Model{
+		manga:     &source.Manga{ID: "m1", Title: "Manga"},
+		selected:  make(map[string]bool),
+		onDisk:    make(map[string]bool),
+		queued:    make(map[string]*downloads.DownloadItem),
+		readLocal: make(map[string]bool),
+	}
+	for i := n; i >= 1; i-- {
+		m.chapters = append(m.chapters, &source.Chapter{
+			ID:            fmt.Sprintf("c%d", i),
+			MangaID:       "m1",
+			ChapterNumber: float64(i),
+		})
+	}
+	return m
+}
+
+// chapterIDs returns the IDs of chapters
+func chapterIDs(chapters []*source.Chapter) []string {
+	var ids []string
+	for _, c := range chapters {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestParseChapterRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		from    float64
+		to      float64
+		wantErr bool
+	}{
+		{input: "5", from: 5, to: 5},
+		{input: "1-10", from: 1, to: 10},
+		{input: "10-3", from: 3, to: 10},
+		{input: " 2.5 - 4 ", from: 2.5, to: 4},
+		{input: "1-2-3", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "5-", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			from, to, err := parseChapterRange(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+		})
+	}
+}
+
+func TestModel_CanQueue(t *testing.T) {
+	m := testModel(3)
+	m.onDisk["c1"] = true
+	m.queued["c2"] = &downloads.DownloadItem{ChapterID: "c2"}
+
+	assert.False(t, m.canQueue(m.chapters[2]), "downloaded chapters are not queued again")
+	assert.False(t, m.canQueue(m.chapters[1]), "queued chapters are not queued again")
+	assert.True(t, m.canQueue(m.chapters[0]))
+
+	m.queued["c2"].Status = downloads.StatusFailed
+	assert.True(t, m.canQueue(m.chapters[1]), "failed chapters can be queued again")
+}
+
+func TestModel_QueueRetriesFailed(t *testing.T) {
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := storage.NewDownloadManager(db)
+	require.NoError(t, store.SaveQueued(&storage.QueuedDownload{
+		ID:         "m1-c2",
+		MangaID:    "m1",
+		ChapterID:  "c2",
+		SourceType: string(source.SourceTypeSuwayomi),
+		Status:     string(downloads.StatusFailed),
+		RetryCount: 3,
+		Error:      "page 1: not found",
+	}))
+
+	// Without connection slots the manager restores the queue but never
+	// starts a download
+	manager := downloads.NewManager(&downloads.DownloadConfig{DownloadPath: t.TempDir()}, source.NewSourceManager())
+	manager.SetStore(store)
+	manager.Start()
+	defer manager.Stop()
+
+	m := testModel(3)
+	m.downloadManager = manager
+	m, _ = m.Update(m.loadDownloadStates())
+	require.Equal(t, downloads.StatusFailed, m.queued["c2"].Status)
+
+	// Downloading all unread chapters retries the failed one
+	m, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, bulkDoneMsg{status: "Queued 3 chapters for download"}, cmd())
+
+	statuses := make(map[string]downloads.DownloadStatus)
+	for _, item := range manager.GetQueue() {
+		statuses[item.ChapterID] = item.Status
+	}
+	assert.Equal(t, map[string]downloads.DownloadStatus{
+		"c1": downloads.StatusQueued,
+		"c2": downloads.StatusQueued,
+		"c3": downloads.StatusQueued,
+	}, statuses)
+}
+
+func TestModel_Targets(t *testing.T) {
+	m := testModel(4)
+
+	// Without a selection the chapter under the cursor is the target
+	m.cursor = 1
+	assert.Equal(t, []string{"c3"}, chapterIDs(m.targets()))
+
+	// Selected chapters are returned in reading order
+	m.selected["c4"] = true
+	m.selected["c1"] = true
+	m.selected["c3"] = true
+	assert.Equal(t, []string{"c1", "c3", "c4"}, chapterIDs(m.targets()))
+
+	m = testModel(0)
+	assert.Empty(t, m.targets())
+}
+
+func TestModel_SubmitNextUnread(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   []string
+		status string
+	}{
+		{
+			name:  "limited to the entered number",
+			input: "2",
+			want:  []string{"c3", "c5"},
+		},
+		{
+			name:  "more than available",
+			input: "20",
+			want:  []string{"c3", "c5", "c7", "c8", "c9", "c10"},
+		},
+		{
+			name:  "default number",
+			input: "",
+			want:  []string{"c3", "c5", "c7", "c8", "c9"},
+		},
+		{
+			name:   "zero",
+			input:  "0",
+			status: "Invalid number: 0",
+		},
+		{
+			name:   "not a number",
+			input:  "1.5",
+			status: "Invalid number: 1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Never started, so it only queues
+			manager := downloads.NewManager(&downloads.DownloadConfig{DownloadPath: t.TempDir()}, source.NewSourceManager())
+
+			m := testModel(10)
+			m.downloadManager = manager
+			m.chapters[len(m.chapters)-1].IsRead = true // c1
+			m.readLocal["c2"] = true
+			m.onDisk["c4"] = true
+			m.queued["c6"] = &downloads.DownloadItem{ChapterID: "c6"}
+
+			m, cmd := m.submitPrompt(promptNextUnread, tt.input)
+			if tt.status != "" {
+				assert.Nil(t, cmd)
+				assert.Equal(t, tt.status, m.status)
+				assert.Empty(t, manager.GetQueue())
+				return
+			}
+
+			require.NotNil(t, cmd)
+			msg := cmd()
+			assert.Equal(t, bulkDoneMsg{status: fmt.Sprintf("Queued %d chapters for download", len(tt.want))}, msg)
+
+			var ids []string
+			for _, item := range manager.GetQueue() {
+				ids = append(ids, item.ChapterID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package manga
+
+import (
+	"fmt"
+
+	"github.com/Justice-Caban/Miryokusha/internal/export"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportDoneMsg reports the result of an export
+type exportDoneMsg struct {
+	path string
+	err  error
+}
+
+// handleExportKey handles keys while the export format prompt is open
+func (m Model) handleExportKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	var format export.Format
+	switch msg.String() {
+	case "e":
+		format = export.FormatEPUB
+	case "p":
+		format = export.FormatPDF
+	case "esc", "n", "q":
+		m.prompt = promptNone
+		return m, nil
+	default:
+		return m, nil
+	}
+
+	m.prompt = promptNone
+	chapters := m.targets()
+	m.status = fmt.Sprintf("Exporting %d chapters as %s...", len(chapters), format)
+	return m, m.exportChapters(chapters, format)
+}
+
+// selectVolume adds every chapter of the volume under the cursor to the
+// selection
+func (m *Model) selectVolume() {
+	if m.cursor >= len(m.chapters) {
+		return
+	}
+
+	volume := m.chapters[m.cursor].VolumeNumber
+	if volume <= 0 {
+		m.status = "Chapter has no volume number"
+		return
+	}
+	for _, c := range export.SelectVolume(m.chapters, volume) {
+		m.selected[c.ID] = true
+	}
+}
+
+// exportChapters writes the chapters to a single EPUB or PDF
+func (m Model) exportChapters(chapters []*source.Chapter, format export.Format) tea.Cmd {
+	exporter := m.exporter
+	manga := m.manga
+	return func() tea.Msg {
+		if exporter == nil {
+			return exportDoneMsg{err: fmt.Errorf("exports are unavailable")}
+		}
+		path, err := exporter.Export(manga, chapters, export.Options{Format: format})
+		return exportDoneMsg{path: path, err: err}
+	}
+}
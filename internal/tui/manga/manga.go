@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/export"
 	"github.com/Justice-Caban/Miryokusha/internal/source"
 	"github.com/Justice-Caban/Miryokusha/internal/storage"
 	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
@@ -17,36 +19,57 @@ type Model struct {
 	height int
 
 	// Data
-	manga    *source.Manga
-	chapters []*source.Chapter
+	manga     *source.Manga
+	chapters  []*source.Chapter
+	onDisk    map[string]bool                    // Chapter IDs in the local download index
+	queued    map[string]*downloads.DownloadItem // Chapters queued or downloading
+	readLocal map[string]bool                    // Chapter IDs read according to local progress
 
 	// UI state
-	cursor  int
-	offset  int
-	loading bool
-	err     error
+	cursor   int
+	offset   int
+	loading  bool
+	err      error
+	selected map[string]bool // Chapter IDs selected for bulk actions
+	prompt   promptKind
+	input    string
+	status   string
+	ticking  bool                   // A download state refresh is scheduled
+	schedule storage.UpdateSchedule // Update schedule of the manga itself
 
 	// Dependencies
-	sourceManager *source.SourceManager
-	storage       *storage.Storage
+	sourceManager   *source.SourceManager
+	storage         *storage.Storage
+	downloadManager *downloads.Manager
+	exporter        *export.Exporter
 }
 
 // NewModel creates a new manga details model
-func NewModel(manga *source.Manga, sm *source.SourceManager, st *storage.Storage) Model {
+func NewModel(manga *source.Manga, sm *source.SourceManager, st *storage.Storage, dm *downloads.Manager, exp *export.Exporter) Model {
 	return Model{
-		manga:         manga,
-		chapters:      nil,
-		cursor:        0,
-		offset:        0,
-		loading:       true,
-		sourceManager: sm,
-		storage:       st,
+		manga:           manga,
+		chapters:        nil,
+		cursor:          0,
+		offset:          0,
+		loading:         true,
+		selected:        make(map[string]bool),
+		sourceManager:   sm,
+		storage:         st,
+		downloadManager: dm,
+		exporter:        exp,
 	}
 }
 
 // Init initializes the manga details model
 func (m Model) Init() tea.Cmd {
-	return m.loadChapters
+	return tea.Batch(m.loadChapters, m.loadDownloadStates, m.loadUpdateSchedule)
+}
+
+// CapturesKeys reports whether the view needs keys that are otherwise
+// handled globally (esc, q), e.g. while a prompt is open or chapters are
+// selected
+func (m Model) CapturesKeys() bool {
+	return m.prompt != promptNone || len(m.selected) > 0
 }
 
 // Update handles messages for the manga details view
@@ -62,9 +85,42 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	case chaptersLoadedMsg:
 		m.chapters = msg.chapters
+		m.onDisk = msg.onDisk
+		m.readLocal = msg.readLocal
 		m.loading = false
 		m.err = msg.err
 		return m, nil
+
+	case downloadStatesMsg:
+		m.queued = msg.queued
+		m.onDisk = msg.onDisk
+		// Keep refreshing while chapters of this manga are in the queue
+		if len(m.queued) > 0 && !m.ticking {
+			m.ticking = true
+			return m, tickDownloads()
+		}
+		return m, nil
+
+	case downloadTickMsg:
+		m.ticking = false
+		return m, m.loadDownloadStates
+
+	case bulkDoneMsg:
+		m.status = msg.status
+		m.selected = make(map[string]bool)
+		return m, m.loadDownloadStates
+
+	case updateScheduleMsg:
+		return m.handleUpdateSchedule(msg)
+
+	case exportDoneMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Export failed: %v", msg.err)
+		} else {
+			m.status = fmt.Sprintf("Exported to %s", msg.path)
+			m.selected = make(map[string]bool)
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -76,6 +132,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.prompt != promptNone {
+		return m.handlePromptKey(msg)
+	}
+
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -111,7 +171,74 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case "r":
 		// Refresh chapter list
 		m.loading = true
-		return m, m.loadChapters
+		return m, tea.Batch(m.loadChapters, m.loadDownloadStates)
+
+	case " ":
+		// Toggle selection and move to the next chapter
+		if m.cursor < len(m.chapters) {
+			id := m.chapters[m.cursor].ID
+			if m.selected[id] {
+				delete(m.selected, id)
+			} else {
+				m.selected[id] = true
+			}
+			if m.cursor < len(m.chapters)-1 {
+				m.cursor++
+				m.adjustOffset()
+			}
+		}
+
+	case "esc":
+		// Only reached with a selection, which esc clears
+		m.selected = make(map[string]bool)
+
+	case "d":
+		// Download the selected chapters, or the one under the cursor
+		var chapters []*source.Chapter
+		for _, c := range m.targets() {
+			if m.canQueue(c) {
+				chapters = append(chapters, c)
+			}
+		}
+		return m, m.queueChapters(chapters)
+
+	case "n":
+		// Download the next N unread chapters
+		m.prompt = promptNextUnread
+		m.input = ""
+
+	case "u":
+		// Download all unread chapters
+		return m, m.queueChapters(m.unreadToDownload())
+
+	case "R":
+		// Download a range of chapters
+		m.prompt = promptRange
+		m.input = ""
+
+	case "x":
+		// Delete downloaded chapters after confirmation
+		for _, c := range m.targets() {
+			if m.onDisk[c.ID] {
+				m.prompt = promptDelete
+				return m, nil
+			}
+		}
+		m.status = "No downloaded chapters to delete"
+
+	case "V":
+		// Select the volume of the chapter under the cursor
+		m.selectVolume()
+
+	case "e":
+		// Export the selected chapters, or the one under the cursor
+		if len(m.targets()) > 0 {
+			m.prompt = promptExport
+		}
+
+	case "U":
+		// Cycle how often this manga is checked for new chapters
+		return m, m.cycleUpdateSchedule()
 	}
 
 	return m, nil
@@ -197,11 +324,23 @@ func (m Model) renderHeader() string {
 	}
 	info = append(info, chapterCountStr)
 
+	if !m.schedule.IsDefault() {
+		info = append(info, fmt.Sprintf("Updates: %s", m.schedule))
+	}
+
+	if len(m.selected) > 0 {
+		info = append(info, fmt.Sprintf("%d selected", len(m.selected)))
+	}
+
 	infoStr := lipgloss.NewStyle().
 		Foreground(theme.ColorSecondary).
 		Render(strings.Join(info, " • "))
 
-	return title + "\n" + infoStr
+	header := title + "\n" + infoStr
+	if m.status != "" {
+		header += "\n" + theme.HelpStyle.Render(m.status)
+	}
+	return header
 }
 
 // renderChapterList renders the list of chapters
@@ -242,17 +381,24 @@ func (m Model) renderChapterList() string {
 				Width(m.width - 8)
 		}
 
+		// Selection marker
+		selectMarker := "  "
+		if m.selected[chapter.ID] {
+			selectMarker = "● "
+		}
+
 		// Read indicator
 		readIndicator := "  "
-		if chapter.IsRead {
+		if m.isRead(chapter) {
 			readIndicator = "✓ "
 		}
 
-		// Downloaded indicator
+		// Downloaded indicator: on the server, and the local download state
 		downloadIndicator := ""
 		if chapter.IsDownloaded {
 			downloadIndicator = " 📥"
 		}
+		downloadIndicator += m.downloadIndicator(chapter)
 
 		// Bookmarked indicator
 		bookmarkIndicator := ""
@@ -267,7 +413,8 @@ func (m Model) renderChapterList() string {
 		}
 
 		// Build line
-		line := fmt.Sprintf("%s %s%s%s",
+		line := fmt.Sprintf("%s%s %s%s%s",
+			selectMarker,
 			readIndicator,
 			chapterTitle,
 			downloadIndicator,
@@ -292,10 +439,40 @@ func (m Model) renderChapterList() string {
 
 // renderFooter renders the footer with controls
 func (m Model) renderFooter() string {
+	switch m.prompt {
+	case promptNextUnread:
+		return theme.WarningStyle.Render(fmt.Sprintf("Download next unread chapters (default %d): %s_", defaultNextUnread, m.input)) +
+			"\n" + theme.HelpStyle.Render("Enter: download • Esc: cancel")
+	case promptRange:
+		return theme.WarningStyle.Render(fmt.Sprintf("Download chapter range (e.g. 10-20): %s_", m.input)) +
+			"\n" + theme.HelpStyle.Render("Enter: download • Esc: cancel")
+	case promptDelete:
+		count := 0
+		for _, c := range m.targets() {
+			if m.onDisk[c.ID] {
+				count++
+			}
+		}
+		return theme.WarningStyle.Render(fmt.Sprintf("Delete %d downloaded chapters from disk?", count)) +
+			"\n" + theme.HelpStyle.Render("y/Enter: delete • n/Esc: cancel")
+	case promptExport:
+		return theme.WarningStyle.Render(fmt.Sprintf("Export %d chapters as one book", len(m.targets()))) +
+			"\n" + theme.HelpStyle.Render("e: EPUB • p: PDF • Esc: cancel")
+	}
+
 	controls := []string{
 		"↑↓/jk: navigate",
 		"g/G: top/bottom",
 		"Enter: read chapter",
+		"Space: select",
+		"d: download",
+		"n: next N unread",
+		"u: all unread",
+		"R: range",
+		"x: delete download",
+		"V: select volume",
+		"e: export",
+		"U: update schedule",
 		"r: refresh",
 		"Esc: back",
 	}
@@ -306,8 +483,10 @@ func (m Model) renderFooter() string {
 // Messages
 
 type chaptersLoadedMsg struct {
-	chapters []*source.Chapter
-	err      error
+	chapters  []*source.Chapter
+	onDisk    map[string]bool
+	readLocal map[string]bool
+	err       error
 }
 
 // OpenChapterMsg is sent when a chapter should be opened in the reader
@@ -344,9 +523,25 @@ func (m Model) loadChapters() tea.Msg {
 		}
 	}
 
+	// Look up which chapters are stored locally and read locally
+	var onDisk map[string]bool
+	readLocal := make(map[string]bool)
+	if m.storage != nil {
+		onDisk, _ = m.storage.Downloads.GetDownloadedChapterIDs(m.manga.ID)
+		if entries, err := m.storage.Progress.GetMangaProgress(m.manga.ID); err == nil {
+			for _, entry := range entries {
+				if entry.IsCompleted {
+					readLocal[entry.ChapterID] = true
+				}
+			}
+		}
+	}
+
 	return chaptersLoadedMsg{
-		chapters: chapters,
-		err:      nil,
+		chapters:  chapters,
+		onDisk:    onDisk,
+		readLocal: readLocal,
+		err:       nil,
 	}
 }
 
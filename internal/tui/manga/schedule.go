@@ -0,0 +1,70 @@
+package manga
+
+import (
+	"fmt"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/updates"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateScheduleMsg carries the update schedule set for the manga itself
+type updateScheduleMsg struct {
+	schedule storage.UpdateSchedule
+	changed  bool // The schedule was just set
+	err      error
+}
+
+// ScheduleChangedMsg is sent when the update schedule of a manga changed
+type ScheduleChangedMsg struct{}
+
+// handleUpdateSchedule applies a loaded or changed update schedule
+func (m Model) handleUpdateSchedule(msg updateScheduleMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Failed to set update schedule: %v", msg.err)
+		return m, nil
+	}
+
+	m.schedule = msg.schedule
+	if !msg.changed {
+		return m, nil
+	}
+
+	switch {
+	case m.schedule.IsDefault():
+		m.status = "Updates follow the manga's categories"
+	case m.schedule.Mode == storage.UpdateModeNever:
+		m.status = "Excluded from library updates"
+	default:
+		m.status = fmt.Sprintf("Checked for new chapters %s", m.schedule)
+	}
+	return m, func() tea.Msg { return ScheduleChangedMsg{} }
+}
+
+// loadUpdateSchedule loads the update schedule set for the manga itself
+func (m Model) loadUpdateSchedule() tea.Msg {
+	if m.storage == nil {
+		return nil
+	}
+	schedule, err := m.storage.Categories.GetMangaUpdateSchedule(m.manga.ID)
+	if err != nil {
+		// The header just shows no schedule
+		return nil
+	}
+	return updateScheduleMsg{schedule: schedule}
+}
+
+// cycleUpdateSchedule sets the manga's update schedule to the next preset,
+// overriding its categories
+func (m Model) cycleUpdateSchedule() tea.Cmd {
+	schedule := updates.NextSchedule(m.schedule)
+	return func() tea.Msg {
+		if m.storage == nil {
+			return updateScheduleMsg{err: fmt.Errorf("storage not available")}
+		}
+		if err := m.storage.Categories.SetMangaUpdateSchedule(m.manga.ID, schedule); err != nil {
+			return updateScheduleMsg{err: err}
+		}
+		return updateScheduleMsg{schedule: schedule, changed: true}
+	}
+}
@@ -0,0 +1,360 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/hooks"
+	"github.com/Justice-Caban/Miryokusha/internal/notify"
+	"github.com/Justice-Caban/Miryokusha/internal/server"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/manga"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notificationEventBuffer is the number of notifications queued for the TUI
+const notificationEventBuffer = 64
+
+// serverCheckInterval is the time between checks of the server's
+// availability, reported when it changes
+const serverCheckInterval = 30 * time.Second
+
+// notificationFeed stores notifications raised by background work and
+// tells the TUI and the configured notifiers about them. Library events are
+// also fired to the configured hooks.
+type notificationFeed struct {
+	store      *storage.NotificationsManager
+	events     chan tea.Msg
+	dispatcher *notify.Dispatcher
+	hooks      *hooks.Dispatcher
+}
+
+// newNotificationFeed creates a feed storing notifications in st, if set.
+// A notifier that cannot be set up is reported in errors.
+func newNotificationFeed(st *storage.Storage, cfg config.NotificationsConfig, errors *ErrorNotificationList) *notificationFeed {
+	feed := &notificationFeed{events: make(chan tea.Msg, notificationEventBuffer)}
+	if st != nil {
+		feed.store = st.Notifications
+	}
+
+	routes := notifierRoutes(cfg, errors)
+	if len(routes) > 0 {
+		feed.dispatcher = notify.NewDispatcher(routes, max(cfg.RateLimit, 0), time.Minute)
+		feed.dispatcher.SetErrorHandler(feed.notifierFailed)
+	}
+	return feed
+}
+
+// notifierRoutes creates the enabled notifiers. The bell is only used when
+// no other notifier is available.
+func notifierRoutes(cfg config.NotificationsConfig, errors *ErrorNotificationList) []notify.Route {
+	var routes []notify.Route
+
+	if cfg.Terminal.Enabled {
+		terminal, err := notify.NewTerminalNotifier(os.Stdout, notify.Protocol(cfg.Terminal.Protocol), os.Getenv("TMUX") != "")
+		if err != nil {
+			errors.AddError("Terminal Notifications Disabled", err.Error(), "Check notifications.terminal in config.yaml.", SeverityWarning)
+		} else {
+			routes = append(routes, notify.Route{Notifier: terminal, Events: notifierEvents(cfg.Terminal.NotifierConfig)})
+		}
+	}
+
+	if cfg.NotifySend.Enabled {
+		// notify-send is optional; without it the bell takes over
+		if notifySend, err := notify.NewNotifySendNotifier(); err == nil {
+			routes = append(routes, notify.Route{Notifier: notifySend, Events: notifierEvents(cfg.NotifySend)})
+		}
+	}
+
+	if len(routes) == 0 && cfg.Bell.Enabled {
+		routes = append(routes, notify.Route{Notifier: notify.NewBellNotifier(os.Stdout), Events: notifierEvents(cfg.Bell)})
+	}
+	return routes
+}
+
+// notifierEvents returns the events a notifier delivers
+func notifierEvents(cfg config.NotifierConfig) map[notify.Event]bool {
+	events := make(map[notify.Event]bool)
+	for _, event := range cfg.DeliveredEvents() {
+		events[notify.Event(event)] = true
+	}
+	return events
+}
+
+// notifierFailed keeps a notifier's failure in the notification center. It
+// is not delivered to the notifiers, which could fail again.
+func (f *notificationFeed) notifierFailed(notifier notify.Notifier, err error) {
+	if f.store == nil {
+		return
+	}
+	n := &storage.Notification{
+		Kind:     storage.NotifyError,
+		Severity: storage.SeverityWarning,
+		Title:    "Notification Failed",
+		Message:  fmt.Sprintf("%s: %v", notifier.Name(), err),
+	}
+	if added, err := f.store.AddUnlessUnread(n); err == nil && added {
+		f.signal(n)
+	}
+}
+
+// Record stores a notification and tells the TUI about it
+func (f *notificationFeed) Record(n *storage.Notification) {
+	if f.store != nil {
+		// A notification that could not be stored is still shown
+		_ = f.store.Add(n)
+	}
+	f.announce(n)
+}
+
+// announce tells the TUI and the notifiers about a notification that is
+// already stored
+func (f *notificationFeed) announce(n *storage.Notification) {
+	if f.dispatcher != nil {
+		f.dispatcher.Notify(notify.Message{
+			Event: notify.Event(n.Kind),
+			Title: n.Title,
+			Body:  n.Message,
+		})
+	}
+	f.signal(n)
+}
+
+// signal tells the TUI about a notification
+func (f *notificationFeed) signal(n *storage.Notification) {
+	// The unread count is read from the store, so dropping an event when
+	// the TUI falls behind only delays it
+	select {
+	case f.events <- notificationAddedMsg{notification: n}:
+	default:
+	}
+}
+
+// watchDownloads records downloads that completed or failed for good
+func (f *notificationFeed) watchDownloads(dm *downloads.Manager) {
+	dm.SetCallbacks(nil, f.downloadCompleted, f.downloadFailed)
+}
+
+// downloadCompleted records a finished download and fires its hooks
+func (f *notificationFeed) downloadCompleted(item *downloads.DownloadItem) {
+	f.hooks.Fire(downloadEvent(hooks.EventDownloadComplete, item, nil))
+	f.Record(&storage.Notification{
+		Kind:      storage.NotifyDownloadComplete,
+		Title:     item.MangaTitle,
+		Message:   fmt.Sprintf("Downloaded %s", item.ChapterName),
+		MangaID:   item.MangaID,
+		ChapterID: item.ChapterID,
+	})
+}
+
+// downloadFailed records a download that failed for good and fires its hooks
+func (f *notificationFeed) downloadFailed(item *downloads.DownloadItem, err error) {
+	f.hooks.Fire(downloadEvent(hooks.EventDownloadFailed, item, err))
+	f.Record(&storage.Notification{
+		Kind:       storage.NotifyDownloadFailed,
+		Severity:   storage.SeverityError,
+		Title:      item.MangaTitle,
+		Message:    fmt.Sprintf("Download of %s failed: %v", item.ChapterName, err),
+		Suggestion: "Retry it from the Downloads view",
+		MangaID:    item.MangaID,
+		ChapterID:  item.ChapterID,
+	})
+}
+
+// watchServer records when the managed server starts, stops or fails
+func (f *notificationFeed) watchServer(sm *server.Manager) {
+	sm.SetCallbacks(func(status server.ServerStatus) {
+		n := &storage.Notification{Kind: storage.NotifyServerStatus}
+		switch status {
+		case server.StatusRunning:
+			n.Title = "Server Started"
+			n.Message = "The managed Suwayomi server is running"
+		case server.StatusStopped:
+			n.Title = "Server Stopped"
+			n.Message = "The managed Suwayomi server was stopped"
+		case server.StatusError:
+			n.Severity = storage.SeverityError
+			n.Title = "Server Failed"
+			n.Message = "The managed Suwayomi server exited with an error"
+			n.Suggestion = "Check the server logs in Settings"
+		default:
+			return
+		}
+		f.Record(n)
+	}, nil)
+}
+
+// listenForNotifications waits for the next notification from background
+// work
+func listenForNotifications(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// countUnreadNotifications counts the unread notifications for the home
+// screen and status bar
+func countUnreadNotifications(st *storage.Storage) tea.Cmd {
+	return func() tea.Msg {
+		if st == nil {
+			return nil
+		}
+		count, err := st.Notifications.CountUnread()
+		if err != nil {
+			return nil
+		}
+		return unreadNotificationsMsg{count: count}
+	}
+}
+
+// pruneNotifications removes notifications past the configured retention
+func pruneNotifications(st *storage.Storage, cfg config.NotificationsConfig) tea.Cmd {
+	return func() tea.Msg {
+		if st == nil {
+			return nil
+		}
+		var before time.Time
+		if cfg.RetentionDays > 0 {
+			before = time.Now().AddDate(0, 0, -cfg.RetentionDays)
+		}
+		// Old notifications are pruned again on the next start
+		_, _ = st.Notifications.Prune(before, max(cfg.MaxCount, 0))
+		return nil
+	}
+}
+
+// checkServerStatus checks whether the server is reachable after a delay
+func checkServerStatus(client *suwayomi.Client, after time.Duration) tea.Cmd {
+	return tea.Tick(after, func(time.Time) tea.Msg {
+		return serverStatusMsg{available: client.IsAvailable()}
+	})
+}
+
+// handleServerStatus records when the server becomes unreachable or
+// reachable again, then schedules the next check
+func (m AppModel) handleServerStatus(msg serverStatusMsg) (AppModel, tea.Cmd) {
+	if m.serverChecked && msg.available != m.serverAvailable {
+		n := &storage.Notification{Kind: storage.NotifyServerStatus}
+		event := hooks.Event{Type: hooks.EventServerUp}
+		if msg.available {
+			n.Title = "Server Reconnected"
+			n.Message = "The Suwayomi server is reachable again"
+		} else {
+			n.Severity = storage.SeverityWarning
+			n.Title = "Server Unreachable"
+			n.Message = "The Suwayomi server stopped responding"
+			n.Suggestion = "Check that the server is running. Details are in Settings → Server Health."
+			event.Type = hooks.EventServerDown
+		}
+		event.Title, event.Message = n.Title, n.Message
+		m.notifyFeed.hooks.Fire(event)
+		m.notifyFeed.Record(n)
+	}
+	m.serverChecked = true
+	m.serverAvailable = msg.available
+	return m, checkServerStatus(m.suwayomiClient, serverCheckInterval)
+}
+
+// checkExtensionUpdates records installed extensions with an update. An
+// update is reported again only once its notification was read.
+func checkExtensionUpdates(client *suwayomi.Client, feed *notificationFeed) tea.Cmd {
+	return func() tea.Msg {
+		if feed.store == nil {
+			return nil
+		}
+		extensions, err := client.ListAvailableExtensions()
+		if err != nil {
+			return nil
+		}
+		for _, ext := range extensions {
+			if !ext.IsInstalled || !ext.HasUpdate {
+				continue
+			}
+			n := &storage.Notification{
+				Kind:       storage.NotifyExtensionUpdate,
+				Title:      ext.Name,
+				Message:    fmt.Sprintf("An update is available for %s %s", ext.Name, ext.VersionName),
+				Suggestion: "Update it from the Extensions view",
+			}
+			if added, err := feed.store.AddUnlessUnread(n); err == nil && added {
+				feed.announce(n)
+			}
+		}
+		return nil
+	}
+}
+
+// jumpTo opens what a notification is about
+func (m AppModel) jumpTo(n *storage.Notification) (AppModel, tea.Cmd) {
+	switch n.Kind {
+	case storage.NotifyNewChapters, storage.NotifyUpdateFailed:
+		if n.MangaID != "" {
+			return m.openManga(n.MangaID, n.Title)
+		}
+	case storage.NotifyDownloadComplete, storage.NotifyDownloadFailed:
+		m.currentView = ViewHome
+		return m.navigateToView(ViewDownloads)
+	case storage.NotifyExtensionUpdate:
+		m.currentView = ViewHome
+		return m.navigateToView(ViewExtensions)
+	case storage.NotifyServerStatus, storage.NotifyError:
+		m.currentView = ViewHome
+		return m.navigateToView(ViewSettings)
+	}
+	return m, nil
+}
+
+// openManga opens the details of a manga known only by its ID, loading them
+// from the source
+func (m AppModel) openManga(mangaID, mangaTitle string) (AppModel, tea.Cmd) {
+	var details *source.Manga
+
+	src := m.sourceManager.GetSource(mangaID)
+	if src == nil {
+		// Try to find a source by type (assume Suwayomi for now)
+		if sources := m.sourceManager.GetSourcesByType(source.SourceTypeSuwayomi); len(sources) > 0 {
+			src = sources[0]
+		}
+	}
+	if src != nil {
+		if fetched, err := src.GetManga(mangaID); err == nil && fetched != nil {
+			details = fetched
+		}
+	}
+
+	// Fallback to a minimal manga if the fetch failed
+	if details == nil {
+		details = &source.Manga{
+			ID:         mangaID,
+			Title:      mangaTitle,
+			SourceType: source.SourceTypeSuwayomi,
+		}
+	}
+
+	mangaModel := manga.NewModel(details, m.sourceManager, m.storage, m.downloadManager, m.exporter)
+	m.mangaModel = &mangaModel
+	m.currentView = ViewManga
+	return m, m.mangaModel.Init()
+}
+
+// Messages
+
+// notificationAddedMsg is sent when background work records a notification
+type notificationAddedMsg struct {
+	notification *storage.Notification
+}
+
+// unreadNotificationsMsg carries the number of unread notifications
+type unreadNotificationsMsg struct {
+	count int
+}
+
+// serverStatusMsg carries whether the server is reachable
+type serverStatusMsg struct {
+	available bool
+}
@@ -0,0 +1,489 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listLimit is the number of notifications loaded into the view
+const listLimit = 500
+
+// Model represents the notification center view model
+type Model struct {
+	width  int
+	height int
+
+	// Data
+	notifications []*storage.Notification
+
+	// UI state
+	cursor     int
+	offset     int
+	filter     int // Index into filters
+	unreadOnly bool
+	status     string
+
+	// Dependencies
+	store *storage.NotificationsManager
+
+	// Loading state
+	loading bool
+	err     error
+}
+
+// filters are the kinds the view can be filtered by; empty shows every kind
+var filters = append([]storage.NotificationKind{""}, storage.NotificationKinds...)
+
+// NewModel creates a new notification center model
+func NewModel(st *storage.Storage) Model {
+	var store *storage.NotificationsManager
+	if st != nil {
+		store = st.Notifications
+	}
+	return Model{
+		store:   store,
+		loading: true,
+	}
+}
+
+// Init initializes the notification center model
+func (m Model) Init() tea.Cmd {
+	return m.loadData
+}
+
+// Update handles messages for the notification center view
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKeyPress(msg)
+
+	case notificationsLoadedMsg:
+		m.notifications = msg.notifications
+		m.loading = false
+		m.err = nil
+		if m.cursor >= len(m.notifications) {
+			m.cursor = max(len(m.notifications)-1, 0)
+		}
+		m.adjustOffset()
+		return m, nil
+
+	case notificationsErrorMsg:
+		m.err = msg.err
+		m.loading = false
+		return m, nil
+
+	case ChangedMsg:
+		m.status = msg.Status
+		return m, m.loadData
+	}
+
+	return m, nil
+}
+
+// handleKeyPress handles keyboard input
+func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.adjustOffset()
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.notifications)-1 {
+			m.cursor++
+			m.adjustOffset()
+		}
+
+	case "g":
+		m.cursor = 0
+		m.offset = 0
+
+	case "G":
+		if len(m.notifications) > 0 {
+			m.cursor = len(m.notifications) - 1
+			m.adjustOffset()
+		}
+
+	case "tab", "f":
+		// Next kind
+		m.filter = (m.filter + 1) % len(filters)
+		return m.reload()
+
+	case "shift+tab", "F":
+		// Previous kind
+		m.filter = (m.filter + len(filters) - 1) % len(filters)
+		return m.reload()
+
+	case "u":
+		// Toggle unread only
+		m.unreadOnly = !m.unreadOnly
+		return m.reload()
+
+	case "r":
+		// Refresh data
+		m.loading = true
+		m.status = ""
+		return m, m.loadData
+
+	case "enter":
+		// Jump to what the notification is about, marking it read
+		if n := m.selected(); n != nil {
+			store := m.store
+			return m, func() tea.Msg {
+				if !n.Read && store != nil {
+					// Jumping works even if the read state is not saved
+					_ = store.SetRead(n.ID, true)
+				}
+				return JumpMsg{Notification: n}
+			}
+		}
+
+	case " ", "m":
+		// Toggle read
+		if n := m.selected(); n != nil {
+			return m, m.setRead(n, !n.Read)
+		}
+
+	case "M":
+		// Mark every notification of the filter read
+		return m, m.markAllRead(filters[m.filter])
+
+	case "x":
+		// Delete the notification
+		if n := m.selected(); n != nil {
+			return m, m.delete(n)
+		}
+	}
+
+	return m, nil
+}
+
+// reload loads the notifications again from the top, e.g. after the filter
+// changed
+func (m Model) reload() (Model, tea.Cmd) {
+	m.cursor = 0
+	m.offset = 0
+	m.status = ""
+	return m, m.loadData
+}
+
+// selected returns the notification under the cursor, or nil
+func (m Model) selected() *storage.Notification {
+	if m.cursor >= 0 && m.cursor < len(m.notifications) {
+		return m.notifications[m.cursor]
+	}
+	return nil
+}
+
+// visibleItems returns the number of notifications that fit on screen; each
+// takes two lines
+func (m Model) visibleItems() int {
+	return max((m.height-12)/2, 1)
+}
+
+// adjustOffset adjusts the scroll offset to keep cursor visible
+func (m *Model) adjustOffset() {
+	visible := m.visibleItems()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+// View renders the notification center view
+func (m Model) View() string {
+	if m.loading {
+		return theme.CenteredText(m.width, m.height, "Loading notifications...")
+	}
+
+	if m.err != nil {
+		return theme.CenteredText(m.width, m.height, fmt.Sprintf("Error: %v", m.err))
+	}
+
+	var b strings.Builder
+
+	// Header
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderList())
+	b.WriteString("\n")
+
+	if m.status != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.ColorSecondary).Render(m.status))
+		b.WriteString("\n")
+	}
+
+	// Footer
+	b.WriteString(m.renderFooter())
+
+	// Apply consistent horizontal padding/centering
+	content := b.String()
+	maxWidth := 120
+	if m.width < maxWidth {
+		maxWidth = m.width - 4
+	}
+
+	contentStyle := lipgloss.NewStyle().
+		Width(maxWidth).
+		Padding(0, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Top,
+		contentStyle.Render(content),
+	)
+}
+
+// renderHeader renders the title and the filter tabs
+func (m Model) renderHeader() string {
+	title := theme.TitleStyle.Render("Notifications")
+
+	activeStyle := lipgloss.NewStyle().
+		Foreground(theme.ColorPrimary).
+		Bold(true).
+		Underline(true)
+	inactiveStyle := lipgloss.NewStyle().Foreground(theme.ColorMuted)
+
+	tabs := make([]string, len(filters))
+	for i, kind := range filters {
+		style := inactiveStyle
+		if i == m.filter {
+			style = activeStyle
+		}
+		tabs[i] = style.Render(KindLabel(kind))
+	}
+
+	scope := "All"
+	if m.unreadOnly {
+		scope = "Unread only"
+	}
+	info := lipgloss.NewStyle().
+		Foreground(theme.ColorMuted).
+		Render(fmt.Sprintf("%s · %d shown", scope, len(m.notifications)))
+
+	return title + "\n" + strings.Join(tabs, "  ") + "\n" + info
+}
+
+// renderList renders the notifications
+func (m Model) renderList() string {
+	if len(m.notifications) == 0 {
+		return theme.CenteredText(m.width, m.height-12, "No notifications")
+	}
+
+	var b strings.Builder
+	visible := m.visibleItems()
+	now := time.Now()
+
+	for i, n := range m.notifications {
+		if i < m.offset || i >= m.offset+visible {
+			continue
+		}
+		b.WriteString(m.renderNotification(n, i == m.cursor, now))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderNotification renders a notification on two lines: its title, then
+// its message
+func (m Model) renderNotification(n *storage.Notification, isCursor bool, now time.Time) string {
+	marker := "●"
+	if n.Read {
+		marker = " "
+	}
+
+	icon, color := severityIcon(n.Severity)
+	title := fmt.Sprintf("%s %s %s", marker, lipgloss.NewStyle().Foreground(color).Render(icon), n.Title)
+	meta := lipgloss.NewStyle().
+		Foreground(theme.ColorMuted).
+		Render(fmt.Sprintf("  %s · %s", KindLabel(n.Kind), formatAge(n.CreatedAt, now)))
+
+	message := n.Message
+	if n.Suggestion != "" {
+		message += " → " + n.Suggestion
+	}
+
+	titleStyle := lipgloss.NewStyle()
+	messageStyle := lipgloss.NewStyle().Foreground(theme.ColorMuted).PaddingLeft(4)
+	if isCursor {
+		titleStyle = titleStyle.
+			Background(theme.ColorPrimary).
+			Foreground(lipgloss.Color("#000000")).
+			Bold(true).
+			Width(m.width - 4)
+	} else if n.Read {
+		titleStyle = titleStyle.Foreground(theme.ColorMuted)
+	}
+
+	return titleStyle.Render(title+meta) + "\n" + messageStyle.Render(message)
+}
+
+// renderFooter renders the footer with controls
+func (m Model) renderFooter() string {
+	controls := []string{
+		"↑↓/jk: navigate",
+		"Tab/f: filter",
+		"u: unread only",
+		"Enter: open",
+		"m: read/unread",
+		"M: mark all read",
+		"x: delete",
+		"r: refresh",
+		"Esc: back",
+	}
+	return theme.HelpStyle.Render(strings.Join(controls, " • "))
+}
+
+// KindLabel names a notification kind for display; the empty kind is every
+// kind
+func KindLabel(kind storage.NotificationKind) string {
+	switch kind {
+	case "":
+		return "All"
+	case storage.NotifyNewChapters:
+		return "New chapters"
+	case storage.NotifyUpdateFailed:
+		return "Update failures"
+	case storage.NotifyDownloadComplete:
+		return "Downloaded"
+	case storage.NotifyDownloadFailed:
+		return "Download failures"
+	case storage.NotifyServerStatus:
+		return "Server"
+	case storage.NotifyExtensionUpdate:
+		return "Extensions"
+	case storage.NotifyError:
+		return "Errors"
+	default:
+		return string(kind)
+	}
+}
+
+// severityIcon returns the icon and color of a severity
+func severityIcon(severity storage.NotificationSeverity) (string, lipgloss.Color) {
+	switch severity {
+	case storage.SeverityWarning:
+		return "⚠", theme.ColorWarning
+	case storage.SeverityError:
+		return "✗", theme.ColorError
+	case storage.SeverityCritical:
+		return "🛑", theme.ColorError
+	default:
+		return "ℹ", theme.ColorSecondary
+	}
+}
+
+// formatAge formats how long ago a notification was created
+func formatAge(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	case age < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	default:
+		return t.Local().Format("Jan 2, 2006")
+	}
+}
+
+// Messages
+
+type notificationsLoadedMsg struct {
+	notifications []*storage.Notification
+}
+
+type notificationsErrorMsg struct {
+	err error
+}
+
+// ChangedMsg is sent when notifications were read, marked unread or
+// deleted, so the unread count can be refreshed
+type ChangedMsg struct {
+	Status string
+}
+
+// JumpMsg is sent to open what a notification is about
+type JumpMsg struct {
+	Notification *storage.Notification
+}
+
+// Commands
+
+func (m Model) loadData() tea.Msg {
+	if m.store == nil {
+		return notificationsErrorMsg{err: fmt.Errorf("storage not available")}
+	}
+
+	notifications, err := m.store.List(storage.NotificationFilter{
+		Kind:       filters[m.filter],
+		UnreadOnly: m.unreadOnly,
+		Limit:      listLimit,
+	})
+	if err != nil {
+		return notificationsErrorMsg{err: fmt.Errorf("failed to load notifications: %w", err)}
+	}
+	return notificationsLoadedMsg{notifications: notifications}
+}
+
+// setRead marks a notification read or unread
+func (m Model) setRead(n *storage.Notification, read bool) tea.Cmd {
+	store := m.store
+	return func() tea.Msg {
+		if store == nil {
+			return nil
+		}
+		if err := store.SetRead(n.ID, read); err != nil {
+			return ChangedMsg{Status: fmt.Sprintf("Failed to update notification: %v", err)}
+		}
+		return ChangedMsg{}
+	}
+}
+
+// markAllRead marks every notification of a kind read
+func (m Model) markAllRead(kind storage.NotificationKind) tea.Cmd {
+	store := m.store
+	return func() tea.Msg {
+		if store == nil {
+			return nil
+		}
+		if err := store.MarkAllRead(kind); err != nil {
+			return ChangedMsg{Status: fmt.Sprintf("Failed to mark notifications read: %v", err)}
+		}
+		return ChangedMsg{Status: "Marked all read"}
+	}
+}
+
+// delete removes a notification
+func (m Model) delete(n *storage.Notification) tea.Cmd {
+	store := m.store
+	return func() tea.Msg {
+		if store == nil {
+			return nil
+		}
+		if err := store.Delete(n.ID); err != nil {
+			return ChangedMsg{Status: fmt.Sprintf("Failed to delete notification: %v", err)}
+		}
+		return ChangedMsg{}
+	}
+}
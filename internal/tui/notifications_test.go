@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/hooks"
+	"github.com/Justice-Caban/Miryokusha/internal/notify"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationFeed_DownloadHooks(t *testing.T) {
+	var mu sync.Mutex
+	var received []hooks.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event hooks.Event
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &event))
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	dispatcher, err := hooks.NewDispatcher([]hooks.Hook{{
+		Name:   "downloads",
+		URL:    server.URL,
+		Events: map[hooks.EventType]bool{hooks.EventDownloadComplete: true, hooks.EventDownloadFailed: true},
+	}}, nil)
+	require.NoError(t, err)
+	feed := &notificationFeed{events: make(chan tea.Msg, 4), hooks: dispatcher}
+
+	item := &downloads.DownloadItem{
+		MangaID:     "m1",
+		MangaTitle:  "Manga",
+		ChapterID:   "c1",
+		ChapterName: "Chapter 3",
+		Chapter:     &source.Chapter{ID: "c1", ChapterNumber: 3},
+	}
+	feed.downloadCompleted(item)
+	feed.downloadFailed(item, errors.New("page 2: not found"))
+
+	// Closing delivers the queued events before returning
+	dispatcher.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2)
+	assert.Equal(t, hooks.EventDownloadComplete, received[0].Type)
+	assert.Equal(t, "Downloaded Chapter 3", received[0].Message)
+	assert.Equal(t, "c1", received[0].ChapterID)
+	assert.Equal(t, 3.0, received[0].ChapterNumber)
+	assert.Empty(t, received[0].Error)
+	assert.Equal(t, hooks.EventDownloadFailed, received[1].Type)
+	assert.Equal(t, "page 2: not found", received[1].Error)
+	assert.False(t, received[1].Time.IsZero())
+
+	// The notification center gets both as well
+	require.Len(t, feed.events, 2)
+	msg := (<-feed.events).(notificationAddedMsg)
+	assert.Equal(t, storage.NotifyDownloadComplete, msg.notification.Kind)
+	msg = (<-feed.events).(notificationAddedMsg)
+	assert.Equal(t, storage.NotifyDownloadFailed, msg.notification.Kind)
+}
+
+func TestNotifierEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		events map[string]bool
+		want   map[notify.Event]bool
+	}{
+		{
+			name: "defaults",
+			want: map[notify.Event]bool{"new_chapters": true, "download_complete": true, "download_failed": true},
+		},
+		{
+			name:   "turning one default off keeps the others",
+			events: map[string]bool{"download_complete": false},
+			want:   map[notify.Event]bool{"new_chapters": true, "download_failed": true},
+		},
+		{
+			name:   "added to the defaults",
+			events: map[string]bool{"update_failed": true, "new_chapters": true},
+			want:   map[notify.Event]bool{"new_chapters": true, "update_failed": true, "download_complete": true, "download_failed": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, notifierEvents(config.NotifierConfig{Enabled: true, Events: tt.events}))
+		})
+	}
+}
@@ -43,6 +43,7 @@ type Model struct {
 	// Reading session tracking
 	sessionStart time.Time
 	pagesRead    int
+	reportedRead map[string]bool // Chapters reported by ChapterReadMsg
 
 	// Dependencies
 	sourceManager *source.SourceManager
@@ -68,6 +69,7 @@ func NewModel(manga *source.Manga, chapter *source.Chapter, sm *source.SourceMan
 		imageRenderer: kitty.NewImageRenderer(),
 		sessionStart:  time.Now(),
 		pagesRead:     0,
+		reportedRead:  make(map[string]bool),
 		loading:       true,
 	}
 }
@@ -127,14 +129,14 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case "g":
 		// Go to first page
 		m.currentPage = 0
-		return m, m.saveProgress
+		return m.saveProgress()
 
 	case "G":
 		// Go to last page
 		if len(m.pages) > 0 {
 			m.currentPage = len(m.pages) - 1
 		}
-		return m, m.saveProgress
+		return m.saveProgress()
 
 	case "b":
 		// Toggle bookmark
@@ -164,7 +166,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		if m.currentPage < 0 {
 			m.currentPage = 0
 		}
-		return m, m.saveProgress
+		return m.saveProgress()
 
 	case "]":
 		// Jump forward 10 pages
@@ -172,7 +174,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		if m.currentPage >= len(m.pages) {
 			m.currentPage = len(m.pages) - 1
 		}
-		return m, m.saveProgress
+		return m.saveProgress()
 	}
 
 	return m, nil
@@ -183,7 +185,7 @@ func (m Model) nextPage() (Model, tea.Cmd) {
 	if m.currentPage < len(m.pages)-1 {
 		m.currentPage++
 		m.pagesRead++
-		return m, m.saveProgress
+		return m.saveProgress()
 	}
 
 	// At end of chapter, offer to go to next chapter
@@ -199,7 +201,7 @@ func (m Model) nextPage() (Model, tea.Cmd) {
 func (m Model) prevPage() (Model, tea.Cmd) {
 	if m.currentPage > 0 {
 		m.currentPage--
-		return m, m.saveProgress
+		return m.saveProgress()
 	}
 
 	// At beginning of chapter, offer to go to previous chapter
@@ -729,6 +731,15 @@ type chapterErrorMsg struct {
 
 type gotoLastPageMsg struct{}
 
+// ChapterReadMsg is sent once per chapter when progress reaches its last page
+type ChapterReadMsg struct {
+	MangaID       string
+	MangaTitle    string
+	ChapterID     string
+	ChapterName   string
+	ChapterNumber float64
+}
+
 // Commands
 
 func (m Model) loadChapter() tea.Msg {
@@ -796,11 +807,26 @@ func (m Model) loadProgress() tea.Msg {
 	}
 }
 
-func (m Model) saveProgress() tea.Msg {
+// saveProgress saves the current page. Reaching the last page completes
+// the chapter, which is reported once per chapter.
+func (m Model) saveProgress() (Model, tea.Cmd) {
 	if m.storage == nil || m.manga == nil || m.chapter == nil {
-		return nil
+		return m, nil
 	}
 
+	completed := len(m.pages) > 0 && m.currentPage >= len(m.pages)-1 && !m.reportedRead[m.chapter.ID]
+	if completed {
+		m.reportedRead[m.chapter.ID] = true
+	}
+	return m, func() tea.Msg {
+		return m.writeProgress(completed)
+	}
+}
+
+// writeProgress stores the current page and returns a ChapterReadMsg if
+// report is set
+func (m Model) writeProgress(report bool) tea.Msg {
+
 	err := m.storage.Progress.UpdateProgress(
 		m.manga.ID,
 		m.manga.Title,
@@ -814,5 +840,15 @@ func (m Model) saveProgress() tea.Msg {
 		return nil
 	}
 
+	if report {
+		return ChapterReadMsg{
+			MangaID:       m.manga.ID,
+			MangaTitle:    m.manga.Title,
+			ChapterID:     m.chapter.ID,
+			ChapterName:   m.chapter.Title,
+			ChapterNumber: m.chapter.ChapterNumber,
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,59 @@
+package reader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pressKey sends a key to the reader and runs the command it returns
+func pressKey(t *testing.T, m Model, key string) (Model, tea.Msg) {
+	t.Helper()
+	m, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	if cmd == nil {
+		return m, nil
+	}
+	return m, cmd()
+}
+
+func TestModel_ReportsChapterReadOnce(t *testing.T) {
+	st, err := storage.NewStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	manga := &source.Manga{ID: "m1", Title: "Manga"}
+	chapters := []*source.Chapter{{ID: "c1", Title: "Chapter 1", ChapterNumber: 1}, {ID: "c2", Title: "Chapter 2", ChapterNumber: 2}}
+	m := NewModel(manga, chapters[0], source.NewSourceManager(), st)
+	m, _ = m.Update(chapterLoadedMsg{pages: make([]*source.Page, 3), chapters: chapters})
+
+	m, msg := pressKey(t, m, "l")
+	assert.Nil(t, msg)
+
+	// Reaching the last page reports the chapter
+	m, msg = pressKey(t, m, "G")
+	assert.Equal(t, ChapterReadMsg{
+		MangaID:       "m1",
+		MangaTitle:    "Manga",
+		ChapterID:     "c1",
+		ChapterName:   "Chapter 1",
+		ChapterNumber: 1,
+	}, msg)
+
+	// Saving on the last page again does not
+	m, msg = pressKey(t, m, "[")
+	assert.Nil(t, msg)
+	m, msg = pressKey(t, m, "G")
+	assert.Nil(t, msg)
+
+	// The next chapter is reported on its own
+	m, _ = m.nextChapter()
+	m, _ = m.Update(chapterLoadedMsg{pages: make([]*source.Page, 1), chapters: chapters, chapterIndex: 1})
+	_, msg = pressKey(t, m, "G")
+	require.IsType(t, ChapterReadMsg{}, msg)
+	assert.Equal(t, "c2", msg.(ChapterReadMsg).ChapterID)
+}
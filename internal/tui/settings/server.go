@@ -0,0 +1,504 @@
+package settings
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// serverField describes an editable Suwayomi server setting.
+// value returns a pointer into the settings struct (*bool, *int, *float64,
+// *string or *[]string), which decides how the field is edited.
+type serverField struct {
+	key     string // GraphQL field name, matches ServerSettingChange.Field
+	label   string
+	section string
+	secret  bool
+	choices []string // Cycled with enter instead of typed
+	value   func(s *suwayomi.ServerSettings) interface{}
+}
+
+// serverFields lists the server settings in display order
+var serverFields = []serverField{
+	{key: "ip", label: "Bind Address", section: "Server", value: func(s *suwayomi.ServerSettings) interface{} { return &s.IP }},
+	{key: "port", label: "Port", section: "Server", value: func(s *suwayomi.ServerSettings) interface{} { return &s.Port }},
+	{key: "authMode", label: "Auth Mode", section: "Authentication", choices: []string{suwayomi.AuthModeNone, suwayomi.AuthModeBasicAuth, suwayomi.AuthModeSimpleLogin}, value: func(s *suwayomi.ServerSettings) interface{} { return &s.AuthMode }},
+	{key: "authUsername", label: "Username", section: "Authentication", value: func(s *suwayomi.ServerSettings) interface{} { return &s.AuthUsername }},
+	{key: "authPassword", label: "Password", section: "Authentication", secret: true, value: func(s *suwayomi.ServerSettings) interface{} { return &s.AuthPassword }},
+	{key: "downloadsPath", label: "Download Path", section: "Downloads", value: func(s *suwayomi.ServerSettings) interface{} { return &s.DownloadsPath }},
+	{key: "downloadAsCbz", label: "Download as CBZ", section: "Downloads", value: func(s *suwayomi.ServerSettings) interface{} { return &s.DownloadAsCbz }},
+	{key: "autoDownloadNewChapters", label: "Auto-Download New", section: "Downloads", value: func(s *suwayomi.ServerSettings) interface{} { return &s.AutoDownloadNewChapters }},
+	{key: "autoDownloadNewChaptersLimit", label: "Auto-Download Limit", section: "Downloads", value: func(s *suwayomi.ServerSettings) interface{} { return &s.AutoDownloadNewChaptersLimit }},
+	{key: "autoDownloadIgnoreReUploads", label: "Ignore Re-Uploads", section: "Downloads", value: func(s *suwayomi.ServerSettings) interface{} { return &s.AutoDownloadIgnoreReUploads }},
+	{key: "updateMangas", label: "Update Manga Info", section: "Library Updates", value: func(s *suwayomi.ServerSettings) interface{} { return &s.UpdateMangas }},
+	{key: "globalUpdateInterval", label: "Update Interval (h)", section: "Library Updates", value: func(s *suwayomi.ServerSettings) interface{} { return &s.GlobalUpdateInterval }},
+	{key: "excludeCompleted", label: "Skip Completed", section: "Library Updates", value: func(s *suwayomi.ServerSettings) interface{} { return &s.ExcludeCompleted }},
+	{key: "excludeNotStarted", label: "Skip Not Started", section: "Library Updates", value: func(s *suwayomi.ServerSettings) interface{} { return &s.ExcludeNotStarted }},
+	{key: "excludeUnreadChapters", label: "Skip With Unread", section: "Library Updates", value: func(s *suwayomi.ServerSettings) interface{} { return &s.ExcludeUnreadChapters }},
+	{key: "maxSourcesInParallel", label: "Parallel Sources", section: "Library Updates", value: func(s *suwayomi.ServerSettings) interface{} { return &s.MaxSourcesInParallel }},
+	{key: "extensionRepos", label: "Extension Repos", section: "Extensions", value: func(s *suwayomi.ServerSettings) interface{} { return &s.ExtensionRepos }},
+	{key: "localSourcePath", label: "Local Source Path", section: "Extensions", value: func(s *suwayomi.ServerSettings) interface{} { return &s.LocalSourcePath }},
+	{key: "flareSolverrEnabled", label: "FlareSolverr", section: "FlareSolverr", value: func(s *suwayomi.ServerSettings) interface{} { return &s.FlareSolverrEnabled }},
+	{key: "flareSolverrUrl", label: "FlareSolverr URL", section: "FlareSolverr", value: func(s *suwayomi.ServerSettings) interface{} { return &s.FlareSolverrURL }},
+	{key: "flareSolverrTimeout", label: "Timeout (s)", section: "FlareSolverr", value: func(s *suwayomi.ServerSettings) interface{} { return &s.FlareSolverrTimeout }},
+	{key: "flareSolverrSessionName", label: "Session Name", section: "FlareSolverr", value: func(s *suwayomi.ServerSettings) interface{} { return &s.FlareSolverrSessionName }},
+	{key: "flareSolverrSessionTtl", label: "Session TTL (min)", section: "FlareSolverr", value: func(s *suwayomi.ServerSettings) interface{} { return &s.FlareSolverrSessionTTL }},
+	{key: "flareSolverrAsResponseFallback", label: "Response Fallback", section: "FlareSolverr", value: func(s *suwayomi.ServerSettings) interface{} { return &s.FlareSolverrAsResponseFallback }},
+	{key: "socksProxyEnabled", label: "SOCKS Proxy", section: "Proxy", value: func(s *suwayomi.ServerSettings) interface{} { return &s.SocksProxyEnabled }},
+	{key: "socksProxyVersion", label: "SOCKS Version", section: "Proxy", value: func(s *suwayomi.ServerSettings) interface{} { return &s.SocksProxyVersion }},
+	{key: "socksProxyHost", label: "Proxy Host", section: "Proxy", value: func(s *suwayomi.ServerSettings) interface{} { return &s.SocksProxyHost }},
+	{key: "socksProxyPort", label: "Proxy Port", section: "Proxy", value: func(s *suwayomi.ServerSettings) interface{} { return &s.SocksProxyPort }},
+	{key: "socksProxyUsername", label: "Proxy Username", section: "Proxy", value: func(s *suwayomi.ServerSettings) interface{} { return &s.SocksProxyUsername }},
+	{key: "socksProxyPassword", label: "Proxy Password", section: "Proxy", secret: true, value: func(s *suwayomi.ServerSettings) interface{} { return &s.SocksProxyPassword }},
+	{key: "backupPath", label: "Backup Path", section: "Backups", value: func(s *suwayomi.ServerSettings) interface{} { return &s.BackupPath }},
+	{key: "backupTime", label: "Backup Time", section: "Backups", value: func(s *suwayomi.ServerSettings) interface{} { return &s.BackupTime }},
+	{key: "backupInterval", label: "Backup Every (days)", section: "Backups", value: func(s *suwayomi.ServerSettings) interface{} { return &s.BackupInterval }},
+	{key: "backupTTL", label: "Keep Backups (days)", section: "Backups", value: func(s *suwayomi.ServerSettings) interface{} { return &s.BackupTTL }},
+	{key: "debugLogsEnabled", label: "Debug Logs", section: "Logging", value: func(s *suwayomi.ServerSettings) interface{} { return &s.DebugLogsEnabled }},
+}
+
+// findServerField returns the field descriptor for a GraphQL field name
+func findServerField(key string) *serverField {
+	for i := range serverFields {
+		if serverFields[i].key == key {
+			return &serverFields[i]
+		}
+	}
+	return nil
+}
+
+// formatServerValue formats a setting value for display
+func formatServerValue(field *serverField, value interface{}) string {
+	if field != nil && field.secret {
+		if s, ok := value.(string); ok && s == "" {
+			return "(empty)"
+		}
+		return "••••••"
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "Enabled"
+		}
+		return "Disabled"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		if v == "" {
+			return "(empty)"
+		}
+		return v
+	case []string:
+		if len(v) == 0 {
+			return "(none)"
+		}
+		return strings.Join(v, ", ")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// fieldValue dereferences the field's pointer into settings
+func fieldValue(field *serverField, s *suwayomi.ServerSettings) interface{} {
+	switch p := field.value(s).(type) {
+	case *bool:
+		return *p
+	case *int:
+		return *p
+	case *float64:
+		return *p
+	case *string:
+		return *p
+	case *[]string:
+		return *p
+	}
+	return nil
+}
+
+// openServerSettings switches to the server settings editor and loads the settings
+func (m Model) openServerSettings() (Model, tea.Cmd) {
+	if m.suwayomiClient == nil {
+		m.setMessage("No Suwayomi server configured", "error")
+		return m, nil
+	}
+
+	m.viewMode = ViewModeServer
+	m.serverCursor = 0
+	m.serverOffset = 0
+	m.serverInputActive = false
+	m.serverLoading = true
+	return m, m.loadServerSettings
+}
+
+// serverDirty reports whether the edited server settings differ from the server
+func (m Model) serverDirty() bool {
+	return len(suwayomi.DiffServerSettings(m.serverSettings, m.serverEdit)) > 0
+}
+
+// handleServerKeys handles keyboard input in the server settings editor
+func (m Model) handleServerKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.serverInputActive {
+		return m.handleServerInput(msg)
+	}
+
+	if m.viewMode == ViewModeServerDiff {
+		switch msg.String() {
+		case "y", "enter":
+			return m.applyServerSettings()
+		case "n", "esc", "q":
+			m.viewMode = ViewModeServer
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		if m.serverDirty() {
+			m.setMessage("Discarded unsaved server changes", "info")
+		}
+		m.viewMode = ViewModeMain
+		m.serverEdit = nil
+		return m, nil
+
+	case "r":
+		m.serverLoading = true
+		return m, m.loadServerSettings
+	}
+
+	if m.serverLoading || m.serverEdit == nil {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.serverCursor > 0 {
+			m.serverCursor--
+			m.adjustServerOffset()
+		}
+
+	case "down", "j":
+		if m.serverCursor < len(serverFields)-1 {
+			m.serverCursor++
+			m.adjustServerOffset()
+		}
+
+	case "g":
+		m.serverCursor = 0
+		m.serverOffset = 0
+
+	case "G":
+		m.serverCursor = len(serverFields) - 1
+		m.adjustServerOffset()
+
+	case "enter", " ":
+		return m.editServerField(&serverFields[m.serverCursor])
+
+	case "u":
+		m.serverEdit = m.serverSettings.Clone()
+		m.setMessage("Reverted server changes", "info")
+
+	case "s", "S":
+		if !m.serverDirty() {
+			m.setMessage("No server changes to save", "info")
+			return m, nil
+		}
+		if err := m.serverEdit.Validate(); err != nil {
+			m.setMessage(fmt.Sprintf("Invalid: %v", err), "error")
+			return m, nil
+		}
+		m.viewMode = ViewModeServerDiff
+	}
+
+	return m, nil
+}
+
+// editServerField toggles, cycles or starts text input for a field
+func (m Model) editServerField(field *serverField) (Model, tea.Cmd) {
+	ptr := field.value(m.serverEdit)
+
+	if len(field.choices) > 0 {
+		if p, ok := ptr.(*string); ok {
+			next := field.choices[0]
+			for i, choice := range field.choices {
+				if choice == *p {
+					next = field.choices[(i+1)%len(field.choices)]
+					break
+				}
+			}
+			*p = next
+		}
+		return m, nil
+	}
+
+	if p, ok := ptr.(*bool); ok {
+		*p = !*p
+		return m, nil
+	}
+
+	m.serverInputActive = true
+	switch p := ptr.(type) {
+	case *[]string:
+		m.serverInput = strings.Join(*p, ",")
+	case *string:
+		m.serverInput = *p
+	default:
+		m.serverInput = formatServerValue(nil, fieldValue(field, m.serverEdit))
+	}
+	if field.secret {
+		// Secrets are typed from scratch rather than revealed
+		m.serverInput = ""
+	}
+	m.serverInputCursor = len(m.serverInput)
+	return m, nil
+}
+
+// handleServerInput handles the inline text input for a server setting
+func (m Model) handleServerInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.serverInputActive = false
+
+	case "enter":
+		field := &serverFields[m.serverCursor]
+		if err := setServerFieldFromInput(field, m.serverEdit, m.serverInput); err != nil {
+			m.setMessage(fmt.Sprintf("%s: %v", field.label, err), "error")
+			return m, nil
+		}
+		m.serverInputActive = false
+
+	case "backspace":
+		if m.serverInputCursor > 0 {
+			m.serverInput = m.serverInput[:m.serverInputCursor-1] + m.serverInput[m.serverInputCursor:]
+			m.serverInputCursor--
+		}
+
+	case "left":
+		if m.serverInputCursor > 0 {
+			m.serverInputCursor--
+		}
+
+	case "right":
+		if m.serverInputCursor < len(m.serverInput) {
+			m.serverInputCursor++
+		}
+
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			text := string(msg.Runes)
+			if msg.Type == tea.KeySpace {
+				text = " "
+			}
+			m.serverInput = m.serverInput[:m.serverInputCursor] + text + m.serverInput[m.serverInputCursor:]
+			m.serverInputCursor += len(text)
+		}
+	}
+
+	return m, nil
+}
+
+// setServerFieldFromInput parses text input into a settings field
+func setServerFieldFromInput(field *serverField, s *suwayomi.ServerSettings, input string) error {
+	input = strings.TrimSpace(input)
+
+	switch p := field.value(s).(type) {
+	case *int:
+		v, err := strconv.Atoi(input)
+		if err != nil {
+			return fmt.Errorf("must be a whole number")
+		}
+		*p = v
+	case *float64:
+		v, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		*p = v
+	case *string:
+		*p = input
+	case *[]string:
+		values := make([]string, 0)
+		for _, part := range strings.Split(input, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+		*p = values
+	default:
+		return fmt.Errorf("cannot be edited as text")
+	}
+
+	return nil
+}
+
+// applyServerSettings sends the changes to the server
+func (m Model) applyServerSettings() (Model, tea.Cmd) {
+	m.viewMode = ViewModeServer
+	m.serverSaving = true
+
+	client := m.suwayomiClient
+	current := m.serverSettings
+	updated := m.serverEdit.Clone()
+
+	return m, func() tea.Msg {
+		settings, err := client.SetServerSettings(current, updated)
+		return serverSettingsSavedMsg{settings: settings, err: err}
+	}
+}
+
+// adjustServerOffset keeps the cursor visible in the server settings list
+func (m *Model) adjustServerOffset() {
+	visible := m.serverVisibleItems()
+	if m.serverCursor < m.serverOffset {
+		m.serverOffset = m.serverCursor
+	} else if m.serverCursor >= m.serverOffset+visible {
+		m.serverOffset = m.serverCursor - visible + 1
+	}
+}
+
+// serverVisibleItems returns how many server settings fit on screen
+func (m Model) serverVisibleItems() int {
+	visible := m.height - 16
+	if visible < 5 {
+		visible = 5
+	}
+	return visible
+}
+
+// renderServerSettings renders the server settings editor
+func (m Model) renderServerSettings() string {
+	var b strings.Builder
+
+	b.WriteString(sectionStyle.Render("Suwayomi Server Settings"))
+	b.WriteString("\n")
+
+	if m.serverLoading {
+		b.WriteString(mutedStyle.Render("⟳ Loading server settings..."))
+		return b.String()
+	}
+
+	if m.serverEdit == nil {
+		b.WriteString(mutedStyle.Render("Server settings not loaded. Press 'r' to retry."))
+		return b.String()
+	}
+
+	if m.serverSaving {
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.ColorWarning).Render("⟳ Saving..."))
+		b.WriteString("\n")
+	} else if changes := suwayomi.DiffServerSettings(m.serverSettings, m.serverEdit); len(changes) > 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.ColorWarning).Render(fmt.Sprintf("● %d unsaved change(s)", len(changes))))
+		b.WriteString("\n")
+	}
+
+	start := m.serverOffset
+	end := start + m.serverVisibleItems()
+	if end > len(serverFields) {
+		end = len(serverFields)
+	}
+
+	section := ""
+	for i := start; i < end; i++ {
+		field := &serverFields[i]
+
+		if field.section != section {
+			section = field.section
+			b.WriteString(mutedStyle.Render("─ " + section))
+			b.WriteString("\n")
+		}
+
+		value := fieldValue(field, m.serverEdit)
+		changed := !reflect.DeepEqual(fieldValue(field, m.serverSettings), value)
+
+		line := fmt.Sprintf("%-22s %s", field.label+":", formatServerValue(field, value))
+		if changed {
+			line += " *"
+		}
+
+		if i == m.serverCursor {
+			b.WriteString(theme.HighlightStyle.Render("▸ " + line))
+		} else if changed {
+			b.WriteString("  " + lipgloss.NewStyle().Foreground(theme.ColorWarning).Render(line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	if m.serverInputActive {
+		field := &serverFields[m.serverCursor]
+		b.WriteString("\n")
+		hint := ""
+		if _, ok := field.value(m.serverEdit).(*[]string); ok {
+			hint = mutedStyle.Render(" (comma-separated)")
+		}
+		b.WriteString(labelStyle.Render(field.label) + hint)
+		b.WriteString("\n> ")
+		input := m.serverInput
+		if field.secret {
+			input = strings.Repeat("•", len(input))
+		}
+		b.WriteString(input[:m.serverInputCursor])
+		b.WriteString(theme.HighlightStyle.Render("_"))
+		b.WriteString(input[m.serverInputCursor:])
+	}
+
+	return b.String()
+}
+
+// renderServerDiff renders the pending server changes for confirmation
+func (m Model) renderServerDiff() string {
+	var b strings.Builder
+
+	b.WriteString(sectionStyle.Render("Review Server Changes"))
+	b.WriteString("\n")
+
+	for _, change := range suwayomi.DiffServerSettings(m.serverSettings, m.serverEdit) {
+		field := findServerField(change.Field)
+		label := change.Field
+		if field != nil {
+			label = field.label
+		}
+
+		b.WriteString(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			labelStyle.Render(label+":"),
+			errorStyle.Render(formatServerValue(field, change.OldValue)),
+			mutedStyle.Render(" → "),
+			successStyle.Render(formatServerValue(field, change.NewValue)),
+		))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString("Apply these changes to the server? (y/n)")
+
+	return b.String()
+}
+
+// Messages
+
+type serverSettingsLoadedMsg struct {
+	settings *suwayomi.ServerSettings
+	err      error
+}
+
+type serverSettingsSavedMsg struct {
+	settings *suwayomi.ServerSettings
+	err      error
+}
+
+// Commands
+
+func (m Model) loadServerSettings() tea.Msg {
+	if m.suwayomiClient == nil {
+		return serverSettingsLoadedMsg{err: fmt.Errorf("no Suwayomi client configured")}
+	}
+
+	settings, err := m.suwayomiClient.GetServerSettings()
+	return serverSettingsLoadedMsg{settings: settings, err: err}
+}
@@ -65,9 +65,20 @@ type Model struct {
 	healthError     error
 
 	// View mode
-	viewMode ViewMode // "main", "logs"
+	viewMode ViewMode // "main", "logs", "server", "server_diff"
 	logLines int      // Number of log lines to show
 
+	// Suwayomi server settings editor
+	serverSettings    *suwayomi.ServerSettings // As stored on the server
+	serverEdit        *suwayomi.ServerSettings // Working copy being edited
+	serverCursor      int
+	serverOffset      int
+	serverLoading     bool
+	serverSaving      bool
+	serverInputActive bool
+	serverInput       string
+	serverInputCursor int
+
 	// Navigation
 	cursor      int  // Current cursor position in settings list
 	editMode    bool // Whether we're in edit mode for the selected setting
@@ -85,6 +96,8 @@ type ViewMode string
 const (
 	ViewModeMain ViewMode = "main"
 	ViewModeLogs ViewMode = "logs"
+	ViewModeServer     ViewMode = "server"      // Edit Suwayomi server settings
+	ViewModeServerDiff ViewMode = "server_diff" // Review server changes before saving
 )
 
 // SettingItem represents a configurable setting
@@ -141,6 +154,12 @@ func (m Model) getSettingsList() []SettingItem {
 			Description: "Reload config file from disk",
 			Type:        SettingTypeAction,
 		},
+		{
+			ID:          "server_settings",
+			Label:       "Suwayomi Server Settings",
+			Description: "Edit downloads, updates, FlareSolverr and auth on the server",
+			Type:        SettingTypeAction,
+		},
 
 		// Smart Updates Settings
 		{
@@ -241,6 +260,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// Handle server settings editor separately
+		if m.viewMode == ViewModeServer || m.viewMode == ViewModeServerDiff {
+			return m.handleServerKeys(msg)
+		}
+
 		// Handle logs view separately
 		if m.viewMode == ViewModeLogs {
 			switch msg.String() {
@@ -308,6 +332,27 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
+	case serverSettingsLoadedMsg:
+		m.serverLoading = false
+		if msg.err != nil {
+			m.setMessage(fmt.Sprintf("Failed to load server settings: %v", msg.err), "error")
+			return m, nil
+		}
+		m.serverSettings = msg.settings
+		m.serverEdit = msg.settings.Clone()
+		return m, nil
+
+	case serverSettingsSavedMsg:
+		m.serverSaving = false
+		if msg.err != nil {
+			m.setMessage(fmt.Sprintf("Failed to save server settings: %v", msg.err), "error")
+			return m, nil
+		}
+		m.serverSettings = msg.settings
+		m.serverEdit = msg.settings.Clone()
+		m.setMessage("Server settings saved", "success")
+		return m, nil
+
 	case healthCheckResultMsg:
 		m.checkingHealth = false
 		m.serverInfo = msg.info
@@ -337,6 +382,10 @@ func (m Model) View() string {
 	switch m.viewMode {
 	case ViewModeLogs:
 		b.WriteString(m.renderServerLogs())
+	case ViewModeServer:
+		b.WriteString(m.renderServerSettings())
+	case ViewModeServerDiff:
+		b.WriteString(m.renderServerDiff())
 	default:
 		// Show server health if available
 		if m.serverInfo != nil || m.checkingHealth || m.healthError != nil {
@@ -450,6 +499,7 @@ func (m Model) renderServerHealth() string {
 		b.WriteString(m.renderConfigLine("Revision", m.serverInfo.Revision))
 		b.WriteString(m.renderConfigLine("Extensions", fmt.Sprintf("%d", m.serverInfo.ExtensionCount)))
 		b.WriteString(m.renderConfigLine("Manga", fmt.Sprintf("%d", m.serverInfo.MangaCount)))
+		b.WriteString(m.renderCapabilities())
 	}
 
 	if !m.lastHealthCheck.IsZero() {
@@ -460,6 +510,35 @@ func (m Model) renderServerHealth() string {
 	return b.String()
 }
 
+// renderCapabilities lists client features the server does not support
+func (m Model) renderCapabilities() string {
+	var b strings.Builder
+	b.WriteString("\n")
+
+	caps := m.suwayomiClient.Capabilities()
+	if caps == nil {
+		b.WriteString(mutedStyle.Render("Server features not detected yet"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	disabled := caps.Disabled()
+	if len(disabled) == 0 {
+		b.WriteString(successStyle.Render("✓ All features supported"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(errorStyle.Render(fmt.Sprintf("Disabled features (%d):", len(disabled))))
+	b.WriteString("\n")
+	for _, feature := range disabled {
+		b.WriteString(fmt.Sprintf("  ✗ %s ", feature.Description))
+		b.WriteString(mutedStyle.Render("— " + feature.Fallback))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // renderSmartUpdates renders the smart updates configuration section
 func (m Model) renderSmartUpdates() string {
 	var b strings.Builder
@@ -619,6 +698,21 @@ func (m Model) renderFooter() string {
 			"l/Esc: back",
 			"c: clear logs",
 		}
+	case ViewModeServer:
+		if m.serverInputActive {
+			controls = []string{"Enter: apply", "←→: move cursor", "Esc: cancel"}
+		} else {
+			controls = []string{
+				"↑↓/jk: navigate",
+				"Enter/Space: edit",
+				"s: review & save",
+				"u: undo changes",
+				"r: reload",
+				"Esc: back",
+			}
+		}
+	case ViewModeServerDiff:
+		controls = []string{"y/Enter: apply", "n/Esc: keep editing"}
 	default:
 		settings := m.getSettingsList()
 		if len(settings) > 0 && m.cursor < len(settings) {
@@ -717,6 +811,9 @@ func (m Model) handleSettingAction(setting SettingItem) (Model, tea.Cmd) {
 	case "health_check":
 		return m, m.performHealthCheck
 
+	case "server_settings":
+		return m.openServerSettings()
+
 	case "reload_config":
 		cfg, err := config.Load()
 		if err == nil {
@@ -864,6 +961,18 @@ func (m Model) renderSettingsList() string {
 	return b.String()
 }
 
+// CapturesKeys reports whether the view needs keys that are otherwise
+// handled globally (esc, q), i.e. while editing server settings
+func (m Model) CapturesKeys() bool {
+	return m.viewMode == ViewModeServer || m.viewMode == ViewModeServerDiff
+}
+
+// Config returns the configuration being edited, which is replaced when it
+// is reloaded from disk
+func (m Model) Config() *config.Config {
+	return m.config
+}
+
 // saveConfig saves the current configuration to disk
 func (m Model) saveConfig() error {
 	if m.config == nil {
@@ -891,6 +1000,10 @@ func (m Model) performHealthCheck() tea.Msg {
 	}
 
 	info, err := m.suwayomiClient.HealthCheck()
+	if err == nil {
+		// Refresh capabilities in case the server was upgraded; failures keep the previous result
+		m.suwayomiClient.DetectCapabilities()
+	}
 	return healthCheckResultMsg{
 		info: info,
 		err:  err,
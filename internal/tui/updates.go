@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/updates"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateEventBuffer is the number of updater events queued for the TUI
+const updateEventBuffer = 64
+
+// newUpdater creates the library updater from the config. Events from the
+// updater's goroutines are sent to the returned channel for the TUI, and
+// its notifications to feed.
+func newUpdater(cfg *config.Config, sm *source.SourceManager, st *storage.Storage, dm *downloads.Manager, feed *notificationFeed) (*updates.Updater, chan tea.Msg) {
+	updater := updates.NewUpdater(updateConfigFrom(cfg), sm, st)
+
+	if cfg.Updates.AutoDownload.Enabled {
+		rules := make([]updates.AutoDownloadRule, 0, len(cfg.Updates.AutoDownload.Rules))
+		for _, rule := range cfg.Updates.AutoDownload.Rules {
+			rules = append(rules, updates.AutoDownloadRule{
+				Categories:         rule.Categories,
+				OnlyIfReadPrevious: rule.OnlyIfReadPrevious,
+				KeepAhead:          rule.KeepAhead,
+				Priority:           rule.Priority,
+			})
+		}
+		updater.SetAutoDownloader(updates.NewAutoDownloader(rules, dm, st))
+	}
+
+	events := make(chan tea.Msg, updateEventBuffer)
+	updater.SetCallbacks(
+		func(task *updates.UpdateTask) {
+			// Progress is also polled from the updater, so dropping an
+			// event when the TUI falls behind loses nothing
+			trySend(events, updateProgressMsg{task: task})
+		},
+		func(summary *updates.UpdateSummary) {
+			for _, event := range newChapterEvents(summary) {
+				feed.hooks.Fire(event)
+			}
+			// The updater keeps the summary in its history, which the home
+			// screen reads, so an update never hangs on a TUI that stopped
+			// reading
+			trySend(events, libraryUpdatedMsg{summary: summary})
+		},
+		// The updater stores its notifications itself
+		feed.announce,
+	)
+	return updater, events
+}
+
+// updateConfigFrom maps the update settings of the config to the updater
+func updateConfigFrom(cfg *config.Config) *updates.UpdateConfig {
+	updateConfig := updates.DefaultUpdateConfig()
+	updateConfig.Interval = updateInterval(cfg)
+	updateConfig.UpdateOnlyStarted = cfg.Updates.UpdateOnlyStarted
+	updateConfig.UpdateOnlyCompleted = cfg.Updates.UpdateOnlyCompleted
+	updateConfig.SmartUpdate = cfg.Updates.SmartUpdate
+
+	smart := storage.DefaultSmartUpdateConfig()
+	if cfg.Updates.MinIntervalHours > 0 {
+		smart.MinIntervalHours = cfg.Updates.MinIntervalHours
+	}
+	if cfg.Updates.MaxConsecutiveFailures > 0 {
+		smart.MaxConsecutiveFailures = cfg.Updates.MaxConsecutiveFailures
+	}
+	if cfg.Updates.IntervalMultiplier > 0 {
+		smart.MultiplyIntervalBy = cfg.Updates.IntervalMultiplier
+	}
+	smart.UpdateOnlyOngoing = cfg.Updates.UpdateOnlyOngoing
+	// The updater filters unread manga itself
+	smart.UpdateOnlyStarted = false
+	updateConfig.SmartUpdateConfig = smart
+	return updateConfig
+}
+
+// updateInterval returns the time between automatic updates, 0 when they
+// are disabled
+func updateInterval(cfg *config.Config) time.Duration {
+	if !cfg.Updates.AutoUpdateEnabled || cfg.Updates.AutoUpdateIntervalHrs <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.Updates.AutoUpdateIntervalHrs) * time.Hour
+}
+
+// trySend sends msg unless events is full, reporting whether it was sent
+func trySend(events chan<- tea.Msg, msg tea.Msg) bool {
+	select {
+	case events <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// listenForUpdates waits for the next event from the library updater
+func listenForUpdates(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// updateLibrary runs a library update in the background. Its result
+// arrives through the updater's events.
+func updateLibrary(updater *updates.Updater) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := updater.UpdateLibrary(); err != nil {
+			return libraryUpdateFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// startLibraryUpdate starts an update unless one is already running
+func (m AppModel) startLibraryUpdate() (AppModel, tea.Cmd) {
+	if m.updater == nil {
+		return m, nil
+	}
+	if m.updater.IsUpdating() {
+		m.updateStatus = "A library update is already running"
+		return m, nil
+	}
+	m.updateStatus = ""
+	return m, updateLibrary(m.updater)
+}
+
+// countUnreadUpdates counts the unread chapters of the Updates feed for the
+// home screen badge
+func countUnreadUpdates(st *storage.Storage) tea.Cmd {
+	return func() tea.Msg {
+		if st == nil {
+			return nil
+		}
+		count, err := st.Updates.CountUnread()
+		if err != nil {
+			return nil
+		}
+		return unreadUpdatesMsg{count: count}
+	}
+}
+
+// syncUpdateSchedule applies changes to the auto-update settings
+func (m AppModel) syncUpdateSchedule() {
+	if m.updater == nil {
+		return
+	}
+	cfg := m.settingsModel.Config()
+	if cfg == nil {
+		return
+	}
+	m.updater.SetInterval(updateInterval(cfg))
+}
+
+// renderUpdateSection renders the progress of a running library update, or
+// the summary of the last one, for the home screen
+func (m AppModel) renderUpdateSection() string {
+	if m.updater == nil {
+		return ""
+	}
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(ColorPrimary).Render("Library Update")
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	lines := []string{header}
+
+	if progress, ok := m.updater.Progress(); ok {
+		lines = append(lines, fmt.Sprintf("%s %d/%d manga checked · %d new chapters · %d failed",
+			renderProgressBar(progress.Checked, progress.Total, 20),
+			progress.Checked, progress.Total, progress.NewChapters, progress.Failed))
+		if len(progress.Checking) > 0 {
+			lines = append(lines, muted.Render("Checking: "+truncateList(progress.Checking, 3)))
+		}
+		if m.lastTask != nil {
+			lines = append(lines, muted.Render("Last: "+formatTask(m.lastTask)))
+		}
+	} else if summary := lastUpdate(m.updater); summary != nil {
+		lines = append(lines, formatSummary(summary))
+		lines = append(lines, summaryDetails(summary, 5)...)
+	} else {
+		lines = append(lines, muted.Render("No update yet this session"))
+	}
+
+	if m.updateStatus != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ColorWarning).Render(m.updateStatus))
+	}
+
+	schedule := "Automatic updates off"
+	if interval := m.updater.Interval(); interval > 0 {
+		schedule = fmt.Sprintf("Automatic updates every %s", formatInterval(interval))
+	} else if m.updater.IsScheduled() {
+		schedule = "Automatic updates only for custom schedules"
+	}
+	lines = append(lines, muted.Render(schedule+" · u to update now"))
+
+	return strings.Join(lines, "\n")
+}
+
+// lastUpdate returns the last completed update from the updater's history,
+// which keeps it even when its libraryUpdatedMsg was dropped
+func lastUpdate(updater *updates.Updater) *updates.UpdateSummary {
+	history := updater.GetUpdateHistory()
+	if len(history) == 0 {
+		return nil
+	}
+	return history[len(history)-1]
+}
+
+// renderUpdateIndicator renders the status bar indicator of a running update
+func (m AppModel) renderUpdateIndicator() string {
+	if m.updater == nil {
+		return ""
+	}
+	progress, ok := m.updater.Progress()
+	if !ok {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(ColorSecondary).
+		Render(fmt.Sprintf("  ⟳ Updating %d/%d", progress.Checked, progress.Total))
+}
+
+// formatSummary describes the result of a library update on one line
+func formatSummary(summary *updates.UpdateSummary) string {
+	parts := []string{
+		fmt.Sprintf("%d manga checked", summary.TotalManga),
+		fmt.Sprintf("%d updated", summary.UpdatedManga),
+		fmt.Sprintf("%d new chapters", summary.NewChapters),
+	}
+	if summary.FailedManga > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", summary.FailedManga))
+	}
+	if summary.SkippedManga > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped by smart update", summary.SkippedManga))
+	}
+	if summary.AutoDownloaded > 0 {
+		parts = append(parts, fmt.Sprintf("%d queued for download", summary.AutoDownloaded))
+	}
+	return fmt.Sprintf("Finished %s in %s: %s",
+		summary.CompletedAt.Format("15:04"),
+		summary.Duration().Round(time.Second),
+		strings.Join(parts, " · "))
+}
+
+// summaryDetails lists the manga an update found chapters for, then the
+// failed ones, up to limit lines
+func summaryDetails(summary *updates.UpdateSummary, limit int) []string {
+	var updated, failed []*updates.UpdateTask
+	for _, task := range summary.Tasks {
+		switch {
+		case task.Status == updates.StatusFailed:
+			failed = append(failed, task)
+		case task.HasNewChapters():
+			updated = append(updated, task)
+		}
+	}
+	sort.Slice(updated, func(i, j int) bool {
+		return updated[i].GetNewChapters() > updated[j].GetNewChapters()
+	})
+
+	var lines []string
+	for _, task := range append(updated, failed...) {
+		if len(lines) == limit {
+			lines = append(lines, lipgloss.NewStyle().Foreground(ColorMuted).
+				Render(fmt.Sprintf("  … and %d more", len(updated)+len(failed)-limit)))
+			break
+		}
+		lines = append(lines, "  "+formatTask(task))
+	}
+	return lines
+}
+
+// formatTask describes the result of checking one manga
+func formatTask(task *updates.UpdateTask) string {
+	if task.Status == updates.StatusFailed {
+		message := "failed"
+		if task.Error != nil {
+			message = task.Error.Error()
+		}
+		return lipgloss.NewStyle().Foreground(ColorError).Render(fmt.Sprintf("✗ %s: %s", task.MangaTitle, message))
+	}
+	if task.HasNewChapters() {
+		return lipgloss.NewStyle().Foreground(ColorSuccess).Render(fmt.Sprintf("+%d %s", task.GetNewChapters(), task.MangaTitle))
+	}
+	return fmt.Sprintf("✓ %s", task.MangaTitle)
+}
+
+// renderProgressBar renders a text progress bar of the given width
+func renderProgressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// truncateList joins up to limit items, noting how many were left out
+func truncateList(items []string, limit int) string {
+	if len(items) <= limit {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s +%d more", strings.Join(items[:limit], ", "), len(items)-limit)
+}
+
+// formatInterval formats an update interval in hours or days
+func formatInterval(d time.Duration) string {
+	hours := int(d.Hours())
+	if hours%24 == 0 {
+		if hours == 24 {
+			return "day"
+		}
+		return fmt.Sprintf("%d days", hours/24)
+	}
+	if hours == 1 {
+		return "hour"
+	}
+	return fmt.Sprintf("%d hours", hours)
+}
+
+// isUpdateInProgress reports whether an update failed because another one
+// was running
+func isUpdateInProgress(err error) bool {
+	return errors.Is(err, updates.ErrUpdateInProgress)
+}
+
+// Messages
+
+// updateProgressMsg is sent when the updater finishes checking a manga
+type updateProgressMsg struct {
+	task *updates.UpdateTask
+}
+
+// libraryUpdatedMsg is sent when a library update completes
+type libraryUpdatedMsg struct {
+	summary *updates.UpdateSummary
+}
+
+// libraryUpdateFailedMsg is sent when a library update could not run
+type libraryUpdateFailedMsg struct {
+	err error
+}
+
+// unreadUpdatesMsg carries the number of unread chapters in the Updates feed
+type unreadUpdatesMsg struct {
+	count int
+}
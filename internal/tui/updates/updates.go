@@ -0,0 +1,444 @@
+package updates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// feedLimit is the number of updates loaded into the feed
+const feedLimit = 500
+
+// Model represents the updates feed view model
+type Model struct {
+	width  int
+	height int
+
+	// Data
+	updates []*storage.ChapterUpdate
+
+	// UI state
+	cursor int
+	offset int
+	status string
+
+	// Dependencies
+	storage         *storage.Storage
+	downloadManager *downloads.Manager
+
+	// Loading state
+	loading bool
+	err     error
+}
+
+// NewModel creates a new updates feed model
+func NewModel(st *storage.Storage, dm *downloads.Manager) Model {
+	return Model{
+		storage:         st,
+		downloadManager: dm,
+		loading:         true,
+	}
+}
+
+// Init initializes the updates feed model
+func (m Model) Init() tea.Cmd {
+	return m.loadData
+}
+
+// Update handles messages for the updates feed view
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKeyPress(msg)
+
+	case updatesLoadedMsg:
+		m.updates = msg.updates
+		m.loading = false
+		m.err = nil
+		if m.cursor >= len(m.updates) {
+			m.cursor = max(len(m.updates)-1, 0)
+		}
+		m.adjustOffset()
+		return m, nil
+
+	case updatesErrorMsg:
+		m.err = msg.err
+		m.loading = false
+		return m, nil
+
+	case ChangedMsg:
+		m.status = msg.Status
+		return m, m.loadData
+	}
+
+	return m, nil
+}
+
+// handleKeyPress handles keyboard input
+func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.adjustOffset()
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.updates)-1 {
+			m.cursor++
+			m.adjustOffset()
+		}
+
+	case "g":
+		m.cursor = 0
+		m.offset = 0
+
+	case "G":
+		if len(m.updates) > 0 {
+			m.cursor = len(m.updates) - 1
+			m.adjustOffset()
+		}
+
+	case "r":
+		// Refresh data
+		m.loading = true
+		m.status = ""
+		return m, m.loadData
+
+	case "enter":
+		// Open the chapter in the reader
+		if u := m.selected(); u != nil {
+			return m, func() tea.Msg {
+				return OpenChapterMsg{MangaID: u.MangaID, MangaTitle: u.MangaTitle, ChapterID: u.ChapterID}
+			}
+		}
+
+	case "m":
+		// Mark the chapter read
+		if u := m.selected(); u != nil && !u.IsRead {
+			return m, m.markRead(u)
+		}
+
+	case "d":
+		// Download the chapter
+		if u := m.selected(); u != nil {
+			return m, m.download(u)
+		}
+
+	case "x":
+		// Dismiss the update
+		if u := m.selected(); u != nil {
+			return m, m.dismiss(u)
+		}
+
+	case "X":
+		// Dismiss every read update
+		return m, m.dismissRead
+	}
+
+	return m, nil
+}
+
+// selected returns the update under the cursor, or nil
+func (m Model) selected() *storage.ChapterUpdate {
+	if m.cursor >= 0 && m.cursor < len(m.updates) {
+		return m.updates[m.cursor]
+	}
+	return nil
+}
+
+// visibleItems returns the number of updates that fit on screen, leaving
+// room for the header, footer and date headers
+func (m Model) visibleItems() int {
+	return max(m.height-14, 1)
+}
+
+// adjustOffset adjusts the scroll offset to keep cursor visible
+func (m *Model) adjustOffset() {
+	visible := m.visibleItems()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+// View renders the updates feed view
+func (m Model) View() string {
+	if m.loading {
+		return theme.CenteredText(m.width, m.height, "Loading updates...")
+	}
+
+	if m.err != nil {
+		return theme.CenteredText(m.width, m.height, fmt.Sprintf("Error: %v", m.err))
+	}
+
+	var b strings.Builder
+
+	// Header
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderFeed())
+	b.WriteString("\n")
+
+	if m.status != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.ColorSecondary).Render(m.status))
+		b.WriteString("\n")
+	}
+
+	// Footer
+	b.WriteString(m.renderFooter())
+
+	// Apply consistent horizontal padding/centering
+	content := b.String()
+	maxWidth := 120
+	if m.width < maxWidth {
+		maxWidth = m.width - 4
+	}
+
+	contentStyle := lipgloss.NewStyle().
+		Width(maxWidth).
+		Padding(0, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Top,
+		contentStyle.Render(content),
+	)
+}
+
+// renderHeader renders the header
+func (m Model) renderHeader() string {
+	title := theme.TitleStyle.Render("Updates")
+
+	unread := 0
+	for _, u := range m.updates {
+		if !u.IsRead {
+			unread++
+		}
+	}
+	info := lipgloss.NewStyle().
+		Foreground(theme.ColorMuted).
+		Render(fmt.Sprintf("%d new chapters · %d unread", len(m.updates), unread))
+
+	return title + "\n" + info
+}
+
+// renderFeed renders the updates grouped by the day they were found
+func (m Model) renderFeed() string {
+	if len(m.updates) == 0 {
+		return theme.CenteredText(m.width, m.height-10, "No new chapters\n\nUpdate the library from the home screen to check for new chapters")
+	}
+
+	var b strings.Builder
+	visible := m.visibleItems()
+	lastLabel := ""
+
+	for i, u := range m.updates {
+		if i < m.offset || i >= m.offset+visible {
+			continue
+		}
+
+		// Date header, repeated at the top when scrolled into a group
+		if label := dayLabel(u.FoundAt, time.Now()); label != lastLabel {
+			if lastLabel != "" {
+				b.WriteString("\n")
+			}
+			b.WriteString(theme.SectionStyle.Render(label))
+			b.WriteString("\n")
+			lastLabel = label
+		}
+
+		b.WriteString(m.renderUpdate(u, i == m.cursor))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderUpdate renders one update line
+func (m Model) renderUpdate(u *storage.ChapterUpdate, isCursor bool) string {
+	marker := "●"
+	if u.IsRead {
+		marker = " "
+	}
+
+	chapterStr := fmt.Sprintf("Ch. %g", u.ChapterNumber)
+	if u.ChapterTitle != "" {
+		chapterStr += ": " + u.ChapterTitle
+	}
+
+	var download string
+	if m.downloadManager != nil {
+		if m.downloadManager.IsDownloaded(u.ChapterID) {
+			download = "  ✓ downloaded"
+		} else if m.downloadManager.IsQueued(u.ChapterID) {
+			download = "  ↓ queued"
+		}
+	}
+
+	line := fmt.Sprintf("  %s %s - %s  %s%s",
+		marker,
+		u.FoundAt.Local().Format("15:04"),
+		u.MangaTitle,
+		lipgloss.NewStyle().Foreground(theme.ColorMuted).Render(chapterStr),
+		lipgloss.NewStyle().Foreground(theme.ColorSuccess).Render(download),
+	)
+
+	itemStyle := lipgloss.NewStyle()
+	if isCursor {
+		itemStyle = itemStyle.
+			Background(theme.ColorPrimary).
+			Foreground(lipgloss.Color("#000000")).
+			Bold(true).
+			Width(m.width - 4)
+	} else if u.IsRead {
+		itemStyle = itemStyle.Foreground(theme.ColorMuted)
+	}
+	return itemStyle.Render(line)
+}
+
+// renderFooter renders the footer with controls
+func (m Model) renderFooter() string {
+	controls := []string{
+		"↑↓/jk: navigate",
+		"Enter: read",
+		"m: mark read",
+		"d: download",
+		"x: dismiss",
+		"X: dismiss read",
+		"r: refresh",
+		"Esc: back",
+	}
+	return theme.HelpStyle.Render(strings.Join(controls, " • "))
+}
+
+// dayLabel names the day a chapter was found: Today, Yesterday, or its date
+func dayLabel(t, now time.Time) string {
+	t, now = t.Local(), now.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case day.Year() == today.Year():
+		return day.Format("Monday, January 2")
+	default:
+		return day.Format("Monday, January 2, 2006")
+	}
+}
+
+// Messages
+
+type updatesLoadedMsg struct {
+	updates []*storage.ChapterUpdate
+}
+
+type updatesErrorMsg struct {
+	err error
+}
+
+// ChangedMsg is sent when the feed changed, e.g. an update was read or
+// dismissed, so the unread count can be refreshed
+type ChangedMsg struct {
+	Status string
+}
+
+// OpenChapterMsg is sent to open a chapter of the feed in the reader
+type OpenChapterMsg struct {
+	MangaID    string
+	MangaTitle string
+	ChapterID  string
+}
+
+// Commands
+
+func (m Model) loadData() tea.Msg {
+	if m.storage == nil {
+		return updatesErrorMsg{err: fmt.Errorf("storage not available")}
+	}
+
+	updates, err := m.storage.Updates.GetUpdates(feedLimit)
+	if err != nil {
+		return updatesErrorMsg{err: fmt.Errorf("failed to load updates: %w", err)}
+	}
+	return updatesLoadedMsg{updates: updates}
+}
+
+// markRead marks the chapter of an update read in local progress
+func (m Model) markRead(u *storage.ChapterUpdate) tea.Cmd {
+	st := m.storage
+	return func() tea.Msg {
+		if err := st.Progress.MarkAsCompleted(u.MangaID, u.ChapterID, u.PageCount); err != nil {
+			return ChangedMsg{Status: fmt.Sprintf("Failed to mark read: %v", err)}
+		}
+		return ChangedMsg{Status: fmt.Sprintf("Marked %s Ch. %g read", u.MangaTitle, u.ChapterNumber)}
+	}
+}
+
+// download queues the chapter of an update for download
+func (m Model) download(u *storage.ChapterUpdate) tea.Cmd {
+	manager := m.downloadManager
+	return func() tea.Msg {
+		if manager == nil {
+			return ChangedMsg{Status: "Downloads are unavailable"}
+		}
+
+		sourceType := source.SourceType(u.SourceType)
+		manga := &source.Manga{ID: u.MangaID, Title: u.MangaTitle, SourceType: sourceType}
+		chapter := &source.Chapter{
+			ID:            u.ChapterID,
+			MangaID:       u.MangaID,
+			Title:         u.ChapterTitle,
+			ChapterNumber: u.ChapterNumber,
+			PageCount:     u.PageCount,
+			SourceType:    sourceType,
+			SourceID:      u.SourceID,
+		}
+		if err := manager.Add(manga, chapter, downloads.PriorityNormal); err != nil {
+			return ChangedMsg{Status: fmt.Sprintf("Not queued: %v", err)}
+		}
+		return ChangedMsg{Status: fmt.Sprintf("Queued %s Ch. %g for download", u.MangaTitle, u.ChapterNumber)}
+	}
+}
+
+// dismiss removes an update from the feed
+func (m Model) dismiss(u *storage.ChapterUpdate) tea.Cmd {
+	st := m.storage
+	return func() tea.Msg {
+		if err := st.Updates.Dismiss(u.ID); err != nil {
+			return ChangedMsg{Status: fmt.Sprintf("Failed to dismiss: %v", err)}
+		}
+		return ChangedMsg{}
+	}
+}
+
+// dismissRead removes every read update from the feed
+func (m Model) dismissRead() tea.Msg {
+	if m.storage == nil {
+		return nil
+	}
+	count, err := m.storage.Updates.DismissRead()
+	if err != nil {
+		return ChangedMsg{Status: fmt.Sprintf("Failed to dismiss: %v", err)}
+	}
+	return ChangedMsg{Status: fmt.Sprintf("Dismissed %d read chapters", count)}
+}
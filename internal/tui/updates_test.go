@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/config"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateConfigFrom(t *testing.T) {
+	defaults := storage.DefaultSmartUpdateConfig()
+
+	tests := []struct {
+		name     string
+		updates  config.UpdateConfig
+		interval time.Duration
+		smart    storage.SmartUpdateConfig
+	}{
+		{
+			name:     "zero values keep the smart update defaults",
+			updates:  config.UpdateConfig{UpdateOnlyStarted: true},
+			interval: 0,
+			smart: storage.SmartUpdateConfig{
+				MinIntervalHours:       defaults.MinIntervalHours,
+				MaxConsecutiveFailures: defaults.MaxConsecutiveFailures,
+				MultiplyIntervalBy:     defaults.MultiplyIntervalBy,
+			},
+		},
+		{
+			name: "configured values",
+			updates: config.UpdateConfig{
+				SmartUpdate:            true,
+				MinIntervalHours:       6,
+				UpdateOnlyOngoing:      true,
+				UpdateOnlyStarted:      true,
+				MaxConsecutiveFailures: 3,
+				IntervalMultiplier:     2,
+				AutoUpdateEnabled:      true,
+				AutoUpdateIntervalHrs:  8,
+			},
+			interval: 8 * time.Hour,
+			smart: storage.SmartUpdateConfig{
+				MinIntervalHours:       6,
+				UpdateOnlyOngoing:      true,
+				MaxConsecutiveFailures: 3,
+				MultiplyIntervalBy:     2,
+			},
+		},
+		{
+			name:    "interval without automatic updates",
+			updates: config.UpdateConfig{AutoUpdateIntervalHrs: 8},
+			smart: storage.SmartUpdateConfig{
+				MinIntervalHours:       defaults.MinIntervalHours,
+				MaxConsecutiveFailures: defaults.MaxConsecutiveFailures,
+				MultiplyIntervalBy:     defaults.MultiplyIntervalBy,
+			},
+		},
+		{
+			name:    "automatic updates without an interval",
+			updates: config.UpdateConfig{AutoUpdateEnabled: true},
+			smart: storage.SmartUpdateConfig{
+				MinIntervalHours:       defaults.MinIntervalHours,
+				MaxConsecutiveFailures: defaults.MaxConsecutiveFailures,
+				MultiplyIntervalBy:     defaults.MultiplyIntervalBy,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := updateConfigFrom(&config.Config{Updates: tt.updates})
+
+			assert.Equal(t, tt.interval, got.Interval)
+			assert.Equal(t, tt.updates.UpdateOnlyStarted, got.UpdateOnlyStarted)
+			assert.Equal(t, tt.updates.SmartUpdate, got.SmartUpdate)
+			// The updater filters unread manga itself
+			assert.Equal(t, tt.smart, *got.SmartUpdateConfig)
+		})
+	}
+}
+
+func TestTrySend(t *testing.T) {
+	events := make(chan tea.Msg, 1)
+
+	assert.True(t, trySend(events, libraryUpdatedMsg{}))
+	assert.False(t, trySend(events, libraryUpdatedMsg{}), "a full channel does not block the updater")
+	assert.Len(t, events, 1)
+}
+
+func TestRenderUpdateSection_DroppedResult(t *testing.T) {
+	server := suwayomitest.NewServer()
+	defer server.Close()
+	server.SeedLibrary(2, 1, 1)
+	sm := source.NewSourceManager()
+	sm.AddSource(source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client()))
+
+	st, err := storage.NewStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Updates.UpdateOnlyStarted = false
+	feed := &notificationFeed{events: make(chan tea.Msg, 4)}
+	updater, events := newUpdater(cfg, sm, st, nil, feed)
+	m := AppModel{updater: updater, updateEvents: events}
+	assert.Contains(t, m.renderUpdateSection(), "No update yet this session")
+
+	// A TUI that fell behind misses the result of the update
+	for trySend(events, updateProgressMsg{}) {
+	}
+	_, err = updater.UpdateLibrary()
+	require.NoError(t, err)
+
+	assert.Contains(t, m.renderUpdateSection(), "2 manga checked")
+}
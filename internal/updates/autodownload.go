@@ -0,0 +1,199 @@
+package updates
+
+import (
+	"sort"
+
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+)
+
+// AutoDownloadRule selects new chapters to download after an update
+type AutoDownloadRule struct {
+	Categories         []string // Categories the rule applies to, empty for every manga
+	OnlyIfReadPrevious bool     // Only download when the chapter before the new ones is read
+	KeepAhead          int      // Max unread chapters downloaded or queued per manga, 0 for no limit
+	Priority           int      // Download priority, lower first; 0 uses downloads.PriorityAuto
+}
+
+// matches reports whether the rule applies to a manga in the given categories
+func (r *AutoDownloadRule) matches(categories map[string]bool) bool {
+	if len(r.Categories) == 0 {
+		return true
+	}
+	for _, name := range r.Categories {
+		if categories[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoDownloader queues new chapters found by library updates according to
+// its rules. The first rule matching a manga decides for it.
+type AutoDownloader struct {
+	rules   []AutoDownloadRule
+	manager *downloads.Manager
+	storage *storage.Storage
+}
+
+// NewAutoDownloader creates an auto-downloader. st may be nil, in which case
+// rules with categories never match and only the source's read state is used.
+func NewAutoDownloader(rules []AutoDownloadRule, manager *downloads.Manager, st *storage.Storage) *AutoDownloader {
+	return &AutoDownloader{
+		rules:   rules,
+		manager: manager,
+		storage: st,
+	}
+}
+
+// Process evaluates the rules for every task of an update that found new
+// chapters and queues the chapters they select. It returns the number of
+// chapters queued.
+func (a *AutoDownloader) Process(summary *UpdateSummary) int {
+	queued := 0
+	for _, task := range summary.Tasks {
+		if task.Status != StatusCompleted || !task.HasNewChapters() {
+			continue
+		}
+
+		rule := a.ruleFor(task.MangaID)
+		if rule == nil {
+			continue
+		}
+
+		for _, chapter := range a.selectChapters(rule, task) {
+			if err := a.manager.Add(a.mangaFor(task), chapter, rule.priority()); err == nil {
+				queued++
+			}
+		}
+	}
+	return queued
+}
+
+// priority returns the download priority of the rule's chapters
+func (r *AutoDownloadRule) priority() int {
+	if r.Priority == 0 {
+		return downloads.PriorityAuto
+	}
+	return r.Priority
+}
+
+// ruleFor returns the first rule matching a manga, or nil
+func (a *AutoDownloader) ruleFor(mangaID string) *AutoDownloadRule {
+	categories := make(map[string]bool)
+	if a.storage != nil && a.storage.Categories != nil {
+		assigned, err := a.storage.Categories.GetMangaCategories(mangaID)
+		if err == nil {
+			for _, category := range assigned {
+				categories[category.Name] = true
+			}
+		}
+	}
+
+	for i := range a.rules {
+		if a.rules[i].matches(categories) {
+			return &a.rules[i]
+		}
+	}
+	return nil
+}
+
+// selectChapters returns the new chapters of a task the rule downloads, in
+// reading order
+func (a *AutoDownloader) selectChapters(rule *AutoDownloadRule, task *UpdateTask) []*source.Chapter {
+	chapters := sortedChapters(task.Chapters)
+	added := sortedChapters(task.NewChapters)
+	if len(added) == 0 {
+		return nil
+	}
+
+	read := a.readChapters(task.MangaID)
+	isRead := func(c *source.Chapter) bool {
+		return c.IsRead || read[c.ID]
+	}
+
+	isNew := make(map[string]bool, len(added))
+	for _, c := range added {
+		isNew[c.ID] = true
+	}
+
+	if rule.OnlyIfReadPrevious {
+		// The latest chapter before the new ones must be read. Manga without
+		// earlier chapters have nothing to catch up on.
+		var previous *source.Chapter
+		for _, c := range chapters {
+			if !isNew[c.ID] {
+				previous = c
+			}
+		}
+		if previous != nil && !isRead(previous) {
+			return nil
+		}
+	}
+
+	// Unread chapters already on disk or queued count towards KeepAhead
+	ahead := 0
+	for _, c := range chapters {
+		if isNew[c.ID] || isRead(c) {
+			continue
+		}
+		if a.manager.IsDownloaded(c.ID) || a.manager.IsQueued(c.ID) {
+			ahead++
+		}
+	}
+
+	var selected []*source.Chapter
+	for _, c := range added {
+		if rule.KeepAhead > 0 && ahead >= rule.KeepAhead {
+			break
+		}
+		if isRead(c) || a.manager.IsDownloaded(c.ID) || a.manager.IsQueued(c.ID) {
+			continue
+		}
+		selected = append(selected, c)
+		ahead++
+	}
+	return selected
+}
+
+// sortedChapters returns a copy of the chapters in reading order
+func sortedChapters(chapters []*source.Chapter) []*source.Chapter {
+	sorted := make([]*source.Chapter, len(chapters))
+	copy(sorted, chapters)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ChapterNumber < sorted[j].ChapterNumber
+	})
+	return sorted
+}
+
+// readChapters returns the chapters of a manga marked read in local progress
+func (a *AutoDownloader) readChapters(mangaID string) map[string]bool {
+	read := make(map[string]bool)
+	if a.storage == nil || a.storage.Progress == nil {
+		return read
+	}
+
+	entries, err := a.storage.Progress.GetMangaProgress(mangaID)
+	if err != nil {
+		return read
+	}
+	for _, entry := range entries {
+		if entry.IsCompleted {
+			read[entry.ChapterID] = true
+		}
+	}
+	return read
+}
+
+// mangaFor returns the manga of a task for the download queue
+func (a *AutoDownloader) mangaFor(task *UpdateTask) *source.Manga {
+	if task.Manga != nil {
+		return task.Manga
+	}
+	return &source.Manga{
+		ID:         task.MangaID,
+		Title:      task.MangaTitle,
+		SourceType: task.SourceType,
+	}
+}
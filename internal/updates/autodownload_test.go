@@ -0,0 +1,260 @@
+package updates
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Justice-Caban/Miryokusha/internal/downloads"
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autoDownloadEnv holds storage with categories and a download manager that
+// only queues, since it is never started
+type autoDownloadEnv struct {
+	storage *storage.Storage
+	manager *downloads.Manager
+}
+
+func newAutoDownloadEnv(t *testing.T) *autoDownloadEnv {
+	t.Helper()
+	st, err := storage.NewStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	manager := downloads.NewManager(&downloads.DownloadConfig{DownloadPath: t.TempDir()}, source.NewSourceManager())
+	manager.SetStore(st.Downloads)
+	return &autoDownloadEnv{storage: st, manager: manager}
+}
+
+// categorize puts a manga in a category, creating it if needed
+func (env *autoDownloadEnv) categorize(t *testing.T, mangaID, name string) {
+	t.Helper()
+	categories, err := env.storage.Categories.GetAll()
+	require.NoError(t, err)
+	var category *storage.Category
+	for _, c := range categories {
+		if c.Name == name {
+			category = c
+		}
+	}
+	if category == nil {
+		category, err = env.storage.Categories.Create(name, false)
+		require.NoError(t, err)
+	}
+	require.NoError(t, env.storage.Categories.AssignManga(mangaID, category.ID))
+}
+
+// queued returns the queued chapter IDs with their priorities
+func (env *autoDownloadEnv) queued() map[string]int {
+	queued := make(map[string]int)
+	for _, item := range env.manager.GetQueue() {
+		queued[item.ChapterID] = item.Priority
+	}
+	return queued
+}
+
+// updateTask returns a completed task for a manga with chapters 1 to total,
+// the last added of which are new. Chapter IDs are "<manga>-<number>".
+func updateTask(mangaID string, total, added int) *UpdateTask {
+	task := &UpdateTask{
+		MangaID:    mangaID,
+		MangaTitle: "Manga " + mangaID,
+		SourceType: source.SourceTypeSuwayomi,
+		Status:     StatusCompleted,
+	}
+	for i := 1; i <= total; i++ {
+		chapter := &source.Chapter{
+			ID:            fmt.Sprintf("%s-%d", mangaID, i),
+			MangaID:       mangaID,
+			ChapterNumber: float64(i),
+			SourceType:    source.SourceTypeSuwayomi,
+		}
+		task.Chapters = append(task.Chapters, chapter)
+		if i > total-added {
+			task.NewChapters = append(task.NewChapters, chapter)
+		}
+	}
+	task.NewChapterCount = len(task.NewChapters)
+	task.OldChapterCount = total - added
+	return task
+}
+
+func TestAutoDownloader_RuleMatching(t *testing.T) {
+	env := newAutoDownloadEnv(t)
+	env.categorize(t, "reading", "Reading")
+	env.categorize(t, "archive", "Archive")
+	env.categorize(t, "both", "Archive")
+	env.categorize(t, "both", "Reading")
+
+	rules := []AutoDownloadRule{
+		{Categories: []string{"Reading"}, Priority: 5},
+		{Categories: []string{"Weekly"}},
+	}
+	failed := updateTask("failed", 2, 1)
+	failed.Status = StatusFailed
+	summary := &UpdateSummary{Tasks: []*UpdateTask{
+		updateTask("reading", 2, 1),
+		updateTask("archive", 2, 1),
+		updateTask("both", 2, 1),
+		updateTask("none", 2, 1),
+		updateTask("unchanged", 2, 0),
+		failed,
+	}}
+
+	queued := NewAutoDownloader(rules, env.manager, env.storage).Process(summary)
+
+	// Only manga in a category of a rule are downloaded, with its priority
+	assert.Equal(t, 2, queued)
+	assert.Equal(t, map[string]int{"reading-2": 5, "both-2": 5}, env.queued())
+
+	// A rule without categories matches every manga, at the default priority
+	env = newAutoDownloadEnv(t)
+	rules = []AutoDownloadRule{{}}
+	queued = NewAutoDownloader(rules, env.manager, env.storage).Process(summary)
+	assert.Equal(t, 4, queued)
+	assert.Equal(t, downloads.PriorityAuto, env.queued()["none-2"])
+}
+
+func TestAutoDownloader_FirstMatchingRuleWins(t *testing.T) {
+	env := newAutoDownloadEnv(t)
+	env.categorize(t, "m", "Reading")
+
+	rules := []AutoDownloadRule{
+		{Categories: []string{"Reading"}, KeepAhead: 1, Priority: 30},
+		{Priority: 1},
+	}
+	summary := &UpdateSummary{Tasks: []*UpdateTask{updateTask("m", 5, 3), updateTask("other", 2, 1)}}
+
+	assert.Equal(t, 2, NewAutoDownloader(rules, env.manager, env.storage).Process(summary))
+	assert.Equal(t, map[string]int{"m-3": 30, "other-2": 1}, env.queued())
+}
+
+func TestAutoDownloader_OnlyIfReadPrevious(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, env *autoDownloadEnv, task *UpdateTask)
+		want  []string
+	}{
+		{
+			name:  "previous chapter unread",
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {},
+		},
+		{
+			name: "previous chapter read on the server",
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				task.Chapters[1].IsRead = true
+			},
+			want: []string{"m-3", "m-4"},
+		},
+		{
+			name: "previous chapter read locally",
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				require.NoError(t, env.storage.Progress.MarkAsCompleted("m", "m-2", 10))
+			},
+			want: []string{"m-3", "m-4"},
+		},
+		{
+			name: "only an older chapter read",
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				task.Chapters[0].IsRead = true
+			},
+		},
+		{
+			name: "no earlier chapters",
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				task.NewChapters = task.Chapters
+				task.NewChapterCount = len(task.Chapters)
+			},
+			want: []string{"m-1", "m-2", "m-3", "m-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newAutoDownloadEnv(t)
+			task := updateTask("m", 4, 2)
+			tt.setup(t, env, task)
+
+			rules := []AutoDownloadRule{{OnlyIfReadPrevious: true}}
+			queued := NewAutoDownloader(rules, env.manager, env.storage).Process(&UpdateSummary{Tasks: []*UpdateTask{task}})
+
+			assert.Equal(t, len(tt.want), queued)
+			var ids []string
+			for _, item := range env.manager.GetQueue() {
+				ids = append(ids, item.ChapterID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
+func TestAutoDownloader_KeepAhead(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepAhead int
+		setup     func(t *testing.T, env *autoDownloadEnv, task *UpdateTask)
+		want      []string
+	}{
+		{
+			name: "no limit",
+			want: []string{"m-3", "m-4", "m-5", "m-6"},
+		},
+		{
+			name:      "limited in reading order",
+			keepAhead: 2,
+			want:      []string{"m-3", "m-4"},
+		},
+		{
+			name:      "unread chapters already queued count",
+			keepAhead: 2,
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				manga := &source.Manga{ID: "m", Title: "Manga m"}
+				require.NoError(t, env.manager.Add(manga, task.Chapters[1], downloads.PriorityNormal))
+			},
+			want: []string{"m-2", "m-3"},
+		},
+		{
+			name:      "unread chapters already downloaded count",
+			keepAhead: 2,
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				require.NoError(t, env.storage.Downloads.MarkDownloaded(&storage.DownloadedChapter{ChapterID: "m-1", MangaID: "m", Path: t.TempDir()}))
+				require.NoError(t, env.storage.Downloads.MarkDownloaded(&storage.DownloadedChapter{ChapterID: "m-2", MangaID: "m", Path: t.TempDir()}))
+			},
+		},
+		{
+			name:      "read chapters do not count",
+			keepAhead: 2,
+			setup: func(t *testing.T, env *autoDownloadEnv, task *UpdateTask) {
+				task.Chapters[0].IsRead = true
+				require.NoError(t, env.storage.Downloads.MarkDownloaded(&storage.DownloadedChapter{ChapterID: "m-1", MangaID: "m", Path: t.TempDir()}))
+				task.Chapters[3].IsRead = true
+			},
+			want: []string{"m-3", "m-5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newAutoDownloadEnv(t)
+			task := updateTask("m", 6, 4)
+			if tt.setup != nil {
+				tt.setup(t, env, task)
+			}
+			before := len(env.manager.GetQueue())
+
+			rules := []AutoDownloadRule{{KeepAhead: tt.keepAhead}}
+			queued := NewAutoDownloader(rules, env.manager, env.storage).Process(&UpdateSummary{Tasks: []*UpdateTask{task}})
+
+			assert.Equal(t, len(env.manager.GetQueue())-before, queued)
+			var ids []string
+			for _, item := range env.manager.GetQueue() {
+				ids = append(ids, item.ChapterID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
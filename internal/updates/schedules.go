@@ -0,0 +1,108 @@
+package updates
+
+import (
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+)
+
+// SchedulePresets are the update schedules offered for categories and
+// manga, in the order they are cycled through
+var SchedulePresets = []storage.UpdateSchedule{
+	{Mode: storage.UpdateModeDefault},
+	{Mode: storage.UpdateModeEvery, Interval: 6 * time.Hour},
+	{Mode: storage.UpdateModeEvery, Interval: 12 * time.Hour},
+	{Mode: storage.UpdateModeEvery, Interval: 24 * time.Hour},
+	{Mode: storage.UpdateModeEvery, Interval: 7 * 24 * time.Hour},
+	{Mode: storage.UpdateModeNever},
+}
+
+// NextSchedule returns the preset after a schedule, or the first preset for
+// a schedule that is not one
+func NextSchedule(schedule storage.UpdateSchedule) storage.UpdateSchedule {
+	for i, preset := range SchedulePresets {
+		if preset == schedule {
+			return SchedulePresets[(i+1)%len(SchedulePresets)]
+		}
+	}
+	return SchedulePresets[0]
+}
+
+// applySchedules splits manga into those checked on a schedule of their own
+// and those following the global settings. Manga never checked, and in
+// scheduled updates manga whose interval has not passed, are left out and
+// counted as skipped.
+func (u *Updater) applySchedules(allManga []*source.Manga, scheduled bool) (own, global []*source.Manga, skipped int) {
+	schedules := u.updateSchedules()
+
+	var lastChecks map[string]time.Time
+	u.mu.RLock()
+	tick, interval := u.tick, u.config.Interval
+	u.mu.RUnlock()
+	if scheduled && u.storage != nil && u.storage.UpdateTracking != nil {
+		// Without the last checks every manga is due
+		lastChecks, _ = u.storage.UpdateTracking.GetLastChecks()
+	}
+	now := u.now()
+
+	for _, m := range allManga {
+		schedule := schedules[m.ID]
+		switch schedule.Mode {
+		case storage.UpdateModeNever:
+			skipped++
+		case storage.UpdateModeEvery:
+			if scheduled && !isDue(lastChecks, m.ID, schedule.Interval, tick, now) {
+				skipped++
+				continue
+			}
+			own = append(own, m)
+		default:
+			// The scheduler also runs for shorter schedules, more often
+			// than the global interval or without one
+			if scheduled && (interval == 0 || tick < interval && !isDue(lastChecks, m.ID, interval, tick, now)) {
+				skipped++
+				continue
+			}
+			global = append(global, m)
+		}
+	}
+	return own, global, skipped
+}
+
+// updateSchedules returns the update schedules of categories and manga,
+// or none without storage
+func (u *Updater) updateSchedules() map[string]storage.UpdateSchedule {
+	if u.storage == nil || u.storage.Categories == nil {
+		return nil
+	}
+	schedules, err := u.storage.Categories.GetUpdateSchedules()
+	if err != nil {
+		// The global settings still apply
+		return nil
+	}
+	return schedules
+}
+
+// schedulerInterval returns how often the scheduler runs: the global
+// interval or the shortest schedule, 0 if nothing is scheduled
+func schedulerInterval(global time.Duration, schedules map[string]storage.UpdateSchedule) time.Duration {
+	tick := global
+	for _, schedule := range schedules {
+		if schedule.Mode == storage.UpdateModeEvery && (tick == 0 || schedule.Interval < tick) {
+			tick = schedule.Interval
+		}
+	}
+	return tick
+}
+
+// isDue reports whether a manga's interval has passed since its last
+// check. Half a tick of slack keeps a check that finished a little after
+// the previous tick from waiting for the next one.
+func isDue(lastChecks map[string]time.Time, mangaID string, interval, tick time.Duration, now time.Time) bool {
+	lastCheck, ok := lastChecks[mangaID]
+	if !ok {
+		return true
+	}
+	return now.Sub(lastCheck) >= interval-tick/2
+}
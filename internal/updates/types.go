@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
 )
 
 // UpdateStatus represents the current state of an update task
@@ -21,13 +22,16 @@ type UpdateTask struct {
 	MangaID       string
 	MangaTitle    string
 	SourceType    source.SourceType
+	Manga         *source.Manga // Manga checked, for queueing its chapters
 	Status        UpdateStatus
 	Error         error
 
 	// Update results
-	OldChapterCount int
-	NewChapterCount int
-	NewChapters     []*source.Chapter
+	OldChapterCount int               // Chapters known before the check
+	NewChapterCount int               // Chapters found that were not known
+	Chapters        []*source.Chapter // Every chapter of the manga
+	NewChapters     []*source.Chapter // The chapters that were not known
+	FirstCheck      bool              // No chapters were known, so none count as new
 
 	// Timing
 	StartedAt   time.Time
@@ -36,15 +40,12 @@ type UpdateTask struct {
 
 // HasNewChapters returns true if new chapters were found
 func (ut *UpdateTask) HasNewChapters() bool {
-	return ut.NewChapterCount > ut.OldChapterCount
+	return ut.NewChapterCount > 0
 }
 
 // GetNewChapters returns the count of new chapters found
 func (ut *UpdateTask) GetNewChapters() int {
-	if ut.NewChapterCount > ut.OldChapterCount {
-		return ut.NewChapterCount - ut.OldChapterCount
-	}
-	return 0
+	return ut.NewChapterCount
 }
 
 // UpdateSummary summarizes an update session
@@ -55,7 +56,10 @@ type UpdateSummary struct {
 	UpdatedManga int // Manga with new chapters
 	FailedManga int
 	NewChapters int
+	SkippedManga int // Manga smart update did not expect to have new chapters
 	Tasks       []*UpdateTask
+
+	AutoDownloaded int // New chapters queued by auto-download rules
 }
 
 // Duration returns how long the update took
@@ -66,6 +70,17 @@ func (us *UpdateSummary) Duration() time.Duration {
 	return us.CompletedAt.Sub(us.StartedAt)
 }
 
+// UpdateProgress is a snapshot of a library update in progress
+type UpdateProgress struct {
+	StartedAt    time.Time
+	Total        int      // Manga to check
+	Checked      int      // Manga checked so far
+	UpdatedManga int      // Manga with new chapters so far
+	NewChapters  int
+	Failed       int
+	Checking     []string // Titles of the manga being checked, sorted
+}
+
 // UpdateConfig holds configuration for library updates
 type UpdateConfig struct {
 	// Update interval (0 = manual only)
@@ -79,8 +94,8 @@ type UpdateConfig struct {
 	MaxConcurrent int // Max concurrent update checks
 
 	// Smart update (skip manga unlikely to have updates)
-	SmartUpdate        bool
-	SmartUpdateMinDays int // Min days since last update to check again
+	SmartUpdate       bool
+	SmartUpdateConfig *storage.SmartUpdateConfig // Smart update rules, nil for the defaults
 
 	// Notifications
 	NotifyNewChapters bool
@@ -95,31 +110,7 @@ func DefaultUpdateConfig() *UpdateConfig {
 		UpdateOnlyCompleted: false,
 		MaxConcurrent:      5,
 		SmartUpdate:        false,
-		SmartUpdateMinDays: 1,
 		NotifyNewChapters:  true,
-		NotifyFailures:     false,
+		NotifyFailures:     true,
 	}
 }
-
-// Notification represents an update notification
-type Notification struct {
-	ID        string
-	Type      NotificationType
-	Title     string
-	Message   string
-	CreatedAt time.Time
-	Read      bool
-
-	// Related data
-	MangaID   string
-	ChapterID string
-}
-
-// NotificationType represents the type of notification
-type NotificationType string
-
-const (
-	NotifyNewChapter    NotificationType = "new_chapter"
-	NotifyUpdateComplete NotificationType = "update_complete"
-	NotifyUpdateFailed   NotificationType = "update_failed"
-)
@@ -2,7 +2,10 @@ package updates
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,6 +13,10 @@ import (
 	"github.com/Justice-Caban/Miryokusha/internal/storage"
 )
 
+// ErrUpdateInProgress is returned when a library update is requested while
+// another one is running
+var ErrUpdateInProgress = errors.New("library update already in progress")
+
 // Updater manages library updates
 type Updater struct {
 	mu sync.RWMutex
@@ -20,11 +27,18 @@ type Updater struct {
 
 	// Update state
 	running         bool
+	updating        bool              // A library update is in progress
+	checking        map[string]string // Titles of the manga being checked, by ID
 	currentSummary  *UpdateSummary
 	updateHistory   []*UpdateSummary
-	notifications   []*Notification
+
+	// Queues new chapters after each update, if set
+	autoDownloader *AutoDownloader
+
+	now func() time.Time
 
 	// Scheduling
+	tick   time.Duration // Time between scheduled updates
 	ticker *time.Ticker
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -32,7 +46,7 @@ type Updater struct {
 	// Callbacks
 	onProgress     func(*UpdateTask)
 	onComplete     func(*UpdateSummary)
-	onNotification func(*Notification)
+	onNotification func(*storage.Notification)
 }
 
 // NewUpdater creates a new library updater
@@ -46,24 +60,49 @@ func NewUpdater(config *UpdateConfig, sm *source.SourceManager, st *storage.Stor
 		sourceManager:  sm,
 		storage:        st,
 		updateHistory:  make([]*UpdateSummary, 0),
-		notifications:  make([]*Notification, 0),
+		checking:       make(map[string]string),
+		now:            time.Now,
 	}
 }
 
-// Start starts the automatic update scheduler
+// Start starts the automatic update scheduler. It runs at the global
+// interval, or more often when a category or manga has a shorter schedule.
 func (u *Updater) Start() {
+	schedules := u.updateSchedules()
+
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	if u.running || u.config.Interval == 0 {
+	if u.running {
+		return
+	}
+	tick := schedulerInterval(u.config.Interval, schedules)
+	if tick == 0 {
 		return
 	}
 
 	u.running = true
+	u.tick = tick
 	u.ctx, u.cancel = context.WithCancel(context.Background())
-	u.ticker = time.NewTicker(u.config.Interval)
+	u.ticker = time.NewTicker(tick)
 
-	go u.scheduleUpdates()
+	go u.scheduleUpdates(u.ctx, u.ticker)
+}
+
+// RefreshSchedules restarts the scheduler after update schedules of
+// categories or manga changed, so shorter schedules are run on time
+func (u *Updater) RefreshSchedules() {
+	tick := schedulerInterval(u.Interval(), u.updateSchedules())
+
+	u.mu.RLock()
+	unchanged := u.running && u.tick == tick
+	u.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	u.Stop()
+	u.Start()
 }
 
 // Stop stops the automatic update scheduler
@@ -84,18 +123,148 @@ func (u *Updater) Stop() {
 	}
 }
 
-// UpdateLibrary performs a full library update
+// SetInterval changes the time between automatic updates and restarts the
+// scheduler with it. An interval of 0 stops automatic updates.
+func (u *Updater) SetInterval(interval time.Duration) {
+	u.mu.RLock()
+	unchanged := u.config.Interval == interval
+	u.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	u.Stop()
+	u.mu.Lock()
+	u.config.Interval = interval
+	u.mu.Unlock()
+	u.Start()
+}
+
+// Interval returns the time between automatic updates, 0 for manual only
+func (u *Updater) Interval() time.Duration {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.config.Interval
+}
+
+// IsScheduled reports whether automatic updates are running
+func (u *Updater) IsScheduled() bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.running
+}
+
+// IsUpdating reports whether a library update is in progress
+func (u *Updater) IsUpdating() bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.updating
+}
+
+// Progress returns a snapshot of the library update in progress. The
+// second result is false when no update is running.
+func (u *Updater) Progress() (UpdateProgress, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if !u.updating || u.currentSummary == nil {
+		return UpdateProgress{}, false
+	}
+
+	summary := u.currentSummary
+	progress := UpdateProgress{
+		StartedAt:    summary.StartedAt,
+		Total:        summary.TotalManga,
+		Checked:      len(summary.Tasks),
+		UpdatedManga: summary.UpdatedManga,
+		NewChapters:  summary.NewChapters,
+		Failed:       summary.FailedManga,
+	}
+	for _, title := range u.checking {
+		progress.Checking = append(progress.Checking, title)
+	}
+	sort.Strings(progress.Checking)
+	return progress, true
+}
+
+// UpdateLibrary performs a full library update. Manga whose schedule is
+// "never" are skipped; manga on an interval are checked whether or not it
+// has passed, and smart update does not skip any. It returns
+// ErrUpdateInProgress if an update is already running.
 func (u *Updater) UpdateLibrary() (*UpdateSummary, error) {
+	return u.updateLibrary(false)
+}
+
+// updateLibrary performs a library update. Scheduled updates only check
+// manga whose interval, or the global one, has passed since their last
+// check, and that smart update expects to have new chapters.
+func (u *Updater) updateLibrary(scheduled bool) (*UpdateSummary, error) {
+	u.mu.Lock()
+	if u.updating {
+		u.mu.Unlock()
+		return nil, ErrUpdateInProgress
+	}
+	u.updating = true
+	u.mu.Unlock()
+
+	defer func() {
+		u.mu.Lock()
+		u.updating = false
+		u.mu.Unlock()
+	}()
+
 	// Get all manga from sources
 	allManga, err := u.sourceManager.ListAllManga()
 	if err != nil {
 		return nil, err
 	}
 
+	// Manga with a schedule of their own bypass the global filters and
+	// smart update
+	scheduledManga, manga, skipped := u.applySchedules(allManga, scheduled)
+
 	// Filter manga based on config
-	manga := u.filterManga(allManga)
+	manga = u.filterManga(manga)
+
+	// Skip manga not expected to have new chapters yet. Updates run by hand
+	// check them anyway, so manga smart update backs off from can recover.
+	if scheduled {
+		selected := u.smartSelect(manga)
+		skipped += len(manga) - len(selected)
+		manga = selected
+	}
+
+	return u.updateMangaList(append(scheduledManga, manga...), skipped)
+}
+
+// smartSelect returns the manga smart update expects to have new chapters.
+// Every manga is selected when smart update is off or tracking is missing.
+func (u *Updater) smartSelect(manga []*source.Manga) []*source.Manga {
+	if !u.config.SmartUpdate || u.storage == nil || u.storage.UpdateTracking == nil {
+		return manga
+	}
+
+	ids := make([]string, len(manga))
+	for i, m := range manga {
+		ids[i] = m.ID
+	}
+	selectedIDs, err := u.storage.UpdateTracking.GetMangaForSmartUpdate(u.config.SmartUpdateConfig, ids, u.now())
+	if err != nil {
+		// Checking too much beats missing chapters
+		return manga
+	}
 
-	return u.updateMangaList(manga)
+	include := make(map[string]bool, len(selectedIDs))
+	for _, id := range selectedIDs {
+		include[id] = true
+	}
+	selected := make([]*source.Manga, 0, len(selectedIDs))
+	for _, m := range manga {
+		if include[m.ID] {
+			selected = append(selected, m)
+		}
+	}
+	return selected
 }
 
 // UpdateManga updates a specific manga
@@ -134,77 +303,52 @@ func (u *Updater) GetUpdateHistory() []*UpdateSummary {
 	return history
 }
 
-// GetNotifications returns all notifications
-func (u *Updater) GetNotifications() []*Notification {
-	u.mu.RLock()
-	defer u.mu.RUnlock()
-
-	notifications := make([]*Notification, len(u.notifications))
-	copy(notifications, u.notifications)
-	return notifications
-}
-
-// ClearNotifications clears all notifications
-func (u *Updater) ClearNotifications() {
-	u.mu.Lock()
-	defer u.mu.Unlock()
-	u.notifications = make([]*Notification, 0)
-}
-
-// MarkNotificationRead marks a notification as read
-func (u *Updater) MarkNotificationRead(id string) {
+// SetAutoDownloader sets the auto-downloader run on every update summary,
+// or disables auto-download if nil
+func (u *Updater) SetAutoDownloader(a *AutoDownloader) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
-
-	for _, notif := range u.notifications {
-		if notif.ID == id {
-			notif.Read = true
-			break
-		}
-	}
+	u.autoDownloader = a
 }
 
 // SetCallbacks sets callback functions for update events
-func (u *Updater) SetCallbacks(onProgress func(*UpdateTask), onComplete func(*UpdateSummary), onNotification func(*Notification)) {
+func (u *Updater) SetCallbacks(onProgress func(*UpdateTask), onComplete func(*UpdateSummary), onNotification func(*storage.Notification)) {
 	u.onProgress = onProgress
 	u.onComplete = onComplete
 	u.onNotification = onNotification
 }
 
 // scheduleUpdates runs the automatic update scheduler
-func (u *Updater) scheduleUpdates() {
+func (u *Updater) scheduleUpdates(ctx context.Context, ticker *time.Ticker) {
 	for {
 		select {
-		case <-u.ctx.Done():
+		case <-ctx.Done():
 			return
-		case <-u.ticker.C:
-			// Perform library update
-			summary, err := u.UpdateLibrary()
-			if err != nil {
-				// Log error but continue
-				continue
-			}
-
-			// Notify if configured
-			if u.config.NotifyNewChapters && summary.NewChapters > 0 {
-				u.addNotification(&Notification{
-					ID:        fmt.Sprintf("update-%d", time.Now().Unix()),
-					Type:      NotifyUpdateComplete,
-					Title:     "Library Updated",
-					Message:   fmt.Sprintf("Found %d new chapters across %d manga", summary.NewChapters, summary.UpdatedManga),
-					CreatedAt: time.Now(),
+		case <-ticker.C:
+			// Perform library update; new chapters and failed manga are
+			// reported as they are checked
+			_, err := u.updateLibrary(true)
+			if err != nil && !errors.Is(err, ErrUpdateInProgress) && u.config.NotifyFailures {
+				u.addNotification(&storage.Notification{
+					Kind:      storage.NotifyUpdateFailed,
+					Severity:  storage.SeverityError,
+					Title:     "Library Update Failed",
+					Message:   err.Error(),
+					CreatedAt: u.now(),
 				})
 			}
 		}
 	}
 }
 
-// updateMangaList updates a list of manga
-func (u *Updater) updateMangaList(mangaList []*source.Manga) (*UpdateSummary, error) {
+// updateMangaList updates a list of manga; skipped is the number of manga
+// left out by smart update
+func (u *Updater) updateMangaList(mangaList []*source.Manga, skipped int) (*UpdateSummary, error) {
 	summary := &UpdateSummary{
-		StartedAt:  time.Now(),
-		TotalManga: len(mangaList),
-		Tasks:      make([]*UpdateTask, 0),
+		StartedAt:    u.now(),
+		TotalManga:   len(mangaList),
+		SkippedManga: skipped,
+		Tasks:        make([]*UpdateTask, 0),
 	}
 
 	u.mu.Lock()
@@ -212,9 +356,12 @@ func (u *Updater) updateMangaList(mangaList []*source.Manga) (*UpdateSummary, er
 	u.mu.Unlock()
 
 	// Use semaphore to limit concurrency
-	sem := make(chan struct{}, u.config.MaxConcurrent)
+	maxConcurrent := u.config.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
-	var mu sync.Mutex
 
 	for _, manga := range mangaList {
 		wg.Add(1)
@@ -225,37 +372,60 @@ func (u *Updater) updateMangaList(mangaList []*source.Manga) (*UpdateSummary, er
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
+			u.mu.Lock()
+			u.checking[m.ID] = m.Title
+			u.mu.Unlock()
+
 			// Update single manga
 			task, err := u.updateSingleManga(m)
 
-			mu.Lock()
+			// The summary is guarded by u.mu so progress can be read while
+			// the update runs
+			var notification *storage.Notification
+			u.mu.Lock()
+			delete(u.checking, m.ID)
 			if err != nil {
 				summary.FailedManga++
 				if task != nil {
 					task.Status = StatusFailed
 					task.Error = err
 				}
+
+				if u.config.NotifyFailures {
+					notification = &storage.Notification{
+						Kind:      storage.NotifyUpdateFailed,
+						Severity:  storage.SeverityWarning,
+						Title:     m.Title,
+						Message:   fmt.Sprintf("Update check failed: %v", err),
+						MangaID:   m.ID,
+						CreatedAt: u.now(),
+					}
+				}
 			} else if task.HasNewChapters() {
 				summary.UpdatedManga++
 				summary.NewChapters += task.GetNewChapters()
 
 				// Send notification for new chapters
 				if u.config.NotifyNewChapters {
-					u.addNotification(&Notification{
-						ID:        fmt.Sprintf("manga-%s-%d", m.ID, time.Now().Unix()),
-						Type:      NotifyNewChapter,
+					notification = &storage.Notification{
+						Kind:      storage.NotifyNewChapters,
 						Title:     m.Title,
 						Message:   fmt.Sprintf("%d new chapter(s) available", task.GetNewChapters()),
-						CreatedAt: time.Now(),
 						MangaID:   m.ID,
-					})
+						ChapterID: sortedChapters(task.NewChapters)[0].ID,
+						CreatedAt: u.now(),
+					}
 				}
 			}
 
 			if task != nil {
 				summary.Tasks = append(summary.Tasks, task)
 			}
-			mu.Unlock()
+			u.mu.Unlock()
+
+			if notification != nil {
+				u.addNotification(notification)
+			}
 
 			// Call progress callback
 			if u.onProgress != nil && task != nil {
@@ -266,10 +436,20 @@ func (u *Updater) updateMangaList(mangaList []*source.Manga) (*UpdateSummary, er
 
 	wg.Wait()
 
-	summary.CompletedAt = time.Now()
+	// Queue new chapters selected by the auto-download rules
+	u.mu.RLock()
+	autoDownloader := u.autoDownloader
+	u.mu.RUnlock()
+	if autoDownloader != nil {
+		queued := autoDownloader.Process(summary)
+		u.mu.Lock()
+		summary.AutoDownloaded = queued
+		u.mu.Unlock()
+	}
 
 	// Add to history
 	u.mu.Lock()
+	summary.CompletedAt = u.now()
 	u.updateHistory = append(u.updateHistory, summary)
 	// Keep only last 50 updates
 	if len(u.updateHistory) > 50 {
@@ -285,22 +465,16 @@ func (u *Updater) updateMangaList(mangaList []*source.Manga) (*UpdateSummary, er
 	return summary, nil
 }
 
-// updateSingleManga updates a single manga
+// updateSingleManga checks a manga for chapters that are not known yet and
+// records the check, or its failure, for smart updates
 func (u *Updater) updateSingleManga(manga *source.Manga) (*UpdateTask, error) {
 	task := &UpdateTask{
 		MangaID:    manga.ID,
 		MangaTitle: manga.Title,
 		SourceType: manga.SourceType,
+		Manga:      manga,
 		Status:     StatusChecking,
-		StartedAt:  time.Now(),
-	}
-
-	// Get current chapter count from storage (if available)
-	if u.storage != nil && u.storage.UpdateTracking != nil {
-		tracking, err := u.storage.UpdateTracking.GetTracking(manga.ID)
-		if err == nil && tracking != nil {
-			task.OldChapterCount = tracking.ChapterCount
-		}
+		StartedAt:  u.now(),
 	}
 
 	// Fetch latest chapters from source
@@ -314,28 +488,133 @@ func (u *Updater) updateSingleManga(manga *source.Manga) (*UpdateTask, error) {
 	}
 
 	if src == nil {
-		task.Status = StatusFailed
-		task.Error = fmt.Errorf("source not found")
-		task.CompletedAt = time.Now()
-		return task, task.Error
+		return u.failTask(task, fmt.Errorf("source not found"))
 	}
 
 	chapters, err := src.ListChapters(manga.ID)
 	if err != nil {
-		task.Status = StatusFailed
-		task.Error = err
-		task.CompletedAt = time.Now()
-		return task, err
+		return u.failTask(task, err)
+	}
+
+	// Chapters seen by earlier checks (if storage is available)
+	known, err := u.knownChapters(manga.ID)
+	if err != nil {
+		return u.failTask(task, err)
 	}
 
-	task.NewChapterCount = len(chapters)
-	task.NewChapters = chapters
+	task.OldChapterCount = len(known)
+	task.Chapters = chapters
+	// On the first check every chapter would look new, so none are
+	task.FirstCheck = len(known) == 0
+	if !task.FirstCheck {
+		for _, chapter := range chapters {
+			if !known[chapter.ID] {
+				task.NewChapters = append(task.NewChapters, chapter)
+			}
+		}
+	}
+	task.NewChapterCount = len(task.NewChapters)
 	task.Status = StatusCompleted
-	task.CompletedAt = time.Now()
+	task.CompletedAt = u.now()
+
+	if err := u.recordCheck(task); err != nil {
+		return u.failTask(task, err)
+	}
 
 	return task, nil
 }
 
+// failTask marks a task as failed and records the failure for smart updates
+func (u *Updater) failTask(task *UpdateTask, err error) (*UpdateTask, error) {
+	task.Status = StatusFailed
+	task.Error = err
+	task.CompletedAt = u.now()
+
+	if u.storage != nil && u.storage.UpdateTracking != nil {
+		// The check failed already; a tracking error adds nothing for the user
+		_ = u.storage.UpdateTracking.RecordUpdateFailure(task.MangaID, task.CompletedAt)
+	}
+	return task, err
+}
+
+// knownChapters returns the IDs of the chapters of a manga seen by earlier
+// checks. Without storage no chapters are known.
+func (u *Updater) knownChapters(mangaID string) (map[string]bool, error) {
+	known := make(map[string]bool)
+	if u.storage == nil || u.storage.UpdateTracking == nil {
+		return known, nil
+	}
+
+	chapters, err := u.storage.UpdateTracking.GetKnownChapters(mangaID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range chapters {
+		known[c.ChapterID] = true
+	}
+	return known, nil
+}
+
+// recordCheck stores the chapters a check saw and updates the manga's
+// release tracking
+func (u *Updater) recordCheck(task *UpdateTask) error {
+	if u.storage == nil || u.storage.UpdateTracking == nil {
+		return nil
+	}
+
+	// New chapters go to the Updates feed
+	if task.HasNewChapters() && u.storage.Updates != nil {
+		feed := make([]*storage.ChapterUpdate, len(task.NewChapters))
+		for i, chapter := range task.NewChapters {
+			feed[i] = &storage.ChapterUpdate{
+				MangaID:       task.MangaID,
+				MangaTitle:    task.MangaTitle,
+				ChapterID:     chapter.ID,
+				ChapterTitle:  chapter.Title,
+				ChapterNumber: chapter.ChapterNumber,
+				PageCount:     chapter.PageCount,
+				SourceType:    string(task.SourceType),
+				SourceID:      chapter.SourceID,
+				FoundAt:       task.CompletedAt,
+			}
+		}
+		if err := u.storage.Updates.AddUpdates(feed); err != nil {
+			return err
+		}
+	}
+
+	if err := u.storage.UpdateTracking.RecordUpdateCheck(task.MangaID, task.CompletedAt, task.HasNewChapters(), len(task.Chapters)); err != nil {
+		return err
+	}
+	// The release calendar shows the title
+	if err := u.storage.UpdateTracking.SetMangaTitle(task.MangaID, task.MangaTitle); err != nil {
+		return err
+	}
+
+	// Completed series are skipped by smart updates when update_only_ongoing is set
+	if task.Manga != nil && task.Manga.Status != "" {
+		completed := strings.EqualFold(task.Manga.Status, "completed")
+		if err := u.storage.UpdateTracking.MarkAsCompleted(task.MangaID, completed); err != nil {
+			return err
+		}
+	}
+
+	// Known chapters are written last: if an earlier step fails, the next
+	// check finds the new chapters again. Every chapter is stored so that
+	// chapters seen before their upload dates were tracked gain one; the
+	// first sighting is kept.
+	known := make([]storage.KnownChapter, len(task.Chapters))
+	for i, chapter := range task.Chapters {
+		known[i] = storage.KnownChapter{
+			ChapterID:     chapter.ID,
+			ChapterNumber: chapter.ChapterNumber,
+			FirstSeen:     task.CompletedAt,
+			UploadDate:    chapter.UploadDate,
+		}
+	}
+	return u.storage.UpdateTracking.AddKnownChapters(task.MangaID, known)
+}
+
 // filterManga filters manga based on update config
 func (u *Updater) filterManga(allManga []*source.Manga) []*source.Manga {
 	if !u.config.UpdateOnlyStarted && !u.config.UpdateOnlyCompleted {
@@ -367,15 +646,12 @@ func (u *Updater) filterManga(allManga []*source.Manga) []*source.Manga {
 	return filtered
 }
 
-// addNotification adds a notification (internal)
-func (u *Updater) addNotification(notif *Notification) {
-	u.mu.Lock()
-	u.notifications = append(u.notifications, notif)
-	// Keep only last 100 notifications
-	if len(u.notifications) > 100 {
-		u.notifications = u.notifications[len(u.notifications)-100:]
+// addNotification stores a notification and passes it to the callback
+func (u *Updater) addNotification(notif *storage.Notification) {
+	if u.storage != nil && u.storage.Notifications != nil {
+		// A notification that could not be stored is still delivered
+		_ = u.storage.Notifications.Add(notif)
 	}
-	u.mu.Unlock()
 
 	// Call notification callback
 	if u.onNotification != nil {
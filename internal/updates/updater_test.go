@@ -0,0 +1,423 @@
+package updates
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Justice-Caban/Miryokusha/internal/source"
+	"github.com/Justice-Caban/Miryokusha/internal/storage"
+	"github.com/Justice-Caban/Miryokusha/internal/suwayomi/suwayomitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUpdater is an updater over a fake server with a clock the test moves
+type testUpdater struct {
+	*Updater
+	server  *suwayomitest.Server
+	storage *storage.Storage
+	clock   time.Time
+}
+
+// newTestUpdater serves mangaCount manga with three chapters each
+func newTestUpdater(t *testing.T, mangaCount int, config *UpdateConfig) *testUpdater {
+	t.Helper()
+
+	server := suwayomitest.NewServer()
+	t.Cleanup(server.Close)
+	server.SeedLibrary(mangaCount, 3, 1)
+
+	sm := source.NewSourceManager()
+	sm.AddSource(source.NewSuwayomiSourceWithClient("suwayomi", "Fake", server.Client()))
+
+	st, err := storage.NewStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	if config == nil {
+		config = DefaultUpdateConfig()
+	}
+	config.UpdateOnlyStarted = false
+
+	tu := &testUpdater{
+		Updater: NewUpdater(config, sm, st),
+		server:  server,
+		storage: st,
+		clock:   time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+	tu.now = func() time.Time { return tu.clock }
+	return tu
+}
+
+// release adds the next chapter of a manga on the fake server
+func (tu *testUpdater) release(t *testing.T, mangaID int, number float64) {
+	t.Helper()
+	_, err := tu.server.AddChapter(mangaID, &suwayomitest.Chapter{
+		Name:          fmt.Sprintf("Chapter %g", number),
+		ChapterNumber: number,
+		UploadDate:    tu.clock,
+		PageCount:     1,
+	})
+	require.NoError(t, err)
+}
+
+// taskFor returns the task of a manga in a summary, or nil if it was not checked
+func taskFor(summary *UpdateSummary, mangaID string) *UpdateTask {
+	for _, task := range summary.Tasks {
+		if task.MangaID == mangaID {
+			return task
+		}
+	}
+	return nil
+}
+
+func TestUpdateLibrary_ChapterDelta(t *testing.T) {
+	tu := newTestUpdater(t, 2, nil)
+
+	// The first check only learns the chapters
+	summary, err := tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.TotalManga)
+	assert.Equal(t, 0, summary.NewChapters)
+	task := taskFor(summary, "1")
+	require.NotNil(t, task)
+	assert.True(t, task.FirstCheck)
+	assert.Len(t, task.Chapters, 3)
+	assert.Empty(t, task.NewChapters)
+
+	tracking, err := tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	require.NotNil(t, tracking)
+	assert.Equal(t, 3, tracking.ChapterCount)
+	assert.Nil(t, tracking.LastChapterFound)
+
+	// A release is found by its ID, not by the chapter count
+	tu.clock = tu.clock.Add(24 * time.Hour)
+	tu.release(t, 1, 4)
+	summary, err = tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.NewChapters)
+	assert.Equal(t, 1, summary.UpdatedManga)
+
+	task = taskFor(summary, "1")
+	require.NotNil(t, task)
+	assert.False(t, task.FirstCheck)
+	assert.Equal(t, 3, task.OldChapterCount)
+	assert.Equal(t, 1, task.NewChapterCount)
+	require.Len(t, task.NewChapters, 1)
+	assert.Equal(t, 4.0, task.NewChapters[0].ChapterNumber)
+	assert.Len(t, task.Chapters, 4)
+	assert.False(t, taskFor(summary, "2").HasNewChapters())
+
+	tracking, err = tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, tracking.ChapterCount)
+	require.NotNil(t, tracking.LastChapterFound)
+	assert.True(t, tracking.LastChapterFound.Equal(tu.clock))
+
+	known, err := tu.storage.UpdateTracking.GetKnownChapters("1")
+	require.NoError(t, err)
+	require.Len(t, known, 4)
+	assert.True(t, known[3].FirstSeen.Equal(tu.clock), "new chapters remember when they were found")
+	assert.True(t, known[3].UploadDate.Equal(tu.clock), "chapters keep their upload date for release estimates")
+	assert.True(t, known[0].FirstSeen.Before(tu.clock), "known chapters keep their first sighting")
+
+	// New chapters are added to the Updates feed
+	feed, err := tu.storage.Updates.GetUpdates(10)
+	require.NoError(t, err)
+	require.Len(t, feed, 1)
+	assert.Equal(t, task.NewChapters[0].ID, feed[0].ChapterID)
+	assert.Equal(t, "Manga 1", feed[0].MangaTitle)
+	assert.True(t, feed[0].FoundAt.Equal(tu.clock))
+
+	// and reported in the notification center
+	notifications, err := tu.storage.Notifications.List(storage.NotificationFilter{Kind: storage.NotifyNewChapters})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "1", notifications[0].MangaID)
+	assert.Equal(t, feed[0].ChapterID, notifications[0].ChapterID)
+
+	// Nothing is new the next time
+	tu.clock = tu.clock.Add(24 * time.Hour)
+	summary, err = tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.NewChapters)
+}
+
+func TestUpdateLibrary_RecordsFailures(t *testing.T) {
+	tu := newTestUpdater(t, 1, nil)
+
+	tu.server.InjectFault(suwayomitest.Fault{Match: "GetChapters", Error: "source is down", Times: 1})
+	summary, err := tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.FailedManga)
+	task := taskFor(summary, "1")
+	require.NotNil(t, task)
+	assert.Equal(t, StatusFailed, task.Status)
+	assert.Error(t, task.Error)
+
+	tracking, err := tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	require.NotNil(t, tracking)
+	assert.Equal(t, 1, tracking.ConsecutiveFailures)
+	assert.True(t, tracking.LastCheck.Equal(tu.clock))
+
+	notifications, err := tu.storage.Notifications.List(storage.NotificationFilter{Kind: storage.NotifyUpdateFailed})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "1", notifications[0].MangaID)
+	assert.Contains(t, notifications[0].Message, "source is down")
+
+	// A failed first check does not make every chapter new later
+	tu.clock = tu.clock.Add(time.Hour)
+	summary, err = tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.FailedManga)
+	assert.Equal(t, 0, summary.NewChapters)
+
+	tracking, err = tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, tracking.ConsecutiveFailures)
+	assert.Equal(t, 2, tracking.FetchCount)
+}
+
+func TestUpdateLibrary_SmartUpdate(t *testing.T) {
+	config := DefaultUpdateConfig()
+	config.SmartUpdate = true
+	config.Interval = 24 * time.Hour
+	tu := newTestUpdater(t, 2, config)
+	tu.tick = config.Interval
+
+	// Manga 1 releases a chapter every 7 days for 5 weeks, manga 2 never
+	// does. The library is updated daily for 60 days.
+	checked := map[string]int{}
+	found := 0
+	skippedOnDay := map[int]int{}
+	next := 4.0
+	for day := 1; day <= 60; day++ {
+		tu.clock = tu.clock.Add(24 * time.Hour)
+		if day%7 == 0 && day <= 35 {
+			tu.release(t, 1, next)
+			next++
+		}
+
+		summary, err := tu.updateLibrary(true)
+		require.NoError(t, err)
+		for _, task := range summary.Tasks {
+			checked[task.MangaID]++
+		}
+		found += summary.NewChapters
+		skippedOnDay[day] = summary.SkippedManga
+	}
+
+	assert.Equal(t, 5, found, "every release is eventually found")
+	assert.Equal(t, 60, checked["2"], "manga without releases fall back to the minimum interval")
+	assert.Less(t, checked["1"], 25, "manga are not checked before their next release is expected")
+	assert.Equal(t, 0, skippedOnDay[14], "checked daily until the release interval is known")
+	assert.Equal(t, 1, skippedOnDay[15], "skipped right after a release once the interval is known")
+
+	tracking, err := tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	require.NotNil(t, tracking.AvgUpdateIntervalDays)
+	assert.GreaterOrEqual(t, *tracking.AvgUpdateIntervalDays, 7.0)
+}
+
+func TestUpdateLibrary_SmartUpdateRecovers(t *testing.T) {
+	config := DefaultUpdateConfig()
+	config.SmartUpdate = true
+	config.Interval = 12 * time.Hour
+	config.SmartUpdateConfig = storage.DefaultSmartUpdateConfig()
+	config.SmartUpdateConfig.MaxConsecutiveFailures = 3
+	tu := newTestUpdater(t, 1, config)
+	tu.tick = config.Interval
+
+	// The source of manga 1 is down for a week of scheduled updates
+	tu.server.InjectFault(suwayomitest.Fault{Match: "GetChapters", Error: "source is down"})
+	checks := 0
+	for step := 0; step < 14; step++ {
+		summary, err := tu.updateLibrary(true)
+		require.NoError(t, err)
+		if task := taskFor(summary, "1"); task != nil {
+			assert.Equal(t, StatusFailed, task.Status)
+			checks++
+		}
+		tu.clock = tu.clock.Add(12 * time.Hour)
+	}
+	// Checked every time until the third failure, then after 12h, 24h and 48h
+	assert.Equal(t, 6, checks, "failing manga are checked less often")
+
+	tracking, err := tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	assert.Equal(t, 6, tracking.ConsecutiveFailures)
+
+	// Once the source recovers, the next due check succeeds and later
+	// releases are found as usual
+	tu.server.ClearFaults()
+	recovered := false
+	for step := 0; step < 8 && !recovered; step++ {
+		tu.clock = tu.clock.Add(12 * time.Hour)
+		summary, err := tu.updateLibrary(true)
+		require.NoError(t, err)
+		task := taskFor(summary, "1")
+		recovered = task != nil && task.Status == StatusCompleted
+	}
+	require.True(t, recovered)
+	tracking, err = tu.storage.UpdateTracking.GetTracking("1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, tracking.ConsecutiveFailures)
+
+	tu.release(t, 1, 4)
+	tu.clock = tu.clock.Add(24 * time.Hour)
+	summary, err := tu.updateLibrary(true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.NewChapters)
+
+	// Updates run by hand check the manga whatever smart update expects
+	tu.server.InjectFault(suwayomitest.Fault{Match: "GetChapters", Error: "source is down", Times: 3})
+	for i := 0; i < 3; i++ {
+		summary, err = tu.UpdateLibrary()
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.FailedManga, "checked despite failing")
+	}
+	tu.release(t, 1, 5)
+	summary, err = tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.NewChapters)
+}
+
+func TestUpdateLibrary_Schedules(t *testing.T) {
+	config := DefaultUpdateConfig()
+	config.Interval = 24 * time.Hour
+	tu := newTestUpdater(t, 4, config)
+	categories := tu.storage.Categories
+
+	// Manga 1 is a weekly simulpub checked every 6h, manga 2 is completed
+	// and never checked, manga 3 is excluded on its own and manga 4
+	// follows the global settings
+	simulpubs, err := categories.Create("Weekly Simulpubs", false)
+	require.NoError(t, err)
+	require.NoError(t, categories.SetUpdateSchedule(simulpubs.ID, storage.UpdateSchedule{Mode: storage.UpdateModeEvery, Interval: 6 * time.Hour}))
+	completed, err := categories.Create("Finished", false)
+	require.NoError(t, err)
+	require.NoError(t, categories.SetUpdateSchedule(completed.ID, storage.UpdateSchedule{Mode: storage.UpdateModeNever}))
+	require.NoError(t, categories.AssignManga("1", simulpubs.ID))
+	require.NoError(t, categories.AssignManga("2", completed.ID))
+	require.NoError(t, categories.SetMangaUpdateSchedule("3", storage.UpdateSchedule{Mode: storage.UpdateModeNever}))
+
+	assert.Equal(t, 6*time.Hour, schedulerInterval(config.Interval, tu.updateSchedules()))
+	assert.Equal(t, 6*time.Hour, schedulerInterval(0, tu.updateSchedules()), "schedules run without a global interval")
+	assert.Equal(t, 24*time.Hour, schedulerInterval(config.Interval, nil))
+
+	// Manual updates skip only manga never checked
+	summary, err := tu.UpdateLibrary()
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.TotalManga)
+	assert.Equal(t, 2, summary.SkippedManga)
+	assert.NotNil(t, taskFor(summary, "1"))
+	assert.NotNil(t, taskFor(summary, "4"))
+
+	// The scheduler runs every 6h; manga 4 is only due once a day
+	tu.tick = 6 * time.Hour
+	for step := 1; step <= 4; step++ {
+		tu.clock = tu.clock.Add(6 * time.Hour)
+		summary, err = tu.updateLibrary(true)
+		require.NoError(t, err)
+		assert.NotNil(t, taskFor(summary, "1"), "step %d", step)
+		assert.Equal(t, step == 4, taskFor(summary, "4") != nil, "step %d", step)
+		assert.Nil(t, taskFor(summary, "2"))
+		assert.Nil(t, taskFor(summary, "3"))
+	}
+
+	// A check by hand counts as the last check
+	tu.clock = tu.clock.Add(time.Hour)
+	_, err = tu.UpdateManga("1")
+	require.NoError(t, err)
+	tu.clock = tu.clock.Add(5 * time.Hour)
+	summary, err = tu.updateLibrary(true)
+	require.NoError(t, err)
+	assert.NotNil(t, taskFor(summary, "1"), "due within half a tick")
+	tu.clock = tu.clock.Add(time.Hour)
+	summary, err = tu.updateLibrary(true)
+	require.NoError(t, err)
+	assert.Nil(t, taskFor(summary, "1"))
+
+	// Without a global interval, scheduled updates only run for schedules
+	tu.config.Interval = 0
+	tu.clock = tu.clock.Add(48 * time.Hour)
+	summary, err = tu.updateLibrary(true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.TotalManga)
+	assert.NotNil(t, taskFor(summary, "1"))
+}
+
+func TestUpdater_SetInterval(t *testing.T) {
+	config := DefaultUpdateConfig()
+	config.Interval = time.Hour
+	tu := newTestUpdater(t, 1, config)
+	tu.Start()
+	t.Cleanup(tu.Stop)
+	require.True(t, tu.IsScheduled())
+
+	// Turning automatic updates off stops the scheduler for good
+	tu.SetInterval(0)
+	assert.False(t, tu.IsScheduled())
+	assert.Equal(t, time.Duration(0), tu.Interval())
+	tu.SetInterval(0)
+	assert.False(t, tu.IsScheduled())
+
+	tu.SetInterval(2 * time.Hour)
+	assert.True(t, tu.IsScheduled())
+	assert.Equal(t, 2*time.Hour, tu.Interval())
+}
+
+func TestUpdateLibrary_InProgress(t *testing.T) {
+	config := DefaultUpdateConfig()
+	config.MaxConcurrent = 2
+	tu := newTestUpdater(t, 2, config)
+	tu.server.InjectFault(suwayomitest.Fault{Latency: 100 * time.Millisecond})
+
+	_, running := tu.Progress()
+	assert.False(t, running)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := tu.UpdateLibrary()
+		done <- err
+	}()
+
+	// Both manga are reported while they are checked
+	var progress UpdateProgress
+	require.Eventually(t, func() bool {
+		var ok bool
+		progress, ok = tu.Progress()
+		return ok && len(progress.Checking) == 2
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"Manga 1", "Manga 2"}, progress.Checking)
+	assert.Equal(t, 2, progress.Total)
+	assert.Equal(t, 0, progress.Checked)
+	assert.True(t, tu.IsUpdating())
+
+	// A second update does not start while the first runs
+	_, err := tu.UpdateLibrary()
+	assert.ErrorIs(t, err, ErrUpdateInProgress)
+
+	require.NoError(t, <-done)
+	_, running = tu.Progress()
+	assert.False(t, running)
+	assert.False(t, tu.IsUpdating())
+}
+
+func TestNextSchedule(t *testing.T) {
+	schedule := storage.UpdateSchedule{}
+	var names []string
+	for range SchedulePresets {
+		schedule = NextSchedule(schedule)
+		names = append(names, schedule.String())
+	}
+	assert.Equal(t, []string{"every 6h", "every 12h", "every 1d", "every 7d", "never", "default"}, names)
+
+	// Schedules set elsewhere start over
+	assert.Equal(t, SchedulePresets[0], NextSchedule(storage.UpdateSchedule{Mode: storage.UpdateModeEvery, Interval: 3 * time.Hour}))
+}